@@ -0,0 +1,542 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefAccountingMappings = "accountingMappings"
+	PrefXeroAccountCode    = "xeroAccountCode"
+	// PrefFreshBooksToken held the token before it moved to the keychain
+	PrefFreshBooksToken      = "freshBooksToken"
+	PrefFreshBooksBusinessID = "freshBooksBusinessID"
+	PrefFreshBooksSent       = "freshBooksSent"
+
+	// DefaultXeroAccountCode is Xero's standard Sales account
+	DefaultXeroAccountCode = "200"
+)
+
+// FreshBooksAPIURL is where time entries are sent.
+var FreshBooksAPIURL = "https://api.freshbooks.com"
+
+// An accountingTool is an accounting app billable time can be handed to.
+// customer and service are what it calls clients and the items work is
+// billed as, which projects map to. numeric is set for apps that want IDs
+// rather than names.
+type accountingTool struct {
+	id       string
+	name     string
+	customer string
+	service  string
+	numeric  bool
+}
+
+var accountingTools = []accountingTool{
+	{id: "quickbooks", name: "QuickBooks Online", customer: "Customer", service: "Product/Service"},
+	{id: "xero", name: "Xero", customer: "Contact", service: "Item code"},
+	{id: "freshbooks", name: "FreshBooks", customer: "Client ID", service: "Service ID", numeric: true},
+}
+
+// AccountingMapping maps clients to an accounting app's customers and
+// projects to its services, the empty project standing for entries without
+// one. Names left unmapped are passed on as they are.
+type AccountingMapping struct {
+	Customers map[string]string `json:"customers,omitempty"`
+	Services  map[string]string `json:"services,omitempty"`
+}
+
+func (m AccountingMapping) customer(client string) string {
+	if customer, ok := m.Customers[client]; ok {
+		return customer
+	}
+	return client
+}
+
+func (m AccountingMapping) service(project string) string {
+	if service, ok := m.Services[project]; ok {
+		return service
+	}
+	return project
+}
+
+// accountingMappings loads the mappings, keyed by accounting app.
+func accountingMappings() map[string]AccountingMapping {
+	mappings := make(map[string]AccountingMapping)
+	raw := fyne.CurrentApp().Preferences().String(PrefAccountingMappings)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mappings); err != nil {
+			log.Printf("accounting: reading mappings: %v", err)
+		}
+	}
+	return mappings
+}
+
+func setAccountingMappings(mappings map[string]AccountingMapping) {
+	raw, err := json.Marshal(mappings)
+	if err != nil {
+		log.Printf("accounting: saving mappings: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefAccountingMappings, string(raw))
+}
+
+// draftInvoice is an invoice for an accounting app to import, with the
+// entries it bills.
+type draftInvoice struct {
+	IssuedInvoice
+	Entries []Entry
+}
+
+// draftInvoices makes an invoice for each client with billable work between
+// from and to, numbered on from the ledger, and counts the entries left out
+// for waiting in Review.
+func draftInvoices(timer *TaskTimer, from, to, issued time.Time) (drafts []draftInvoice, unreviewed int) {
+	var clients []string
+	for _, entry := range entriesBetween(timer, from, to) {
+		if entry.Client != "" && !contains(clients, entry.Client) {
+			clients = append(clients, entry.Client)
+		}
+	}
+	sort.Strings(clients)
+
+	number := nextInvoiceNumber()
+	for _, client := range clients {
+		entries, skipped := invoiceEntries(timer, client, from, to)
+		unreviewed += skipped
+		if len(entries) == 0 {
+			continue
+		}
+		drafts = append(drafts, draftInvoice{
+			IssuedInvoice: newInvoice(number, client, from, to, issued, entries),
+			Entries:       entries,
+		})
+		number = invoiceNumberAfter(number)
+	}
+	return drafts, unreviewed
+}
+
+// dueDate is when an invoice is due, the day it's issued if the client has
+// no payment terms, as the accounting apps need one.
+func (d draftInvoice) dueDate() time.Time {
+	if d.Due.IsZero() {
+		return d.Issued
+	}
+	return d.Due
+}
+
+// lineDescription describes an entry on an invoice line.
+func lineDescription(entry Entry, locale ExportLocale) string {
+	description := locale.FormatDay(entry.Start) + " " + entry.Task
+	if entry.Notes != "" {
+		description += ": " + entry.Notes
+	}
+	return description
+}
+
+// lineService is the service an entry is billed as, Hours for entries
+// without a project unless that's mapped too.
+func lineService(entry Entry, mapping AccountingMapping) string {
+	if service := mapping.service(entry.Project); service != "" {
+		return service
+	}
+	return "Hours"
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// writeQuickBooksCSV writes invoices in the layout QuickBooks Online
+// imports, a line for each entry. Dates follow the export locale, to match
+// the company's date format; amounts always use a decimal point.
+func writeQuickBooksCSV(w io.Writer, drafts []draftInvoice, mapping AccountingMapping, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{
+		"InvoiceNo", "Customer", "InvoiceDate", "DueDate", "Terms", "Item(Product/Service)",
+		"ItemDescription", "ItemQuantity", "ItemRate", "ItemAmount", "TaxRate", "ServiceDate",
+	}); err != nil {
+		return err
+	}
+	for _, draft := range drafts {
+		settings := clientSettings()[draft.Client]
+		terms := ""
+		if settings.PaymentDays > 0 {
+			terms = fmt.Sprintf("Net %d", settings.PaymentDays)
+		}
+		taxRate := ""
+		if draft.TaxRate > 0 {
+			taxRate = strconv.FormatFloat(draft.TaxRate, 'f', -1, 64) + "%"
+		}
+		for _, entry := range draft.Entries {
+			hours := entry.Duration.Hours()
+			if err := out.Write([]string{
+				draft.Number,
+				mapping.customer(draft.Client),
+				locale.FormatDay(draft.Issued),
+				locale.FormatDay(draft.dueDate()),
+				terms,
+				lineService(entry, mapping),
+				lineDescription(entry, locale),
+				formatAmount(hours),
+				formatAmount(settings.HourlyRate),
+				formatAmount(hours * settings.HourlyRate),
+				taxRate,
+				locale.FormatDay(entry.Start),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeXeroCSV writes invoices in Xero's sales invoice import layout, a line
+// for each entry. Tax is left to the account's default rate.
+func writeXeroCSV(w io.Writer, drafts []draftInvoice, mapping AccountingMapping, accountCode string, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{
+		"*ContactName", "*InvoiceNumber", "*InvoiceDate", "*DueDate", "InventoryItemCode",
+		"*Description", "*Quantity", "*UnitAmount", "*AccountCode", "*TaxType", "Currency",
+	}); err != nil {
+		return err
+	}
+	for _, draft := range drafts {
+		settings := clientSettings()[draft.Client]
+		for _, entry := range draft.Entries {
+			// Xero only takes item codes it knows, so unmapped projects go
+			// in the description instead
+			item := mapping.Services[entry.Project]
+			description := lineDescription(entry, locale)
+			if item == "" && entry.Project != "" {
+				description = entry.Project + " – " + description
+			}
+			if err := out.Write([]string{
+				mapping.customer(draft.Client),
+				draft.Number,
+				locale.FormatDay(draft.Issued),
+				locale.FormatDay(draft.dueDate()),
+				item,
+				description,
+				formatAmount(entry.Duration.Hours()),
+				formatAmount(settings.HourlyRate),
+				accountCode,
+				"",
+				draft.Currency,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+type freshBooksTimeEntry struct {
+	IsLogged  bool   `json:"is_logged"`
+	Duration  int    `json:"duration"`
+	Note      string `json:"note,omitempty"`
+	StartedAt string `json:"started_at"`
+	ClientID  int    `json:"client_id"`
+	ServiceID int    `json:"service_id,omitempty"`
+}
+
+// postFreshBooksTimeEntry logs an entry as a FreshBooks time entry for the
+// client and service it maps to.
+func postFreshBooksTimeEntry(token, businessID string, entry Entry, clientID, serviceID int) error {
+	note := entry.Task
+	if entry.Notes != "" {
+		note += ": " + entry.Notes
+	}
+	body, err := json.Marshal(map[string]freshBooksTimeEntry{"time_entry": {
+		IsLogged:  true,
+		Duration:  int(entry.Duration.Seconds()),
+		Note:      note,
+		StartedAt: entry.Start.UTC().Format(time.RFC3339),
+		ClientID:  clientID,
+		ServiceID: serviceID,
+	}})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/timetracking/business/%s/time_entries", strings.TrimRight(FreshBooksAPIURL, "/"), businessID)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("freshbooks: logging time: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("freshbooks: logging time: %s", resp.Status)
+	}
+	return nil
+}
+
+// sendToFreshBooks logs the entries not sent before as FreshBooks time
+// entries in the background. Entries whose client isn't mapped to a
+// FreshBooks client ID are skipped. done reports how many were sent and
+// skipped, and the error that stopped it, if any.
+func sendToFreshBooks(entries []Entry, mapping AccountingMapping, done func(sent, skipped int, err error)) {
+	prefs := fyne.CurrentApp().Preferences()
+	token := keychainSecret(KeyringFreshBooksToken)
+	businessID := prefs.String(PrefFreshBooksBusinessID)
+	alreadySent := prefs.StringList(PrefFreshBooksSent)
+
+	go func() {
+		var sentIDs []string
+		skipped := 0
+		var err error
+		for _, entry := range entries {
+			if contains(alreadySent, entry.ID) {
+				continue
+			}
+			clientID, convErr := strconv.Atoi(mapping.Customers[entry.Client])
+			if convErr != nil {
+				skipped++
+				continue
+			}
+			serviceID, _ := strconv.Atoi(mapping.Services[entry.Project])
+			if err = postFreshBooksTimeEntry(token, businessID, entry, clientID, serviceID); err != nil {
+				break
+			}
+			sentIDs = append(sentIDs, entry.ID)
+		}
+		fyne.Do(func() {
+			// Remember what was sent so it isn't logged twice next time
+			prefs.SetStringList(PrefFreshBooksSent, append(prefs.StringList(PrefFreshBooksSent), sentIDs...))
+			done(len(sentIDs), skipped, err)
+		})
+	}()
+}
+
+// showAccountingExportDialog hands a period's billable time to an
+// accounting app: draft invoices in the CSV layout QuickBooks Online or
+// Xero imports, entered in the ledger once saved, or time entries sent to
+// FreshBooks.
+func showAccountingExportDialog(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Accounting Export")) {
+		return
+	}
+
+	var names []string
+	for _, tool := range accountingTools {
+		names = append(names, tool.name)
+	}
+	toolSelect := widget.NewSelect(names, nil)
+	toolSelect.SetSelectedIndex(0)
+	fromInput := widget.NewEntry()
+	fromInput.PlaceHolder = "YYYY-MM-DD"
+	toInput := widget.NewEntry()
+	toInput.PlaceHolder = "YYYY-MM-DD"
+	periodSelect := newReportPeriodSelect(fromInput, toInput)
+	periodSelect.SetSelectedIndex(2)
+	mappingBtn := widget.NewButton(tr("Clients and projects…"), func() {
+		showAccountingMappingDialog(timer, accountingTools[toolSelect.SelectedIndex()])
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Export to"), toolSelect),
+		widget.NewFormItem(tr("Period"), periodSelect),
+		widget.NewFormItem(tr("From"), fromInput),
+		widget.NewFormItem(tr("To"), toInput),
+		widget.NewFormItem(tr("Mapping"), mappingBtn),
+	}
+	d := dialog.NewForm(tr("Accounting Export"), tr("Export"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		from, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		// Include the whole of the final day
+		to = to.AddDate(0, 0, 1)
+
+		tool := accountingTools[toolSelect.SelectedIndex()]
+		mapping := accountingMappings()[tool.id]
+		drafts, unreviewed := draftInvoices(timer, from, to, time.Now())
+		if len(drafts) == 0 {
+			dialog.ShowInformation(tr("Accounting Export"), tr("No billable client work in this period"), timer.window)
+			return
+		}
+		message := tr("{{.Count}} entries in this period still need review and are left out. Export anyway?", map[string]any{
+			"Count": unreviewed,
+		})
+		confirmUnreviewed(timer, unreviewed, message, func() {
+			if tool.id == "freshbooks" {
+				exportToFreshBooks(timer, drafts, mapping)
+				return
+			}
+			fileName := fmt.Sprintf("%s-%s.csv", tool.id, from.Format("2006-01"))
+			saveExport(timer, fileName, func(w io.Writer) error {
+				var err error
+				if tool.id == "xero" {
+					accountCode := fyne.CurrentApp().Preferences().StringWithFallback(PrefXeroAccountCode, DefaultXeroAccountCode)
+					err = writeXeroCSV(w, drafts, mapping, accountCode, currentExportLocale())
+				} else {
+					err = writeQuickBooksCSV(w, drafts, mapping, currentExportLocale())
+				}
+				if err != nil {
+					return err
+				}
+				for _, draft := range drafts {
+					if err := issueInvoice(draft.IssuedInvoice); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+	}, timer.window)
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+}
+
+// exportToFreshBooks sends the drafts' entries to FreshBooks, which makes
+// the invoices from them itself.
+func exportToFreshBooks(timer *TaskTimer, drafts []draftInvoice, mapping AccountingMapping) {
+	prefs := fyne.CurrentApp().Preferences()
+	if keychainSecret(KeyringFreshBooksToken) == "" || prefs.String(PrefFreshBooksBusinessID) == "" {
+		dialog.ShowInformation("FreshBooks", tr("Enter your FreshBooks access token and business ID under Clients and projects first."), timer.window)
+		return
+	}
+	var entries []Entry
+	for _, draft := range drafts {
+		entries = append(entries, draft.Entries...)
+	}
+	sendToFreshBooks(entries, mapping, func(sent, skipped int, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+		message := tr("Sent {{.Count}} entries to FreshBooks.", map[string]any{"Count": sent})
+		if skipped > 0 {
+			message += "\n" + tr("{{.Count}} entries were skipped because their client has no FreshBooks client ID.", map[string]any{"Count": skipped})
+		}
+		dialog.ShowInformation("FreshBooks", message, timer.window)
+	})
+}
+
+// showAccountingMappingDialog maps each client and project to what an
+// accounting app calls them, along with the app's own settings.
+func showAccountingMappingDialog(timer *TaskTimer, tool accountingTool) {
+	prefs := fyne.CurrentApp().Preferences()
+	mappings := accountingMappings()
+	mapping := mappings[tool.id]
+
+	form := container.NewVBox()
+	var settings []*widget.FormItem
+	tokenInput := widget.NewPasswordEntry()
+	businessInput := widget.NewEntry()
+	accountInput := widget.NewEntry()
+	switch tool.id {
+	case "freshbooks":
+		tokenInput.SetText(keychainSecret(KeyringFreshBooksToken))
+		businessInput.SetText(prefs.String(PrefFreshBooksBusinessID))
+		settings = append(settings,
+			widget.NewFormItem(tr("Access token"), tokenInput),
+			widget.NewFormItem(tr("Business ID"), businessInput))
+	case "xero":
+		accountInput.SetText(prefs.StringWithFallback(PrefXeroAccountCode, DefaultXeroAccountCode))
+		settings = append(settings, widget.NewFormItem(tr("Sales account code"), accountInput))
+	}
+	if len(settings) > 0 {
+		form.Add(widget.NewForm(settings...))
+		form.Add(widget.NewSeparator())
+	}
+
+	newInput := func(current, fallback string) *widget.Entry {
+		input := widget.NewEntry()
+		if !tool.numeric {
+			input.PlaceHolder = fallback
+		}
+		input.SetText(current)
+		return input
+	}
+
+	customerInputs := make(map[string]*widget.Entry)
+	form.Add(widget.NewLabelWithStyle(tr(tool.customer), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, client := range knownClients(timer) {
+		input := newInput(mapping.Customers[client], client)
+		customerInputs[client] = input
+		form.Add(widget.NewForm(widget.NewFormItem(client, input)))
+	}
+	if len(customerInputs) == 0 {
+		form.Add(widget.NewLabel(tr("No entries have a client yet.")))
+	}
+
+	serviceInputs := make(map[string]*widget.Entry)
+	form.Add(widget.NewLabelWithStyle(tr(tool.service), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, project := range append(knownProjects(timer), "") {
+		label, fallback := project, project
+		if project == "" {
+			label, fallback = tr("No project"), "Hours"
+		}
+		input := newInput(mapping.Services[project], fallback)
+		serviceInputs[project] = input
+		form.Add(widget.NewForm(widget.NewFormItem(label, input)))
+	}
+
+	d := dialog.NewCustomConfirm(tool.name, tr("Save"), tr("Cancel"), container.NewVScroll(form), func(ok bool) {
+		if !ok {
+			return
+		}
+		mapping = AccountingMapping{Customers: make(map[string]string), Services: make(map[string]string)}
+		for client, input := range customerInputs {
+			if value := strings.TrimSpace(input.Text); value != "" {
+				mapping.Customers[client] = value
+			}
+		}
+		for project, input := range serviceInputs {
+			if value := strings.TrimSpace(input.Text); value != "" {
+				mapping.Services[project] = value
+			}
+		}
+		mappings[tool.id] = mapping
+		setAccountingMappings(mappings)
+
+		switch tool.id {
+		case "freshbooks":
+			if err := setKeychainSecrets(map[string]string{KeyringFreshBooksToken: strings.TrimSpace(tokenInput.Text)}); err != nil {
+				dialog.ShowError(err, timer.window)
+			}
+			prefs.SetString(PrefFreshBooksBusinessID, strings.TrimSpace(businessInput.Text))
+		case "xero":
+			if code := strings.TrimSpace(accountInput.Text); code != "" {
+				prefs.SetString(PrefXeroAccountCode, code)
+			} else {
+				prefs.RemoveValue(PrefXeroAccountCode)
+			}
+		}
+	}, timer.window)
+	d.Resize(fyne.NewSize(400, 480))
+	d.Show()
+}
@@ -0,0 +1,32 @@
+//go:build darwin && !ios
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// activeWindowScript asks System Events for the frontmost application and its
+// front window's title. The title needs the accessibility permission; without
+// it only the application is known.
+const activeWindowScript = `tell application "System Events"
+	set frontApp to first application process whose frontmost is true
+	set appName to name of frontApp
+	set windowTitle to ""
+	try
+		set windowTitle to name of front window of frontApp
+	end try
+	return appName & linefeed & windowTitle
+end tell`
+
+// activeWindow reads the frontmost application through osascript.
+func activeWindow() (ActiveWindow, error) {
+	out, err := exec.Command("osascript", "-e", activeWindowScript).Output()
+	if err != nil {
+		return ActiveWindow{}, fmt.Errorf("osascript: %w", err)
+	}
+	app, title, _ := strings.Cut(strings.TrimRight(string(out), "\n"), "\n")
+	return ActiveWindow{App: app, Title: title}, nil
+}
@@ -0,0 +1,54 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	xpropWindowPattern = regexp.MustCompile(`window id # (0x[0-9a-f]+)`)
+	xpropStringPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+)
+
+// activeWindow reads the focused X11 window through xprop, which has to be
+// installed: the application is the window's class and the title its name.
+// Wayland doesn't let other programs see the focused window.
+func activeWindow() (ActiveWindow, error) {
+	out, err := exec.Command("xprop", "-root", "_NET_ACTIVE_WINDOW").Output()
+	if err != nil {
+		return ActiveWindow{}, fmt.Errorf("xprop: %w", err)
+	}
+	m := xpropWindowPattern.FindSubmatch(out)
+	if m == nil || string(m[1]) == "0x0" {
+		return ActiveWindow{}, errNoActiveWindow
+	}
+
+	out, err = exec.Command("xprop", "-id", string(m[1]), "WM_CLASS", "_NET_WM_NAME").Output()
+	if err != nil {
+		return ActiveWindow{}, fmt.Errorf("xprop: %w", err)
+	}
+	var window ActiveWindow
+	for _, line := range strings.Split(string(out), "\n") {
+		values := xpropStringPattern.FindAllStringSubmatch(line, -1)
+		if len(values) == 0 {
+			continue
+		}
+		// WM_CLASS holds the instance and then the class
+		value, err := strconv.Unquote(`"` + values[len(values)-1][1] + `"`)
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "WM_CLASS"):
+			window.App = value
+		case strings.HasPrefix(line, "_NET_WM_NAME"):
+			window.Title = value
+		}
+	}
+	return window, nil
+}
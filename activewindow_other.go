@@ -0,0 +1,8 @@
+//go:build !linux && !windows && (!darwin || ios)
+
+package main
+
+// activeWindow has no native implementation on this platform.
+func activeWindow() (ActiveWindow, error) {
+	return ActiveWindow{}, errActiveWindowUnsupported
+}
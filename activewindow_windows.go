@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	getForegroundWindow        = syscall.NewLazyDLL("user32.dll").NewProc("GetForegroundWindow")
+	getWindowTextW             = syscall.NewLazyDLL("user32.dll").NewProc("GetWindowTextW")
+	getWindowThreadProcessID   = syscall.NewLazyDLL("user32.dll").NewProc("GetWindowThreadProcessId")
+	queryFullProcessImageNameW = syscall.NewLazyDLL("kernel32.dll").NewProc("QueryFullProcessImageNameW")
+)
+
+// processQueryLimitedInformation is PROCESS_QUERY_LIMITED_INFORMATION, enough
+// to read another process's executable path
+const processQueryLimitedInformation = 0x1000
+
+// activeWindow reads the foreground window's title, and names its
+// application after the executable, e.g. "idea64".
+func activeWindow() (ActiveWindow, error) {
+	hwnd, _, _ := getForegroundWindow.Call()
+	if hwnd == 0 {
+		return ActiveWindow{}, errNoActiveWindow
+	}
+
+	var window ActiveWindow
+	title := make([]uint16, 512)
+	if n, _, _ := getWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&title[0])), uintptr(len(title))); n > 0 {
+		window.Title = syscall.UTF16ToString(title[:n])
+	}
+
+	var pid uint32
+	getWindowThreadProcessID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	process, err := syscall.OpenProcess(processQueryLimitedInformation, false, pid)
+	if err != nil {
+		return window, nil
+	}
+	defer syscall.CloseHandle(process)
+	path := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(path))
+	if ok, _, _ := queryFullProcessImageNameW.Call(uintptr(process), 0, uintptr(unsafe.Pointer(&path[0])), uintptr(unsafe.Pointer(&size))); ok != 0 {
+		exe := filepath.Base(syscall.UTF16ToString(path[:size]))
+		window.App = strings.TrimSuffix(exe, filepath.Ext(exe))
+	}
+	return window, nil
+}
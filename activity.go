@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefActivitySuggestions = "activitySuggestions"
+	PrefActivityTasks       = "activityTasks"
+
+	// ActivitySampleInterval is how often the focused window is read
+	ActivitySampleInterval = 30 * time.Second
+
+	// ActivitySuggestAfter is how long one application has to stay focused
+	// with no timer running before a task is suggested
+	ActivitySuggestAfter = 20 * time.Minute
+)
+
+var (
+	errNoActiveWindow          = errors.New("no window is focused")
+	errActiveWindowUnsupported = errors.New("reading the focused window isn't supported here")
+)
+
+// ActiveWindow is the focused window as sampled. It is only kept in memory.
+type ActiveWindow struct {
+	App   string
+	Title string
+}
+
+// Key is what a stretch of activity is grouped by: the application, or the
+// title where the application can't be read.
+func (w ActiveWindow) Key() string {
+	if w.App != "" {
+		return w.App
+	}
+	return w.Title
+}
+
+// activityTasks loads the task accepted for each application. Applications
+// are only stored once a suggestion for them is accepted.
+func activityTasks() map[string]string {
+	tasks := make(map[string]string)
+	raw := fyne.CurrentApp().Preferences().String(PrefActivityTasks)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &tasks); err != nil {
+			log.Printf("activity: reading tasks: %v", err)
+		}
+	}
+	return tasks
+}
+
+func setActivityTasks(tasks map[string]string) {
+	raw, err := json.Marshal(tasks)
+	if err != nil {
+		log.Printf("activity: saving tasks: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefActivityTasks, string(raw))
+}
+
+// watchActiveWindow samples the focused window while suggestions are turned
+// on, and suggests starting a task once one application has been in use for
+// ActivitySuggestAfter with no timer running. Each stretch is suggested at
+// most once.
+func watchActiveWindow(timer *TaskTimer) {
+	var current string
+	var since time.Time
+	suggested := false
+	reported := false
+	for range time.Tick(ActivitySampleInterval) {
+		if !fyne.CurrentApp().Preferences().Bool(PrefActivitySuggestions) {
+			current = ""
+			continue
+		}
+
+		window, err := activeWindow()
+		if err != nil {
+			// Unsupported platforms and missing tools would fail every time
+			if !reported {
+				log.Printf("activity: %v", err)
+				reported = true
+			}
+			current = ""
+			continue
+		}
+
+		now := time.Now()
+		var running bool
+		fyne.DoAndWait(func() {
+			running = timer.isRunning
+		})
+		if running || window.Key() == "" {
+			current = ""
+			continue
+		}
+		if window.Key() != current {
+			current, since, suggested = window.Key(), now, false
+			continue
+		}
+		if !suggested && now.Sub(since) >= ActivitySuggestAfter {
+			suggested = true
+			fyne.Do(func() {
+				suggestActivityTask(timer, window, now.Sub(since))
+			})
+		}
+	}
+}
+
+// suggestActivityTask offers to start a task for the application in use,
+// the one accepted for it before if any. Accepting remembers the choice.
+func suggestActivityTask(timer *TaskTimer, window ActiveWindow, spent time.Duration) {
+	if timer.isRunning {
+		return
+	}
+
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.PlaceHolder = tr("Task")
+	taskInput.SetText(activityTasks()[window.Key()])
+
+	message := widget.NewLabel(tr("You've been in {{.App}} for {{.Duration}} with no timer running. Start a task?", map[string]any{
+		"App":      window.Key(),
+		"Duration": formatShortDuration(spent.Truncate(time.Minute)),
+	}))
+	message.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", message),
+		widget.NewFormItem(tr("Task"), taskInput),
+	}
+	d := dialog.NewForm(tr("Start Timing?"), tr("Start"), tr("Not now"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected {
+			dialog.ShowInformation(tr("Start Timing?"), tr("Choose a task."), timer.window)
+			return
+		}
+		tasks := activityTasks()
+		tasks[window.Key()] = taskName
+		setActivityTasks(tasks)
+		startTask(timer, taskName)
+	}, timer.window)
+	d.Resize(fyne.NewSize(380, 0))
+	d.Show()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefAdjustStep is how many minutes the quick adjustment buttons add or
+	// take off the session
+	PrefAdjustStep = "adjustStep"
+
+	DefaultAdjustStep = 5
+)
+
+var AdjustSteps = []int{1, 5, 10, 15}
+
+// adjustStep returns the step of the quick adjustment buttons.
+func adjustStep() time.Duration {
+	return time.Duration(fyne.CurrentApp().Preferences().IntWithFallback(PrefAdjustStep, DefaultAdjustStep)) * time.Minute
+}
+
+// adjustSession corrects the session in progress by delta, e.g. to take off
+// a short interruption the timer ran through. The session can't go below
+// zero. The correction is kept on the entry when the session is stopped.
+func adjustSession(timer *TaskTimer, delta time.Duration) {
+	if !timer.isRunning && timer.elapsedTime == 0 {
+		return
+	}
+	delta = max(delta, -timer.elapsedTime)
+	timer.adjustment += delta
+	setElapsed(timer, timer.elapsedTime+delta)
+	writeStatus(timer)
+}
+
+// createAdjustButtons puts a button either side of the clock to take the
+// adjustment step off the session or add it on.
+func createAdjustButtons(timer *TaskTimer, clock fyne.CanvasObject) fyne.CanvasObject {
+	step := adjustStep()
+	minusBtn := widget.NewButton("−"+formatShortDuration(step), func() {
+		adjustSession(timer, -adjustStep())
+	})
+	plusBtn := widget.NewButton("+"+formatShortDuration(step), func() {
+		adjustSession(timer, adjustStep())
+	})
+	return container.NewBorder(nil, nil, minusBtn, plusBtn, clock)
+}
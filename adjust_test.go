@@ -0,0 +1,133 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+// uiThread stands in for the app's UI thread, which the test driver doesn't
+// have: functions passed to fyne.Do run on it one at a time, as do the ones
+// the test runs there itself.
+type uiThread struct {
+	fyne.App
+	queue chan func()
+}
+
+func newUIThread(app fyne.App) *uiThread {
+	ui := &uiThread{App: app, queue: make(chan func(), 1024)}
+	go func() {
+		for fn := range ui.queue {
+			fn()
+		}
+	}()
+	return ui
+}
+
+func (ui *uiThread) Driver() fyne.Driver {
+	return uiDriver{ui.App.Driver(), ui}
+}
+
+// run runs fn on the UI thread and waits for it.
+func (ui *uiThread) run(fn func()) {
+	done := make(chan struct{})
+	ui.queue <- func() {
+		defer close(done)
+		fn()
+	}
+	<-done
+}
+
+type uiDriver struct {
+	fyne.Driver
+	ui *uiThread
+}
+
+func (d uiDriver) DoFromGoroutine(fn func(), wait bool) {
+	if wait {
+		d.ui.run(fn)
+		return
+	}
+	d.ui.queue <- fn
+}
+
+// TestAdjustWhileTicking adjusts the session from the UI thread while the
+// ticker advances it; run with -race.
+func TestAdjustWhileTicking(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	ui := newUIThread(test.NewTempApp(t))
+	fyne.SetCurrentApp(ui)
+	precisionMode.Store(true)
+	t.Cleanup(func() { precisionMode.Store(false) })
+
+	timer := &TaskTimer{taskName: "Code", stopTicker: make(chan bool, 1)}
+	timer.liveFeed = NewLiveFeed(timer)
+	newTimerBindings(timer)
+
+	started := time.Now()
+	ui.run(func() {
+		timer.isRunning = true
+		timer.tickedAt = started
+	})
+	go startTimer(timer)
+	for range 20 {
+		ui.run(func() { adjustSession(timer, time.Minute) })
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var elapsed time.Duration
+	ui.run(func() {
+		advanceElapsed(timer, time.Now())
+		timer.isRunning = false
+		timer.stopTicker <- true
+		elapsed = timer.elapsedTime
+	})
+	ran := time.Since(started)
+	if want := 20 * time.Minute; elapsed < want || elapsed > want+ran {
+		t.Errorf("elapsed = %v, want 20m plus up to %v", elapsed, ran)
+	}
+}
+
+// TestAdjustmentRoundTrip saves adjusted entries in every backend and
+// reads them back.
+func TestAdjustmentRoundTrip(t *testing.T) {
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	want := map[string]time.Duration{"added": 15 * time.Minute, "taken off": -10 * time.Minute, "none": 0}
+	for name, backend := range storageBackends {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), backend.file)
+			store, err := backend.open(path, "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id, adjustment := range want {
+				entry := Entry{ID: id, Task: "Code", Start: start, End: start.Add(time.Hour), Duration: time.Hour + adjustment, Updated: start, Adjustment: adjustment}
+				if err := store.PutEntry(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+			store.Close()
+
+			if store, err = backend.open(path, "secret"); err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			entries, err := store.Entries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(want) {
+				t.Fatalf("loaded %d entries, want %d", len(entries), len(want))
+			}
+			for _, entry := range entries {
+				if entry.Adjustment != want[entry.ID] || entry.Duration != time.Hour+want[entry.ID] {
+					t.Errorf("entry %q loaded adjusted by %v to %v, want %v to %v",
+						entry.ID, entry.Adjustment, entry.Duration, want[entry.ID], time.Hour+want[entry.ID])
+				}
+			}
+		})
+	}
+}
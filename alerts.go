@@ -0,0 +1,179 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Alert is a notification that stays active until the user dismisses it.
+// Raising an alert with the same ID replaces the active one.
+type Alert struct {
+	ID      string
+	Title   string
+	Message string
+}
+
+// AlertRenotifyIntervals is the escalation schedule: an alert that is left
+// unanswered is shown again after each interval in turn, then left alone.
+var AlertRenotifyIntervals = []time.Duration{
+	5 * time.Minute,
+	2 * time.Minute,
+	1 * time.Minute,
+}
+
+// AlertSnoozeDurations are the snooze choices offered on every alert.
+var AlertSnoozeDurations = []time.Duration{
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+}
+
+type activeAlert struct {
+	alert   Alert
+	attempt int
+	pending *time.Timer
+	dialog  dialog.Dialog
+}
+
+// AlertScheduler owns every alert in the app so snoozing and re-notification
+// behave the same everywhere, instead of each feature running its own timers.
+type AlertScheduler struct {
+	mu     sync.Mutex
+	window fyne.Window
+	active map[string]*activeAlert
+}
+
+func NewAlertScheduler(window fyne.Window) *AlertScheduler {
+	return &AlertScheduler{
+		window: window,
+		active: make(map[string]*activeAlert),
+	}
+}
+
+// Raise shows an alert now and keeps re-notifying until it is dismissed.
+func (s *AlertScheduler) Raise(alert Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopLocked(alert.ID)
+	active := &activeAlert{alert: alert}
+	s.active[alert.ID] = active
+	s.fireLocked(active)
+}
+
+// Snooze hides an alert and shows it again after d, restarting escalation.
+func (s *AlertScheduler) Snooze(id string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active, ok := s.active[id]
+	if !ok {
+		return
+	}
+	s.hideLocked(active)
+	active.attempt = 0
+	active.pending = s.fireAfter(active, d)
+}
+
+// Dismiss clears an alert and cancels any pending re-notification.
+func (s *AlertScheduler) Dismiss(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopLocked(id)
+}
+
+// IsActive reports whether an alert is showing, snoozed or awaiting re-notification.
+func (s *AlertScheduler) IsActive(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.active[id]
+	return ok
+}
+
+func (s *AlertScheduler) stopLocked(id string) {
+	if active, ok := s.active[id]; ok {
+		s.hideLocked(active)
+		delete(s.active, id)
+	}
+}
+
+func (s *AlertScheduler) hideLocked(active *activeAlert) {
+	if active.pending != nil {
+		active.pending.Stop()
+		active.pending = nil
+	}
+	if d := active.dialog; d != nil {
+		active.dialog = nil
+		fyne.Do(d.Hide)
+	}
+}
+
+// fireLocked shows the alert and schedules the next escalation step. It runs
+// on the UI thread.
+func (s *AlertScheduler) fireLocked(active *activeAlert) {
+	s.hideLocked(active)
+
+	alert := active.alert
+	title := alert.Title
+	if active.attempt > 0 {
+		title = tr("Reminder: {{.Title}}", map[string]any{"Title": title})
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(title, alert.Message))
+
+	d := s.newAlertDialog(alert, title)
+	active.dialog = d
+	d.Show()
+
+	if active.attempt < len(AlertRenotifyIntervals) {
+		interval := AlertRenotifyIntervals[active.attempt]
+		active.attempt++
+		active.pending = s.fireAfter(active, interval)
+	}
+}
+
+// fireAfter shows an alert again after d, on the UI thread, unless it was
+// dismissed, replaced or rescheduled in the meantime. The caller holds mu.
+func (s *AlertScheduler) fireAfter(active *activeAlert, d time.Duration) *time.Timer {
+	var pending *time.Timer
+	pending = time.AfterFunc(d, func() {
+		fyne.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			// Snoozing can't stop a timer that already went off
+			if s.active[active.alert.ID] == active && active.pending == pending {
+				s.fireLocked(active)
+			}
+		})
+	})
+	return pending
+}
+
+func (s *AlertScheduler) newAlertDialog(alert Alert, title string) dialog.Dialog {
+	buttons := container.NewHBox(widget.NewButton(tr("Dismiss"), func() {
+		s.Dismiss(alert.ID)
+	}))
+	for _, d := range AlertSnoozeDurations {
+		buttons.Add(widget.NewButton(tr("Snooze {{.Duration}}", map[string]any{"Duration": formatShortDuration(d)}), func() {
+			s.Snooze(alert.ID, d)
+		}))
+	}
+
+	content := container.NewVBox(widget.NewLabel(alert.Message), buttons)
+	return dialog.NewCustomWithoutButtons(title, content, s.window)
+}
+
+// formatShortDuration renders whole minutes or hours compactly in the UI
+// language, e.g. "15m" or "1h".
+func formatShortDuration(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return tr("{{.Hours}}h", map[string]any{"Hours": int(d / time.Hour)})
+	}
+	return tr("{{.Minutes}}m", map[string]any{"Minutes": int(d / time.Minute)})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestAlertRenotifiesOnUIThread(t *testing.T) {
+	ui := newUIThread(test.NewTempApp(t))
+	fyne.SetCurrentApp(ui)
+	intervals := AlertRenotifyIntervals
+	AlertRenotifyIntervals = []time.Duration{10 * time.Millisecond}
+	t.Cleanup(func() { AlertRenotifyIntervals = intervals })
+
+	scheduler := NewAlertScheduler(test.NewWindow(nil))
+	alert := Alert{ID: "budget", Title: "Budget", Message: "80% of Website used"}
+	shown := func() dialog.Dialog {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return scheduler.active[alert.ID].dialog
+	}
+	var first dialog.Dialog
+	ui.run(func() {
+		scheduler.Raise(alert)
+		first = shown()
+	})
+
+	// While the UI thread is busy the reminder has to wait for it
+	release := make(chan struct{})
+	ui.queue <- func() { <-release }
+	time.Sleep(50 * time.Millisecond)
+	if shown() != first {
+		t.Error("the reminder was built off the UI thread")
+	}
+	close(release)
+
+	var reminder dialog.Dialog
+	ui.run(func() { reminder = shown() })
+	if reminder == nil || reminder == first {
+		t.Error("the reminder wasn't shown once the UI thread was free")
+	}
+	ui.run(func() { scheduler.Dismiss(alert.ID) })
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Audit actions, the kinds of change the audit log records.
+const (
+	AuditEdited   = "edited"
+	AuditDeleted  = "deleted"
+	AuditSplit    = "split"
+	AuditRestored = "restored"
+)
+
+// AuditRecord is one change to an entry: who made it, on which device, when,
+// and the entry before and after. Before is nil for an entry that didn't
+// exist yet and After for one deleted. Hash covers the record and the hash
+// of the record before it, so changing or removing a record afterwards
+// breaks the chain from there on.
+type AuditRecord struct {
+	EntryID string
+	At      time.Time
+	User    string
+	Device  string
+	Action  string
+	Before  *Entry `json:",omitempty"`
+	After   *Entry `json:",omitempty"`
+	Hash    string
+}
+
+// AuditLog appends to the store's audit log, which the stores only ever add
+// to. Records are chained by hash, starting from the last one stored. Each
+// device keeps its own log, which isn't synced: changes synced from other
+// devices are added to it as they arrive, naming the device they came from.
+type AuditLog struct {
+	mu     sync.Mutex
+	store  Store
+	last   string
+	loaded bool
+}
+
+func NewAuditLog(store Store) *AuditLog {
+	return &AuditLog{store: store}
+}
+
+// Append records a change made on this device now. Failures are logged: the
+// change itself has already been made.
+func (a *AuditLog) Append(entryID, action string, before, after *Entry) {
+	_, device := auditAuthor()
+	a.AppendFrom(device, time.Now(), entryID, action, before, after)
+}
+
+// AppendFrom records a change made on another device at a given time, such
+// as one that arrived with a sync.
+func (a *AuditLog) AppendFrom(device string, at time.Time, entryID, action string, before, after *Entry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.loaded {
+		records, err := a.store.AuditLog()
+		if err != nil {
+			log.Printf("audit: %v", err)
+			return
+		}
+		if len(records) > 0 {
+			a.last = records[len(records)-1].Hash
+		}
+		a.loaded = true
+	}
+
+	name, _ := auditAuthor()
+	record := AuditRecord{
+		EntryID: entryID,
+		At:      at.UTC(),
+		User:    name,
+		Device:  device,
+		Action:  action,
+		Before:  cloneAuditEntry(before),
+		After:   cloneAuditEntry(after),
+	}
+	record.Hash = auditHash(a.last, record)
+	if err := a.store.AppendAudit(record); err != nil {
+		log.Printf("audit: %v", err)
+		return
+	}
+	a.last = record.Hash
+}
+
+// cloneAuditEntry copies an entry for the log, so later changes to the
+// entries don't reach it.
+func cloneAuditEntry(entry *Entry) *Entry {
+	if entry == nil {
+		return nil
+	}
+	clone := *entry
+	clone.Tags = slices.Clone(entry.Tags)
+	return &clone
+}
+
+// Records returns the whole log, oldest first.
+func (a *AuditLog) Records() ([]AuditRecord, error) {
+	return a.store.AuditLog()
+}
+
+// auditHash chains a record to the hash of the one before it.
+func auditHash(previous string, record AuditRecord) string {
+	record.Hash = ""
+	raw, _ := json.Marshal(record)
+	sum := sha256.Sum256(append([]byte(previous), raw...))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyAuditLog returns the index of the first record whose hash doesn't
+// match, or -1 if the log is intact.
+func verifyAuditLog(records []AuditRecord) int {
+	var previous string
+	for i, record := range records {
+		if auditHash(previous, record) != record.Hash {
+			return i
+		}
+		previous = record.Hash
+	}
+	return -1
+}
+
+// auditAuthor names who is making a change: the team user name if one is
+// set, otherwise the account signed in, and this device's host name.
+func auditAuthor() (name, device string) {
+	name = fyne.CurrentApp().Preferences().String(PrefTeamUserName)
+	if name == "" {
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+		}
+	}
+	device, _ = os.Hostname()
+	return name, device
+}
+
+// auditEntry records a change to an entry in the timer's audit log.
+func auditEntry(timer *TaskTimer, entryID, action string, before, after *Entry) {
+	if timer.audit != nil {
+		timer.audit.Append(entryID, action, before, after)
+	}
+}
+
+// auditSyncedEntry records a change synced from another device in the
+// timer's audit log.
+func auditSyncedEntry(timer *TaskTimer, device string, at time.Time, entryID, action string, before, after *Entry) {
+	if timer.audit != nil {
+		timer.audit.AppendFrom(device, at, entryID, action, before, after)
+	}
+}
+
+// auditChanges describes what a record changed, one line per field.
+func auditChanges(record AuditRecord) []string {
+	if record.Before == nil || record.After == nil {
+		entry := record.Before
+		if entry == nil {
+			entry = record.After
+		}
+		if entry == nil {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s – %s  %s  %s",
+			formatDayTime(entry.Start), entry.End.Format("15:04"), entry.Task, formatDuration(entry.Duration))}
+	}
+
+	before, after := record.Before, record.After
+	var changes []string
+	changed := func(field, from, to string) {
+		if from == to {
+			return
+		}
+		if from == "" {
+			from = "—"
+		}
+		if to == "" {
+			to = "—"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+	}
+	changed(tr("Task"), before.Task, after.Task)
+	changed(tr("Project"), before.Project, after.Project)
+	changed(tr("Client"), guestText(before.Client), guestText(after.Client))
+	changed(tr("Start"), formatDayTime(before.Start), formatDayTime(after.Start))
+	changed(tr("End"), formatDayTime(before.End), formatDayTime(after.End))
+	changed(tr("Duration"), formatDuration(before.Duration), formatDuration(after.Duration))
+	changed(tr("Adjustment"), formatDuration(before.Adjustment), formatDuration(after.Adjustment))
+	changed(tr("Notes"), before.Notes, after.Notes)
+	changed(tr("Tags"), strings.Join(before.Tags, ", "), strings.Join(after.Tags, ", "))
+	changed(tr("Billable"), yesNo(!before.NonBillable), yesNo(!after.NonBillable))
+	changed(tr("Stopped automatically"), yesNo(before.AutoStopped), yesNo(after.AutoStopped))
+	changed(tr("Needs review"), yesNo(before.Review != ""), yesNo(after.Review != ""))
+	return changes
+}
+
+func yesNo(b bool) string {
+	if b {
+		return tr("yes")
+	}
+	return tr("no")
+}
+
+func auditActionName(action string) string {
+	switch action {
+	case AuditEdited:
+		return tr("Edited")
+	case AuditDeleted:
+		return tr("Deleted")
+	case AuditSplit:
+		return tr("Split")
+	case AuditRestored:
+		return tr("Restored")
+	}
+	return action
+}
+
+// showAuditLog lists the changes to one entry, or to every entry if entryID
+// is empty, newest first, and says whether the log is intact.
+func showAuditLog(timer *TaskTimer, entryID string) {
+	records, err := timer.audit.Records()
+	if err != nil {
+		dialog.ShowError(err, timer.window)
+		return
+	}
+
+	status := widget.NewLabel(tr("The log is intact: no record has been changed or removed."))
+	if broken := verifyAuditLog(records); broken >= 0 {
+		status.SetText(tr("The log has been altered: records from {{.Time}} on don't match their hashes.", map[string]any{
+			"Time": formatDayTime(records[broken].At.Local()),
+		}))
+		status.Importance = widget.DangerImportance
+	}
+	status.Wrapping = fyne.TextWrapWord
+
+	var shown []AuditRecord
+	for _, record := range records {
+		if entryID == "" || record.EntryID == entryID {
+			shown = append(shown, record)
+		}
+	}
+	slices.Reverse(shown)
+
+	list := container.NewVBox()
+	if len(shown) == 0 {
+		list.Add(widget.NewLabel(tr("No changes recorded")))
+	}
+	for _, record := range shown {
+		heading := widget.NewLabelWithStyle(tr("{{.Action}} {{.Time}} by {{.User}} on {{.Device}}", map[string]any{
+			"Action": auditActionName(record.Action),
+			"Time":   formatDayTime(record.At.Local()),
+			"User":   record.User,
+			"Device": record.Device,
+		}), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		heading.Wrapping = fyne.TextWrapWord
+		list.Add(heading)
+		for _, change := range auditChanges(record) {
+			line := widget.NewLabel(change)
+			line.Wrapping = fyne.TextWrapWord
+			list.Add(line)
+		}
+	}
+
+	fileName := "audit-log.csv"
+	if entryID != "" {
+		fileName = "audit-log-" + entryID + ".csv"
+	}
+	exportBtn := widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+		saveExport(timer, fileName, func(w io.Writer) error {
+			return writeAuditCSV(w, shown)
+		})
+	})
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(480, 360))
+	d := dialog.NewCustom(tr("Audit Log"), tr("Close"), container.NewBorder(status, exportBtn, nil, nil, scroll), timer.window)
+	d.Show()
+}
+
+// writeAuditCSV writes records with the hashes that chain them in the log.
+func writeAuditCSV(w io.Writer, records []AuditRecord) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"entry", "time", "user", "device", "action", "before", "after", "hash"}); err != nil {
+		return err
+	}
+	for _, record := range records {
+		before, err := auditEntryJSON(record.Before)
+		if err != nil {
+			return err
+		}
+		after, err := auditEntryJSON(record.After)
+		if err != nil {
+			return err
+		}
+		if err := out.Write([]string{
+			record.EntryID,
+			record.At.Format(time.RFC3339Nano),
+			record.User,
+			record.Device,
+			record.Action,
+			before,
+			after,
+			record.Hash,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func auditEntryJSON(entry *Entry) (string, error) {
+	if entry == nil {
+		return "", nil
+	}
+	raw, err := json.Marshal(entry)
+	return string(raw), err
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+)
+
+const (
+	// MinimizedFlag opens the window minimized, as the autostart entry does
+	MinimizedFlag = "--minimized"
+
+	// AutostartName names the autostart entry on every platform, after the
+	// app's ID
+	AutostartName = "io.github.0jc1.gotime"
+)
+
+var (
+	errAutostartUnsupported = errors.New("starting on login isn't supported here")
+	errMinimizeUnsupported  = errors.New("minimizing the window isn't supported here")
+)
+
+// minimizeOnStart minimizes the window once the app is running, for a start
+// on login. Failing that, the window stays open.
+func minimizeOnStart(myApp fyne.App, w fyne.Window) {
+	myApp.Lifecycle().SetOnStarted(func() {
+		native, ok := w.(driver.NativeWindow)
+		if !ok {
+			return
+		}
+		native.RunNative(func(context any) {
+			if err := minimizeWindow(context); err != nil {
+				log.Printf("autostart: %v", err)
+			}
+		})
+	})
+}
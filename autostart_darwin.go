@@ -0,0 +1,96 @@
+//go:build darwin && !ios
+
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#import <AppKit/AppKit.h>
+
+static void miniaturize(uintptr_t window) {
+	[(NSWindow *)window miniaturize:nil];
+}
+*/
+import "C"
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+// autostartPath is the launch agent, which launchd loads on login.
+func autostartPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", AutostartName+".plist"), nil
+}
+
+func autostartInstalled() bool {
+	path, err := autostartPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// setAutostart writes or removes the launch agent, which starts this
+// executable minimized. It takes effect from the next login.
+func setAutostart(enabled bool) error {
+	path, err := autostartPath()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(exe))
+	plist := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + AutostartName + `</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>` + escaped.String() + `</string>
+		<string>` + MinimizedFlag + `</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>ProcessType</key>
+	<string>Interactive</string>
+</dict>
+</plist>
+`
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(plist), 0o644)
+}
+
+// minimizeWindow sends a window to the Dock.
+func minimizeWindow(context any) error {
+	mac, ok := context.(driver.MacWindowContext)
+	if !ok || mac.NSWindow == 0 {
+		return errMinimizeUnsupported
+	}
+	C.miniaturize(C.uintptr_t(mac.NSWindow))
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+// autostartPath is the XDG autostart entry, which desktop sessions run on
+// login.
+func autostartPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "autostart", AutostartName+".desktop"), nil
+}
+
+func autostartInstalled() bool {
+	path, err := autostartPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// setAutostart writes or removes the autostart entry, which starts this
+// executable minimized.
+func setAutostart(enabled bool) error {
+	path, err := autostartPath()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// The Exec key quotes arguments in double quotes, escaping these four
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(exe)
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=gotime
+Comment=Task timer
+Exec="%s" %s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`, quoted, MinimizedFlag)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(entry), 0o644)
+}
+
+// minimizeWindow iconifies an X11 window through xdotool, which has to be
+// installed.
+func minimizeWindow(context any) error {
+	x11, ok := context.(driver.X11WindowContext)
+	if !ok || x11.WindowHandle == 0 {
+		return errMinimizeUnsupported
+	}
+	window := fmt.Sprintf("%d", x11.WindowHandle)
+	if err := exec.Command("xdotool", "windowminimize", window).Run(); err != nil {
+		return fmt.Errorf("xdotool: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !windows && (!darwin || ios)
+
+package main
+
+// The platform starts apps itself, so there's no entry to install.
+func autostartInstalled() bool {
+	return false
+}
+
+func setAutostart(enabled bool) error {
+	return errAutostartUnsupported
+}
+
+func minimizeWindow(context any) error {
+	return errMinimizeUnsupported
+}
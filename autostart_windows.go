@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"fyne.io/fyne/v2/driver"
+	"golang.org/x/sys/windows/registry"
+)
+
+// autostartKey is the per-user Run key, whose commands Windows runs on
+// login.
+const autostartKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+var showWindow = syscall.NewLazyDLL("user32.dll").NewProc("ShowWindow")
+
+const swMinimize = 6
+
+func autostartInstalled() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	_, _, err = key.GetStringValue(AutostartName)
+	return err == nil
+}
+
+// setAutostart adds or removes the Run value, which starts this executable
+// minimized.
+func setAutostart(enabled bool) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	if !enabled {
+		if err := key.DeleteValue(AutostartName); err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return key.SetStringValue(AutostartName, `"`+exe+`" `+MinimizedFlag)
+}
+
+// minimizeWindow minimizes a window to the taskbar.
+func minimizeWindow(context any) error {
+	win, ok := context.(driver.WindowsWindowContext)
+	if !ok || win.HWND == 0 {
+		return errMinimizeUnsupported
+	}
+	showWindow.Call(win.HWND, swMinimize)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	// PrefAutoStop is when a forgotten timer is stopped: off when blank,
+	// AutoStopSchedule, or a time of day such as "23:59"
+	PrefAutoStop = "autoStop"
+
+	// AutoStopSchedule stops the timer at the end of the day's last work
+	// block
+	AutoStopSchedule = "schedule"
+)
+
+// autoStopChoices are the stop times offered in the settings, after the end
+// of the work schedule.
+func autoStopChoices() []string {
+	var choices []string
+	for hour := 17; hour < 24; hour++ {
+		choices = append(choices, fmt.Sprintf("%02d:00", hour))
+	}
+	return append(choices, "23:59")
+}
+
+// autoStopTime returns when today's sessions are stopped, if they are.
+func autoStopTime(now time.Time) (time.Time, bool) {
+	today := dayStart(now)
+	setting := fyne.CurrentApp().Preferences().String(PrefAutoStop)
+	switch setting {
+	case "":
+		return time.Time{}, false
+	case AutoStopSchedule:
+		blocks, err := parseWorkSchedule(fyne.CurrentApp().Preferences().StringList(PrefWorkSchedule))
+		if err != nil {
+			return time.Time{}, false
+		}
+		var end time.Duration
+		for _, block := range blocks {
+			if block.Days[today.Weekday()] {
+				end = max(end, block.End)
+			}
+		}
+		if end == 0 {
+			return time.Time{}, false
+		}
+		return time.Date(today.Year(), today.Month(), today.Day(), 0, int(end.Minutes()), 0, 0, today.Location()), true
+	}
+	clock, err := time.Parse("15:04", setting)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(today.Year(), today.Month(), today.Day(), clock.Hour(), clock.Minute(), 0, 0, today.Location()), true
+}
+
+// watchAutoStop stops a session still running at the stop time, recording
+// it flagged for review. Sessions started after the stop time, such as an
+// evening's overtime, are left running.
+func watchAutoStop(timer *TaskTimer) {
+	go func() {
+		for now := range time.Tick(ScheduleCheckInterval) {
+			fyne.Do(func() {
+				checkAutoStop(timer, now)
+			})
+		}
+	}()
+}
+
+func checkAutoStop(timer *TaskTimer, now time.Time) {
+	if !timer.isRunning {
+		return
+	}
+	stopAt, ok := autoStopTime(now)
+	if !ok || now.Before(stopAt) || !timer.sessionStart.Before(stopAt) {
+		return
+	}
+
+	taskName := timer.taskName
+	timer.autoStopped = true
+	stopSession(timer)
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(tr("Timer Stopped"),
+		tr("{{.Task}} was still running at {{.Time}}, so it was stopped. Check the entry in History.", map[string]any{
+			"Task": taskName,
+			"Time": stopAt.Format("15:04"),
+		})))
+}
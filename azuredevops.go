@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const (
+	PrefAzureDevOpsEnabled = "azureDevOpsEnabled"
+	PrefAzureDevOpsURL     = "azureDevOpsURL"
+	// PrefAzureDevOpsToken held the token before it moved to the keychain
+	PrefAzureDevOpsToken = "azureDevOpsToken"
+
+	azureDevOpsAPIVersion   = "7.0"
+	azureCompletedWorkField = "Microsoft.VSTS.Scheduling.CompletedWork"
+	azureRemainingWorkField = "Microsoft.VSTS.Scheduling.RemainingWork"
+)
+
+// Work items are mentioned as AB#123, as in commit messages
+var azureWorkItemPattern = regexp.MustCompile(`\bAB#(\d+)\b`)
+
+// azureWorkItemID returns the work item named in a task, if any.
+func azureWorkItemID(taskName string) string {
+	if m := azureWorkItemPattern.FindStringSubmatch(taskName); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pushAzureDevOpsWork adds a recorded entry to the completed work of its
+// Azure DevOps work item in the background, if the integration is enabled and
+// the task names a work item.
+func pushAzureDevOpsWork(timer *TaskTimer, entry Entry) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefAzureDevOpsEnabled) {
+		return
+	}
+	id := azureWorkItemID(entry.Task)
+	if id == "" {
+		return
+	}
+
+	orgURL := strings.TrimRight(prefs.String(PrefAzureDevOpsURL), "/")
+	token := keychainSecret(KeyringAzureDevOpsToken)
+
+	go func() {
+		err := postAzureDevOpsWork(orgURL, token, id, entry)
+		if err != nil {
+			log.Print(err)
+			fyne.Do(func() {
+				dialog.ShowError(err, timer.window)
+			})
+		}
+	}()
+}
+
+// azureWorkItem is a work item's revision and numeric fields.
+type azureWorkItem struct {
+	Rev    int
+	Fields map[string]float64
+}
+
+type azurePatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// postAzureDevOpsWork adds the entry's hours to the work item's completed
+// work and takes them off its remaining work. Work items have no worklog, so
+// the fields are read and written back, guarded by the revision so a
+// concurrent edit fails rather than being overwritten.
+func postAzureDevOpsWork(orgURL, token, id string, entry Entry) error {
+	endpoint := fmt.Sprintf("%s/_apis/wit/workitems/%s?api-version=%s", orgURL, id, azureDevOpsAPIVersion)
+
+	item, err := getAzureWorkItem(endpoint, token)
+	if err != nil {
+		return fmt.Errorf("azure devops: reading work item %s: %w", id, err)
+	}
+
+	hours := entry.Duration.Hours()
+	ops := []azurePatchOp{
+		{Op: "test", Path: "/rev", Value: item.Rev},
+		{Op: "add", Path: "/fields/" + azureCompletedWorkField, Value: item.Fields[azureCompletedWorkField] + hours},
+	}
+	if remaining, ok := item.Fields[azureRemainingWorkField]; ok {
+		ops = append(ops, azurePatchOp{Op: "add", Path: "/fields/" + azureRemainingWorkField, Value: max(0, remaining-hours)})
+	}
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("", token)
+	req.Header.Set("Content-Type", "application/json-patch+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure devops: logging work on %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("azure devops: logging work on %s: %s", id, resp.Status)
+	}
+	return nil
+}
+
+// getAzureWorkItem reads a work item.
+func getAzureWorkItem(endpoint, token string) (azureWorkItem, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return azureWorkItem{}, err
+	}
+	req.SetBasicAuth("", token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return azureWorkItem{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return azureWorkItem{}, fmt.Errorf("%s", resp.Status)
+	}
+
+	var raw struct {
+		Rev    int            `json:"rev"`
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return azureWorkItem{}, err
+	}
+	item := azureWorkItem{Rev: raw.Rev, Fields: make(map[string]float64)}
+	for name, value := range raw.Fields {
+		if number, ok := value.(float64); ok {
+			item.Fields[name] = number
+		}
+	}
+	return item, nil
+}
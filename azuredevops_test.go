@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestAzureWorkItemID(t *testing.T) {
+	tests := []struct{ task, want string }{
+		{"AB#123 Fix login", "123"},
+		{"Fix login (AB#45)", "45"},
+		{"Fix login", ""},
+		{"#123 Fix login", ""},
+		{"TAB#123", ""},
+	}
+	for _, tc := range tests {
+		if got := azureWorkItemID(tc.task); got != tc.want {
+			t.Errorf("azureWorkItemID(%q) = %q, want %q", tc.task, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PomodoroLength is the stretch of focused work that counts as a Pomodoro.
+const PomodoroLength = 25 * time.Minute
+
+// targetStreak counts the consecutive days up to today that reached the
+// daily target, zero if none is set. Like trackingStreak, it isn't broken
+// by today not having reached the target yet, or by days off.
+func targetStreak(timer *TaskTimer, now time.Time) int {
+	target := dayTarget()
+	if target <= 0 {
+		return 0
+	}
+	today := dayStart(now)
+	days := dailyTotal(timer, addDays(today, -StreakLookback), addDays(today, 1))
+	return streak(today, loadTimeOff(), func(day time.Time) bool {
+		return days[day] >= target
+	})
+}
+
+// longestSession returns the longest entry that was never paused.
+func longestSession(entries []Entry) time.Duration {
+	var longest time.Duration
+	for _, entry := range entries {
+		if entry.End.Sub(entry.Start)-entry.Duration+entry.Adjustment >= time.Minute {
+			continue
+		}
+		longest = max(longest, entry.Duration)
+	}
+	return longest
+}
+
+// pomodoros counts the whole Pomodoros in entries, each session on its own,
+// so two short sessions don't add up to one.
+func pomodoros(entries []Entry) int {
+	count := 0
+	for _, entry := range entries {
+		count += int(entry.Duration / PomodoroLength)
+	}
+	return count
+}
+
+// createBadgesContainer shows the streaks, the longest session and the
+// Pomodoros done as a row of badges, leaving out any not yet earned.
+func createBadgesContainer(timer *TaskTimer) fyne.CanvasObject {
+	badges := container.NewHBox()
+	scroll := container.NewHScroll(badges)
+
+	render := func() {
+		now := time.Now()
+		history, _ := timer.history.Get()
+		today := dayStart(now)
+		week := weekStart(now)
+
+		var texts []string
+		if streak := trackingStreak(timer, now); streak >= 2 {
+			texts = append(texts, tr("🔥 {{.Days}}-day streak", map[string]any{"Days": streak}))
+		}
+		if streak := targetStreak(timer, now); streak >= 1 {
+			texts = append(texts, tr("🎯 {{.Days}} days on target", map[string]any{"Days": streak}))
+		}
+		if longest := longestSession(history); longest >= PomodoroLength {
+			texts = append(texts, tr("⏱ Longest session {{.Duration}}", map[string]any{
+				"Duration": formatShortDuration(longest.Truncate(time.Minute)),
+			}))
+		}
+		todayCount := pomodoros(EntryFilter{From: today, To: addDays(today, 1)}.Apply(history))
+		weekCount := pomodoros(EntryFilter{From: week, To: addDays(week, 7)}.Apply(history))
+		if weekCount > 0 {
+			texts = append(texts, tr("🍅 {{.Today}} today · {{.Week}} this week", map[string]any{
+				"Today": todayCount,
+				"Week":  weekCount,
+			}))
+		}
+
+		badges.RemoveAll()
+		for _, text := range texts {
+			badge := widget.NewLabel(text)
+			badge.Importance = widget.HighImportance
+			badges.Add(badge)
+		}
+		if len(texts) == 0 {
+			scroll.Hide()
+		} else {
+			scroll.Show()
+		}
+	}
+	listenWhileShown(timer, timer.history, render)
+
+	return scroll
+}
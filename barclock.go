@@ -0,0 +1,62 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+)
+
+// PrefBarClock shows the session's time in the macOS menu bar and on the
+// Windows taskbar button; it's on unless turned off
+const PrefBarClock = "barClock"
+
+// BarClock is what the menu bar or taskbar shows of the timer.
+type BarClock struct {
+	// Shown is false while no session is open, or with the setting off
+	Shown   bool
+	Elapsed time.Duration
+	Text    string
+	Task    string
+	Running bool
+	// Progress is today's share of the daily target, or negative without
+	// a target
+	Progress float64
+}
+
+// watchBarClock keeps the menu bar or taskbar clock in step with the timer,
+// on every tick and whenever it starts or stops.
+func watchBarClock(timer *TaskTimer) {
+	listener := binding.NewDataListener(func() {
+		updateBarClock(timer)
+	})
+	timer.elapsed.AddListener(listener)
+	timer.running.AddListener(listener)
+	timer.sessionOpen.AddListener(listener)
+}
+
+// updateBarClock shows the timer's current state outside the window.
+func updateBarClock(timer *TaskTimer) {
+	clock := BarClock{Progress: -1}
+	if fyne.CurrentApp().Preferences().BoolWithFallback(PrefBarClock, true) && timer.elapsedTime > 0 {
+		text, _ := timer.elapsed.Get()
+		clock = BarClock{
+			Shown:    true,
+			Elapsed:  timer.elapsedTime,
+			Text:     text,
+			Running:  timer.isRunning,
+			Progress: -1,
+		}
+		if timer.taskName != NoTaskSelected {
+			clock.Task = timer.taskName
+		}
+		if target := dayTarget(); target > 0 {
+			var today time.Duration
+			for _, duration := range daySummaryTotals(timer, time.Now()) {
+				today += duration
+			}
+			clock.Progress = min(float64(today)/float64(target), 1)
+		}
+	}
+	setBarClock(timer.window, clock)
+}
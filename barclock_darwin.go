@@ -0,0 +1,62 @@
+//go:build darwin && !ios
+
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#include <stdlib.h>
+#import <AppKit/AppKit.h>
+
+static NSStatusItem *clockItem;
+
+// setClockItem shows title in a menu bar item of its own, or removes the
+// item for a NULL title. AppKit is only touched on the main thread.
+static void setClockItem(const char *title, const char *tooltip) {
+	@autoreleasepool {
+		NSString *text = title ? [NSString stringWithUTF8String:title] : nil;
+		NSString *tip = tooltip ? [NSString stringWithUTF8String:tooltip] : nil;
+		dispatch_async(dispatch_get_main_queue(), ^{
+			if (text == nil) {
+				if (clockItem != nil) {
+					[[NSStatusBar systemStatusBar] removeStatusItem:clockItem];
+					[clockItem release];
+					clockItem = nil;
+				}
+				return;
+			}
+			if (clockItem == nil) {
+				clockItem = [[[NSStatusBar systemStatusBar] statusItemWithLength:NSVariableStatusItemLength] retain];
+				clockItem.button.font = [NSFont monospacedDigitSystemFontOfSize:0 weight:NSFontWeightRegular];
+			}
+			clockItem.button.title = text;
+			clockItem.button.toolTip = tip;
+		});
+	}
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"fyne.io/fyne/v2"
+)
+
+// setBarClock shows the session's time as a menu bar item, with the task
+// as its tooltip. A paused session shows a pause sign before the time.
+func setBarClock(w fyne.Window, clock BarClock) {
+	if !clock.Shown {
+		C.setClockItem(nil, nil)
+		return
+	}
+	text := clock.Text
+	if !clock.Running {
+		text = "⏸ " + text
+	}
+	title := C.CString(text)
+	defer C.free(unsafe.Pointer(title))
+	tooltip := C.CString(clock.Task)
+	defer C.free(unsafe.Pointer(tooltip))
+	C.setClockItem(title, tooltip)
+}
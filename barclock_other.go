@@ -0,0 +1,9 @@
+//go:build !windows && (!darwin || ios)
+
+package main
+
+import "fyne.io/fyne/v2"
+
+// setBarClock has nowhere to show the clock on this platform; on Linux,
+// status bars can follow the timer over D-Bus instead.
+func setBarClock(w fyne.Window, clock BarClock) {}
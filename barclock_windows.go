@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"syscall"
+	"unsafe"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	coInitializeEx     = syscall.NewLazyDLL("ole32.dll").NewProc("CoInitializeEx")
+	coCreateInstance   = syscall.NewLazyDLL("ole32.dll").NewProc("CoCreateInstance")
+	createDIBSection   = syscall.NewLazyDLL("gdi32.dll").NewProc("CreateDIBSection")
+	createBitmap       = syscall.NewLazyDLL("gdi32.dll").NewProc("CreateBitmap")
+	deleteObject       = syscall.NewLazyDLL("gdi32.dll").NewProc("DeleteObject")
+	createIconIndirect = syscall.NewLazyDLL("user32.dll").NewProc("CreateIconIndirect")
+	destroyIcon        = syscall.NewLazyDLL("user32.dll").NewProc("DestroyIcon")
+
+	clsidTaskbarList = syscall.GUID{Data1: 0x56FDF344, Data2: 0xFD6D, Data3: 0x11D0, Data4: [8]byte{0x95, 0x8A, 0x00, 0x60, 0x97, 0xC9, 0xA0, 0x90}}
+	iidTaskbarList3  = syscall.GUID{Data1: 0xEA1AFB91, Data2: 0x9E28, Data3: 0x4B86, Data4: [8]byte{0x90, 0xE9, 0x9E, 0x9F, 0x8A, 0x5E, 0xEF, 0xAF}}
+)
+
+// taskbarList is the ITaskbarList3 the clock is shown through, and
+// taskbarOverlayMinute what the overlay last showed. Both are only used on
+// the main thread.
+var (
+	taskbarList          *comObject
+	taskbarOverlayMinute = -1
+)
+
+// comObject is the start of any COM object: a pointer to its methods.
+type comObject struct {
+	vtable *[32]uintptr
+}
+
+const (
+	coinitApartmentThreaded = 0x2
+	clsctxInprocServer      = 0x1
+
+	// ITaskbarList3 methods, by their place in its vtable
+	taskbarHrInit           = 3
+	taskbarSetProgressValue = 9
+	taskbarSetProgressState = 10
+	taskbarSetOverlayIcon   = 18
+
+	tbpfNoProgress = 0x0
+	tbpfNormal     = 0x2
+	tbpfPaused     = 0x8
+
+	// taskbarProgressSteps is the resolution the progress is given in
+	taskbarProgressSteps = 1000
+
+	// overlaySize is the side of the overlay icon, which Windows draws
+	// over the corner of the taskbar button
+	overlaySize = 16
+)
+
+// setBarClock shows the session's minutes as an overlay on the taskbar
+// button, red while running and grey while paused, and today's progress
+// toward the daily target as the button's progress bar.
+func setBarClock(w fyne.Window, clock BarClock) {
+	native, ok := w.(driver.NativeWindow)
+	if !ok {
+		return
+	}
+	native.RunNative(func(context any) {
+		win, ok := context.(driver.WindowsWindowContext)
+		if !ok || win.HWND == 0 {
+			return
+		}
+		if err := showTaskbarClock(win.HWND, clock); err != nil {
+			log.Printf("taskbar: %v", err)
+		}
+	})
+}
+
+func showTaskbarClock(hwnd uintptr, clock BarClock) error {
+	if taskbarList == nil {
+		coInitializeEx.Call(0, coinitApartmentThreaded)
+		if hr, _, _ := coCreateInstance.Call(
+			uintptr(unsafe.Pointer(&clsidTaskbarList)), 0, clsctxInprocServer,
+			uintptr(unsafe.Pointer(&iidTaskbarList3)), uintptr(unsafe.Pointer(&taskbarList)),
+		); hr != 0 {
+			taskbarList = nil
+			return fmt.Errorf("creating the taskbar list: 0x%08X", uint32(hr))
+		}
+		taskbarCall(taskbarHrInit)
+	}
+
+	state := uintptr(tbpfNoProgress)
+	if clock.Shown && clock.Progress >= 0 {
+		state = tbpfNormal
+		if !clock.Running {
+			state = tbpfPaused
+		}
+		args := []uintptr{hwnd}
+		args = append(args, ulonglong(uint64(clock.Progress*taskbarProgressSteps))...)
+		args = append(args, ulonglong(taskbarProgressSteps)...)
+		taskbarCall(taskbarSetProgressValue, args...)
+	}
+	taskbarCall(taskbarSetProgressState, hwnd, state)
+
+	// The overlay only changes with the minute, or between running and
+	// paused, which the sign of the cached minute stands for
+	minute := -1
+	if clock.Shown {
+		minute = int(clock.Elapsed.Minutes())
+		if !clock.Running {
+			minute = -2 - minute
+		}
+	}
+	if minute == taskbarOverlayMinute {
+		return nil
+	}
+	taskbarOverlayMinute = minute
+
+	if !clock.Shown {
+		taskbarCall(taskbarSetOverlayIcon, hwnd, 0, 0)
+		return nil
+	}
+	icon, err := overlayIcon(overlayText(clock), clock.Running)
+	if err != nil {
+		return err
+	}
+	// The taskbar keeps its own copy of the icon
+	defer destroyIcon.Call(icon)
+	description, _ := syscall.UTF16PtrFromString(clock.Text)
+	taskbarCall(taskbarSetOverlayIcon, hwnd, icon, uintptr(unsafe.Pointer(description)))
+	return nil
+}
+
+// taskbarCall calls an ITaskbarList3 method through its vtable.
+func taskbarCall(method int, args ...uintptr) uintptr {
+	this := uintptr(unsafe.Pointer(taskbarList))
+	hr, _, _ := syscall.SyscallN(taskbarList.vtable[method], append([]uintptr{this}, args...)...)
+	return hr
+}
+
+// ulonglong passes a 64-bit argument, which takes two words on 32-bit
+// Windows.
+func ulonglong(v uint64) []uintptr {
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		return []uintptr{uintptr(v)}
+	}
+	return []uintptr{uintptr(v), uintptr(v >> 32)}
+}
+
+// overlayText fits the session's time in two characters: its minutes in
+// the first hour, then its hours.
+func overlayText(clock BarClock) string {
+	if clock.Elapsed.Hours() < 1 {
+		return fmt.Sprint(int(clock.Elapsed.Minutes()))
+	}
+	if clock.Elapsed.Hours() < 10 {
+		return fmt.Sprintf("%dh", int(clock.Elapsed.Hours()))
+	}
+	return fmt.Sprint(int(clock.Elapsed.Hours()))
+}
+
+// overlayIcon draws text on a coloured disc and makes an icon of it.
+func overlayIcon(text string, running bool) (uintptr, error) {
+	img := image.NewRGBA(image.Rect(0, 0, overlaySize, overlaySize))
+	disc := color.RGBA{R: 0xC4, G: 0x2B, B: 0x1C, A: 0xFF}
+	if !running {
+		disc = color.RGBA{R: 0x70, G: 0x70, B: 0x70, A: 0xFF}
+	}
+	// Distances are doubled, to measure from the middle of each pixel
+	for y := range overlaySize {
+		for x := range overlaySize {
+			dx, dy := 2*x+1-overlaySize, 2*y+1-overlaySize
+			if dx*dx+dy*dy <= overlaySize*overlaySize {
+				img.SetRGBA(x, y, disc)
+			}
+		}
+	}
+	drawer := font.Drawer{Dst: img, Src: image.White, Face: basicfont.Face7x13}
+	width := drawer.MeasureString(text).Ceil()
+	drawer.Dot = fixed.P((overlaySize-width)/2, 13)
+	drawer.DrawString(text)
+
+	// A 32-bit top-down DIB in BGRA order, with its own alpha, so the mask
+	// is left empty
+	header := struct {
+		Size, Width, Height  int32
+		Planes, BitCount     uint16
+		Compression, ImgSize uint32
+		XPels, YPels         int32
+		ClrUsed, ClrImp      uint32
+	}{Size: 40, Width: overlaySize, Height: -overlaySize, Planes: 1, BitCount: 32}
+	var bits unsafe.Pointer
+	bitmap, _, _ := createDIBSection.Call(0, uintptr(unsafe.Pointer(&header)), 0, uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if bitmap == 0 {
+		return 0, errors.New("creating the overlay bitmap failed")
+	}
+	defer deleteObject.Call(bitmap)
+	pixels := unsafe.Slice((*byte)(bits), overlaySize*overlaySize*4)
+	for i := 0; i < len(pixels); i += 4 {
+		pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = img.Pix[i+2], img.Pix[i+1], img.Pix[i], img.Pix[i+3]
+	}
+	mask, _, _ := createBitmap.Call(overlaySize, overlaySize, 1, 1, 0)
+	defer deleteObject.Call(mask)
+
+	info := struct {
+		Icon               int32
+		XHotspot, YHotspot uint32
+		Mask, Color        uintptr
+	}{Icon: 1, Mask: mask, Color: bitmap}
+	icon, _, err := createIconIndirect.Call(uintptr(unsafe.Pointer(&info)))
+	if icon == 0 {
+		return 0, fmt.Errorf("creating the overlay icon: %w", err)
+	}
+	return icon, nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PrefInternalTasks lists the tasks whose new entries are internal rather
+// than billable. Each entry can still be marked either way.
+const PrefInternalTasks = "internalTasks"
+
+// BillableReportPeriods is how many weeks, months or quarters the billable
+// report goes back, the current one included.
+const BillableReportPeriods = 12
+
+// isInternalTask reports whether a task's entries are internal by default.
+func isInternalTask(taskName string) bool {
+	return slices.Contains(fyne.CurrentApp().Preferences().StringList(PrefInternalTasks), taskName)
+}
+
+// setTaskInternal sets whether a task's new entries are internal.
+func setTaskInternal(taskName string, internal bool) {
+	prefs := fyne.CurrentApp().Preferences()
+	tasks := slices.DeleteFunc(prefs.StringList(PrefInternalTasks), func(t string) bool { return t == taskName })
+	if internal {
+		tasks = append(tasks, taskName)
+	}
+	prefs.SetStringList(PrefInternalTasks, tasks)
+}
+
+// renameTaskInternal carries the default along with a renamed task. A task
+// merged into an existing one takes the target's.
+func renameTaskInternal(timer *TaskTimer, from, to string) {
+	internal := isInternalTask(from)
+	setTaskInternal(from, false)
+	if internal && !slices.Contains(timer.tasks.Tasks(), to) {
+		setTaskInternal(to, true)
+	}
+}
+
+// billableSplit adds up the billable and the internal time of entries.
+func billableSplit(entries []Entry) (billable, internal time.Duration) {
+	for _, entry := range entries {
+		if entry.NonBillable {
+			internal += entry.Duration
+		} else {
+			billable += entry.Duration
+		}
+	}
+	return billable, internal
+}
+
+// billablePercent is the billable share of the time, rounded.
+func billablePercent(billable, internal time.Duration) int {
+	if billable+internal <= 0 {
+		return 0
+	}
+	return int((billable*100 + (billable+internal)/2) / (billable + internal))
+}
+
+// A billableGrouping is how the billable report divides time.
+type billableGrouping struct {
+	label string
+	start func(t time.Time) time.Time
+	next  func(start time.Time) time.Time
+	title func(start time.Time) string
+}
+
+var billableGroupings = []billableGrouping{
+	{
+		label: "By week",
+		start: weekStart,
+		next:  func(start time.Time) time.Time { return addDays(start, 7) },
+		title: weekTitle,
+	},
+	{
+		label: "By month",
+		start: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		},
+		next: func(start time.Time) time.Time { return start.AddDate(0, 1, 0) },
+		title: func(start time.Time) string {
+			return monthName(start.Month()) + " " + strconv.Itoa(start.Year())
+		},
+	},
+	{
+		label: "By quarter",
+		start: fiscalQuarterStart,
+		next:  func(start time.Time) time.Time { return start.AddDate(0, 3, 0) },
+		title: func(start time.Time) string {
+			// A fiscal year not starting in January is named for both years
+			year := fiscalYearStart(start)
+			months := (start.Year()-year.Year())*12 + int(start.Month()) - int(year.Month())
+			name := strconv.Itoa(year.Year())
+			if year.Month() != time.January {
+				name += fmt.Sprintf("/%02d", (year.Year()+1)%100)
+			}
+			return fmt.Sprintf("Q%d %s", months/3+1, name)
+		},
+	},
+}
+
+// billablePeriod is a row of the billable report.
+type billablePeriod struct {
+	Title    string
+	Start    time.Time
+	Billable time.Duration
+	Internal time.Duration
+}
+
+// billableReport splits entries into the count periods of grouping up to
+// the one holding now, oldest first. Entries waiting for review are left out.
+func billableReport(entries []Entry, grouping billableGrouping, now time.Time, count int) []billablePeriod {
+	starts := []time.Time{grouping.start(now)}
+	for len(starts) < count {
+		// A period's start is found from a moment just before the next one's
+		starts = append([]time.Time{grouping.start(starts[0].Add(-time.Nanosecond))}, starts...)
+	}
+
+	periods := make([]billablePeriod, len(starts))
+	for i, start := range starts {
+		periods[i] = billablePeriod{Title: grouping.title(start), Start: start}
+	}
+	end := grouping.next(starts[len(starts)-1])
+	for _, entry := range reviewedEntries(entries) {
+		if entry.Start.Before(starts[0]) || !entry.Start.Before(end) {
+			continue
+		}
+		i, found := slices.BinarySearchFunc(starts, entry.Start, func(start, t time.Time) int {
+			return start.Compare(t)
+		})
+		if !found {
+			i--
+		}
+		if entry.NonBillable {
+			periods[i].Internal += entry.Duration
+		} else {
+			periods[i].Billable += entry.Duration
+		}
+	}
+	return periods
+}
+
+// showBillableReport shows billable against internal time for each recent
+// week, month or quarter.
+func showBillableReport(timer *TaskTimer) {
+	rows := container.NewVBox()
+	var periods []billablePeriod
+
+	var labels []string
+	for _, grouping := range billableGroupings {
+		labels = append(labels, tr(grouping.label))
+	}
+	groupingSelect := widget.NewSelect(labels, nil)
+	groupingSelect.OnChanged = func(string) {
+		grouping := billableGroupings[max(groupingSelect.SelectedIndex(), 0)]
+		periods = billableReport(allEntries(timer), grouping, time.Now(), BillableReportPeriods)
+
+		rows.RemoveAll()
+		rows.Add(container.NewGridWithColumns(4,
+			widget.NewLabelWithStyle(tr("Period"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabelWithStyle(tr("Billable"), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+			widget.NewLabelWithStyle(tr("Internal"), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(""),
+		))
+		for _, period := range slices.Backward(periods) {
+			share := widget.NewProgressBar()
+			share.SetValue(float64(billablePercent(period.Billable, period.Internal)) / 100)
+			share.TextFormatter = func() string {
+				if period.Billable+period.Internal == 0 {
+					return "—"
+				}
+				return tr("{{.Percent}}% billable", map[string]any{"Percent": billablePercent(period.Billable, period.Internal)})
+			}
+			rows.Add(container.NewGridWithColumns(4,
+				widget.NewLabel(period.Title),
+				widget.NewLabelWithStyle(formatDuration(period.Billable), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatDuration(period.Internal), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				share,
+			))
+		}
+	}
+	groupingSelect.SetSelectedIndex(1)
+
+	exportBtn := widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+		saveExport(timer, "billable.csv", func(w io.Writer) error {
+			return writeBillableCSV(w, periods, currentExportLocale())
+		})
+	})
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(560, 360))
+	content := container.NewBorder(groupingSelect, exportBtn, nil, nil, scroll)
+	dialog.NewCustom(tr("Billable vs. Internal"), tr("Close"), content, timer.window).Show()
+}
+
+// writeBillableCSV writes the report with hours as decimals, formatted for
+// the export locale.
+func writeBillableCSV(w io.Writer, periods []billablePeriod, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+	if err := out.Write([]string{"period", "start", "billable_hours", "internal_hours", "billable_percent"}); err != nil {
+		return err
+	}
+	for _, period := range periods {
+		if err := out.Write([]string{
+			period.Title,
+			period.Start.Format("2006-01-02"),
+			locale.FormatDecimal(period.Billable.Hours()),
+			locale.FormatDecimal(period.Internal.Hours()),
+			strconv.Itoa(billablePercent(period.Billable, period.Internal)),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
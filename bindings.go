@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Bound timer state. Views listen to these rather than being updated one by
+// one, so a new view only has to bind to what it shows:
+//
+//   - elapsed is the session time as shown, e.g. "00:42:10"
+//   - taskTitle is the selected task, or the translated placeholder
+//   - running is whether the timer is counting
+//   - sessionOpen is whether a session is under way, running or paused
+//   - history is a snapshot of every entry, replaced after each change
+//   - templates are the task templates, as saved
+//   - estimates are the task estimates, as saved
+//   - styles are the task colors and icons, as saved
+//
+// List bindings only notify their own listeners when the length changes, so
+// history is a single item holding the whole slice.
+func newTimerBindings(timer *TaskTimer) {
+	timer.elapsed = binding.NewString()
+	timer.elapsed.Set(formatDuration(0))
+	timer.taskTitle = binding.NewString()
+	timer.taskTitle.Set(tr(NoTaskSelected))
+	timer.running = binding.NewBool()
+	timer.sessionOpen = binding.NewBool()
+	timer.history = binding.NewItem(func(a, b []Entry) bool { return false })
+	timer.templates = binding.NewItem(func(a, b []TaskTemplate) bool { return false })
+	timer.templates.Set(loadTaskTemplates())
+	timer.estimates = binding.NewItem(func(a, b TaskEstimates) bool { return false })
+	timer.estimates.Set(loadTaskEstimates())
+	timer.styles = binding.NewItem(func(a, b TaskStyles) bool { return false })
+	timer.styles.Set(loadTaskStyles())
+}
+
+// setElapsed sets the session time and what the bound displays show.
+func setElapsed(timer *TaskTimer, elapsed time.Duration) {
+	timer.elapsedTime = elapsed
+	timer.elapsed.Set(formatDuration(elapsed))
+	timer.sessionOpen.Set(elapsed > 0)
+}
+
+// publishHistoryLocked hands bound views a fresh copy of the entries. The
+// caller holds taskListMutex.
+func publishHistoryLocked(timer *TaskTimer) {
+	timer.history.Set(slices.Clone(timer.entries))
+}
+
+// bindText keeps a canvas text, which has no binding of its own, showing
+// data. It returns the listener for unbinding.
+func bindText(text *canvas.Text, data binding.String) binding.DataListener {
+	listener := binding.NewDataListener(func() {
+		text.Text, _ = data.Get()
+		text.Refresh()
+	})
+	data.AddListener(listener)
+	return listener
+}
+
+// bindToggleButton labels a button as Pause while running, Resume while a
+// session is paused and Start otherwise. It returns the listener for
+// unbinding from both.
+func bindToggleButton(button *widget.Button, running, sessionOpen binding.Bool) binding.DataListener {
+	listener := binding.NewDataListener(func() {
+		on, _ := running.Get()
+		open, _ := sessionOpen.Get()
+		switch {
+		case on:
+			button.SetText(tr("⏸ Pause"))
+		case open:
+			button.SetText(tr("▶ Resume"))
+		default:
+			button.SetText(tr("▶ Start"))
+		}
+	})
+	running.AddListener(listener)
+	sessionOpen.AddListener(listener)
+	return listener
+}
+
+// listenWhileShown calls fn whenever data changes, starting straight away,
+// until the current view is swapped for another.
+func listenWhileShown(timer *TaskTimer, data binding.DataItem, fn func()) {
+	listener := binding.NewDataListener(fn)
+	data.AddListener(listener)
+	timer.viewListeners = append(timer.viewListeners, func() {
+		data.RemoveListener(listener)
+	})
+}
+
+// observeTasksWhileShown calls fn whenever the task list changes, until the
+// current view is swapped for another.
+func observeTasksWhileShown(timer *TaskTimer, fn func()) {
+	timer.viewListeners = append(timer.viewListeners, timer.tasks.AddObserver(fn))
+}
+
+// unbindView detaches the listeners of the view being swapped out, so hidden
+// views stop receiving updates.
+func unbindView(timer *TaskTimer) {
+	for _, remove := range timer.viewListeners {
+		remove()
+	}
+	timer.viewListeners = nil
+}
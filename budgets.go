@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefBudgets = "budgets"
+
+	BudgetTask    = "task"
+	BudgetProject = "project"
+
+	// BudgetCheckInterval is how often a running session is checked
+	// against the budgets
+	BudgetCheckInterval = time.Minute
+)
+
+// BudgetAlertPercents are the shares of a budget at which an alert is
+// raised, in increasing order.
+var BudgetAlertPercents = []int{80, 100}
+
+// Budget caps the total time tracked on a task or a project. Alerted is
+// the highest of BudgetAlertPercents already alerted on, so each alert is
+// raised once, even across restarts. Deadline, if set, is the last day the
+// work is due, for the capacity plan.
+type Budget struct {
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name"`
+	Limit    time.Duration `json:"limit"`
+	Alerted  int           `json:"alerted,omitempty"`
+	Deadline time.Time     `json:"deadline,omitzero"`
+}
+
+// Covers reports whether an entry counts towards the budget.
+func (b Budget) Covers(entry Entry) bool {
+	if b.Kind == BudgetProject {
+		return entry.Project == b.Name
+	}
+	return entry.Task == b.Name
+}
+
+// Percent is the share of the budget used, which may be over 100.
+func (b Budget) Percent(used time.Duration) int {
+	if b.Limit <= 0 {
+		return 0
+	}
+	return int(100 * used / b.Limit)
+}
+
+func loadBudgets() []Budget {
+	var budgets []Budget
+	raw := fyne.CurrentApp().Preferences().String(PrefBudgets)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &budgets); err != nil {
+			log.Printf("budgets: reading budgets: %v", err)
+		}
+	}
+	return budgets
+}
+
+func setBudgets(budgets []Budget) {
+	raw, err := json.Marshal(budgets)
+	if err != nil {
+		log.Printf("budgets: saving budgets: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefBudgets, string(raw))
+}
+
+// sessionEntry is the entry the session in progress would be recorded as,
+// so far.
+func sessionEntry(timer *TaskTimer) Entry {
+	entry := Entry{Task: timer.taskName, Duration: timer.elapsedTime}
+	if timer.template.Name == entry.Task {
+		timer.template.Apply(&entry)
+	}
+	applyRules(&entry)
+	return entry
+}
+
+// budgetUsed totals the time counting towards a budget, including the
+// session in progress.
+func budgetUsed(timer *TaskTimer, budget Budget, entries []Entry) time.Duration {
+	var used time.Duration
+	for _, entry := range entries {
+		if budget.Covers(entry) {
+			used += entry.Duration
+		}
+	}
+	if session := sessionEntry(timer); session.Duration > 0 && budget.Covers(session) {
+		used += session.Duration
+	}
+	return used
+}
+
+// checkBudgets raises an alert for each budget that crossed one of
+// BudgetAlertPercents since it was last checked. A budget that drops back
+// below a threshold, e.g. after its limit was raised, alerts again when it
+// next crosses it.
+func checkBudgets(timer *TaskTimer) {
+	// Until the history is in, every budget would look unused
+	if !timer.loaded {
+		return
+	}
+	budgets := loadBudgets()
+	entries, _ := timer.history.Get()
+
+	changed := false
+	for i, budget := range budgets {
+		percent := budget.Percent(budgetUsed(timer, budget, entries))
+		reached := 0
+		for _, threshold := range BudgetAlertPercents {
+			if percent >= threshold {
+				reached = threshold
+			}
+		}
+		if reached > budget.Alerted {
+			timer.alerts.Raise(budgetAlert(budget, reached))
+		}
+		if reached != budget.Alerted {
+			budgets[i].Alerted = reached
+			changed = true
+		}
+	}
+	if changed {
+		setBudgets(budgets)
+	}
+}
+
+func budgetAlert(budget Budget, percent int) Alert {
+	data := map[string]any{
+		"Name":    budget.Name,
+		"Percent": percent,
+		"Limit":   formatShortDuration(budget.Limit),
+	}
+	message := tr("{{.Name}} has used {{.Percent}}% of its {{.Limit}} budget.", data)
+	if percent >= 100 {
+		message = tr("{{.Name}} has used up its {{.Limit}} budget.", data)
+	}
+	return Alert{
+		ID:      "budget:" + budget.Kind + ":" + budget.Name,
+		Title:   tr("Budget"),
+		Message: message,
+	}
+}
+
+// watchBudgets checks the budgets whenever the history changes, and every
+// BudgetCheckInterval to catch the session in progress.
+func watchBudgets(timer *TaskTimer) {
+	timer.history.AddListener(binding.NewDataListener(func() {
+		checkBudgets(timer)
+	}))
+	go func() {
+		for range time.Tick(BudgetCheckInterval) {
+			fyne.Do(func() {
+				checkBudgets(timer)
+			})
+		}
+	}()
+}
+
+// createBudgetUsageContainer shows how much of each budget is used, for the
+// stats view. It follows the history while shown.
+func createBudgetUsageContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	listenWhileShown(timer, timer.history, func() {
+		entries, _ := timer.history.Get()
+		list.RemoveAll()
+		for _, budget := range loadBudgets() {
+			used := budgetUsed(timer, budget, entries)
+			bar := widget.NewProgressBar()
+			bar.Max = float64(budget.Limit)
+			bar.SetValue(float64(min(used, budget.Limit)))
+			bar.TextFormatter = func() string {
+				return tr("{{.Used}} of {{.Limit}} ({{.Percent}}%)", map[string]any{
+					"Used":    formatDuration(used),
+					"Limit":   formatShortDuration(budget.Limit),
+					"Percent": budget.Percent(used),
+				})
+			}
+			list.Add(widget.NewLabel(budget.Name))
+			list.Add(bar)
+		}
+	})
+	return list
+}
+
+// createBudgetListContainer lists the budgets with buttons to edit or
+// delete each one.
+func createBudgetListContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	var render func()
+	render = func() {
+		budgets := loadBudgets()
+		list.RemoveAll()
+		for i, budget := range budgets {
+			text := tr("{{.Name}} ({{.Kind}}): {{.Limit}}", map[string]any{
+				"Name":  budget.Name,
+				"Kind":  tr(budgetKindName(budget.Kind)),
+				"Limit": formatShortDuration(budget.Limit),
+			})
+			if !budget.Deadline.IsZero() {
+				text += " · " + tr("due {{.Date}}", map[string]any{"Date": formatDate(budget.Deadline)})
+			}
+			label := widget.NewLabel(text)
+			label.Truncation = fyne.TextTruncateEllipsis
+			buttons := container.NewHBox(
+				widget.NewButton(tr("Edit"), func() { showBudgetDialog(timer, i, render) }),
+				widget.NewButton(tr("Delete"), func() {
+					dialog.ShowConfirm(tr("Delete Budget"), tr("Delete the budget for \"{{.Name}}\"?", map[string]any{"Name": budget.Name}), func(ok bool) {
+						if ok {
+							setBudgets(slices.Delete(budgets, i, i+1))
+							timer.alerts.Dismiss(budgetAlert(budget, 0).ID)
+							render()
+						}
+					}, timer.window)
+				}),
+			)
+			list.Add(container.NewBorder(nil, nil, nil, buttons, label))
+		}
+		if len(budgets) == 0 {
+			list.Add(widget.NewLabel(tr("No budgets yet")))
+		}
+	}
+	render()
+
+	return container.NewVBox(
+		list,
+		container.NewHBox(
+			widget.NewButton(tr("Add Budget"), func() { showBudgetDialog(timer, -1, render) }),
+			widget.NewButton(tr("Capacity Plan…"), func() { showCapacityPlan(timer) }),
+		),
+	)
+}
+
+func budgetKindName(kind string) string {
+	if kind == BudgetProject {
+		return "Project"
+	}
+	return "Task"
+}
+
+// showBudgetDialog edits the budget at index, or creates one if index is
+// negative.
+func showBudgetDialog(timer *TaskTimer, index int, saved func()) {
+	budgets := loadBudgets()
+	budget := Budget{Kind: BudgetTask}
+	if index >= 0 {
+		budget = budgets[index]
+	}
+
+	nameInput := widget.NewSelectEntry(nil)
+	nameInput.SetText(budget.Name)
+	kindSelect := widget.NewSelect([]string{tr("Task"), tr("Project")}, func(kind string) {
+		if kind == tr("Project") {
+			nameInput.SetOptions(knownProjects(timer))
+		} else {
+			nameInput.SetOptions(timer.tasks.Active())
+		}
+	})
+	kindSelect.SetSelected(tr(budgetKindName(budget.Kind)))
+	hoursInput := widget.NewEntry()
+	hoursInput.PlaceHolder = tr("Hours")
+	if budget.Limit > 0 {
+		hoursInput.SetText(formatHours(budget.Limit))
+	}
+	deadlineInput := widget.NewEntry()
+	deadlineInput.PlaceHolder = tr("YYYY-MM-DD, optional")
+	if !budget.Deadline.IsZero() {
+		deadlineInput.SetText(budget.Deadline.Format("2006-01-02"))
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("For"), kindSelect),
+		widget.NewFormItem(tr("Name"), nameInput),
+		widget.NewFormItem(tr("Hours"), hoursInput),
+		widget.NewFormItem(tr("Deadline"), deadlineInput),
+	}
+	title := tr("Add Budget")
+	if index >= 0 {
+		title = tr("Edit Budget")
+	}
+	dialog.ShowForm(title, tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		name := strings.TrimSpace(nameInput.Text)
+		limit, err := parseHours(hoursInput.Text)
+		if name == "" || err != nil || limit <= 0 {
+			dialog.ShowInformation(title, tr("Enter a name and a number of hours."), timer.window)
+			return
+		}
+		var deadline time.Time
+		if text := strings.TrimSpace(deadlineInput.Text); text != "" {
+			if deadline, err = time.ParseInLocation("2006-01-02", text, time.Local); err != nil {
+				dialog.ShowInformation(title, tr("Write the deadline as YYYY-MM-DD."), timer.window)
+				return
+			}
+		}
+
+		edited := Budget{
+			Kind:     BudgetTask,
+			Name:     name,
+			Limit:    limit,
+			Deadline: deadline,
+		}
+		if kindSelect.Selected == tr("Project") {
+			edited.Kind = BudgetProject
+		}
+		if index >= 0 {
+			// Keeps already alerted thresholds from alerting again
+			if edited.Kind == budget.Kind && edited.Name == budget.Name {
+				edited.Alerted = budget.Alerted
+			}
+			budgets[index] = edited
+		} else {
+			budgets = append(budgets, edited)
+		}
+		setBudgets(budgets)
+		checkBudgets(timer)
+		saved()
+	}, timer.window)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// CapacityPlanHorizon is how many days ahead the capacity plan looks before
+// giving up on work that doesn't fit.
+const CapacityPlanHorizon = 2 * 365
+
+// budgetPlan is where a budget's remaining time falls in the capacity plan.
+// Finish is the day it would be done, zero if that's beyond the horizon, and
+// Short how much of it would still be left at the end of its deadline.
+type budgetPlan struct {
+	Budget    Budget
+	Remaining time.Duration
+	Finish    time.Time
+	Short     time.Duration
+}
+
+// planBudgets fills each day's capacity from today on with what's left of
+// each budget: those with the earliest deadlines first, then those without
+// one in their order. It returns the plans in that order along with the day
+// all the work would be done, zero if that's beyond the horizon.
+func planBudgets(budgets []Budget, used func(Budget) time.Duration, today time.Time, dayCapacity func(day time.Time) time.Duration) ([]budgetPlan, time.Time) {
+	var plans []budgetPlan
+	for _, budget := range budgets {
+		if remaining := budget.Limit - used(budget); remaining > 0 {
+			plans = append(plans, budgetPlan{Budget: budget, Remaining: remaining})
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		a, b := plans[i].Budget.Deadline, plans[j].Budget.Deadline
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+
+	end := addDays(today, CapacityPlanHorizon)
+	day, left := today, dayCapacity(today)
+	var finish time.Time
+	for i := range plans {
+		plan := &plans[i]
+		work := plan.Remaining
+		var doneInTime time.Duration
+		for work > 0 && day.Before(end) {
+			if left <= 0 {
+				day = addDays(day, 1)
+				left = dayCapacity(day)
+				continue
+			}
+			done := min(work, left)
+			work -= done
+			left -= done
+			if !day.After(plan.Budget.Deadline) {
+				doneInTime += done
+			}
+		}
+		if !plan.Budget.Deadline.IsZero() {
+			plan.Short = plan.Remaining - doneInTime
+		}
+		if work == 0 {
+			plan.Finish = day
+			finish = day
+		} else {
+			finish = time.Time{}
+		}
+	}
+	return plans, finish
+}
+
+// capacityPlanWeeks is how many weeks from today to finish, the day the
+// work would be done, counted in tenths.
+func capacityPlanWeeks(today, finish time.Time) float64 {
+	days := 0
+	for day := today; !day.After(finish); day = addDays(day, 1) {
+		days++
+	}
+	return float64(days*10/7) / 10
+}
+
+// showCapacityPlan looks ahead at the time left on the budgets against the
+// work schedule: how many weeks of committed work there are, and whether
+// each budget can be done by its deadline.
+func showCapacityPlan(timer *TaskTimer) {
+	now := time.Now()
+	today := dayStart(now)
+	tracked := dailyTotal(timer, today, addDays(today, 1))[today]
+	dayCapacity := func(day time.Time) time.Duration {
+		capacity := scheduledCapacity(day, addDays(day, 1))
+		// What's been tracked today has used up some of it already
+		if day.Equal(today) {
+			capacity = max(capacity-tracked, 0)
+		}
+		return capacity
+	}
+	entries, _ := timer.history.Get()
+	used := func(budget Budget) time.Duration {
+		return budgetUsed(timer, budget, entries)
+	}
+	plans, finish := planBudgets(loadBudgets(), used, today, dayCapacity)
+
+	var total time.Duration
+	for _, plan := range plans {
+		total += plan.Remaining
+	}
+	// Averaged over four weeks, so a week off doesn't read as no capacity
+	weekly := scheduledCapacity(today, addDays(today, 28)) / 4
+	summary := widget.NewLabel("")
+	summary.Wrapping = fyne.TextWrapWord
+	switch {
+	case len(plans) == 0:
+		summary.SetText(tr("No budgets have time left. Add budgets with hours, and deadlines, under Tasks."))
+	case weekly <= 0:
+		summary.SetText(tr("No working time is scheduled. Set a work schedule or weekly hours in the settings."))
+	case finish.IsZero():
+		summary.SetText(tr("{{.Hours}} of committed work is more than fits in the next two years.", map[string]any{
+			"Hours": formatDuration(total),
+		}))
+	default:
+		summary.SetText(tr("About {{.Weeks}} weeks of committed work ({{.Hours}} at {{.Weekly}} a week), done around {{.Date}}.", map[string]any{
+			"Weeks":  strconv.FormatFloat(capacityPlanWeeks(today, finish), 'f', -1, 64),
+			"Hours":  formatDuration(total),
+			"Weekly": formatDuration(weekly),
+			"Date":   formatDate(finish),
+		}))
+	}
+
+	bold := fyne.TextStyle{Bold: true}
+	rows := container.NewVBox(container.NewGridWithColumns(5,
+		widget.NewLabelWithStyle(tr("Budget"), fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle(tr("Left"), fyne.TextAlignTrailing, bold),
+		widget.NewLabelWithStyle(tr("Deadline"), fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle(tr("Done around"), fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle(tr("Status"), fyne.TextAlignLeading, bold),
+	))
+	for _, plan := range plans {
+		deadline, done := "—", "—"
+		if !plan.Budget.Deadline.IsZero() {
+			deadline = formatDate(plan.Budget.Deadline)
+		}
+		if !plan.Finish.IsZero() {
+			done = formatDate(plan.Finish)
+		}
+		status := widget.NewLabel(tr("No deadline"))
+		switch {
+		case plan.Budget.Deadline.IsZero():
+		case plan.Short > 0:
+			status = widget.NewLabelWithStyle(tr("{{.Hours}} short", map[string]any{"Hours": formatDuration(plan.Short)}), fyne.TextAlignLeading, bold)
+		default:
+			status.SetText(tr("On track"))
+		}
+		name := widget.NewLabel(plan.Budget.Name)
+		name.Truncation = fyne.TextTruncateEllipsis
+		rows.Add(container.NewGridWithColumns(5,
+			name,
+			widget.NewLabelWithStyle(formatDuration(plan.Remaining), fyne.TextAlignTrailing, fyne.TextStyle{}),
+			widget.NewLabel(deadline),
+			widget.NewLabel(done),
+			status,
+		))
+	}
+
+	note := widget.NewLabel(tr("The plan gives all the scheduled time, less days off, to the budgets, earliest deadline first."))
+	note.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(600, 280))
+	content := container.NewBorder(summary, note, nil, nil, scroll)
+	dialog.NewCustom(tr("Capacity Plan"), tr("Close"), content, timer.window).Show()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanBudgets(t *testing.T) {
+	monday := time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local)
+	workdays := func(day time.Time) time.Duration {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			return 0
+		}
+		return 8 * time.Hour
+	}
+	used := map[string]time.Duration{"Website": 4 * time.Hour, "Audit": 6 * time.Hour}
+	usedOn := func(budget Budget) time.Duration { return used[budget.Name] }
+	budgets := []Budget{
+		{Kind: BudgetProject, Name: "Reports", Limit: 12 * time.Hour},
+		{Kind: BudgetProject, Name: "Website", Limit: 20 * time.Hour, Deadline: addDays(monday, 2)},
+		// Used up
+		{Kind: BudgetTask, Name: "Audit", Limit: 5 * time.Hour, Deadline: monday},
+		{Kind: BudgetTask, Name: "Docs", Limit: 10 * time.Hour, Deadline: addDays(monday, 1)},
+	}
+
+	plans, finish := planBudgets(budgets, usedOn, monday, workdays)
+	want := []budgetPlan{
+		// 8h on Monday and 2h on Tuesday
+		{Remaining: 10 * time.Hour, Finish: addDays(monday, 1)},
+		// 6h on Tuesday, 8h on Wednesday and 2h on Thursday, after its deadline
+		{Remaining: 16 * time.Hour, Finish: addDays(monday, 3), Short: 2 * time.Hour},
+		// 6h on Thursday and 6h on Friday, with no deadline to miss
+		{Remaining: 12 * time.Hour, Finish: addDays(monday, 4)},
+	}
+	names := []string{"Docs", "Website", "Reports"}
+	if len(plans) != len(want) {
+		t.Fatalf("planned %d budgets, want %d", len(plans), len(want))
+	}
+	for i, plan := range plans {
+		w := want[i]
+		if plan.Budget.Name != names[i] || plan.Remaining != w.Remaining || !plan.Finish.Equal(w.Finish) || plan.Short != w.Short {
+			t.Errorf("plan %d = %s: %v left, done %v, %v short; want %s: %v left, done %v, %v short",
+				i, plan.Budget.Name, plan.Remaining, plan.Finish, plan.Short, names[i], w.Remaining, w.Finish, w.Short)
+		}
+	}
+	if want := addDays(monday, 4); !finish.Equal(want) {
+		t.Errorf("all done %v, want %v", finish, want)
+	}
+
+	// More than fits before the horizon
+	huge := []Budget{{Kind: BudgetProject, Name: "Rewrite", Limit: 10000 * time.Hour, Deadline: addDays(monday, 30)}}
+	plans, finish = planBudgets(huge, usedOn, monday, workdays)
+	if len(plans) != 1 {
+		t.Fatalf("planned %d budgets, want 1", len(plans))
+	}
+	if !plans[0].Finish.IsZero() || !finish.IsZero() {
+		t.Errorf("a plan beyond the horizon finishes %v, all done %v; want neither", plans[0].Finish, finish)
+	}
+	// 23 working days until the deadline
+	if want := 10000*time.Hour - 23*8*time.Hour; plans[0].Short != want {
+		t.Errorf("%v short, want %v", plans[0].Short, want)
+	}
+}
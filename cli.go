@@ -0,0 +1,379 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StatusFileName is written by the running app so the command line can
+// report on it without a connection to the GUI.
+const StatusFileName = "status.json"
+
+// Status is the running app's timer state as seen by the command line.
+type Status struct {
+	Task      string        `json:"task"`
+	Running   bool          `json:"running"`
+	Elapsed   time.Duration `json:"-"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	// TimeZone is the zone the app counts days in, so the command line
+	// agrees with it on when today started
+	TimeZone string      `json:"time_zone,omitempty"`
+	Today    []TaskTotal `json:"today,omitempty"`
+}
+
+// TaskTotal is the tracked time for one task.
+type TaskTotal struct {
+	Task     string        `json:"task"`
+	Duration time.Duration `json:"-"`
+}
+
+// Durations are written in whole seconds, which is easier on shell scripts
+// than Go's nanoseconds.
+func (s Status) MarshalJSON() ([]byte, error) {
+	type plain Status
+	return json.Marshal(struct {
+		plain
+		ElapsedSeconds int64 `json:"elapsed_seconds"`
+	}{plain(s), int64(s.Elapsed.Seconds())})
+}
+
+func (s *Status) UnmarshalJSON(data []byte) error {
+	type plain Status
+	var raw struct {
+		plain
+		ElapsedSeconds int64 `json:"elapsed_seconds"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = Status(raw.plain)
+	s.Elapsed = time.Duration(raw.ElapsedSeconds) * time.Second
+	return nil
+}
+
+func (t TaskTotal) MarshalJSON() ([]byte, error) {
+	type plain TaskTotal
+	return json.Marshal(struct {
+		plain
+		Seconds int64 `json:"seconds"`
+	}{plain(t), int64(t.Duration.Seconds())})
+}
+
+func (t *TaskTotal) UnmarshalJSON(data []byte) error {
+	type plain TaskTotal
+	var raw struct {
+		plain
+		Seconds int64 `json:"seconds"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*t = TaskTotal(raw.plain)
+	t.Duration = time.Duration(raw.Seconds) * time.Second
+	return nil
+}
+
+// Current returns the status with the elapsed time brought up to now. Totals
+// written on an earlier day in the app's time zone are dropped.
+func (s Status) Current(now time.Time) Status {
+	loc := time.Local
+	if s.TimeZone != "" {
+		if zone, err := time.LoadLocation(s.TimeZone); err == nil {
+			loc = zone
+		}
+	}
+	written, today := s.UpdatedAt.In(loc), now.In(loc)
+	if written.YearDay() != today.YearDay() || written.Year() != today.Year() {
+		s.Today = nil
+	}
+	if s.Running {
+		s.Elapsed += now.Sub(s.UpdatedAt)
+		s.UpdatedAt = now
+	}
+	return s
+}
+
+func statusFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gotime", StatusFileName), nil
+}
+
+// writeStatus records the timer state for the command line and passes it
+// on to the live feed and D-Bus. It is called
+// whenever the timer starts, stops or changes task; in between, readers
+// extrapolate the elapsed time from UpdatedAt.
+func writeStatus(timer *TaskTimer) {
+	status := timerStatus(timer)
+	if err := saveStatus(status); err != nil {
+		fmt.Fprintf(os.Stderr, "status: %v\n", err)
+	}
+	timer.liveFeed.Publish(liveStateOf(status))
+	emitDBusState(status)
+}
+
+// timerStatus returns the timer's state and today's totals.
+func timerStatus(timer *TaskTimer) Status {
+	now := time.Now()
+	today := dayStart(now)
+	totals := totalsBetween(timer, today, addDays(today, 1))
+
+	status := Status{
+		Running:   timer.isRunning,
+		Elapsed:   timer.elapsedTime,
+		UpdatedAt: now,
+		TimeZone:  time.Local.String(),
+	}
+	if timer.taskName != NoTaskSelected {
+		status.Task = timer.taskName
+	}
+	for _, taskName := range sortedTaskNames(totals) {
+		status.Today = append(status.Today, TaskTotal{Task: taskName, Duration: totals[taskName]})
+	}
+	return status
+}
+
+// saveStatus writes through a temporary file so readers never see a
+// partially written status.
+func saveStatus(status Status) error {
+	path, err := statusFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// clearStatus removes the status file when the app exits.
+func clearStatus() {
+	if path, err := statusFilePath(); err == nil {
+		os.Remove(path)
+	}
+}
+
+func loadStatus() (Status, error) {
+	var status Status
+	path, err := statusFilePath()
+	if err != nil {
+		return status, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return status, err
+	}
+	err = json.Unmarshal(raw, &status)
+	return status, err
+}
+
+// runCLI handles command-line use and returns the exit code.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "status":
+		return runStatusCommand(args[1:], stdout, stderr)
+	case "report":
+		return runReportCommand(args[1:], stdout, stderr)
+	case "start", "stop", "switch":
+		return runTimerCommand(args[0], args[1:], stdout, stderr)
+	case "completion":
+		return runCompletionCommand(args[1:], stdout, stderr)
+	case "serve":
+		return runServeCommand(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "gotime: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage: gotime [command]
+
+Without a command, gotime opens the timer window; with --minimized, it
+opens minimized, as it does on login.
+
+Commands:
+  status [--json]       Show the running task and its elapsed time
+  report [--json]       Show today's totals per task
+  start [TASK]          Start the timer in the running app, on TASK if given
+  stop                  Pause the timer in the running app
+  switch TASK           Move the running app's timer to TASK
+  completion SHELL      Print a completion script for bash, zsh or fish
+  serve [flags]         Run a team server; see gotime serve -help
+`)
+}
+
+// currentStatus reads the app's status. A missing file means the app isn't
+// running, which is reported as an idle status rather than an error.
+func currentStatus() (Status, error) {
+	status, err := loadStatus()
+	if errors.Is(err, os.ErrNotExist) {
+		return Status{}, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	return status.Current(time.Now()), nil
+}
+
+func runStatusCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the status as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	status, err := currentStatus()
+	if err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		status.Today = nil
+		raw, _ := json.Marshal(status)
+		fmt.Fprintln(stdout, string(raw))
+		return 0
+	}
+
+	printStatus(stdout, status)
+	return 0
+}
+
+// printStatus writes the running task and its elapsed time on one line.
+func printStatus(stdout io.Writer, status Status) {
+	switch {
+	case status.Task == "":
+		fmt.Fprintln(stdout, "No task")
+	case status.Running:
+		fmt.Fprintf(stdout, "%s %s\n", status.Task, formatDuration(status.Elapsed))
+	default:
+		fmt.Fprintf(stdout, "%s %s (paused)\n", status.Task, formatDuration(status.Elapsed))
+	}
+}
+
+func runReportCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	status, err := currentStatus()
+	if err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		if status.Today == nil {
+			status.Today = []TaskTotal{}
+		}
+		raw, _ := json.Marshal(status.Today)
+		fmt.Fprintln(stdout, string(raw))
+		return 0
+	}
+
+	if len(status.Today) == 0 {
+		fmt.Fprintln(stdout, "Nothing tracked today")
+	}
+	for _, total := range status.Today {
+		fmt.Fprintf(stdout, "%s  %s\n", formatDuration(total.Duration), total.Task)
+	}
+	return 0
+}
+
+func runCompletionCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "Usage: gotime completion bash|zsh|fish")
+		return 2
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "gotime: no completions for %q\n", args[0])
+		return 2
+	}
+	fmt.Fprint(stdout, script)
+	return 0
+}
+
+var completionScripts = map[string]string{
+	"bash": `_gotime() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    case $COMP_CWORD in
+        1) COMPREPLY=($(compgen -W "status report start stop switch completion serve help --minimized" -- "$cur")) ;;
+        *) case ${COMP_WORDS[1]} in
+               status|report) COMPREPLY=($(compgen -W "--json" -- "$cur")) ;;
+               completion) COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")) ;;
+               serve) COMPREPLY=($(compgen -W "-addr -config -data -add-member -manager -add-project -tls -cert -key" -- "$cur")) ;;
+           esac ;;
+    esac
+}
+complete -F _gotime gotime
+`,
+	"zsh": `#compdef gotime
+
+_gotime() {
+    local -a commands
+    commands=(
+        'status:show the running task and its elapsed time'
+        'report:show today'"'"'s totals per task'
+        'start:start the timer'
+        'stop:pause the timer'
+        'switch:move the timer to another task'
+        'completion:print a completion script'
+        'serve:run a team server'
+        'help:show usage'
+        '--minimized:open the timer window minimized'
+    )
+    if (( CURRENT == 2 )); then
+        _describe command commands
+        return
+    fi
+    case $words[2] in
+        status|report) _arguments '--json[print JSON]' ;;
+        completion) _values shell bash zsh fish ;;
+        serve) _arguments '-addr[address to listen on]:address' '-config[members and projects file]:file:_files' '-data[directory for entries]:directory:_files -/' '-add-member[add a member]:name' '-manager[let the member see team reports]' '-add-project[share a project]:project' '-tls[serve HTTPS]' '-cert[TLS certificate]:file:_files' '-key[TLS private key]:file:_files' ;;
+    esac
+}
+
+compdef _gotime gotime
+`,
+	"fish": `complete -c gotime -f
+complete -c gotime -n __fish_use_subcommand -a status -d 'Show the running task and its elapsed time'
+complete -c gotime -n __fish_use_subcommand -a report -d 'Show today\'s totals per task'
+complete -c gotime -n __fish_use_subcommand -a start -d 'Start the timer'
+complete -c gotime -n __fish_use_subcommand -a stop -d 'Pause the timer'
+complete -c gotime -n __fish_use_subcommand -a switch -d 'Move the timer to another task'
+complete -c gotime -n __fish_use_subcommand -a completion -d 'Print a completion script'
+complete -c gotime -n __fish_use_subcommand -a serve -d 'Run a team server'
+complete -c gotime -n __fish_use_subcommand -a help -d 'Show usage'
+complete -c gotime -n __fish_use_subcommand -l minimized -d 'Open the timer window minimized'
+complete -c gotime -n '__fish_seen_subcommand_from status report' -l json -d 'Print JSON'
+complete -c gotime -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'
+complete -c gotime -n '__fish_seen_subcommand_from serve' -o addr -o config -o data -o add-member -o manager -o add-project -o tls -o cert -o key
+`,
+}
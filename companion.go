@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const (
+	PrefCompanionEnabled = "companionEnabled"
+	PrefCompanionPaired  = "companionPaired"
+
+	// CompanionPort is where browser extensions find the app, on the
+	// loopback interface only
+	CompanionPort = 47615
+
+	// CompanionPairTimeout is how long a pairing request waits for the
+	// user to answer
+	CompanionPairTimeout = 2 * time.Minute
+)
+
+// Only browser extensions may pair; ordinary web pages have http(s) origins
+var companionOriginSchemes = []string{"chrome-extension://", "moz-extension://", "safari-web-extension://"}
+
+// CompanionPairing is a browser extension the user allowed to control the
+// timer. Its token is only accepted from the origin that paired.
+type CompanionPairing struct {
+	Name   string    `json:"name"`
+	Origin string    `json:"origin"`
+	Paired time.Time `json:"paired"`
+}
+
+// CompanionTimer is the timer state the companion endpoint reports. An empty
+// Task means none is selected.
+type CompanionTimer struct {
+	Task           string   `json:"task"`
+	Running        bool     `json:"running"`
+	ElapsedSeconds int64    `json:"elapsed_seconds"`
+	Tasks          []string `json:"tasks"`
+}
+
+// BrowserCompanion serves a small JSON API on localhost for a browser
+// extension button:
+//
+//	POST /v1/pair          {"name": "..."} → {"token": "..."}, once the user allows it
+//	GET  /v1/timer         → CompanionTimer
+//	POST /v1/timer/start   {"task": "..."}, the task being optional
+//	POST /v1/timer/stop    pauses the timer
+//	POST /v1/timer/switch  {"task": "..."}
+//
+// Every call but pairing needs the token as a bearer token. Timer calls
+// answer with the timer state after the change.
+type BrowserCompanion struct {
+	timer   *TaskTimer
+	mu      sync.Mutex
+	server  *http.Server
+	pairing bool
+}
+
+func NewBrowserCompanion(timer *TaskTimer) *BrowserCompanion {
+	return &BrowserCompanion{timer: timer}
+}
+
+// Apply starts or stops the server to match the setting.
+func (c *BrowserCompanion) Apply() error {
+	if !fyne.CurrentApp().Preferences().Bool(PrefCompanionEnabled) {
+		c.Close()
+		return nil
+	}
+	if err := c.start(); err != nil {
+		return fmt.Errorf("companion: %w", err)
+	}
+	return nil
+}
+
+func (c *BrowserCompanion) start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.server != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", CompanionPort))
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/pair", c.handlePair)
+	mux.HandleFunc("GET /v1/timer", c.authorized(c.handleTimer))
+	mux.HandleFunc("POST /v1/timer/start", c.authorized(c.handleStart))
+	mux.HandleFunc("POST /v1/timer/stop", c.authorized(c.handleStop))
+	mux.HandleFunc("POST /v1/timer/switch", c.authorized(c.handleSwitch))
+	c.server = &http.Server{Handler: c.guard(mux), ReadHeaderTimeout: 10 * time.Second}
+	go c.server.Serve(listener)
+	return nil
+}
+
+// Close stops the server.
+func (c *BrowserCompanion) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.server != nil {
+		c.server.Close()
+		c.server = nil
+	}
+}
+
+// guard turns away web pages, and pages reaching the port through a
+// rebound DNS name, before anything else runs. It answers CORS preflights
+// for extension origins.
+func (c *BrowserCompanion) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.Host)
+		if host != "127.0.0.1" && host != "localhost" {
+			companionError(w, http.StatusForbidden, "unexpected host")
+			return
+		}
+		origin := r.Header.Get("Origin")
+		if origin != "" {
+			if !companionExtensionOrigin(origin) {
+				companionError(w, http.StatusForbidden, "only browser extensions may connect")
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func companionExtensionOrigin(origin string) bool {
+	for _, scheme := range companionOriginSchemes {
+		if strings.HasPrefix(origin, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized only lets paired extensions through, from the origin that
+// paired. Requests without an origin come from outside a browser.
+func (c *BrowserCompanion) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		pairing, paired := companionPairings()[token]
+		origin := r.Header.Get("Origin")
+		if !ok || !paired || (origin != "" && origin != pairing.Origin) {
+			companionError(w, http.StatusUnauthorized, "not paired")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handlePair asks the user whether to let an extension control the timer,
+// and hands it a token if they agree. One request is asked about at a time.
+func (c *BrowserCompanion) handlePair(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		companionError(w, http.StatusForbidden, "only browser extensions may pair")
+		return
+	}
+	var request struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		name = origin
+	}
+
+	c.mu.Lock()
+	busy := c.pairing
+	c.pairing = true
+	c.mu.Unlock()
+	if busy {
+		companionError(w, http.StatusConflict, "another pairing request is waiting")
+		return
+	}
+	defer func() {
+		c.mu.Lock()
+		c.pairing = false
+		c.mu.Unlock()
+	}()
+
+	answers := make(chan bool, 1)
+	var confirm *dialog.ConfirmDialog
+	fyne.Do(func() {
+		confirm = dialog.NewConfirm(tr("Pair Browser Extension"),
+			tr("{{.Name}} ({{.Origin}}) wants to see and control your timer. Allow it?", map[string]any{
+				"Name":   name,
+				"Origin": origin,
+			}), func(ok bool) {
+				answers <- ok
+			}, c.timer.window)
+		confirm.SetConfirmText(tr("Allow"))
+		confirm.SetDismissText(tr("Deny"))
+		confirm.Show()
+		c.timer.window.RequestFocus()
+	})
+
+	var allowed bool
+	select {
+	case allowed = <-answers:
+	case <-time.After(CompanionPairTimeout):
+		fyne.Do(func() {
+			confirm.Hide()
+		})
+	case <-r.Context().Done():
+		fyne.Do(func() {
+			confirm.Hide()
+		})
+		return
+	}
+	if !allowed {
+		companionError(w, http.StatusForbidden, "pairing was not allowed")
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		companionError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pairings := companionPairings()
+	pairings[token] = CompanionPairing{Name: name, Origin: origin, Paired: time.Now()}
+	setCompanionPairings(pairings)
+	companionReply(w, map[string]string{"token": token})
+}
+
+func (c *BrowserCompanion) handleTimer(w http.ResponseWriter, r *http.Request) {
+	c.reply(w, nil)
+}
+
+func (c *BrowserCompanion) handleStart(w http.ResponseWriter, r *http.Request) {
+	taskName, err := companionTaskName(r)
+	if err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	c.reply(w, func() error {
+		return startRemotely(c.timer, taskName)
+	})
+}
+
+// startRemotely starts the timer for an outside caller, on taskName if it
+// isn't empty and on the selected task otherwise. It runs on the UI thread.
+func startRemotely(timer *TaskTimer, taskName string) error {
+	if taskName != "" {
+		switchTask(timer, taskName)
+		return nil
+	}
+	if timer.taskName == NoTaskSelected {
+		return errors.New("select a task first")
+	}
+	if !timer.isRunning {
+		resumeTimer(timer)
+	}
+	return nil
+}
+
+func (c *BrowserCompanion) handleStop(w http.ResponseWriter, r *http.Request) {
+	c.reply(w, func() error {
+		if c.timer.isRunning {
+			pauseTimer(c.timer)
+		}
+		return nil
+	})
+}
+
+func (c *BrowserCompanion) handleSwitch(w http.ResponseWriter, r *http.Request) {
+	taskName, err := companionTaskName(r)
+	if err == nil && taskName == "" {
+		err = errTaskNameEmpty
+	}
+	if err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	c.reply(w, func() error {
+		switchTask(c.timer, taskName)
+		return nil
+	})
+}
+
+// reply runs change on the UI thread, if there is one, and answers with the
+// timer state afterwards.
+func (c *BrowserCompanion) reply(w http.ResponseWriter, change func() error) {
+	var state CompanionTimer
+	var err error
+	fyne.DoAndWait(func() {
+		if change != nil {
+			if err = change(); err != nil {
+				return
+			}
+		}
+		state = CompanionTimer{
+			Running:        c.timer.isRunning,
+			ElapsedSeconds: int64(c.timer.elapsedTime.Seconds()),
+			Tasks:          c.timer.tasks.Active(),
+		}
+		if c.timer.taskName != NoTaskSelected {
+			state.Task = c.timer.taskName
+		}
+	})
+	if err != nil {
+		companionError(w, http.StatusConflict, err.Error())
+		return
+	}
+	companionReply(w, state)
+}
+
+// companionTaskName reads the optional task from a request body.
+func companionTaskName(r *http.Request) (string, error) {
+	var request struct {
+		Task string `json:"task"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(request.Task), nil
+}
+
+func companionReply(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func companionError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// companionPairings loads the paired extensions by token.
+func companionPairings() map[string]CompanionPairing {
+	pairings := make(map[string]CompanionPairing)
+	raw := fyne.CurrentApp().Preferences().String(PrefCompanionPaired)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &pairings); err != nil {
+			log.Printf("companion: reading pairings: %v", err)
+		}
+	}
+	return pairings
+}
+
+func setCompanionPairings(pairings map[string]CompanionPairing) {
+	raw, err := json.Marshal(pairings)
+	if err != nil {
+		log.Printf("companion: saving pairings: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefCompanionPaired, string(raw))
+}
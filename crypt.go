@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// sealedMagic starts every file sealed by sealData, so a wrong or
+	// missing passphrase can be told apart from a corrupt file
+	sealedMagic = "gotime-sealed-v1\n"
+
+	sealSaltSize = 16
+	// sealIterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+	sealIterations = 600_000
+)
+
+var (
+	errWrongPassphrase = errors.New("wrong passphrase, or the data was changed")
+	errNotSealed       = errors.New("not an encrypted gotime file")
+)
+
+// sealer encrypts with AES-256-GCM under a key derived from a passphrase.
+// Deriving the key is deliberately slow, so data that is saved often keeps
+// one sealer, and with it one salt, instead of calling sealData each time.
+type sealer struct {
+	salt []byte
+	gcm  cipher.AEAD
+}
+
+// newSealer derives the key for a salt, or for a new random salt if salt is
+// nil.
+func newSealer(passphrase string, salt []byte) (*sealer, error) {
+	if passphrase == "" {
+		return nil, errors.New("no passphrase set")
+	}
+	if salt == nil {
+		salt = make([]byte, sealSaltSize)
+		rand.Read(salt)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, sealIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{salt: salt, gcm: gcm}, nil
+}
+
+// newSealerFor derives the key a sealed file was written with.
+func newSealerFor(passphrase string, sealed []byte) (*sealer, error) {
+	rest, ok := bytes.CutPrefix(sealed, []byte(sealedMagic))
+	if !ok || len(rest) < sealSaltSize {
+		return nil, errNotSealed
+	}
+	return newSealer(passphrase, rest[:sealSaltSize])
+}
+
+// seal writes the magic, salt and a random nonce in front of the
+// ciphertext.
+func (s *sealer) seal(data []byte) []byte {
+	nonce := make([]byte, s.gcm.NonceSize())
+	rand.Read(nonce)
+
+	sealed := append([]byte(sealedMagic), s.salt...)
+	sealed = append(sealed, nonce...)
+	return s.gcm.Seal(sealed, nonce, data, []byte(sealedMagic))
+}
+
+func (s *sealer) open(sealed []byte) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(sealed, []byte(sealedMagic))
+	if !ok || len(rest) < sealSaltSize+s.gcm.NonceSize() {
+		return nil, errNotSealed
+	}
+	rest = rest[sealSaltSize:]
+
+	data, err := s.gcm.Open(nil, rest[:s.gcm.NonceSize()], rest[s.gcm.NonceSize():], []byte(sealedMagic))
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return data, nil
+}
+
+// sealData encrypts data under a new random salt.
+func sealData(passphrase string, data []byte) ([]byte, error) {
+	s, err := newSealer(passphrase, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.seal(data), nil
+}
+
+// openSealed decrypts data written by sealData.
+func openSealed(passphrase string, sealed []byte) ([]byte, error) {
+	s, err := newSealerFor(passphrase, sealed)
+	if err != nil {
+		return nil, err
+	}
+	return s.open(sealed)
+}
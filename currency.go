@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefHomeCurrency  = "homeCurrency"
+	PrefExchangeRates = "exchangeRates"
+
+	// DefaultHomeCurrency is assumed until one is set
+	DefaultHomeCurrency = "EUR"
+)
+
+// ExchangeRatesURL serves the European Central Bank's reference rates, with
+// no account needed.
+var ExchangeRatesURL = "https://api.frankfurter.app/latest"
+
+// ExchangeRates are how many units of each currency one unit of Base buys,
+// as of Date.
+type ExchangeRates struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func homeCurrency() string {
+	return fyne.CurrentApp().Preferences().StringWithFallback(PrefHomeCurrency, DefaultHomeCurrency)
+}
+
+// normalizeCurrency upper-cases a currency code as typed.
+func normalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func exchangeRates() ExchangeRates {
+	var rates ExchangeRates
+	raw := fyne.CurrentApp().Preferences().String(PrefExchangeRates)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &rates); err != nil {
+			log.Printf("currency: reading exchange rates: %v", err)
+		}
+	}
+	return rates
+}
+
+func setExchangeRates(rates ExchangeRates) {
+	raw, err := json.Marshal(rates)
+	if err != nil {
+		log.Printf("currency: saving exchange rates: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefExchangeRates, string(raw))
+}
+
+// rate is what one unit of the base buys of currency.
+func (r ExchangeRates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+// Convert changes an amount from one currency into another, through the
+// base if neither is it. It reports false if either rate is missing.
+func (r ExchangeRates) Convert(amount float64, from, to string) (float64, bool) {
+	if from == to {
+		return amount, true
+	}
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
+
+// fetchExchangeRates downloads the latest rates for base.
+func fetchExchangeRates(base string) (ExchangeRates, error) {
+	resp, err := httpClient.Get(ExchangeRatesURL + "?from=" + url.QueryEscape(base))
+	if err != nil {
+		return ExchangeRates{}, fmt.Errorf("currency: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ExchangeRates{}, fmt.Errorf("currency: exchange rates for %s: %s", base, resp.Status)
+	}
+	var rates ExchangeRates
+	if err := json.NewDecoder(resp.Body).Decode(&rates); err != nil {
+		return ExchangeRates{}, fmt.Errorf("currency: %w", err)
+	}
+	return rates, nil
+}
+
+// clientCurrency is what a client is billed in: their own currency, or the
+// home currency if none is set.
+func clientCurrency(settings ClientSettings) string {
+	if settings.Currency != "" {
+		return settings.Currency
+	}
+	return homeCurrency()
+}
+
+// clientEarnings is what a client's billable hours came to in a period, in
+// their currency and in the home one.
+type clientEarnings struct {
+	Client    string
+	Currency  string
+	Hours     float64
+	Amount    float64
+	Home      float64
+	Converted bool
+}
+
+// earningsByClient prices the billable, reviewed entries that have a client
+// at each client's rate, as invoices do, and converts the amounts into home.
+func earningsByClient(entries []Entry, clients map[string]ClientSettings, home string, rates ExchangeRates) []clientEarnings {
+	hours := make(map[string]float64)
+	for _, entry := range roundEntries(reviewedEntries(entries)) {
+		if entry.Client != "" && !entry.NonBillable {
+			hours[entry.Client] += entry.Duration.Hours()
+		}
+	}
+
+	var earnings []clientEarnings
+	for client, h := range hours {
+		settings := clients[client]
+		e := clientEarnings{
+			Client:   client,
+			Currency: clientCurrency(settings),
+			Hours:    h,
+			Amount:   h * settings.HourlyRate,
+		}
+		e.Home, e.Converted = rates.Convert(e.Amount, e.Currency, home)
+		earnings = append(earnings, e)
+	}
+	sort.Slice(earnings, func(i, j int) bool {
+		return earnings[i].Client < earnings[j].Client
+	})
+	return earnings
+}
+
+func formatMoney(amount float64, currency string) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64) + " " + currency
+}
+
+// showEarningsDialog sums up what each client's hours earned in a period,
+// converted into the home currency at the stored exchange rates, which can
+// be fetched or typed in.
+func showEarningsDialog(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Earnings")) {
+		return
+	}
+
+	fromInput := widget.NewEntry()
+	fromInput.PlaceHolder = "YYYY-MM-DD"
+	toInput := widget.NewEntry()
+	toInput.PlaceHolder = "YYYY-MM-DD"
+	periodSelect := newReportPeriodSelect(fromInput, toInput)
+	homeInput := widget.NewEntry()
+	homeInput.SetText(homeCurrency())
+	rows := container.NewVBox()
+	ratesLabel := widget.NewLabel("")
+	ratesLabel.Wrapping = fyne.TextWrapWord
+
+	render := func() {
+		rows.RemoveAll()
+		from, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		if err != nil {
+			return
+		}
+		home := homeCurrency()
+		rates := exchangeRates()
+		earnings := earningsByClient(entriesBetween(timer, from, to.AddDate(0, 0, 1)), clientSettings(), home, rates)
+		if len(earnings) == 0 {
+			rows.Add(widget.NewLabel(tr("No billable client work in this period")))
+		}
+
+		var total float64
+		missing := false
+		for _, e := range earnings {
+			converted := tr("no exchange rate")
+			if e.Converted {
+				converted = formatMoney(e.Home, home)
+				total += e.Home
+			} else {
+				missing = true
+			}
+			rows.Add(container.NewGridWithColumns(4,
+				widget.NewLabel(e.Client),
+				widget.NewLabelWithStyle(strconv.FormatFloat(e.Hours, 'f', 2, 64)+" h", fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatMoney(e.Amount, e.Currency), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(converted, fyne.TextAlignTrailing, fyne.TextStyle{}),
+			))
+		}
+		if len(earnings) > 0 {
+			rows.Add(widget.NewSeparator())
+			rows.Add(widget.NewLabelWithStyle(tr("Total {{.Amount}}", map[string]any{"Amount": formatMoney(total, home)}),
+				fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+		}
+		if missing {
+			rows.Add(widget.NewLabel(tr("Clients without an exchange rate are left out of the total.")))
+		}
+
+		if rates.Date == "" {
+			ratesLabel.SetText(tr("No exchange rates yet."))
+		} else {
+			ratesLabel.SetText(tr("Exchange rates from {{.Date}}.", map[string]any{"Date": rates.Date}))
+		}
+	}
+
+	fromInput.OnChanged = func(string) { render() }
+	toInput.OnChanged = func(string) { render() }
+	homeInput.OnChanged = func(text string) {
+		if code := normalizeCurrency(text); len(code) == 3 {
+			fyne.CurrentApp().Preferences().SetString(PrefHomeCurrency, code)
+			render()
+		}
+	}
+	periodSelect.SetSelectedIndex(2)
+
+	var updateBtn *widget.Button
+	updateBtn = widget.NewButtonWithIcon(tr("Update Rates"), theme.ViewRefreshIcon(), func() {
+		updateBtn.Disable()
+		home := homeCurrency()
+		go func() {
+			rates, err := fetchExchangeRates(home)
+			fyne.Do(func() {
+				updateBtn.Enable()
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				setExchangeRates(rates)
+				render()
+			})
+		}()
+	})
+	editBtn := widget.NewButton(tr("Edit Rates…"), func() {
+		showExchangeRatesDialog(timer, render)
+	})
+	exportBtn := widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+		from, _ := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		to, _ := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		earnings := earningsByClient(entriesBetween(timer, from, to.AddDate(0, 0, 1)), clientSettings(), homeCurrency(), exchangeRates())
+		saveExport(timer, "earnings.csv", func(w io.Writer) error {
+			return writeEarningsCSV(w, earnings, homeCurrency(), currentExportLocale())
+		})
+	})
+
+	top := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Period"), periodSelect),
+			widget.NewFormItem(tr("From"), fromInput),
+			widget.NewFormItem(tr("To"), toInput),
+			widget.NewFormItem(tr("Home currency"), homeInput),
+		),
+		widget.NewSeparator(),
+	)
+	bottom := container.NewVBox(ratesLabel, container.NewHBox(updateBtn, editBtn, exportBtn))
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(520, 240))
+	dialog.NewCustom(tr("Earnings"), tr("Close"), container.NewBorder(top, bottom, nil, nil, scroll), timer.window).Show()
+}
+
+// showExchangeRatesDialog edits the rate of each client's currency against
+// the home currency by hand. saved is called after they're stored.
+func showExchangeRatesDialog(timer *TaskTimer, saved func()) {
+	home := homeCurrency()
+	rates := exchangeRates()
+
+	var currencies []string
+	for _, settings := range clientSettings() {
+		currency := clientCurrency(settings)
+		if currency != home && !contains(currencies, currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	if len(currencies) == 0 {
+		dialog.ShowInformation(tr("Exchange Rates"), tr("Every client is billed in {{.Currency}}. Set a client's currency under Clients.", map[string]any{"Currency": home}), timer.window)
+		return
+	}
+	sort.Strings(currencies)
+
+	inputs := make(map[string]*widget.Entry)
+	var items []*widget.FormItem
+	for _, currency := range currencies {
+		input := widget.NewEntry()
+		if rate, ok := rates.Convert(1, home, currency); ok {
+			input.SetText(strconv.FormatFloat(rate, 'f', -1, 64))
+		}
+		inputs[currency] = input
+		items = append(items, widget.NewFormItem(tr("1 {{.Home}} in {{.Currency}}", map[string]any{"Home": home, "Currency": currency}), input))
+	}
+
+	dialog.ShowForm(tr("Exchange Rates"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		edited := ExchangeRates{Base: home, Date: time.Now().Format("2006-01-02"), Rates: make(map[string]float64)}
+		for currency, input := range inputs {
+			rate, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(input.Text), ",", ".", 1), 64)
+			if err != nil || rate <= 0 {
+				continue
+			}
+			edited.Rates[currency] = rate
+		}
+		setExchangeRates(edited)
+		saved()
+	}, timer.window)
+}
+
+// writeEarningsCSV writes the earnings with amounts formatted for the export
+// locale.
+func writeEarningsCSV(w io.Writer, earnings []clientEarnings, home string, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+	if err := out.Write([]string{"client", "hours", "amount", "currency", "amount_" + strings.ToLower(home)}); err != nil {
+		return err
+	}
+	for _, e := range earnings {
+		converted := ""
+		if e.Converted {
+			converted = locale.FormatDecimal(e.Home)
+		}
+		if err := out.Write([]string{e.Client, locale.FormatDecimal(e.Hours), locale.FormatDecimal(e.Amount), e.Currency, converted}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
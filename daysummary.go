@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefDaySummaryEnabled = "daySummaryEnabled"
+	PrefDaySummaryTime    = "daySummaryTime"
+	PrefDaySummaryEmail   = "daySummaryEmail"
+
+	// PrefLastDaySummary holds the day the summary was last sent, so it is
+	// sent once a day even across restarts
+	PrefLastDaySummary = "lastDaySummary"
+
+	DefaultDaySummaryTime = "18:00"
+
+	// DaySummaryCheckInterval is how often the scheduler checks whether the
+	// summary is due
+	DaySummaryCheckInterval = time.Minute
+)
+
+// daySummaryTimes are the times of day the summary can be sent at.
+func daySummaryTimes() []string {
+	var times []string
+	for minutes := 15 * 60; minutes <= 23*60; minutes += 30 {
+		times = append(times, fmt.Sprintf("%02d:%02d", minutes/60, minutes%60))
+	}
+	return times
+}
+
+// daySummaryDue returns when today's summary is due, in the configured
+// time of day.
+func daySummaryDue(now time.Time) time.Time {
+	clock, err := time.Parse("15:04", fyne.CurrentApp().Preferences().StringWithFallback(PrefDaySummaryTime, DefaultDaySummaryTime))
+	if err != nil {
+		clock, _ = time.Parse("15:04", DefaultDaySummaryTime)
+	}
+	return dayStart(now).Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+}
+
+// watchDaySummary sends the day's summary once it is due, checking every
+// DaySummaryCheckInterval. A summary missed while the app was closed is
+// sent when it next runs the same day.
+func watchDaySummary(timer *TaskTimer) {
+	go func() {
+		for range time.Tick(DaySummaryCheckInterval) {
+			fyne.Do(func() {
+				maybeSendDaySummary(timer, time.Now())
+			})
+		}
+	}()
+}
+
+func maybeSendDaySummary(timer *TaskTimer, now time.Time) {
+	prefs := fyne.CurrentApp().Preferences()
+	today := dayStart(now).Format("2006-01-02")
+	if !prefs.Bool(PrefDaySummaryEnabled) || !timer.loaded || now.Before(daySummaryDue(now)) || prefs.String(PrefLastDaySummary) == today {
+		return
+	}
+	prefs.SetString(PrefLastDaySummary, today)
+
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(tr("Today's Summary"), daySummaryText(timer, now)))
+	showDaySummary(timer)
+}
+
+// daySummaryTotals totals today's time per task, counting the session in
+// progress.
+func daySummaryTotals(timer *TaskTimer, now time.Time) map[string]time.Duration {
+	totals := totalsBetween(timer, dayStart(now), addDays(now, 1))
+	if session := sessionEntry(timer); session.Duration > 0 {
+		totals[session.Task] += session.Duration
+	}
+	return totals
+}
+
+// daySummaryText is the day's report as plain text, for the notification,
+// the clipboard and email.
+func daySummaryText(timer *TaskTimer, now time.Time) string {
+	totals := daySummaryTotals(timer, now)
+	var total time.Duration
+	for _, duration := range totals {
+		total += duration
+	}
+
+	lines := []string{tr("{{.Day}}: {{.Total}} tracked", map[string]any{
+		"Day":   formatDate(now),
+		"Total": formatDuration(total),
+	})}
+	if len(totals) == 0 {
+		lines = append(lines, tr("Nothing was tracked."))
+	}
+	for _, taskName := range sortedTaskNames(totals) {
+		lines = append(lines, fmt.Sprintf("%s: %s", taskName, formatDuration(totals[taskName])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// showDaySummary shows today's totals with buttons to export the day's
+// entries, email the report or copy it.
+func showDaySummary(timer *TaskTimer) {
+	now := time.Now()
+	text := daySummaryText(timer, now)
+
+	exportBtn := widget.NewButton(tr("Export CSV…"), func() {
+		saveExport(timer, "gotime-"+now.Format("2006-01-02")+".csv", func(w io.Writer) error {
+			entries := entriesBetween(timer, dayStart(now), addDays(now, 1))
+			return writeEntriesCSV(w, roundEntries(entries), currentExportLocale())
+		})
+	})
+	emailBtn := widget.NewButton(tr("Email…"), func() {
+		if err := fyne.CurrentApp().OpenURL(daySummaryMailto(text)); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	})
+	copyBtn := widget.NewButton(tr("Copy"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(text)
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(text),
+		container.NewHBox(exportBtn, emailBtn, copyBtn),
+	)
+	dialog.ShowCustom(tr("Today's Summary"), tr("Close"), content, timer.window)
+}
+
+// daySummaryMailto opens a new email with the report, to the address set
+// in settings if there is one.
+func daySummaryMailto(text string) *url.URL {
+	query := url.Values{}
+	query.Set("subject", tr("Time tracked on {{.Day}}", map[string]any{"Day": formatDate(time.Now())}))
+	query.Set("body", text)
+	return &url.URL{
+		Scheme: "mailto",
+		Opaque: fyne.CurrentApp().Preferences().String(PrefDaySummaryEmail),
+		// Mail clients read + literally, so spaces are sent as %20
+		RawQuery: strings.ReplaceAll(query.Encode(), "+", "%20"),
+	}
+}
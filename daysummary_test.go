@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestDaySummaryMailto(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	text := "Code: 2h 30m\nC++ & Go: 1h"
+
+	for _, address := range []string{"lead@example.com", ""} {
+		prefs.SetString(PrefDaySummaryEmail, address)
+		u := daySummaryMailto(text)
+		if prefix := "mailto:" + address + "?"; !strings.HasPrefix(u.String(), prefix) {
+			t.Errorf("%s doesn't start with %s", u, prefix)
+		}
+		// Mail clients would show a + as is
+		if strings.Contains(u.RawQuery, "+") {
+			t.Errorf("%s encodes spaces as +", u)
+		}
+		query, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := query.Get("body"); got != text {
+			t.Errorf("body %q, want %q", got, text)
+		}
+		if got := query.Get("subject"); !strings.Contains(got, formatDate(time.Now())) {
+			t.Errorf("subject %q doesn't name today", got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+//go:build linux
+
+package main
+
+import (
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+const (
+	// DBusName is the name the app takes on the session bus, and DBusPath
+	// the object its interface of the same name lives on
+	DBusName = "org.gotime.Tracker"
+	DBusPath = dbus.ObjectPath("/org/gotime/Tracker")
+)
+
+// dbusConn is the session bus connection the service runs on, once it has
+// started.
+var dbusConn atomic.Pointer[dbus.Conn]
+
+// DBusTracker is the org.gotime.Tracker interface, for desktop shell
+// extensions, panel widgets and status bars:
+//
+//	Start(task)   starts the timer, on task if it isn't empty
+//	Stop()        pauses the timer
+//	Switch(task)  moves the timer to task
+//	Status()      → task, running, elapsed seconds
+//
+// StateChanged(task, running, elapsed seconds) is sent whenever the timer
+// starts, stops, switches task or is adjusted. An empty task means none is
+// selected.
+type DBusTracker struct {
+	timer *TaskTimer
+}
+
+func (t *DBusTracker) Start(taskName string) *dbus.Error {
+	var err error
+	fyne.DoAndWait(func() {
+		err = startRemotely(t.timer, strings.TrimSpace(taskName))
+	})
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+func (t *DBusTracker) Stop() *dbus.Error {
+	fyne.DoAndWait(func() {
+		if t.timer.isRunning {
+			pauseTimer(t.timer)
+		}
+	})
+	return nil
+}
+
+func (t *DBusTracker) Switch(taskName string) *dbus.Error {
+	taskName = strings.TrimSpace(taskName)
+	if taskName == "" {
+		return dbus.MakeFailedError(errTaskNameEmpty)
+	}
+	fyne.DoAndWait(func() {
+		switchTask(t.timer, taskName)
+	})
+	return nil
+}
+
+func (t *DBusTracker) Status() (string, bool, int64, *dbus.Error) {
+	var status Status
+	fyne.DoAndWait(func() {
+		status = timerStatus(t.timer)
+	})
+	return status.Task, status.Running, int64(status.Elapsed.Seconds()), nil
+}
+
+// startDBusService publishes the tracker on the session bus. Without a
+// session bus, or with another instance of the app holding the name, the
+// app goes on without it.
+func startDBusService(timer *TaskTimer) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		log.Printf("dbus: session bus unavailable: %v", err)
+		return
+	}
+
+	tracker := &DBusTracker{timer: timer}
+	node := &introspect.Node{
+		Name: string(DBusPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name: DBusName,
+				Methods: []introspect.Method{
+					{Name: "Start", Args: []introspect.Arg{{Name: "task", Type: "s", Direction: "in"}}},
+					{Name: "Stop"},
+					{Name: "Switch", Args: []introspect.Arg{{Name: "task", Type: "s", Direction: "in"}}},
+					{Name: "Status", Args: []introspect.Arg{
+						{Name: "task", Type: "s", Direction: "out"},
+						{Name: "running", Type: "b", Direction: "out"},
+						{Name: "elapsed", Type: "x", Direction: "out"},
+					}},
+				},
+				Signals: []introspect.Signal{{
+					Name: "StateChanged",
+					Args: []introspect.Arg{
+						{Name: "task", Type: "s"},
+						{Name: "running", Type: "b"},
+						{Name: "elapsed", Type: "x"},
+					},
+				}},
+			},
+		},
+	}
+	if err := conn.Export(tracker, DBusPath, DBusName); err != nil {
+		log.Printf("dbus: %v", err)
+		return
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), DBusPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		log.Printf("dbus: %v", err)
+		return
+	}
+
+	reply, err := conn.RequestName(DBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		log.Printf("dbus: %v", err)
+		return
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		log.Printf("dbus: %s is taken, another instance is running", DBusName)
+		return
+	}
+	dbusConn.Store(conn)
+}
+
+// emitDBusState sends StateChanged, once the service has started.
+func emitDBusState(status Status) {
+	conn := dbusConn.Load()
+	if conn == nil {
+		return
+	}
+	if err := conn.Emit(DBusPath, DBusName+".StateChanged", status.Task, status.Running, int64(status.Elapsed.Seconds())); err != nil {
+		log.Printf("dbus: %v", err)
+	}
+}
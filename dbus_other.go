@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// startDBusService has nothing to do where there's no session bus.
+func startDBusService(timer *TaskTimer) {}
+
+func emitDBusState(status Status) {}
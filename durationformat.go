@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefDurationFormat = "durationFormat"
+
+	// PrefPrecisionMode shows tenths of a second, for timing short
+	// experiments, and exports durations in milliseconds
+	PrefPrecisionMode = "precisionMode"
+
+	DurationFormatClock   = "clock"
+	DurationFormatDecimal = "decimal"
+	DurationFormatHuman   = "human"
+)
+
+var DurationFormats = []string{DurationFormatClock, DurationFormatDecimal, DurationFormatHuman}
+
+// durationFormat is the format durations are shown in, and precisionMode
+// whether the clock shows tenths of a second. The ticker reads it
+// while the settings may change it, and the command line leaves it unset.
+var (
+	durationFormat atomic.Value
+	precisionMode  atomic.Bool
+)
+
+// applyDurationFormat shows durations in the configured format from now on.
+func applyDurationFormat(prefs fyne.Preferences) {
+	durationFormat.Store(prefs.StringWithFallback(PrefDurationFormat, DurationFormatClock))
+}
+
+// applyPrecisionMode shows tenths of a second and exports milliseconds
+// from now on if precision mode is on.
+func applyPrecisionMode(prefs fyne.Preferences) {
+	precisionMode.Store(prefs.Bool(PrefPrecisionMode))
+}
+
+func currentDurationFormat() string {
+	if format, ok := durationFormat.Load().(string); ok {
+		return format
+	}
+	return DurationFormatClock
+}
+
+// durationFormatName names a format for the settings, by example.
+func durationFormatName(format string) string {
+	d := time.Hour + 45*time.Minute
+	switch format {
+	case DurationFormatDecimal:
+		return formatDecimalDuration(d)
+	case DurationFormatHuman:
+		return formatHumanDuration(d)
+	default:
+		return formatClockDuration(d)
+	}
+}
+
+// formatDuration renders a duration in the chosen format: 01:45:00, 1.75h
+// or 1h 45m.
+func formatDuration(d time.Duration) string {
+	switch currentDurationFormat() {
+	case DurationFormatDecimal:
+		return formatDecimalDuration(d)
+	case DurationFormatHuman:
+		return formatHumanDuration(d)
+	default:
+		return formatClockDuration(d)
+	}
+}
+
+// formatClockDuration renders a duration as HH:MM:SS, with tenths of a
+// second in precision mode.
+func formatClockDuration(d time.Duration) string {
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+	if precisionMode.Load() {
+		tenths := (d % time.Second) / (100 * time.Millisecond)
+		return fmt.Sprintf("%02d:%02d:%02d.%d", hours, minutes, seconds, tenths)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
+
+// formatDecimalDuration renders a duration in hours to two decimals, e.g.
+// 1.75h.
+func formatDecimalDuration(d time.Duration) string {
+	return tr("{{.Hours}}h", map[string]any{"Hours": strconv.FormatFloat(d.Hours(), 'f', 2, 64)})
+}
+
+// formatHumanDuration renders a duration in hours and minutes, e.g. 1h 45m,
+// and in seconds under a minute.
+func formatHumanDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return tr("{{.Seconds}}s", map[string]any{"Seconds": int(d / time.Second)})
+	case d < time.Hour:
+		return tr("{{.Minutes}}m", map[string]any{"Minutes": int(d / time.Minute)})
+	case d%time.Hour < time.Minute:
+		return tr("{{.Hours}}h", map[string]any{"Hours": int(d / time.Hour)})
+	}
+	return tr("{{.Hours}}h", map[string]any{"Hours": int(d / time.Hour)}) + " " +
+		tr("{{.Minutes}}m", map[string]any{"Minutes": int(d % time.Hour / time.Minute)})
+}
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a single recorded session of work on a task. IDs are random, so
+// entries recorded on different devices never collide, and Updated tells
+// which copy of an entry edited on two devices is newer. NonBillable entries
+// are left off invoices; entries are billable unless marked, which is what
+// every entry recorded before the flag existed was. AutoStopped entries were
+// stopped at the end of the day rather than by the user, and are flagged in
+// History until reviewed; Review names any other reason an entry waits for
+// review, such as having been imported. Adjustment is how much the duration
+// was corrected by hand while the session ran, e.g. -5m for an interruption.
+type Entry struct {
+	ID          string
+	Task        string
+	Project     string
+	Client      string
+	Start       time.Time
+	End         time.Time
+	Duration    time.Duration
+	Notes       string
+	Tags        []string
+	Updated     time.Time
+	NonBillable bool
+	AutoStopped bool
+	Adjustment  time.Duration
+	Review      string
+}
+
+var errEntryNotFound = errors.New("entry not found")
+
+// recordEntry stores a finished session and adds it to the task totals. It
+// returns the entry as stored, with its ID assigned.
+func recordEntry(timer *TaskTimer, entry Entry) Entry {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = rand.Text()
+	}
+	entry.Updated = time.Now()
+	timer.entries = append(timer.entries, entry)
+	addToTotalsLocked(timer, entry, 1)
+	storeEntry(timer, entry)
+	publishHistoryLocked(timer)
+	return entry
+}
+
+// updateEntry replaces the entry with the same ID and recalculates the totals.
+func updateEntry(timer *TaskTimer, entry Entry) error {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	i := entryIndexLocked(timer, entry.ID)
+	if i < 0 {
+		return errEntryNotFound
+	}
+	before := timer.entries[i]
+	addToTotalsLocked(timer, before, -1)
+	entry.Updated = time.Now()
+	timer.entries[i] = entry
+	addToTotalsLocked(timer, entry, 1)
+	storeEntry(timer, entry)
+	auditEntry(timer, entry.ID, AuditEdited, &before, &entry)
+	publishHistoryLocked(timer)
+	return nil
+}
+
+// deleteEntry removes an entry and recalculates the totals.
+func deleteEntry(timer *TaskTimer, id string) error {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	i := entryIndexLocked(timer, id)
+	if i < 0 {
+		return errEntryNotFound
+	}
+	before := timer.entries[i]
+	addToTotalsLocked(timer, before, -1)
+	timer.entries = append(timer.entries[:i], timer.entries[i+1:]...)
+	unstoreEntry(timer, id, time.Now())
+	auditEntry(timer, id, AuditDeleted, &before, nil)
+	publishHistoryLocked(timer)
+	return nil
+}
+
+// mergeEntries merges another device's entries into the current ones and
+// rebuilds the totals. It merges with the entries as they are now, so any
+// recorded, edited or deleted here while the other device's were on their
+// way are kept. An entry is only removed if a tombstone in the store shows it
+// was deleted after its last update, and only entries that changed are
+// written to the store. Edits and deletions taken over are added to the audit
+// log as made on device.
+func mergeEntries(timer *TaskTimer, entries []Entry, device string) error {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	tombstones, err := timer.store.Tombstones()
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]Entry, len(timer.entries))
+	for _, entry := range timer.entries {
+		previous[entry.ID] = entry
+	}
+	timer.entries = mergeEntrySets(tombstones, timer.entries, entries)
+	timer.taskList = make(map[string]time.Duration)
+	timer.dailyTotals = make(map[time.Time]map[string]time.Duration)
+	for _, entry := range timer.entries {
+		addToTotalsLocked(timer, entry, 1)
+		if old, ok := previous[entry.ID]; !ok || !old.Updated.Equal(entry.Updated) {
+			storeEntry(timer, entry)
+			if ok {
+				auditSyncedEntry(timer, device, entry.Updated, entry.ID, AuditEdited, &old, &entry)
+			}
+		}
+		delete(previous, entry.ID)
+	}
+	// Whatever is left was deleted elsewhere, when its tombstone says
+	for id, old := range previous {
+		unstoreEntry(timer, id, tombstones[id])
+		auditSyncedEntry(timer, device, tombstones[id], id, AuditDeleted, &old, nil)
+	}
+	publishHistoryLocked(timer)
+	return nil
+}
+
+// splitEntry divides an entry at a point in time, assigning the second half
+// to another task. Tracked time is split at the same point, with any paused
+// time left in the second half.
+func splitEntry(timer *TaskTimer, id string, at time.Time, secondTask string) error {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	i := entryIndexLocked(timer, id)
+	if i < 0 {
+		return errEntryNotFound
+	}
+	entry := timer.entries[i]
+	if !at.After(entry.Start) || !at.Before(entry.End) {
+		return errors.New("the split time must fall inside the entry")
+	}
+	first, second := cutEntry(entry, at)
+	second.Task = secondTask
+	first.Updated, second.Updated = time.Now(), time.Now()
+
+	addToTotalsLocked(timer, timer.entries[i], -1)
+	timer.entries[i] = first
+	timer.entries = append(timer.entries, second)
+	addToTotalsLocked(timer, first, 1)
+	addToTotalsLocked(timer, second, 1)
+	storeEntry(timer, first)
+	storeEntry(timer, second)
+	auditEntry(timer, first.ID, AuditSplit, &entry, &first)
+	auditEntry(timer, second.ID, AuditSplit, &entry, &second)
+	publishHistoryLocked(timer)
+	return nil
+}
+
+// cutEntry divides an entry at a point inside it. Tracked time is split at
+// the same point, with any paused time left in the second part, which gets
+// a new ID. An adjustment stays with the first part.
+func cutEntry(entry Entry, at time.Time) (first, second Entry) {
+	first, second = entry, entry
+	second.ID = rand.Text()
+	second.Start = at
+	second.Tags = slices.Clone(entry.Tags)
+	second.Adjustment = 0
+
+	first.End = at
+	first.Duration = min(entry.Duration, at.Sub(entry.Start))
+	second.Duration -= first.Duration
+	return first, second
+}
+
+// splitAtMidnight cuts an entry that spans midnight into one entry per
+// calendar day, so each day's totals only count time worked on that day.
+func splitAtMidnight(entry Entry) []Entry {
+	var parts []Entry
+	for {
+		midnight := addDays(entry.Start, 1)
+		if !midnight.Before(entry.End) {
+			return append(parts, entry)
+		}
+		var first Entry
+		first, entry = cutEntry(entry, midnight)
+		parts = append(parts, first)
+	}
+}
+
+func entryIndexLocked(timer *TaskTimer, id string) int {
+	return entryIndex(timer.entries, id)
+}
+
+func entryIndex(entries []Entry, id string) int {
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// addToTotalsLocked adds an entry to, or with sign -1 removes it from, the
+// task totals and the daily totals. Keeping the aggregates up to date as
+// entries change means views never have to sum the raw entries.
+func addToTotalsLocked(timer *TaskTimer, entry Entry, sign time.Duration) {
+	adjust := func(totals map[string]time.Duration) {
+		totals[entry.Task] += sign * entry.Duration
+		if totals[entry.Task] == 0 {
+			delete(totals, entry.Task)
+		}
+	}
+	adjust(timer.taskList)
+
+	day := dayStart(entry.Start)
+	if timer.dailyTotals[day] == nil {
+		timer.dailyTotals[day] = make(map[string]time.Duration)
+	}
+	adjust(timer.dailyTotals[day])
+	if len(timer.dailyTotals[day]) == 0 {
+		delete(timer.dailyTotals, day)
+	}
+}
+
+// totalsBetween sums the daily totals per task for the days in [from, to).
+// Entries count towards the day they started on.
+func totalsBetween(timer *TaskTimer, from, to time.Time) map[string]time.Duration {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	totals := make(map[string]time.Duration)
+	for day := dayStart(from); day.Before(to); day = addDays(day, 1) {
+		for taskName, duration := range timer.dailyTotals[day] {
+			totals[taskName] += duration
+		}
+	}
+	return totals
+}
+
+// dailyTotal returns the time tracked on each day in [from, to) across all
+// tasks, keyed by the day's local midnight. Days without time are left out.
+func dailyTotal(timer *TaskTimer, from, to time.Time) map[time.Time]time.Duration {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	days := make(map[time.Time]time.Duration)
+	for day := dayStart(from); day.Before(to); day = addDays(day, 1) {
+		for _, duration := range timer.dailyTotals[day] {
+			days[day] += duration
+		}
+	}
+	return days
+}
+
+// importEntries records historical entries, e.g. from another tracker, and
+// makes their tasks available in the selector. They wait for review.
+func importEntries(timer *TaskTimer, entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Start.Before(entries[j].Start)
+	})
+
+	for _, entry := range entries {
+		entry.Review = ReviewImported
+		recordEntry(timer, entry)
+		timer.tasks.Ensure(entry.Task)
+	}
+}
+
+// entriesForTask returns a copy of the entries recorded against a task.
+func entriesForTask(timer *TaskTimer, taskName string) []Entry {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	var entries []Entry
+	for _, entry := range timer.entries {
+		if entry.Task == taskName {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// allEntries returns a copy of every recorded entry.
+func allEntries(timer *TaskTimer) []Entry {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	return append([]Entry(nil), timer.entries...)
+}
+
+// entriesBetween returns a copy of the entries that started in [from, to).
+func entriesBetween(timer *TaskTimer, from, to time.Time) []Entry {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	var entries []Entry
+	for _, entry := range timer.entries {
+		if !entry.Start.Before(from) && entry.Start.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// matchesQuery reports whether an entry's task or notes contain the query,
+// ignoring case.
+func matchesQuery(entry Entry, query string) bool {
+	query = strings.ToLower(query)
+	return strings.Contains(strings.ToLower(entry.Task), query) ||
+		strings.Contains(strings.ToLower(entry.Notes), query)
+}
+
+// AllProjects and AllTags are the filter options that don't filter.
+const (
+	AllProjects = "All projects"
+	AllTags     = "All tags"
+)
+
+// EntryFilter narrows entries down by task, project and date. Zero fields
+// don't filter.
+type EntryFilter struct {
+	// Query fuzzy-matches the task name
+	Query   string
+	Project string
+	Tag     string
+	// From and To bound the start time to [From, To)
+	From time.Time
+	To   time.Time
+}
+
+func (f EntryFilter) IsZero() bool {
+	return strings.TrimSpace(f.Query) == "" && f.Project == "" && f.Tag == "" && f.From.IsZero() && f.To.IsZero()
+}
+
+func (f EntryFilter) Match(entry Entry) bool {
+	return fuzzyMatch(f.Query, entry.Task) &&
+		(f.Project == "" || entry.Project == f.Project) &&
+		(f.Tag == "" || contains(entry.Tags, f.Tag)) &&
+		(f.From.IsZero() || !entry.Start.Before(f.From)) &&
+		(f.To.IsZero() || entry.Start.Before(f.To))
+}
+
+// Previous is the filter moved back to the period of the same length just
+// before, for comparing with. It needs both ends of the period.
+func (f EntryFilter) Previous() (EntryFilter, bool) {
+	if f.From.IsZero() || f.To.IsZero() {
+		return f, false
+	}
+	// Counted in days, which Sub isn't across a change of the clocks
+	days := int(f.To.Sub(f.From).Hours()/24 + 0.5)
+	f.From, f.To = addDays(f.From, -days), f.From
+	return f, true
+}
+
+// Apply returns the entries that match the filter.
+func (f EntryFilter) Apply(entries []Entry) []Entry {
+	var matched []Entry
+	for _, entry := range entries {
+		if f.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// knownProjects lists the projects used on any entry, and those shared with
+// the team.
+func knownProjects(timer *TaskTimer) []string {
+	projects := teamProjects()
+	for _, entry := range allEntries(timer) {
+		if entry.Project != "" && !contains(projects, entry.Project) {
+			projects = append(projects, entry.Project)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// knownTags lists the tags used on any entry.
+func knownTags(timer *TaskTimer) []string {
+	var tags []string
+	for _, entry := range allEntries(timer) {
+		for _, tag := range entry.Tags {
+			if !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// totalsByTask sums entry durations per task.
+func totalsByTask(entries []Entry) map[string]time.Duration {
+	totals := make(map[string]time.Duration)
+	for _, entry := range entries {
+		totals[entry.Task] += entry.Duration
+	}
+	return totals
+}
+
+// renameTask moves all entries and totals from one task name to another.
+// Renaming onto an existing task merges the two.
+func renameTask(timer *TaskTimer, from, to string) {
+	if from == to {
+		return
+	}
+
+	timer.taskListMutex.Lock()
+	for i := range timer.entries {
+		if timer.entries[i].Task == from {
+			before := timer.entries[i]
+			timer.entries[i].Task = to
+			timer.entries[i].Updated = time.Now()
+			storeEntry(timer, timer.entries[i])
+			auditEntry(timer, before.ID, AuditEdited, &before, &timer.entries[i])
+		}
+	}
+	moveTotal := func(totals map[string]time.Duration) {
+		if duration, ok := totals[from]; ok {
+			totals[to] += duration
+			delete(totals, from)
+		}
+	}
+	moveTotal(timer.taskList)
+	for _, totals := range timer.dailyTotals {
+		moveTotal(totals)
+	}
+	publishHistoryLocked(timer)
+	timer.taskListMutex.Unlock()
+
+	// Moved first, so the renamed task is listed with its icon
+	renameTaskStyle(timer, from, to)
+
+	// Before the task list learns the new name, which decides whether a
+	// merge keeps the target's default
+	renameTaskInternal(timer, from, to)
+
+	// Keep the running session pointing at the new name
+	timer.tasks.Rename(from, to)
+	renameTaskEstimate(timer, from, to)
+	renameGitHubTaskIssue(from, to)
+	if timer.taskName == from {
+		timer.taskPicker.SetSelected(to)
+	}
+}
+
+// writeEntriesCSV writes entries with a header row to w, formatted for the
+// given export locale.
+func writeEntriesCSV(w io.Writer, entries []Entry, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+
+	// Raw milliseconds in precision mode are named the same in every locale
+	columns := []string{"task", "project", "client", "start", "end", "duration", "notes"}
+	if precisionMode.Load() {
+		columns[5] = "duration_ms"
+	}
+	if isoWeeks() {
+		columns = append(columns, "week")
+	}
+	var header []string
+	for _, key := range columns {
+		header = append(header, locale.Header(key))
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			entry.Task,
+			entry.Project,
+			entry.Client,
+			locale.FormatTime(entry.Start),
+			locale.FormatTime(entry.End),
+			locale.FormatDuration(entry.Duration),
+			entry.Notes,
+		}
+		if isoWeeks() {
+			record = append(record, isoWeekLabel(entry.Start))
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+)
+
+const PrefTaskEstimates = "taskEstimates"
+
+// TaskEstimates are the estimated total durations per task, compared with
+// the time actually tracked on the task over all time.
+type TaskEstimates map[string]time.Duration
+
+func loadTaskEstimates() TaskEstimates {
+	estimates := TaskEstimates{}
+	raw := fyne.CurrentApp().Preferences().String(PrefTaskEstimates)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &estimates); err != nil {
+			log.Printf("estimates: reading estimates: %v", err)
+		}
+	}
+	return estimates
+}
+
+// saveTaskEstimates saves the estimates and hands them to bound views.
+func saveTaskEstimates(timer *TaskTimer, estimates TaskEstimates) {
+	raw, err := json.Marshal(estimates)
+	if err != nil {
+		log.Printf("estimates: saving estimates: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefTaskEstimates, string(raw))
+	timer.estimates.Set(estimates)
+}
+
+// taskEstimates returns the bound estimates, which views must not modify.
+func taskEstimates(timer *TaskTimer) TaskEstimates {
+	estimates, _ := timer.estimates.Get()
+	return estimates
+}
+
+// setTaskEstimate sets a task's estimate, or removes it if estimate isn't
+// positive.
+func setTaskEstimate(timer *TaskTimer, taskName string, estimate time.Duration) {
+	estimates := maps.Clone(taskEstimates(timer))
+	if estimate > 0 {
+		estimates[taskName] = estimate
+	} else {
+		delete(estimates, taskName)
+	}
+	saveTaskEstimates(timer, estimates)
+}
+
+// renameTaskEstimate moves an estimate along with a renamed task. A task
+// merged into one with its own estimate keeps the target's.
+func renameTaskEstimate(timer *TaskTimer, from, to string) {
+	estimates := maps.Clone(taskEstimates(timer))
+	estimate, ok := estimates[from]
+	if !ok {
+		return
+	}
+	if _, exists := estimates[to]; !exists {
+		estimates[to] = estimate
+	}
+	delete(estimates, from)
+	saveTaskEstimates(timer, estimates)
+}
+
+// parseHours reads a number of hours, with either decimal mark, e.g. "1.5"
+// or "1,5".
+func parseHours(text string) (time.Duration, error) {
+	hours, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(text), ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hours * float64(time.Hour)), nil
+}
+
+// formatHours writes a duration as a number of hours for an input.
+func formatHours(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', -1, 64)
+}
+
+// formatVariance describes how far actual is from estimate, e.g.
+// "+30m (+13%)" when over and "-1h (-25%)" when under.
+func formatVariance(actual, estimate time.Duration) string {
+	variance := actual - estimate
+	sign := "+"
+	if variance < 0 {
+		sign = "-"
+		variance = -variance
+	}
+	percent := 0
+	if estimate > 0 {
+		percent = int(100 * variance / estimate)
+	}
+	return fmt.Sprintf("%s%s (%s%d%%)", sign, formatShortDuration(variance.Truncate(time.Minute)), sign, percent)
+}
+
+// trackedOnTask totals the time recorded against a task.
+func trackedOnTask(entries []Entry, taskName string) time.Duration {
+	var total time.Duration
+	for _, entry := range entries {
+		if entry.Task == taskName {
+			total += entry.Duration
+		}
+	}
+	return total
+}
+
+// createEstimatesContainer compares each estimated task's tracked time with
+// its estimate, for the stats view. It follows the history while shown.
+func createEstimatesContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	render := func() {
+		entries, _ := timer.history.Get()
+		estimates := taskEstimates(timer)
+		taskNames := make([]string, 0, len(estimates))
+		for taskName := range estimates {
+			taskNames = append(taskNames, taskName)
+		}
+		slices.Sort(taskNames)
+
+		list.RemoveAll()
+		for _, taskName := range taskNames {
+			estimate := estimates[taskName]
+			actual := trackedOnTask(entries, taskName)
+			if timer.taskName == taskName {
+				actual += timer.elapsedTime
+			}
+			label := widget.NewLabel(tr("{{.Task}}: {{.Actual}} of {{.Estimate}} estimated, {{.Variance}}", map[string]any{
+				"Task":     taskName,
+				"Actual":   formatDuration(actual),
+				"Estimate": formatShortDuration(estimate),
+				"Variance": formatVariance(actual, estimate),
+			}))
+			label.Truncation = fyne.TextTruncateEllipsis
+			list.Add(label)
+		}
+	}
+	listenWhileShown(timer, timer.history, render)
+	listenWhileShown(timer, timer.estimates, render)
+	return list
+}
+
+// createEstimateProgress is a thin bar under the clock showing how much of
+// the selected task's estimate is used, counting the session in progress.
+// It is hidden for tasks without an estimate.
+func createEstimateProgress(timer *TaskTimer) fyne.CanvasObject {
+	bar := widget.NewProgressBar()
+	bar.Hide()
+
+	// The history total only changes with the history or the task, not
+	// with every tick
+	var tracked, estimate time.Duration
+	render := func() {
+		if estimate <= 0 {
+			bar.Hide()
+			return
+		}
+		actual := tracked + timer.elapsedTime
+		bar.Max = float64(estimate)
+		bar.TextFormatter = func() string {
+			return tr("{{.Actual}} of {{.Estimate}} estimated", map[string]any{
+				"Actual":   formatDuration(actual),
+				"Estimate": formatShortDuration(estimate),
+			})
+		}
+		bar.SetValue(float64(min(actual, estimate)))
+		bar.Show()
+	}
+	recount := func() {
+		entries, _ := timer.history.Get()
+		tracked = trackedOnTask(entries, timer.taskName)
+		estimate = taskEstimates(timer)[timer.taskName]
+		render()
+	}
+	timer.history.AddListener(binding.NewDataListener(recount))
+	timer.taskTitle.AddListener(binding.NewDataListener(recount))
+	timer.estimates.AddListener(binding.NewDataListener(recount))
+	timer.elapsed.AddListener(binding.NewDataListener(render))
+	return bar
+}
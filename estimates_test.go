@@ -0,0 +1,58 @@
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTaskEstimates(t *testing.T) {
+	test.NewTempApp(t)
+	timer := &TaskTimer{}
+	newTimerBindings(timer)
+	check := func(step string, want TaskEstimates) {
+		t.Helper()
+		if got := taskEstimates(timer); !maps.Equal(got, want) {
+			t.Errorf("%s: estimates %v, want %v", step, got, want)
+		}
+		if saved := loadTaskEstimates(); !maps.Equal(saved, want) {
+			t.Errorf("%s: saved %v, want %v", step, saved, want)
+		}
+	}
+
+	setTaskEstimate(timer, "Design", 4*time.Hour)
+	setTaskEstimate(timer, "Build", 10*time.Hour)
+	setTaskEstimate(timer, "Test", 2*time.Hour)
+	check("setting", TaskEstimates{"Design": 4 * time.Hour, "Build": 10 * time.Hour, "Test": 2 * time.Hour})
+	setTaskEstimate(timer, "Test", 0)
+	check("clearing", TaskEstimates{"Design": 4 * time.Hour, "Build": 10 * time.Hour})
+
+	renameTaskEstimate(timer, "Design", "UX")
+	check("renaming", TaskEstimates{"UX": 4 * time.Hour, "Build": 10 * time.Hour})
+	// Merged into a task with its own estimate, which it keeps
+	renameTaskEstimate(timer, "UX", "Build")
+	check("merging", TaskEstimates{"Build": 10 * time.Hour})
+	renameTaskEstimate(timer, "Docs", "Build")
+	check("renaming a task without an estimate", TaskEstimates{"Build": 10 * time.Hour})
+}
+
+func TestFormatVariance(t *testing.T) {
+	tests := []struct {
+		actual, estimate time.Duration
+		want             string
+	}{
+		{4*time.Hour + 30*time.Minute, 4 * time.Hour, "+30m (+12%)"},
+		{3 * time.Hour, 4 * time.Hour, "-1h (-25%)"},
+		{4 * time.Hour, 4 * time.Hour, "+0m (+0%)"},
+		// Seconds are dropped
+		{time.Hour + 90*time.Second, time.Hour, "+1m (+2%)"},
+		{2 * time.Hour, 0, "+2h (+0%)"},
+	}
+	for _, tc := range tests {
+		if got := formatVariance(tc.actual, tc.estimate); got != tc.want {
+			t.Errorf("formatVariance(%v, %v) = %q, want %q", tc.actual, tc.estimate, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefExpenses        = "expenses"
+	PrefExpenseCurrency = "expenseCurrency"
+)
+
+// Expense is a reimbursable cost logged against a project and client, with
+// an optional copy of the receipt kept in the app's storage.
+type Expense struct {
+	ID          string    `json:"id"`
+	Date        time.Time `json:"date"`
+	Project     string    `json:"project"`
+	Client      string    `json:"client"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Receipt     string    `json:"receipt,omitempty"`
+}
+
+func expenses() []Expense {
+	var list []Expense
+	raw := fyne.CurrentApp().Preferences().String(PrefExpenses)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &list); err != nil {
+			log.Printf("expenses: reading expenses: %v", err)
+		}
+	}
+	return list
+}
+
+func setExpenses(list []Expense) {
+	raw, err := json.Marshal(list)
+	if err != nil {
+		log.Printf("expenses: saving expenses: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefExpenses, string(raw))
+}
+
+// expensesForClient returns a client's expenses dated in [from, to), oldest
+// first.
+func expensesForClient(client string, from, to time.Time) []Expense {
+	var list []Expense
+	for _, expense := range expenses() {
+		if expense.Client == client && !expense.Date.Before(from) && expense.Date.Before(to) {
+			list = append(list, expense)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Date.Before(list[j].Date)
+	})
+	return list
+}
+
+// deleteExpense removes an expense and its stored receipt.
+func deleteExpense(id string) {
+	var kept []Expense
+	for _, expense := range expenses() {
+		if expense.ID != id {
+			kept = append(kept, expense)
+			continue
+		}
+		if expense.Receipt != "" {
+			if uri, err := storage.ParseURI(expense.Receipt); err == nil {
+				if err := storage.Delete(uri); err != nil {
+					log.Printf("expenses: deleting receipt: %v", err)
+				}
+			}
+		}
+	}
+	setExpenses(kept)
+}
+
+// storeReceipt copies a receipt into the app's storage so the expense keeps
+// it even if the original file is moved.
+func storeReceipt(id string, reader fyne.URIReadCloser) (string, error) {
+	defer reader.Close()
+
+	name := "receipt-" + id + strings.ToLower(reader.URI().Extension())
+	writer, err := fyne.CurrentApp().Storage().Create(name)
+	if err != nil {
+		return "", fmt.Errorf("expenses: saving receipt: %w", err)
+	}
+	defer writer.Close()
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		return "", fmt.Errorf("expenses: saving receipt: %w", err)
+	}
+	return writer.URI().String(), nil
+}
+
+// showLogExpenseDialog records a new expense.
+func showLogExpenseDialog(timer *TaskTimer, onSaved func()) {
+	prefs := fyne.CurrentApp().Preferences()
+
+	dateInput := widget.NewEntry()
+	dateInput.SetText(time.Now().Format("2006-01-02"))
+	var clients []string
+	if !guestMode() {
+		clients = knownClients(timer)
+	}
+	clientInput := widget.NewSelectEntry(clients)
+	projectInput := widget.NewEntry()
+	descriptionInput := widget.NewEntry()
+	amountInput := widget.NewEntry()
+	amountInput.PlaceHolder = tr("0.00")
+	currencyInput := widget.NewEntry()
+	currencyInput.PlaceHolder = "EUR"
+	currencyInput.SetText(prefs.String(PrefExpenseCurrency))
+
+	var receipt fyne.URIReadCloser
+	receiptLabel := widget.NewLabel(tr("None"))
+	receiptBtn := widget.NewButton(tr("Attach…"), func() {
+		d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			if receipt != nil {
+				receipt.Close()
+			}
+			receipt = reader
+			receiptLabel.SetText(reader.URI().Name())
+		}, timer.window)
+		d.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".pdf"}))
+		d.Show()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Date"), dateInput),
+		widget.NewFormItem(tr("Client"), clientInput),
+		widget.NewFormItem(tr("Project"), projectInput),
+		widget.NewFormItem(tr("Description"), descriptionInput),
+		widget.NewFormItem(tr("Amount"), amountInput),
+		widget.NewFormItem(tr("Currency"), currencyInput),
+		widget.NewFormItem(tr("Receipt"), container.NewBorder(nil, nil, nil, receiptBtn, receiptLabel)),
+	}
+	d := dialog.NewForm(tr("Log Expense"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			if receipt != nil {
+				receipt.Close()
+			}
+			return
+		}
+
+		date, err := time.ParseInLocation("2006-01-02", dateInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		amount, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(amountInput.Text), ",", ".", 1), 64)
+		if err != nil || amount <= 0 {
+			dialog.ShowInformation(tr("Log Expense"), tr("Enter the amount spent."), timer.window)
+			return
+		}
+
+		expense := Expense{
+			ID:          rand.Text(),
+			Date:        date,
+			Project:     strings.TrimSpace(projectInput.Text),
+			Client:      strings.TrimSpace(clientInput.Text),
+			Description: strings.TrimSpace(descriptionInput.Text),
+			Amount:      amount,
+			Currency:    strings.ToUpper(strings.TrimSpace(currencyInput.Text)),
+		}
+		if receipt != nil {
+			expense.Receipt, err = storeReceipt(expense.ID, receipt)
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+		}
+
+		prefs.SetString(PrefExpenseCurrency, expense.Currency)
+		setExpenses(append(expenses(), expense))
+		onSaved()
+	}, timer.window)
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+}
+
+// showExpensesDialog lists logged expenses, newest first, with a button to
+// log another.
+func showExpensesDialog(timer *TaskTimer) {
+	list := container.NewVBox()
+
+	var render func()
+	render = func() {
+		list.RemoveAll()
+		all := expenses()
+		sort.Slice(all, func(i, j int) bool {
+			return all[i].Date.After(all[j].Date)
+		})
+		if len(all) == 0 {
+			list.Add(widget.NewLabel(tr("No expenses logged")))
+		}
+
+		for _, expense := range all {
+			summary := fmt.Sprintf("%s  %s  %s %s",
+				formatDate(expense.Date), expense.Description,
+				guestText(strconv.FormatFloat(expense.Amount, 'f', 2, 64)), expense.Currency)
+			if expense.Client != "" {
+				summary += " · " + guestText(expense.Client)
+			}
+			if expense.Receipt != "" {
+				summary += " 📎"
+			}
+			label := widget.NewLabel(summary)
+			label.Truncation = fyne.TextTruncateEllipsis
+
+			id := expense.ID
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				dialog.ShowConfirm(tr("Delete Expense"), tr("Delete this expense and its receipt?"), func(ok bool) {
+					if ok {
+						deleteExpense(id)
+						render()
+					}
+				}, timer.window)
+			})
+			list.Add(container.NewBorder(nil, nil, nil, deleteBtn, label))
+		}
+	}
+	render()
+
+	logBtn := widget.NewButton(tr("Log expense…"), func() {
+		showLogExpenseDialog(timer, render)
+	})
+	d := dialog.NewCustom(tr("Expenses"), tr("Close"), container.NewBorder(nil, logBtn, nil, nil, container.NewVScroll(list)), timer.window)
+	d.Resize(fyne.NewSize(420, 420))
+	d.Show()
+}
+
+// receiptImage returns a stored receipt as a data URI if it's an image, so
+// it can be embedded in an invoice.
+func receiptImage(expense Expense) (string, bool) {
+	if expense.Receipt == "" {
+		return "", false
+	}
+	uri, err := storage.ParseURI(expense.Receipt)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(filepath.Ext(uri.Path())) {
+	case ".png", ".jpg", ".jpeg":
+	default:
+		return "", false
+	}
+
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		log.Printf("expenses: reading receipt: %v", err)
+		return "", false
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		log.Printf("expenses: reading receipt: %v", err)
+		return "", false
+	}
+	return dataURI(uri.Path(), raw), true
+}
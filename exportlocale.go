@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const PrefExportLocale = "exportLocale"
+
+// ExportLocale controls how numbers, dates and column headers are written to
+// exported files. It is independent of the UI language because exports often
+// go to clients in other countries.
+type ExportLocale struct {
+	Tag        string
+	Name       string
+	DateLayout string
+	DayLayout  string
+	Decimal    string
+	Separator  rune
+	Headers    map[string]string
+}
+
+// isoExportLocale is the default, machine-friendly format: RFC 3339 dates,
+// durations in seconds and untranslated headers.
+var isoExportLocale = ExportLocale{
+	Tag:        "",
+	Name:       "ISO (machine readable)",
+	DateLayout: time.RFC3339,
+	DayLayout:  "2006-01-02",
+	Separator:  ',',
+}
+
+var exportLocales = []ExportLocale{
+	isoExportLocale,
+	{
+		Tag:        "en-US",
+		Name:       "English (US)",
+		DateLayout: "01/02/2006 3:04 PM",
+		DayLayout:  "01/02/2006",
+		Decimal:    ".",
+		Separator:  ',',
+		Headers: map[string]string{
+			"task": "Task", "project": "Project", "client": "Client",
+			"start": "Start", "end": "End", "duration": "Hours",
+			"invoice": "Invoice", "date": "Date", "hours": "Hours",
+			"rate": "Rate", "amount": "Amount", "total": "Total",
+			"notes": "Notes", "expenses": "Expenses",
+			"description": "Description", "receipts": "Receipts",
+			"summary": "Summary", "noproject": "No project",
+			"timesheet": "Timesheet", "share": "Share", "week": "Week",
+			"number": "Invoice no.", "issued": "Date of issue", "due": "Due by",
+			"subtotal": "Subtotal", "tax": "Sales tax",
+		},
+	},
+	{
+		Tag:        "en-GB",
+		Name:       "English (UK)",
+		DateLayout: "02/01/2006 15:04",
+		DayLayout:  "02/01/2006",
+		Decimal:    ".",
+		Separator:  ',',
+		Headers: map[string]string{
+			"task": "Task", "project": "Project", "client": "Client",
+			"start": "Start", "end": "End", "duration": "Hours",
+			"invoice": "Invoice", "date": "Date", "hours": "Hours",
+			"rate": "Rate", "amount": "Amount", "total": "Total",
+			"notes": "Notes", "expenses": "Expenses",
+			"description": "Description", "receipts": "Receipts",
+			"summary": "Summary", "noproject": "No project",
+			"timesheet": "Timesheet", "share": "Share", "week": "Week",
+			"number": "Invoice no.", "issued": "Date of issue", "due": "Due by",
+			"subtotal": "Subtotal", "tax": "VAT",
+		},
+	},
+	{
+		Tag:        "de-DE",
+		Name:       "Deutsch",
+		DateLayout: "02.01.2006 15:04",
+		DayLayout:  "02.01.2006",
+		Decimal:    ",",
+		Separator:  ';',
+		Headers: map[string]string{
+			"task": "Aufgabe", "project": "Projekt", "client": "Kunde",
+			"start": "Beginn", "end": "Ende", "duration": "Stunden",
+			"invoice": "Rechnung", "date": "Datum", "hours": "Stunden",
+			"rate": "Satz", "amount": "Betrag", "total": "Summe",
+			"notes": "Notizen", "expenses": "Auslagen",
+			"description": "Beschreibung", "receipts": "Belege",
+			"summary": "Übersicht", "noproject": "Ohne Projekt",
+			"timesheet": "Stundenzettel", "share": "Anteil", "week": "KW",
+			"number": "Rechnungsnr.", "issued": "Rechnungsdatum", "due": "Zahlbar bis",
+			"subtotal": "Zwischensumme", "tax": "USt.",
+		},
+	},
+	{
+		Tag:        "fr-FR",
+		Name:       "Français",
+		DateLayout: "02/01/2006 15:04",
+		DayLayout:  "02/01/2006",
+		Decimal:    ",",
+		Separator:  ';',
+		Headers: map[string]string{
+			"task": "Tâche", "project": "Projet", "client": "Client",
+			"start": "Début", "end": "Fin", "duration": "Heures",
+			"invoice": "Facture", "date": "Date", "hours": "Heures",
+			"rate": "Taux", "amount": "Montant", "total": "Total",
+			"notes": "Notes", "expenses": "Frais",
+			"description": "Description", "receipts": "Justificatifs",
+			"summary": "Résumé", "noproject": "Sans projet",
+			"timesheet": "Feuille de temps", "share": "Part", "week": "Semaine",
+			"number": "Facture n°", "issued": "Date d'émission", "due": "À régler avant le",
+			"subtotal": "Sous-total", "tax": "TVA",
+		},
+	},
+	{
+		Tag:        "es-ES",
+		Name:       "Español",
+		DateLayout: "02/01/2006 15:04",
+		DayLayout:  "02/01/2006",
+		Decimal:    ",",
+		Separator:  ';',
+		Headers: map[string]string{
+			"task": "Tarea", "project": "Proyecto", "client": "Cliente",
+			"start": "Inicio", "end": "Fin", "duration": "Horas",
+			"invoice": "Factura", "date": "Fecha", "hours": "Horas",
+			"rate": "Tarifa", "amount": "Importe", "total": "Total",
+			"notes": "Notas", "expenses": "Gastos",
+			"description": "Descripción", "receipts": "Recibos",
+			"summary": "Resumen", "noproject": "Sin proyecto",
+			"timesheet": "Hoja de horas", "share": "Porcentaje", "week": "Semana",
+			"number": "Factura n.º", "issued": "Fecha de emisión", "due": "Vencimiento",
+			"subtotal": "Subtotal", "tax": "IVA",
+		},
+	},
+	{
+		Tag:        "nl-NL",
+		Name:       "Nederlands",
+		DateLayout: "02-01-2006 15:04",
+		DayLayout:  "02-01-2006",
+		Decimal:    ",",
+		Separator:  ';',
+		Headers: map[string]string{
+			"task": "Taak", "project": "Project", "client": "Klant",
+			"start": "Begin", "end": "Einde", "duration": "Uren",
+			"invoice": "Factuur", "date": "Datum", "hours": "Uren",
+			"rate": "Tarief", "amount": "Bedrag", "total": "Totaal",
+			"notes": "Notities", "expenses": "Onkosten",
+			"description": "Omschrijving", "receipts": "Bonnen",
+			"summary": "Overzicht", "noproject": "Zonder project",
+			"timesheet": "Urenstaat", "share": "Aandeel", "week": "Week",
+			"number": "Factuurnr.", "issued": "Factuurdatum", "due": "Te betalen voor",
+			"subtotal": "Subtotaal", "tax": "btw",
+		},
+	},
+}
+
+// currentExportLocale returns the locale chosen in settings.
+func currentExportLocale() ExportLocale {
+	return exportLocaleByTag(fyne.CurrentApp().Preferences().String(PrefExportLocale))
+}
+
+func exportLocaleByTag(tag string) ExportLocale {
+	for _, locale := range exportLocales {
+		if locale.Tag == tag {
+			return locale
+		}
+	}
+	return isoExportLocale
+}
+
+func exportLocaleNames() []string {
+	names := make([]string, len(exportLocales))
+	for i, locale := range exportLocales {
+		names[i] = tr(locale.Name)
+	}
+	return names
+}
+
+// exportLocaleByName finds a locale by its translated name.
+func exportLocaleByName(name string) ExportLocale {
+	for _, locale := range exportLocales {
+		if tr(locale.Name) == name {
+			return locale
+		}
+	}
+	return isoExportLocale
+}
+
+// Header translates a column key, falling back to the key itself.
+func (l ExportLocale) Header(key string) string {
+	if header, ok := l.Headers[key]; ok {
+		return header
+	}
+	if key == "duration" {
+		return "duration_seconds"
+	}
+	return key
+}
+
+func (l ExportLocale) FormatTime(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
+
+func (l ExportLocale) FormatDay(t time.Time) string {
+	return t.Format(l.DayLayout)
+}
+
+// FormatDecimal writes a number with two decimals and the locale's decimal mark.
+func (l ExportLocale) FormatDecimal(v float64) string {
+	formatted := strconv.FormatFloat(v, 'f', 2, 64)
+	if l.Decimal == "" {
+		return formatted
+	}
+	return strings.Replace(formatted, ".", l.Decimal, 1)
+}
+
+// FormatDuration writes whole milliseconds in precision mode and whole
+// seconds in the ISO locale. Otherwise it follows the display format:
+// HH:MM:SS, decimal hours with the locale's decimal mark, or hours and
+// minutes, untranslated like the numbers.
+func (l ExportLocale) FormatDuration(d time.Duration) string {
+	if precisionMode.Load() {
+		return strconv.FormatInt(d.Milliseconds(), 10)
+	}
+	if l.Decimal == "" {
+		return strconv.FormatFloat(d.Seconds(), 'f', 0, 64)
+	}
+	switch currentDurationFormat() {
+	case DurationFormatClock:
+		return formatClockDuration(d)
+	case DurationFormatHuman:
+		return fmt.Sprintf("%dh %dm", d/time.Hour, d%time.Hour/time.Minute)
+	default:
+		return l.FormatDecimal(d.Hours())
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefExtensions = "extensions"
+
+	ExtensionKindExporter = "exporter"
+	ExtensionKindRule     = "rule"
+
+	// maxExtensionSize bounds downloads so a bad URL can't exhaust memory
+	maxExtensionSize = 1 << 20
+)
+
+// extensionPermissions describes, for the install prompt, what each
+// permission lets an extension read. Extensions only ever receive the data
+// their permissions cover.
+var extensionPermissions = map[string]string{
+	"tasks":   "Task names",
+	"entries": "Your time entries: task, project, client, tags, start and end times and notes",
+}
+
+// Extension is a community-contributed exporter or rule. Extensions are
+// declarative: exporters are Go text templates rendered over the permitted
+// data, and rules are a pattern plus the fields to fill in, so neither can
+// touch the file system or network.
+type Extension struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+
+	// Exporter fields
+	Extension string `json:"extension,omitempty"`
+	Template  string `json:"template,omitempty"`
+
+	// Rule fields: when Match matches a task name, empty fields on the
+	// recorded entry are filled in and the tags are added
+	Match   string   `json:"match,omitempty"`
+	Project string   `json:"project,omitempty"`
+	Client  string   `json:"client,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// ExportData is what an exporter template is rendered with.
+type ExportData struct {
+	Task        string
+	Entries     []Entry
+	Total       time.Duration
+	GeneratedAt time.Time
+}
+
+var exportTemplateFuncs = template.FuncMap{
+	"duration": formatDuration,
+	"hours":    func(d time.Duration) string { return fmt.Sprintf("%.2f", d.Hours()) },
+	"date":     func(t time.Time, layout string) string { return t.Format(layout) },
+}
+
+func (e Extension) hasPermission(permission string) bool {
+	return contains(e.Permissions, permission)
+}
+
+// validate checks an extension before it is offered for install.
+func (e Extension) validate() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return errors.New("extension: missing name")
+	}
+	for _, permission := range e.Permissions {
+		if _, ok := extensionPermissions[permission]; !ok {
+			return fmt.Errorf("extension: unknown permission %q", permission)
+		}
+	}
+
+	switch e.Kind {
+	case ExtensionKindExporter:
+		if _, err := template.New(e.Name).Funcs(exportTemplateFuncs).Parse(e.Template); err != nil {
+			return fmt.Errorf("extension: %w", err)
+		}
+	case ExtensionKindRule:
+		if !e.hasPermission("tasks") {
+			return errors.New("extension: rules need the \"tasks\" permission")
+		}
+		if _, err := regexp.Compile(e.Match); err != nil {
+			return fmt.Errorf("extension: %w", err)
+		}
+	default:
+		return fmt.Errorf("extension: unknown kind %q", e.Kind)
+	}
+	return nil
+}
+
+// Render runs an exporter over the given task's entries, passing only the
+// data its permissions allow.
+func (e Extension) Render(w io.Writer, taskName string, entries []Entry) error {
+	tmpl, err := template.New(e.Name).Funcs(exportTemplateFuncs).Parse(e.Template)
+	if err != nil {
+		return err
+	}
+
+	data := ExportData{GeneratedAt: time.Now()}
+	if e.hasPermission("tasks") {
+		data.Task = taskName
+	}
+	if e.hasPermission("entries") {
+		data.Entries = entries
+		for _, entry := range entries {
+			data.Total += entry.Duration
+		}
+	}
+	return tmpl.Execute(w, data)
+}
+
+func installedExtensions() []Extension {
+	var extensions []Extension
+	raw := fyne.CurrentApp().Preferences().String(PrefExtensions)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &extensions); err != nil {
+			log.Printf("extensions: reading installed extensions: %v", err)
+		}
+	}
+	return extensions
+}
+
+func setInstalledExtensions(extensions []Extension) {
+	raw, err := json.Marshal(extensions)
+	if err != nil {
+		log.Printf("extensions: saving installed extensions: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefExtensions, string(raw))
+}
+
+// installedExporters lists the installed exporter extensions.
+func installedExporters() []Extension {
+	var exporters []Extension
+	for _, extension := range installedExtensions() {
+		if extension.Kind == ExtensionKindExporter {
+			exporters = append(exporters, extension)
+		}
+	}
+	return exporters
+}
+
+// applyRules fills in an entry's empty project and client and adds the tags
+// from the first matching rule extension.
+func applyRules(entry *Entry) {
+	for _, extension := range installedExtensions() {
+		if extension.Kind != ExtensionKindRule {
+			continue
+		}
+		pattern, err := regexp.Compile(extension.Match)
+		if err != nil || !pattern.MatchString(entry.Task) {
+			continue
+		}
+		if entry.Project == "" {
+			entry.Project = extension.Project
+		}
+		if entry.Client == "" {
+			entry.Client = extension.Client
+		}
+		for _, tag := range extension.Tags {
+			if !contains(entry.Tags, tag) {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+		return
+	}
+}
+
+func parseExtension(r io.Reader) (Extension, error) {
+	var extension Extension
+	if err := json.NewDecoder(io.LimitReader(r, maxExtensionSize)).Decode(&extension); err != nil {
+		return extension, fmt.Errorf("extension: %w", err)
+	}
+	return extension, extension.validate()
+}
+
+// confirmInstallExtension shows what the extension is and which data it
+// reads before installing it, replacing any extension with the same name.
+func confirmInstallExtension(timer *TaskTimer, extension Extension) {
+	details := container.NewVBox(
+		widget.NewLabelWithStyle(extension.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	if extension.Description != "" {
+		description := widget.NewLabel(extension.Description)
+		description.Wrapping = fyne.TextWrapWord
+		details.Add(description)
+	}
+	canRead := tr("This exporter can read:")
+	if extension.Kind == ExtensionKindRule {
+		canRead = tr("This rule can read:")
+	}
+	details.Add(widget.NewLabel(canRead))
+	if len(extension.Permissions) == 0 {
+		details.Add(widget.NewLabel(tr("• Nothing")))
+	}
+	for _, permission := range extension.Permissions {
+		details.Add(widget.NewLabel("• " + tr(extensionPermissions[permission])))
+	}
+
+	d := dialog.NewCustomConfirm(tr("Install Extension"), tr("Install"), tr("Cancel"), details, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var extensions []Extension
+		for _, installed := range installedExtensions() {
+			if installed.Name != extension.Name {
+				extensions = append(extensions, installed)
+			}
+		}
+		setInstalledExtensions(append(extensions, extension))
+		showView(timer, timer.currentView)
+	}, timer.window)
+	d.Resize(fyne.NewSize(360, 0))
+	d.Show()
+}
+
+func showInstallExtensionFromFileDialog(timer *TaskTimer) {
+	d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		extension, err := parseExtension(reader)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		confirmInstallExtension(timer, extension)
+	}, timer.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	d.Show()
+}
+
+func showInstallExtensionFromURLDialog(timer *TaskTimer) {
+	urlInput := widget.NewEntry()
+	urlInput.PlaceHolder = "https://example.com/exporter.json"
+
+	items := []*widget.FormItem{widget.NewFormItem(tr("URL"), urlInput)}
+	dialog.ShowForm(tr("Install Extension"), tr("Download"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		url := strings.TrimSpace(urlInput.Text)
+		go func() {
+			extension, err := downloadExtension(url)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				confirmInstallExtension(timer, extension)
+			})
+		}()
+	}, timer.window)
+}
+
+func downloadExtension(url string) (Extension, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return Extension{}, fmt.Errorf("extension: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Extension{}, fmt.Errorf("extension: %s responded with %s", url, resp.Status)
+	}
+	return parseExtension(resp.Body)
+}
+
+// createExtensionsList shows the installed extensions with a remove button each.
+func createExtensionsList(timer *TaskTimer) *fyne.Container {
+	list := container.NewVBox()
+	extensions := installedExtensions()
+	if len(extensions) == 0 {
+		list.Add(widget.NewLabel(tr("No extensions installed")))
+	}
+
+	for _, extension := range extensions {
+		name := extension.Name
+		removeBtn := widget.NewButton(tr("Remove"), func() {
+			var kept []Extension
+			for _, installed := range installedExtensions() {
+				if installed.Name != name {
+					kept = append(kept, installed)
+				}
+			}
+			setInstalledExtensions(kept)
+			showView(timer, timer.currentView)
+		})
+		list.Add(container.NewBorder(nil, nil, nil, removeBtn,
+			widget.NewLabel(fmt.Sprintf("%s (%s)", name, tr(extension.Kind)))))
+	}
+	return list
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefWeeklyHours is the contractual hours per week, zero for none
+	PrefWeeklyHours = "weeklyHours"
+
+	// PrefFlexStart is the day the flexitime balance counts from, written
+	// YYYY-MM-DD
+	PrefFlexStart = "flexStart"
+
+	PrefFlexCorrections = "flexCorrections"
+)
+
+// FlexCorrection adjusts the flexitime balance by hand, e.g. for a balance
+// carried over or overtime paid out.
+type FlexCorrection struct {
+	ID     string        `json:"id"`
+	Date   time.Time     `json:"date"`
+	Amount time.Duration `json:"amount"`
+	Note   string        `json:"note,omitempty"`
+}
+
+func flexCorrections() []FlexCorrection {
+	var list []FlexCorrection
+	raw := fyne.CurrentApp().Preferences().String(PrefFlexCorrections)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &list); err != nil {
+			log.Printf("flexitime: reading corrections: %v", err)
+		}
+	}
+	return list
+}
+
+func setFlexCorrections(list []FlexCorrection) {
+	raw, err := json.Marshal(list)
+	if err != nil {
+		log.Printf("flexitime: saving corrections: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefFlexCorrections, string(raw))
+}
+
+// weeklyHours returns the contractual hours per week, zero if none are set.
+func weeklyHours() time.Duration {
+	return time.Duration(fyne.CurrentApp().Preferences().Float(PrefWeeklyHours) * float64(time.Hour))
+}
+
+// flexStart returns the day the balance counts from, the start of this week
+// if none is set.
+func flexStart(now time.Time) time.Time {
+	start, err := time.ParseInLocation("2006-01-02", fyne.CurrentApp().Preferences().String(PrefFlexStart), time.Local)
+	if err != nil {
+		return weekStart(now)
+	}
+	return dayStart(start)
+}
+
+// flexBalance returns the time worked beyond the contractual hours from the
+// start day up to now, corrections included, along with this week's share.
+// The weekly hours are spread evenly over the working days, and days off
+// expect nothing. Today only adds to the balance once its hours are worked,
+// so the balance doesn't drop first thing each morning.
+func flexBalance(timer *TaskTimer, now time.Time) (balance, week time.Duration) {
+	days := workdays()
+	count := 0
+	for _, works := range days {
+		if works {
+			count++
+		}
+	}
+	daily := weeklyHours() / time.Duration(count)
+
+	start, today, thisWeek := flexStart(now), dayStart(now), weekStart(now)
+	tracked := dailyTotal(timer, start, addDays(today, 1))
+	off := loadTimeOff()
+	for day := start; !day.After(today); day = addDays(day, 1) {
+		delta := tracked[day]
+		if days[day.Weekday()] && off.On(day) == "" {
+			delta -= daily
+		}
+		if day.Equal(today) {
+			delta = max(delta, 0)
+		}
+		balance += delta
+		if !day.Before(thisWeek) {
+			week += delta
+		}
+	}
+	for _, correction := range flexCorrections() {
+		if !correction.Date.Before(start) && correction.Date.Before(addDays(today, 1)) {
+			balance += correction.Amount
+		}
+	}
+	return balance, week
+}
+
+// formatBalance writes a balance with its sign, e.g. "+3:20:00".
+func formatBalance(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	return sign + formatDuration(d.Truncate(time.Minute))
+}
+
+// createFlexitimeContainer shows the flexitime balance and this week's share
+// of it, with a button to correct it, hidden while no weekly hours are set.
+func createFlexitimeContainer(timer *TaskTimer) fyne.CanvasObject {
+	label := widget.NewLabel("")
+	var box *fyne.Container
+
+	render := func() {
+		if weeklyHours() <= 0 {
+			box.Hide()
+			return
+		}
+		balance, week := flexBalance(timer, time.Now())
+		label.SetText(tr("Flexitime balance {{.Balance}} · {{.Week}} this week", map[string]any{
+			"Balance": formatBalance(balance),
+			"Week":    formatBalance(week),
+		}))
+		if balance < 0 {
+			label.Importance = widget.WarningImportance
+		} else {
+			label.Importance = widget.MediumImportance
+		}
+		label.Refresh()
+		box.Show()
+	}
+	correctBtn := widget.NewButtonWithIcon(tr("Corrections…"), theme.DocumentCreateIcon(), func() {
+		showFlexCorrectionsDialog(timer, render)
+	})
+	box = container.NewBorder(nil, nil, nil, correctBtn, label)
+	listenWhileShown(timer, timer.history, render)
+
+	return box
+}
+
+// showFlexCorrectionsDialog lists the corrections to the flexitime balance,
+// newest first, with a form to add another.
+func showFlexCorrectionsDialog(timer *TaskTimer, onChanged func()) {
+	list := container.NewVBox()
+
+	var render func()
+	render = func() {
+		list.RemoveAll()
+		all := flexCorrections()
+		sort.Slice(all, func(i, j int) bool {
+			return all[i].Date.After(all[j].Date)
+		})
+		if len(all) == 0 {
+			list.Add(widget.NewLabel(tr("No corrections")))
+		}
+
+		for _, correction := range all {
+			summary := fmt.Sprintf("%s  %s", formatDate(correction.Date), formatBalance(correction.Amount))
+			if correction.Note != "" {
+				summary += " · " + correction.Note
+			}
+			label := widget.NewLabel(summary)
+			label.Truncation = fyne.TextTruncateEllipsis
+
+			id := correction.ID
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				var kept []FlexCorrection
+				for _, correction := range flexCorrections() {
+					if correction.ID != id {
+						kept = append(kept, correction)
+					}
+				}
+				setFlexCorrections(kept)
+				render()
+				onChanged()
+			})
+			list.Add(container.NewBorder(nil, nil, nil, deleteBtn, label))
+		}
+	}
+	render()
+
+	dateInput := widget.NewEntry()
+	dateInput.SetText(time.Now().Format("2006-01-02"))
+	hoursInput := widget.NewEntry()
+	hoursInput.PlaceHolder = tr("Hours, negative to take off")
+	noteInput := widget.NewEntry()
+	noteInput.PlaceHolder = tr("e.g. carried over, paid out")
+	addBtn := widget.NewButtonWithIcon(tr("Add"), theme.ContentAddIcon(), func() {
+		date, err := time.ParseInLocation("2006-01-02", dateInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		amount, err := parseHours(hoursInput.Text)
+		if err != nil || amount == 0 {
+			dialog.ShowInformation(tr("Flexitime"), tr("Enter the correction as a number of hours."), timer.window)
+			return
+		}
+		setFlexCorrections(append(flexCorrections(), FlexCorrection{
+			ID:     rand.Text(),
+			Date:   date,
+			Amount: amount,
+			Note:   strings.TrimSpace(noteInput.Text),
+		}))
+		hoursInput.SetText("")
+		noteInput.SetText("")
+		render()
+		onChanged()
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(tr("Date"), dateInput),
+		widget.NewFormItem(tr("Hours"), hoursInput),
+		widget.NewFormItem(tr("Note"), noteInput),
+	)
+	content := container.NewBorder(container.NewVBox(form, addBtn, widget.NewSeparator()), nil, nil, nil, container.NewVScroll(list))
+	d := dialog.NewCustom(tr("Flexitime Corrections"), tr("Close"), content, timer.window)
+	d.Resize(fyne.NewSize(450, 450))
+	d.Show()
+}
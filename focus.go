@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	// PrefFocusMode turns Do Not Disturb on while a tagged session runs
+	PrefFocusMode = "focusMode"
+
+	// PrefFocusModeTag is the tag that marks a session as deep work
+	PrefFocusModeTag = "focusModeTag"
+
+	DefaultFocusModeTag = "deep work"
+)
+
+var errFocusModeUnsupported = errors.New("turning on Do Not Disturb isn't supported here")
+
+// focusModeOn is whether the app turned Do Not Disturb on, so it only turns
+// off what it turned on.
+var focusModeOn bool
+
+// sessionTags returns the tags the session in progress will be recorded
+// with, from its template and the rule extensions.
+func sessionTags(timer *TaskTimer) []string {
+	entry := Entry{Task: timer.taskName}
+	if timer.template.Name == entry.Task {
+		timer.template.Apply(&entry)
+	}
+	applyRules(&entry)
+	return entry.Tags
+}
+
+// updateFocusMode turns the system's Do Not Disturb on while a session
+// tagged as deep work runs, if enabled, and off again once it stops.
+func updateFocusMode(timer *TaskTimer) {
+	prefs := fyne.CurrentApp().Preferences()
+	want := false
+	if timer.isRunning && prefs.Bool(PrefFocusMode) {
+		tag := prefs.String(PrefFocusModeTag)
+		if tag == "" {
+			tag = DefaultFocusModeTag
+		}
+		for _, sessionTag := range sessionTags(timer) {
+			if strings.EqualFold(sessionTag, tag) {
+				want = true
+			}
+		}
+	}
+	setFocusMode(want)
+}
+
+// setFocusMode turns Do Not Disturb on or off unless it already is.
+func setFocusMode(on bool) {
+	if on == focusModeOn {
+		return
+	}
+	if err := setDoNotDisturb(on); err != nil {
+		log.Printf("focus: %v", err)
+		return
+	}
+	focusModeOn = on
+}
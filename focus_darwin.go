@@ -0,0 +1,27 @@
+//go:build darwin && !ios
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Focus has no public interface on macOS, so the user sets up a shortcut
+// for each direction in the Shortcuts app with the "Set Focus" action.
+const (
+	focusOnShortcut  = "gotime Focus On"
+	focusOffShortcut = "gotime Focus Off"
+)
+
+// setDoNotDisturb runs the shortcut that turns Focus on or off.
+func setDoNotDisturb(on bool) error {
+	shortcut := focusOffShortcut
+	if on {
+		shortcut = focusOnShortcut
+	}
+	if output, err := exec.Command("shortcuts", "run", shortcut).CombinedOutput(); err != nil {
+		return fmt.Errorf("shortcuts run %q: %w: %s", shortcut, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const gnomeNotificationsSchema = "org.gnome.desktop.notifications"
+
+// bannersBefore is GNOME's show-banners setting from before Do Not Disturb
+// was turned on, to put back afterwards.
+var bannersBefore string
+
+// setDoNotDisturb switches GNOME's Do Not Disturb, which hides notification
+// banners, through gsettings. Turning it off restores the setting it found.
+func setDoNotDisturb(on bool) error {
+	banners := bannersBefore
+	if on {
+		output, err := exec.Command("gsettings", "get", gnomeNotificationsSchema, "show-banners").Output()
+		if err != nil {
+			return fmt.Errorf("gsettings: %w", err)
+		}
+		bannersBefore = strings.TrimSpace(string(output))
+		banners = "false"
+	}
+	if err := exec.Command("gsettings", "set", gnomeNotificationsSchema, "show-banners", banners).Run(); err != nil {
+		return fmt.Errorf("gsettings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDoNotDisturbRestoresBanners turns Do Not Disturb on and off with
+// banners already hidden, which must leave them hidden.
+func TestDoNotDisturbRestoresBanners(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\n[ \"$1\" = get ] && echo false\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "gsettings"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	if err := setDoNotDisturb(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := setDoNotDisturb(false); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	want := []string{
+		"get org.gnome.desktop.notifications show-banners",
+		"set org.gnome.desktop.notifications show-banners false",
+		"set org.gnome.desktop.notifications show-banners false",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("gsettings calls = %q, want %q", got, want)
+	}
+}
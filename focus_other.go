@@ -0,0 +1,8 @@
+//go:build !linux && !windows && (!darwin || ios)
+
+package main
+
+// setDoNotDisturb has no native implementation on this platform.
+func setDoNotDisturb(on bool) error {
+	return errFocusModeUnsupported
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const pushNotificationsKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\PushNotifications`
+
+// toastBefore is the ToastEnabled value from before Do Not Disturb was turned
+// on, to put back afterwards; "" if it wasn't set.
+var toastBefore string
+
+// setDoNotDisturb turns app notifications off, as Focus Assist does, through
+// the per-user toast setting. Focus Assist itself has no public interface.
+// Turning it off restores the setting it found.
+func setDoNotDisturb(on bool) error {
+	if !on {
+		if toastBefore == "" {
+			// Unset, which Windows takes as on
+			return reg("delete", pushNotificationsKey, "/v", "ToastEnabled", "/f")
+		}
+		return reg("add", pushNotificationsKey, "/v", "ToastEnabled", "/t", "REG_DWORD", "/d", toastBefore, "/f")
+	}
+
+	toastBefore = ""
+	output, err := exec.Command("reg", "query", pushNotificationsKey, "/v", "ToastEnabled").Output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		// The value isn't set
+	case err != nil:
+		return fmt.Errorf("reg query: %w", err)
+	default:
+		// e.g. "    ToastEnabled    REG_DWORD    0x1"
+		fields := strings.Fields(string(output))
+		if len(fields) == 0 {
+			return errors.New("reg query: no output")
+		}
+		value, err := strconv.ParseUint(strings.TrimPrefix(fields[len(fields)-1], "0x"), 16, 32)
+		if err != nil {
+			return fmt.Errorf("reg query: %w", err)
+		}
+		toastBefore = strconv.FormatUint(value, 10)
+	}
+	return reg("add", pushNotificationsKey, "/v", "ToastEnabled", "/t", "REG_DWORD", "/d", "0", "/f")
+}
+
+func reg(args ...string) error {
+	if output, err := exec.Command("reg", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("reg %s: %w: %s", args[0], err, output)
+	}
+	return nil
+}
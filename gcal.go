@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/oauth2"
+)
+
+const (
+	PrefGoogleClientID = "googleClientID"
+	// PrefGoogleClientSecret and PrefGoogleToken held the client secret and
+	// the OAuth token before they moved to the keychain
+	PrefGoogleClientSecret  = "googleClientSecret"
+	PrefGoogleToken         = "googleToken"
+	PrefGoogleCalendarID    = "googleCalendarID"
+	PrefGoogleCreateEvents  = "googleCreateEvents"
+	PrefGoogleSuggestEvents = "googleSuggestEvents"
+	PrefGoogleStartMeetings = "googleStartMeetings"
+
+	GoogleCalendarAPIBase = "https://www.googleapis.com/calendar/v3"
+	GoogleCalendarScope   = "https://www.googleapis.com/auth/calendar.events"
+	CalendarSyncInterval  = 15 * time.Minute
+
+	// MeetingCheckInterval is how often today's events are checked for one
+	// that has just begun
+	MeetingCheckInterval = 30 * time.Second
+
+	googleDefaultCalendarID  = "primary"
+	googleOAuthLoginTimeout  = 5 * time.Minute
+	googleOAuthCallbackPath  = "/callback"
+	googleOAuthCallbackReply = "gotime is now connected to Google Calendar. You can close this window."
+)
+
+var googleEndpoint = oauth2.Endpoint{
+	AuthURL:  "https://accounts.google.com/o/oauth2/auth",
+	TokenURL: "https://oauth2.googleapis.com/token",
+}
+
+// CalendarSync pushes completed sessions to Google Calendar and pulls today's
+// events in as suggested tasks. Failed pushes are queued and retried by the
+// sync loop.
+type CalendarSync struct {
+	mu      sync.Mutex
+	timer   *TaskTimer
+	pending []Entry
+
+	// events are today's events as of the last sync; started holds the IDs
+	// of meetings a timer was already started for
+	events  []calendarEvent
+	started map[string]bool
+}
+
+func NewCalendarSync(timer *TaskTimer) *CalendarSync {
+	return &CalendarSync{timer: timer, started: make(map[string]bool)}
+}
+
+func googleOAuthConfig(redirectURL string) *oauth2.Config {
+	prefs := fyne.CurrentApp().Preferences()
+	return &oauth2.Config{
+		ClientID:     prefs.String(PrefGoogleClientID),
+		ClientSecret: keychainSecret(KeyringGoogleClientSecret),
+		Endpoint:     googleEndpoint,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{GoogleCalendarScope},
+	}
+}
+
+func googleCalendarID() string {
+	return fyne.CurrentApp().Preferences().StringWithFallback(PrefGoogleCalendarID, googleDefaultCalendarID)
+}
+
+// Connected reports whether a Google account has been authorized.
+func (c *CalendarSync) Connected() bool {
+	return keychainSecret(KeyringGoogleToken) != ""
+}
+
+// Connect runs the OAuth installed-app flow: the consent page opens in the
+// browser and redirects back to a short-lived loopback server.
+func (c *CalendarSync) Connect() error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	redirectURL := fmt.Sprintf("http://%s%s", listener.Addr(), googleOAuthCallbackPath)
+	config := googleOAuthConfig(redirectURL)
+	if config.ClientID == "" {
+		return errors.New("google: set an OAuth client ID first")
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	codes := make(chan string, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != googleOAuthCallbackPath || r.FormValue("state") != state {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, googleOAuthCallbackReply)
+		select {
+		case codes <- r.FormValue("code"):
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL, err := url.Parse(config.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.ApprovalForce,
+		oauth2.S256ChallengeOption(verifier),
+	))
+	if err != nil {
+		return err
+	}
+	if err := fyne.CurrentApp().OpenURL(authURL); err != nil {
+		return err
+	}
+
+	var code string
+	select {
+	case code = <-codes:
+	case <-time.After(googleOAuthLoginTimeout):
+		return errors.New("google: timed out waiting for authorization")
+	}
+	if code == "" {
+		return errors.New("google: authorization was denied")
+	}
+
+	token, err := config.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return fmt.Errorf("google: %w", err)
+	}
+	return saveGoogleToken(token)
+}
+
+// Disconnect forgets the stored Google credentials.
+func (c *CalendarSync) Disconnect() error {
+	return setKeychainSecrets(map[string]string{KeyringGoogleToken: ""})
+}
+
+func saveGoogleToken(token *oauth2.Token) error {
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return setKeychainSecrets(map[string]string{KeyringGoogleToken: string(raw)})
+}
+
+// do sends an authorized Calendar API request, persisting the access token
+// whenever it was refreshed.
+func (c *CalendarSync) do(method, path string, body any, result any) error {
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(keychainSecret(KeyringGoogleToken)), &token); err != nil {
+		return fmt.Errorf("google: not connected: %w", err)
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	source := googleOAuthConfig("").TokenSource(ctx, &token)
+	fresh, err := source.Token()
+	if err != nil {
+		return fmt.Errorf("google: %w", err)
+	}
+	if fresh.AccessToken != token.AccessToken {
+		if err := saveGoogleToken(fresh); err != nil {
+			return err
+		}
+	}
+
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, GoogleCalendarAPIBase+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	fresh.SetAuthHeader(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("google: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("google: %s %s responded with %s", method, path, resp.Status)
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+type calendarEventTime struct {
+	DateTime time.Time `json:"dateTime,omitzero"`
+}
+
+type calendarEvent struct {
+	ID      string            `json:"id,omitempty"`
+	Summary string            `json:"summary"`
+	Start   calendarEventTime `json:"start"`
+	End     calendarEventTime `json:"end"`
+}
+
+// Push creates a calendar event for a completed session if event creation is
+// enabled. Failures are queued for the next sync.
+func (c *CalendarSync) Push(entry Entry) {
+	if !fyne.CurrentApp().Preferences().Bool(PrefGoogleCreateEvents) || !c.Connected() {
+		return
+	}
+
+	go func() {
+		if err := c.createEvent(entry); err != nil {
+			log.Print(err)
+			c.mu.Lock()
+			c.pending = append(c.pending, entry)
+			c.mu.Unlock()
+		}
+	}()
+}
+
+func (c *CalendarSync) createEvent(entry Entry) error {
+	event := calendarEvent{
+		Summary: entry.Task,
+		Start:   calendarEventTime{DateTime: entry.Start},
+		End:     calendarEventTime{DateTime: entry.End},
+	}
+	path := fmt.Sprintf("/calendars/%s/events", url.PathEscape(googleCalendarID()))
+	return c.do(http.MethodPost, path, event, nil)
+}
+
+// todaysEvents lists today's events in start order.
+func (c *CalendarSync) todaysEvents() ([]calendarEvent, error) {
+	today := dayStart(time.Now())
+
+	query := url.Values{}
+	query.Set("timeMin", today.Format(time.RFC3339))
+	query.Set("timeMax", addDays(today, 1).Format(time.RFC3339))
+	query.Set("singleEvents", "true")
+	query.Set("orderBy", "startTime")
+
+	var result struct {
+		Items []calendarEvent `json:"items"`
+	}
+	path := fmt.Sprintf("/calendars/%s/events?%s", url.PathEscape(googleCalendarID()), query.Encode())
+	if err := c.do(http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// Run syncs immediately and then every CalendarSyncInterval, and starts
+// timers for meetings as they begin.
+func (c *CalendarSync) Run() {
+	syncTicker := time.NewTicker(CalendarSyncInterval)
+	defer syncTicker.Stop()
+	meetingTicker := time.NewTicker(MeetingCheckInterval)
+	defer meetingTicker.Stop()
+
+	c.sync()
+	for {
+		select {
+		case <-syncTicker.C:
+			c.sync()
+		case now := <-meetingTicker.C:
+			c.startMeetings(now)
+		}
+	}
+}
+
+// startMeetings starts the timer for an event in progress, once per event.
+// The task is named after the event, so rule extensions matching the title
+// assign the recorded entry's project, client and tags.
+func (c *CalendarSync) startMeetings(now time.Time) {
+	if !fyne.CurrentApp().Preferences().Bool(PrefGoogleStartMeetings) {
+		return
+	}
+
+	c.mu.Lock()
+	var meeting *calendarEvent
+	for i, event := range c.events {
+		// All-day events have no start time and are never meetings
+		if event.Summary == "" || event.Start.DateTime.IsZero() || c.started[event.ID] {
+			continue
+		}
+		if !now.Before(event.Start.DateTime) && now.Before(event.End.DateTime) {
+			c.started[event.ID] = true
+			meeting = &c.events[i]
+		}
+	}
+	c.mu.Unlock()
+
+	if meeting != nil {
+		title := meeting.Summary
+		fyne.Do(func() {
+			startTask(c.timer, title)
+		})
+	}
+}
+
+// InMeeting reports whether one of today's events is in progress.
+func (c *CalendarSync) InMeeting(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, event := range c.events {
+		if event.Start.DateTime.IsZero() {
+			continue
+		}
+		if !now.Before(event.Start.DateTime) && now.Before(event.End.DateTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CalendarSync) sync() {
+	if !c.Connected() {
+		return
+	}
+
+	// Retry sessions that failed to push earlier
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	for _, entry := range pending {
+		if err := c.createEvent(entry); err != nil {
+			log.Print(err)
+			c.mu.Lock()
+			c.pending = append(c.pending, entry)
+			c.mu.Unlock()
+		}
+	}
+
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefGoogleSuggestEvents) && !prefs.Bool(PrefGoogleStartMeetings) {
+		return
+	}
+	events, err := c.todaysEvents()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	c.mu.Lock()
+	c.events = events
+	c.mu.Unlock()
+
+	if !prefs.Bool(PrefGoogleSuggestEvents) {
+		return
+	}
+	fyne.Do(func() {
+		for _, event := range events {
+			if event.Summary != "" {
+				c.timer.tasks.Ensure(event.Summary)
+			}
+		}
+	})
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
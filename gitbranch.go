@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const (
+	PrefGitRepoPath    = "gitRepoPath"
+	PrefGitAutoStart   = "gitAutoStart"
+	PrefGitTicketTasks = "gitTicketTasks"
+
+	// GitBranchPollInterval is how often the configured repository's
+	// checked-out branch is read
+	GitBranchPollInterval = 5 * time.Second
+)
+
+// A ticket number leading a branch's last segment, e.g. feature/123-login
+var gitBranchNumberPattern = regexp.MustCompile(`(?:^|/)(\d+)(?:[-_]|$)`)
+
+// branchTicket returns the ticket a branch is for: a Jira-style key such as
+// PROJ-42, or an issue number written as #123. It is "" if there is none.
+func branchTicket(branch string) string {
+	if key := jiraIssueKeyPattern.FindString(branch); key != "" {
+		return key
+	}
+	if m := gitBranchNumberPattern.FindStringSubmatch(branch); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
+
+// branchTaskName names the task for a branch. With ticket naming on, a branch
+// for a ticket maps to an existing task mentioning the ticket, such as one
+// started from the issue, or else to a task named after the ticket.
+// Otherwise the task is named after the branch.
+func branchTaskName(timer *TaskTimer, branch string) string {
+	if !fyne.CurrentApp().Preferences().Bool(PrefGitTicketTasks) {
+		return branch
+	}
+	ticket := branchTicket(branch)
+	if ticket == "" {
+		return branch
+	}
+	ticketPattern := regexp.MustCompile(`(?:^|[^\w#])` + regexp.QuoteMeta(ticket) + `\b`)
+	for _, taskName := range timer.tasks.Tasks() {
+		if ticketPattern.MatchString(taskName) {
+			return taskName
+		}
+	}
+	return ticket
+}
+
+// gitBranch returns the branch checked out in a repository, or "" when HEAD
+// is detached. Linked worktrees, where .git is a file pointing at the real
+// git directory, are supported.
+func gitBranch(repo string) (string, error) {
+	gitDir := filepath.Join(repo, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		raw, err := os.ReadFile(gitDir)
+		if err != nil {
+			return "", err
+		}
+		dir, ok := strings.CutPrefix(strings.TrimSpace(string(raw)), "gitdir:")
+		if !ok {
+			return "", fmt.Errorf("git: %s is not a repository", repo)
+		}
+		gitDir = strings.TrimSpace(dir)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(repo, gitDir)
+		}
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+	if !ok {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// watchGitBranch follows the branch checked out in the configured repository
+// and, when it changes, suggests or starts the task named after it.
+func watchGitBranch(timer *TaskTimer) {
+	var lastRepo, lastBranch string
+	for range time.Tick(GitBranchPollInterval) {
+		repo := fyne.CurrentApp().Preferences().String(PrefGitRepoPath)
+		if repo == "" {
+			lastRepo, lastBranch = "", ""
+			continue
+		}
+
+		branch, err := gitBranch(repo)
+		if err != nil {
+			if repo != lastRepo {
+				log.Print(err)
+			}
+			lastRepo = repo
+			continue
+		}
+		if repo == lastRepo && branch == lastBranch {
+			continue
+		}
+		lastRepo, lastBranch = repo, branch
+
+		if branch != "" {
+			fyne.Do(func() {
+				suggestBranchTask(timer, branch)
+			})
+		}
+	}
+}
+
+// suggestBranchTask offers to switch to the task for a branch, or switches
+// straight away if auto-start is on.
+func suggestBranchTask(timer *TaskTimer, branch string) {
+	taskName := branchTaskName(timer, branch)
+	if timer.taskName == taskName {
+		return
+	}
+	if fyne.CurrentApp().Preferences().Bool(PrefGitAutoStart) {
+		startTask(timer, taskName)
+		return
+	}
+
+	message := tr("You switched to the branch \"{{.Branch}}\". Start timing it?", map[string]any{"Branch": branch})
+	if taskName != branch {
+		message = tr("You switched to the branch \"{{.Branch}}\". Start timing \"{{.Task}}\"?", map[string]any{
+			"Branch": branch,
+			"Task":   taskName,
+		})
+	}
+	dialog.ShowConfirm(tr("Git Branch"), message, func(ok bool) {
+		if ok {
+			startTask(timer, taskName)
+		}
+	}, timer.window)
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefGitHubToken held the token before it moved to the keychain
+	PrefGitHubToken      = "githubToken"
+	PrefGitHubRepo       = "githubRepo"
+	PrefGitHubComment    = "githubComment"
+	PrefGitHubTaskIssues = "githubTaskIssues"
+
+	GitHubAPIBase = "https://api.github.com"
+)
+
+var (
+	// A full issue URL, owner/repo#123, or #123 or 123 in the default repo
+	githubIssueURLPattern = regexp.MustCompile(`^https?://github\.com/([\w.-]+/[\w.-]+)/(?:issues|pull)/(\d+)`)
+	githubIssueRefPattern = regexp.MustCompile(`^(?:([\w.-]+/[\w.-]+)#|#?)(\d+)$`)
+
+	errGitHubIssueRef = errors.New("enter an issue URL, owner/repo#123 or an issue number")
+)
+
+// GitHubIssue identifies an issue, e.g. owner/repo#123.
+type GitHubIssue struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+func (i GitHubIssue) String() string {
+	return fmt.Sprintf("%s#%d", i.Repo, i.Number)
+}
+
+// URL is the issue's page on GitHub.
+func (i GitHubIssue) URL() *url.URL {
+	u, _ := url.Parse(fmt.Sprintf("https://github.com/%s/issues/%d", i.Repo, i.Number))
+	return u
+}
+
+// parseGitHubIssue reads a pasted issue reference. Bare numbers are in the
+// default repository from settings.
+func parseGitHubIssue(ref string) (GitHubIssue, error) {
+	ref = strings.TrimSpace(ref)
+	if m := githubIssueURLPattern.FindStringSubmatch(ref); m != nil {
+		number, _ := strconv.Atoi(m[2])
+		return GitHubIssue{Repo: m[1], Number: number}, nil
+	}
+	m := githubIssueRefPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return GitHubIssue{}, errGitHubIssueRef
+	}
+	repo := m[1]
+	if repo == "" {
+		repo = strings.TrimSpace(fyne.CurrentApp().Preferences().String(PrefGitHubRepo))
+	}
+	if repo == "" {
+		return GitHubIssue{}, errGitHubIssueRef
+	}
+	number, _ := strconv.Atoi(m[2])
+	return GitHubIssue{Repo: repo, Number: number}, nil
+}
+
+// githubTaskIssues loads which issue each task was started from.
+func githubTaskIssues() map[string]GitHubIssue {
+	issues := make(map[string]GitHubIssue)
+	raw := fyne.CurrentApp().Preferences().String(PrefGitHubTaskIssues)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &issues); err != nil {
+			log.Printf("github: reading task issues: %v", err)
+		}
+	}
+	return issues
+}
+
+func setGitHubTaskIssues(issues map[string]GitHubIssue) {
+	raw, err := json.Marshal(issues)
+	if err != nil {
+		log.Printf("github: saving task issues: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefGitHubTaskIssues, string(raw))
+}
+
+// githubIssueForTask returns the issue a task was started from, if any.
+func githubIssueForTask(taskName string) (GitHubIssue, bool) {
+	issue, ok := githubTaskIssues()[taskName]
+	return issue, ok
+}
+
+// renameGitHubTaskIssue keeps a renamed task linked to its issue. A task
+// merged into one with its own issue keeps the target's.
+func renameGitHubTaskIssue(from, to string) {
+	issues := githubTaskIssues()
+	issue, ok := issues[from]
+	if !ok {
+		return
+	}
+	if _, exists := issues[to]; !exists {
+		issues[to] = issue
+	}
+	delete(issues, from)
+	setGitHubTaskIssues(issues)
+}
+
+type githubIssueJSON struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+func githubRequest(method, path string, body any, out any) error {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, GitHubAPIBase+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := keychainSecret(KeyringGitHubToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("github: %s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchGitHubIssueTitle looks up an issue's title.
+func fetchGitHubIssueTitle(issue GitHubIssue) (string, error) {
+	var found githubIssueJSON
+	if err := githubRequest(http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", issue.Repo, issue.Number), nil, &found); err != nil {
+		return "", err
+	}
+	return found.Title, nil
+}
+
+// fetchOpenGitHubIssues lists the open issues in a repository, most recently
+// updated first, leaving out pull requests.
+func fetchOpenGitHubIssues(repo string) ([]GitHubIssue, []string, error) {
+	var found []githubIssueJSON
+	if err := githubRequest(http.MethodGet, "/repos/"+repo+"/issues?state=open&sort=updated&per_page=50", nil, &found); err != nil {
+		return nil, nil, err
+	}
+	var issues []GitHubIssue
+	var titles []string
+	for _, issue := range found {
+		if issue.PullRequest != nil {
+			continue
+		}
+		issues = append(issues, GitHubIssue{Repo: repo, Number: issue.Number})
+		titles = append(titles, issue.Title)
+	}
+	return issues, titles, nil
+}
+
+// githubTaskName names the task for an issue, e.g. "#123 Fix the build", with
+// the repository when it isn't the default one.
+func githubTaskName(issue GitHubIssue, title string) string {
+	ref := fmt.Sprintf("#%d", issue.Number)
+	if issue.Repo != fyne.CurrentApp().Preferences().String(PrefGitHubRepo) {
+		ref = issue.String()
+	}
+	return ref + " " + title
+}
+
+// startGitHubIssue creates the task for an issue, links it to the issue and
+// starts the timer on it.
+func startGitHubIssue(timer *TaskTimer, issue GitHubIssue, title string) {
+	taskName := githubTaskName(issue, title)
+	issues := githubTaskIssues()
+	issues[taskName] = issue
+	setGitHubTaskIssues(issues)
+	startTask(timer, taskName)
+}
+
+// showGitHubIssueDialog offers the default repository's open issues and
+// takes a pasted issue as well.
+func showGitHubIssueDialog(timer *TaskTimer) {
+	issueInput := widget.NewSelectEntry(nil)
+	issueInput.PlaceHolder = "https://github.com/owner/repo/issues/123"
+
+	// Options read "#123 Title"; picking one fills that in
+	var openIssues []GitHubIssue
+	var openTitles []string
+	if repo := fyne.CurrentApp().Preferences().String(PrefGitHubRepo); repo != "" {
+		go func() {
+			issues, titles, err := fetchOpenGitHubIssues(repo)
+			if err != nil {
+				log.Print(err)
+				return
+			}
+			fyne.Do(func() {
+				openIssues, openTitles = issues, titles
+				var options []string
+				for i, issue := range issues {
+					options = append(options, fmt.Sprintf("#%d %s", issue.Number, titles[i]))
+				}
+				issueInput.SetOptions(options)
+			})
+		}()
+	}
+
+	items := []*widget.FormItem{widget.NewFormItem(tr("Issue"), issueInput)}
+	dialog.ShowForm(tr("Start from GitHub Issue"), tr("Start"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		text := strings.TrimSpace(issueInput.Text)
+		for i, issue := range openIssues {
+			if text == fmt.Sprintf("#%d %s", issue.Number, openTitles[i]) {
+				startGitHubIssue(timer, issue, openTitles[i])
+				return
+			}
+		}
+
+		issue, err := parseGitHubIssue(text)
+		if err != nil {
+			dialog.ShowInformation(tr("Start from GitHub Issue"), tr(err.Error()), timer.window)
+			return
+		}
+		go func() {
+			title, err := fetchGitHubIssueTitle(issue)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				startGitHubIssue(timer, issue, title)
+			})
+		}()
+	}, timer.window)
+}
+
+// pushGitHubComment comments the time a session tracked on its issue, with
+// the total so far, if commenting is enabled and the task is linked to an
+// issue.
+func pushGitHubComment(timer *TaskTimer, entry Entry) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefGitHubComment) {
+		return
+	}
+	issue, ok := githubIssueForTask(entry.Task)
+	if !ok {
+		return
+	}
+
+	total := trackedOnTask(allEntries(timer), entry.Task)
+	comment := tr("⏱ Tracked {{.Duration}} on this issue, {{.Total}} in total.", map[string]any{
+		"Duration": formatDuration(entry.Duration.Truncate(time.Second)),
+		"Total":    formatDuration(total.Truncate(time.Second)),
+	})
+	go func() {
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments", issue.Repo, issue.Number)
+		if err := githubRequest(http.MethodPost, path, map[string]string{"body": comment}, nil); err != nil {
+			log.Print(err)
+			fyne.Do(func() {
+				dialog.ShowError(err, timer.window)
+			})
+		}
+	}()
+}
+
+// createGitHubIssueLink links to the selected task's issue, for the timer
+// view. It is hidden for tasks not started from an issue.
+func createGitHubIssueLink(timer *TaskTimer) fyne.CanvasObject {
+	link := widget.NewHyperlink("", nil)
+	link.Alignment = fyne.TextAlignCenter
+	link.Hide()
+	timer.taskTitle.AddListener(binding.NewDataListener(func() {
+		issue, ok := githubIssueForTask(timer.taskName)
+		if !ok {
+			link.Hide()
+			return
+		}
+		link.SetText(issue.String())
+		link.SetURL(issue.URL())
+		link.Show()
+	}))
+	return link
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestParseGitHubIssue(t *testing.T) {
+	tests := []struct {
+		ref, defaultRepo string
+		want             GitHubIssue
+		ok               bool
+	}{
+		{"https://github.com/0jc1/gotime/issues/42", "", GitHubIssue{"0jc1/gotime", 42}, true},
+		{"https://github.com/0jc1/gotime/pull/7#discussion", "", GitHubIssue{"0jc1/gotime", 7}, true},
+		{"  fyne-io/fyne#1200 ", "", GitHubIssue{"fyne-io/fyne", 1200}, true},
+		{"#42", "0jc1/gotime", GitHubIssue{"0jc1/gotime", 42}, true},
+		{"42", "0jc1/gotime", GitHubIssue{"0jc1/gotime", 42}, true},
+		// An explicit repository wins over the default
+		{"fyne-io/fyne#3", "0jc1/gotime", GitHubIssue{"fyne-io/fyne", 3}, true},
+		{"#42", "", GitHubIssue{}, false},
+		{"gotime#42", "0jc1/gotime", GitHubIssue{}, false},
+		{"https://gitlab.com/0jc1/gotime/issues/42", "", GitHubIssue{}, false},
+		{"", "0jc1/gotime", GitHubIssue{}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			test.NewTempApp(t).Preferences().SetString(PrefGitHubRepo, tc.defaultRepo)
+			got, err := parseGitHubIssue(tc.ref)
+			if (err == nil) != tc.ok || got != tc.want {
+				t.Errorf("parseGitHubIssue(%q) = %v, %v; want %v, ok %v", tc.ref, got, err, tc.want, tc.ok)
+			}
+		})
+	}
+}
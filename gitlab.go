@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const (
+	PrefGitLabEnabled = "gitlabEnabled"
+	PrefGitLabURL     = "gitlabURL"
+	// PrefGitLabToken held the token before it moved to the keychain
+	PrefGitLabToken   = "gitlabToken"
+	PrefGitLabProject = "gitlabProject"
+
+	GitLabDefaultURL = "https://gitlab.com"
+)
+
+// An issue reference in a task name: group/project#123, or #123 in the
+// default project
+var gitlabIssuePattern = regexp.MustCompile(`(?:^|\s)([\w.-]+(?:/[\w.-]+)+)?#(\d+)\b`)
+
+// gitlabIssue returns the project and issue number named in a task, with
+// the project falling back to the default one from settings.
+func gitlabIssue(taskName, defaultProject string) (project, iid string) {
+	m := gitlabIssuePattern.FindStringSubmatch(taskName)
+	if m == nil {
+		return "", ""
+	}
+	project = m[1]
+	if project == "" {
+		project = defaultProject
+	}
+	if project == "" {
+		return "", ""
+	}
+	return project, m[2]
+}
+
+// gitlabDuration writes a duration the way GitLab's time tracking reads it,
+// in whole minutes and at least one.
+func gitlabDuration(entry Entry) string {
+	return fmt.Sprintf("%dm", max(1, int(entry.Duration.Round(time.Minute).Minutes())))
+}
+
+// pushGitLabSpentTime adds a recorded entry to the time spent on its GitLab
+// issue in the background, if the integration is enabled and the task names
+// an issue.
+func pushGitLabSpentTime(timer *TaskTimer, entry Entry) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefGitLabEnabled) {
+		return
+	}
+	project, iid := gitlabIssue(entry.Task, strings.TrimSpace(prefs.String(PrefGitLabProject)))
+	if iid == "" {
+		return
+	}
+
+	// Blank means gitlab.com
+	baseURL := strings.TrimRight(prefs.String(PrefGitLabURL), "/")
+	if baseURL == "" {
+		baseURL = GitLabDefaultURL
+	}
+	token := keychainSecret(KeyringGitLabToken)
+
+	go func() {
+		err := postGitLabSpentTime(baseURL, token, project, iid, entry)
+		if err != nil {
+			log.Print(err)
+			fyne.Do(func() {
+				dialog.ShowError(err, timer.window)
+			})
+		}
+	}()
+}
+
+func postGitLabSpentTime(baseURL, token, project, iid string, entry Entry) error {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues/%s/add_spent_time?%s",
+		baseURL, url.PathEscape(project), iid, url.Values{
+			"duration": {gitlabDuration(entry)},
+			"summary":  {entry.Notes},
+		}.Encode())
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitlab: logging time on %s#%s: %w", project, iid, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("gitlab: logging time on %s#%s: %s", project, iid, resp.Status)
+	}
+	return nil
+}
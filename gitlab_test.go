@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestGitLabIssue(t *testing.T) {
+	tests := []struct {
+		task, defaultProject string
+		project, iid         string
+	}{
+		{"Fix login group/app#12", "", "group/app", "12"},
+		{"group/sub/app#7 review", "", "group/sub/app", "7"},
+		{"#12 Fix login", "group/app", "group/app", "12"},
+		// A project in the task wins over the default
+		{"other/app#3", "group/app", "other/app", "3"},
+		{"#12 Fix login", "", "", ""},
+		{"Fix login", "group/app", "", ""},
+		// Part of a word isn't a reference
+		{"issue#12", "group/app", "", ""},
+	}
+	for _, tc := range tests {
+		project, iid := gitlabIssue(tc.task, tc.defaultProject)
+		if project != tc.project || iid != tc.iid {
+			t.Errorf("gitlabIssue(%q, %q) = %q, %q; want %q, %q", tc.task, tc.defaultProject, project, iid, tc.project, tc.iid)
+		}
+	}
+}
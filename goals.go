@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const PrefWeeklyGoals = "weeklyGoals"
+
+// WeeklyGoals are the target durations per task for one week.
+type WeeklyGoals map[string]time.Duration
+
+// weekStart returns midnight on the first day of the week containing t.
+func weekStart(t time.Time) time.Time {
+	daysSinceStart := (int(t.Local().Weekday()) - int(firstWeekday()) + 7) % 7
+	return addDays(t, -daysSinceStart)
+}
+
+// StreakLookback bounds how far back trackingStreak looks.
+const StreakLookback = 366
+
+// trackingStreak counts the consecutive days with tracked time up to today.
+// A streak still counts if nothing has been tracked yet today, and days off
+// are passed over.
+func trackingStreak(timer *TaskTimer, now time.Time) int {
+	today := dayStart(now)
+	days := dailyTotal(timer, addDays(today, -StreakLookback), addDays(today, 1))
+	return streak(today, loadTimeOff(), func(day time.Time) bool {
+		return days[day] > 0
+	})
+}
+
+// streak counts the consecutive days up to today on which kept holds,
+// passing over days off and today if it doesn't hold yet.
+func streak(today time.Time, off TimeOff, kept func(day time.Time) bool) int {
+	day := today
+	if !kept(day) {
+		day = addDays(day, -1)
+	}
+	count := 0
+	for i := 0; i < StreakLookback; i, day = i+1, addDays(day, -1) {
+		switch {
+		case kept(day):
+			count++
+		case off.On(day) == "":
+			return count
+		}
+	}
+	return count
+}
+
+func weekKey(start time.Time) string {
+	return start.Format("2006-01-02")
+}
+
+func loadAllWeeklyGoals() map[string]WeeklyGoals {
+	all := make(map[string]WeeklyGoals)
+	raw := fyne.CurrentApp().Preferences().String(PrefWeeklyGoals)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &all); err != nil {
+			log.Printf("goals: reading weekly goals: %v", err)
+		}
+	}
+	return all
+}
+
+// weeklyGoals returns the goals set for the week starting at start.
+func weeklyGoals(start time.Time) WeeklyGoals {
+	if goals, ok := loadAllWeeklyGoals()[weekKey(start)]; ok {
+		return goals
+	}
+	return WeeklyGoals{}
+}
+
+func setWeeklyGoals(start time.Time, goals WeeklyGoals) {
+	all := loadAllWeeklyGoals()
+	all[weekKey(start)] = goals
+
+	raw, err := json.Marshal(all)
+	if err != nil {
+		log.Printf("goals: saving weekly goals: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefWeeklyGoals, string(raw))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefGuestMode = "guestMode"
+
+	// GuestPlaceholder stands in for hidden values in guest mode
+	GuestPlaceholder = "•••"
+)
+
+// guestMode reports whether client names, rates and amounts should be hidden,
+// e.g. while the screen is shared.
+func guestMode() bool {
+	return fyne.CurrentApp().Preferences().Bool(PrefGuestMode)
+}
+
+// guestText returns s, or the placeholder in guest mode. Empty values stay
+// empty so it doesn't look like something is there.
+func guestText(s string) string {
+	if s != "" && guestMode() {
+		return GuestPlaceholder
+	}
+	return s
+}
+
+// blockedInGuestMode tells the user a screen is unavailable in guest mode and
+// reports whether it should stay closed.
+func blockedInGuestMode(timer *TaskTimer, title string) bool {
+	if !guestMode() {
+		return false
+	}
+	dialog.ShowInformation(title, tr("This is hidden while guest mode is on."), timer.window)
+	return true
+}
+
+// newGuestModeCheck is the sidebar toggle for guest mode. Views are rebuilt
+// when it changes so nothing stays on screen.
+func newGuestModeCheck(timer *TaskTimer) *widget.Check {
+	check := widget.NewCheck(tr("Guest mode"), func(on bool) {
+		fyne.CurrentApp().Preferences().SetBool(PrefGuestMode, on)
+		showView(timer, timer.currentView)
+	})
+	check.SetChecked(guestMode())
+	return check
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// readHamsterDB reads the facts from a Hamster database, usually
+// ~/.local/share/hamster/hamster.db. Activities become tasks, categories
+// projects, descriptions notes, and tags tags. Facts still running are
+// skipped.
+func readHamsterDB(path string) ([]Entry, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("hamster: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT f.id, a.name, COALESCE(c.name, ''), f.start_time, f.end_time, COALESCE(f.description, '')
+		FROM facts f
+		JOIN activities a ON a.id = f.activity_id
+		LEFT JOIN categories c ON c.id = a.category_id
+		WHERE f.end_time IS NOT NULL
+		ORDER BY f.start_time`)
+	if err != nil {
+		return nil, fmt.Errorf("hamster: not a Hamster database: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	index := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var entry Entry
+		var start, end any
+		if err := rows.Scan(&id, &entry.Task, &entry.Project, &start, &end, &entry.Notes); err != nil {
+			return nil, fmt.Errorf("hamster: %w", err)
+		}
+		if entry.Start, err = hamsterTime(start); err != nil {
+			return nil, err
+		}
+		if entry.End, err = hamsterTime(end); err != nil {
+			return nil, err
+		}
+		entry.Duration = entry.End.Sub(entry.Start)
+		index[id] = len(entries)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("hamster: %w", err)
+	}
+
+	tags, err := db.Query(`SELECT ft.fact_id, t.name FROM fact_tags ft JOIN tags t ON t.id = ft.tag_id`)
+	if err != nil {
+		return nil, fmt.Errorf("hamster: %w", err)
+	}
+	defer tags.Close()
+	for tags.Next() {
+		var id int64
+		var tag string
+		if err := tags.Scan(&id, &tag); err != nil {
+			return nil, fmt.Errorf("hamster: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			entries[i].Tags = append(entries[i].Tags, tag)
+		}
+	}
+	return entries, tags.Err()
+}
+
+// hamsterTime reads a fact's time, which Hamster stores as local time
+// without a zone. The driver hands back TIMESTAMP columns either as text or
+// as a time it takes to be UTC.
+func hamsterTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.Local), nil
+	case []byte:
+		return hamsterTime(string(v))
+	case string:
+		for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("hamster: unreadable time %v", value)
+}
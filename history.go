@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// HistoryPageSize is how many entries the History view shows per page
+	HistoryPageSize = 20
+
+	historyTimeLayout = "2006-01-02 15:04"
+)
+
+// createHistoryContainer lists individual entries, newest first, a page at
+// a time. Each entry can be edited in place, split or deleted, and the list
+// can be searched by task and notes.
+func createHistoryContainer(timer *TaskTimer) fyne.CanvasObject {
+	searchInput := widget.NewEntry()
+	searchInput.PlaceHolder = tr("Search tasks and notes")
+	overlapsCheck := widget.NewCheck(tr("Overlapping only"), nil)
+	list := container.NewVBox()
+	pageLabel := widget.NewLabel("")
+	prevBtn := widget.NewButtonWithIcon(tr("Previous"), theme.NavigateBackIcon(), nil)
+	nextBtn := widget.NewButtonWithIcon(tr("Next"), theme.NavigateNextIcon(), nil)
+
+	page := 0
+	selection := entrySelection{}
+	var pageIDs []string
+	var render func()
+	bulkBar, updateBulkBar := newHistoryBulkBar(timer, selection, func() []string { return pageIDs }, func() { render() })
+	render = func() {
+		var entries []Entry
+		query := strings.TrimSpace(searchInput.Text)
+		all := allEntries(timer)
+		// Forget ticked entries since deleted, here or by a sync
+		stored := make(map[string]bool, len(all))
+		for _, entry := range all {
+			stored[entry.ID] = true
+		}
+		for id := range selection {
+			if !stored[id] {
+				delete(selection, id)
+			}
+		}
+		var overlaps map[string][]Entry
+		if !overlapsAllowed() {
+			overlaps = findOverlaps(all)
+		}
+		for _, entry := range all {
+			if overlapsCheck.Checked && len(overlaps[entry.ID]) == 0 {
+				continue
+			}
+			if query == "" || matchesQuery(entry, query) {
+				entries = append(entries, entry)
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Start.After(entries[j].Start)
+		})
+
+		pages := max(1, (len(entries)+HistoryPageSize-1)/HistoryPageSize)
+		page = min(max(page, 0), pages-1)
+
+		list.RemoveAll()
+		if len(entries) == 0 && (query != "" || overlapsCheck.Checked) {
+			list.Add(widget.NewLabel(tr("No matching entries")))
+		} else if len(entries) == 0 {
+			list.Add(widget.NewLabel(tr("No entries recorded")))
+		}
+		pageIDs = nil
+		for _, entry := range entries[page*HistoryPageSize : min(len(entries), (page+1)*HistoryPageSize)] {
+			selectCheck := widget.NewCheck("", func(selected bool) {
+				if selected {
+					selection[entry.ID] = true
+				} else {
+					delete(selection, entry.ID)
+				}
+				updateBulkBar()
+			})
+			selectCheck.Checked = selection[entry.ID]
+			list.Add(newHistoryRow(timer, entry, overlaps[entry.ID], selectCheck, render))
+			pageIDs = append(pageIDs, entry.ID)
+		}
+		updateBulkBar()
+
+		pageLabel.SetText(tr("Page {{.Page}} of {{.Pages}}", map[string]any{"Page": page + 1, "Pages": pages}))
+		if page == 0 {
+			prevBtn.Disable()
+		} else {
+			prevBtn.Enable()
+		}
+		if page == pages-1 {
+			nextBtn.Disable()
+		} else {
+			nextBtn.Enable()
+		}
+	}
+
+	prevBtn.OnTapped = func() {
+		page--
+		render()
+	}
+	nextBtn.OnTapped = func() {
+		page++
+		render()
+	}
+	searchInput.OnChanged = func(string) {
+		page = 0
+		render()
+	}
+	overlapsCheck.OnChanged = func(bool) {
+		page = 0
+		render()
+	}
+	render()
+	// Renaming or merging a task elsewhere relabels its entries
+	observeTasksWhileShown(timer, render)
+
+	auditBtn := widget.NewButtonWithIcon(tr("Audit Log…"), theme.HistoryIcon(), func() {
+		showAuditLog(timer, "")
+	})
+
+	if overlapsAllowed() {
+		overlapsCheck.Hide()
+	}
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, overlapsCheck, searchInput),
+		bulkBar,
+		list,
+		container.NewBorder(nil, nil, container.NewHBox(prevBtn, pageLabel, nextBtn), auditBtn),
+	)
+}
+
+// newHistoryRow shows an entry with selectCheck, if not nil, which ticks it
+// for the bulk actions, and its buttons, and flags it if it waits for review
+// or overlaps the entries in overlaps.
+func newHistoryRow(timer *TaskTimer, entry Entry, overlaps []Entry, selectCheck *widget.Check, refresh func()) fyne.CanvasObject {
+	summary := widget.NewLabel(fmt.Sprintf("%s – %s  %s  %s",
+		weekdayAbbrev(entry.Start.Weekday())+" "+formatDayTime(entry.Start),
+		entry.End.Format("15:04"),
+		entry.Task,
+		formatDuration(entry.Duration),
+	))
+	summary.Truncation = fyne.TextTruncateEllipsis
+	if entry.Adjustment != 0 {
+		sign := "+"
+		if entry.Adjustment < 0 {
+			sign = "−"
+		}
+		summary.SetText(summary.Text + "  " + tr("(adjusted {{.Duration}})", map[string]any{
+			"Duration": sign + formatShortDuration(entry.Adjustment.Abs()),
+		}))
+	}
+	// Time the timer didn't count, apart from what was taken off by hand
+	if paused := entry.End.Sub(entry.Start) - entry.Duration + entry.Adjustment; paused >= time.Minute {
+		summary.SetText(summary.Text + "  " + tr("(paused {{.Duration}})", map[string]any{
+			"Duration": formatShortDuration(paused.Truncate(time.Minute)),
+		}))
+	}
+	if len(entry.Tags) > 0 {
+		summary.SetText(summary.Text + "  #" + strings.Join(entry.Tags, " #"))
+	}
+
+	row := container.NewVBox()
+	editor := container.NewVBox()
+	editor.Hide()
+
+	editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+		if editor.Visible() {
+			editor.Hide()
+			return
+		}
+		editor.Objects = []fyne.CanvasObject{newHistoryEditor(timer, entry, refresh, editor.Hide)}
+		editor.Show()
+	})
+	splitBtn := widget.NewButtonWithIcon("", theme.ContentCutIcon(), func() {
+		showSplitEntryDialog(timer, entry, refresh)
+	})
+	deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		dialog.ShowConfirm(tr("Delete Entry"), tr("Delete this entry?"), func(ok bool) {
+			if !ok {
+				return
+			}
+			err := changeEntries(timer, tr("Entry deleted"), func() error {
+				return deleteEntry(timer, entry.ID)
+			})
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			refresh()
+		}, timer.window)
+	})
+
+	auditBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		showAuditLog(timer, entry.ID)
+	})
+
+	var leading fyne.CanvasObject
+	if selectCheck != nil {
+		leading = selectCheck
+	}
+	row.Add(container.NewBorder(nil, nil, leading, container.NewHBox(editBtn, splitBtn, auditBtn, deleteBtn), summary))
+	if needsReview(entry) {
+		flag := widget.NewLabel(reviewReason(entry))
+		flag.Wrapping = fyne.TextWrapWord
+		reviewedBtn := widget.NewButton(tr("Looks right"), func() {
+			err := changeEntries(timer, tr("Entry approved"), func() error {
+				return updateEntry(timer, approveEntry(entry))
+			})
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			refresh()
+		})
+		row.Add(container.NewBorder(nil, nil, nil, reviewedBtn, flag))
+	}
+	if len(overlaps) > 0 {
+		flag := widget.NewLabel(tr("⚠ Overlaps:") + "\n" + describeOverlaps(overlaps))
+		flag.Importance = widget.WarningImportance
+		flag.Wrapping = fyne.TextWrapWord
+		trimBtn := widget.NewButton(tr("Trim the others"), func() {
+			err := changeEntries(timer, tr("Entries trimmed"), func() error {
+				return trimOverlaps(timer, entry)
+			})
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			refresh()
+		})
+		row.Add(container.NewBorder(nil, nil, nil, trimBtn, flag))
+	}
+	if entry.Notes != "" {
+		notes := widget.NewLabelWithStyle(entry.Notes, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		notes.Wrapping = fyne.TextWrapWord
+		row.Add(notes)
+	}
+	row.Add(editor)
+	return row
+}
+
+// newHistoryEditor is the inline form for changing an entry's times, task
+// and notes.
+func newHistoryEditor(timer *TaskTimer, entry Entry, refresh, cancel func()) fyne.CanvasObject {
+	startInput := widget.NewEntry()
+	startInput.SetText(entry.Start.Format(historyTimeLayout))
+	endInput := widget.NewEntry()
+	endInput.SetText(entry.End.Format(historyTimeLayout))
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.SetText(entry.Task)
+	notesInput := widget.NewMultiLineEntry()
+	notesInput.SetText(entry.Notes)
+	billableCheck := widget.NewCheck(tr("Billable"), nil)
+	billableCheck.SetChecked(!entry.NonBillable)
+
+	form := widget.NewForm(
+		widget.NewFormItem(tr("Start"), startInput),
+		widget.NewFormItem(tr("End"), endInput),
+		widget.NewFormItem(tr("Task"), taskInput),
+		widget.NewFormItem(tr("Notes"), notesInput),
+		widget.NewFormItem("", billableCheck),
+	)
+	form.SubmitText = tr("Save")
+	form.OnCancel = cancel
+	form.OnSubmit = func() {
+		start, err := time.ParseInLocation(historyTimeLayout, startInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		end, err := time.ParseInLocation(historyTimeLayout, endInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if !end.After(start) {
+			dialog.ShowInformation(tr("Edit Entry"), tr("The end must be after the start."), timer.window)
+			return
+		}
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected {
+			dialog.ShowInformation(tr("Edit Entry"), tr("Choose a task."), timer.window)
+			return
+		}
+
+		edited := entry
+		edited.Task = taskName
+		edited.Notes = strings.TrimSpace(notesInput.Text)
+		edited.NonBillable = !billableCheck.Checked
+		// Saving the entry is reviewing it
+		edited = approveEntry(edited)
+
+		// Editing the times replaces the tracked duration with the new span;
+		// otherwise keep it so paused time stays excluded
+		if !start.Equal(entry.Start.Truncate(time.Minute)) || !end.Equal(entry.End.Truncate(time.Minute)) {
+			edited.Start, edited.End = start, end
+			edited.Duration = end.Sub(start)
+		}
+
+		saveWithoutOverlaps(timer, tr("Entry edited"), edited, func() error {
+			return updateEntry(timer, edited)
+		}, func() {
+			timer.tasks.Ensure(taskName)
+			refresh()
+		})
+	}
+	return form
+}
+
+func showSplitEntryDialog(timer *TaskTimer, entry Entry, refresh func()) {
+	midpoint := entry.Start.Add(entry.End.Sub(entry.Start) / 2)
+	atInput := widget.NewEntry()
+	atInput.SetText(midpoint.Format(historyTimeLayout))
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.SetText(entry.Task)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Split at"), atInput),
+		widget.NewFormItem(tr("Second half"), taskInput),
+	}
+	dialog.ShowForm(tr("Split Entry"), tr("Split"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		at, err := time.ParseInLocation(historyTimeLayout, atInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected {
+			taskName = entry.Task
+		}
+
+		err = changeEntries(timer, tr("Entry split"), func() error {
+			return splitEntry(timer, entry.ID, at, taskName)
+		})
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		timer.tasks.Ensure(taskName)
+		refresh()
+	}, timer.window)
+}
+
+// showSplitSessionDialog asks where to divide the session in progress and
+// which task each part belongs to.
+func showSplitSessionDialog(timer *TaskTimer) {
+	if timer.elapsedTime == 0 {
+		dialog.ShowInformation(tr("Split Session"), tr("Start a session before splitting it."), timer.window)
+		return
+	}
+
+	now := time.Now()
+	midpoint := timer.sessionStart.Add(now.Sub(timer.sessionStart) / 2)
+	atInput := widget.NewEntry()
+	atInput.SetText(midpoint.Format(historyTimeLayout))
+	firstInput := widget.NewSelectEntry(timer.tasks.Active())
+	secondInput := widget.NewSelectEntry(timer.tasks.Active())
+	if timer.taskName != NoTaskSelected {
+		firstInput.SetText(timer.taskName)
+		secondInput.SetText(timer.taskName)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Split at"), atInput),
+		widget.NewFormItem(tr("First half"), firstInput),
+		widget.NewFormItem(tr("Second half"), secondInput),
+	}
+	dialog.ShowForm(tr("Split Session"), tr("Split"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		at, err := time.ParseInLocation(historyTimeLayout, atInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		firstTask := strings.TrimSpace(firstInput.Text)
+		secondTask := strings.TrimSpace(secondInput.Text)
+		if firstTask == "" || firstTask == NoTaskSelected || secondTask == "" || secondTask == NoTaskSelected {
+			dialog.ShowInformation(tr("Split Session"), tr("Choose a task for each half."), timer.window)
+			return
+		}
+
+		if err := splitSession(timer, at, firstTask, secondTask); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	}, timer.window)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// shownText joins the text of every label under obj.
+func shownText(obj fyne.CanvasObject) string {
+	switch obj := obj.(type) {
+	case *widget.Label:
+		return obj.Text + "\n"
+	case *fyne.Container:
+		var text strings.Builder
+		for _, child := range obj.Objects {
+			text.WriteString(shownText(child))
+		}
+		return text.String()
+	}
+	return ""
+}
+
+func TestHistoryFollowsTaskRenames(t *testing.T) {
+	app := test.NewTempApp(t)
+	store, err := OpenJSONStore(filepath.Join(t.TempDir(), "gotime.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	timer := &TaskTimer{
+		taskName:    NoTaskSelected,
+		taskList:    make(map[string]time.Duration),
+		dailyTotals: make(map[time.Time]map[string]time.Duration),
+		store:       store,
+		audit:       NewAuditLog(store),
+		tasks:       NewTaskStore(store, app.Preferences()),
+		window:      test.NewWindow(nil),
+	}
+	newTimerBindings(timer)
+	start := time.Now().Add(-2 * time.Hour)
+	recordEntry(timer, Entry{Task: "Write code", Start: start, End: start.Add(time.Hour), Duration: time.Hour})
+
+	history := createHistoryContainer(timer)
+	if !strings.Contains(shownText(history), "Write code") {
+		t.Fatalf("the entry isn't listed:\n%s", shownText(history))
+	}
+	renameTask(timer, "Write code", "Write docs")
+	if shown := shownText(history); !strings.Contains(shown, "Write docs") {
+		t.Errorf("the history still shows the old name:\n%s", shown)
+	}
+
+	// Once the view is swapped out it stops following the list
+	unbindView(timer)
+	renameTask(timer, "Write docs", "Write tests")
+	if shown := shownText(history); strings.Contains(shown, "Write tests") {
+		t.Errorf("a hidden history view was rebuilt:\n%s", shown)
+	}
+}
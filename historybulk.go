@@ -0,0 +1,222 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// entrySelection is the IDs of the entries ticked in History. It's kept
+// across pages and searches until an action is applied to it or it's
+// cleared.
+type entrySelection map[string]bool
+
+// entries returns the selected entries still stored, e.g. not deleted by a
+// sync since.
+func (s entrySelection) entries(timer *TaskTimer) []Entry {
+	var selected []Entry
+	for _, entry := range allEntries(timer) {
+		if s[entry.ID] {
+			selected = append(selected, entry)
+		}
+	}
+	return selected
+}
+
+func (s entrySelection) clear() {
+	for id := range s {
+		delete(s, id)
+	}
+}
+
+// newHistoryBulkBar is the bar History shows while entries are ticked, with
+// the actions that apply to all of them at once. pageIDs lists the entries
+// on the page shown, for Select Page, and refresh redraws the list after the
+// selection or the entries change. The bar hides itself until something is
+// ticked; update redraws it after the selection changes.
+func newHistoryBulkBar(timer *TaskTimer, selection entrySelection, pageIDs func() []string, refresh func()) (bar fyne.CanvasObject, update func()) {
+	countLabel := widget.NewLabel("")
+	done := func() {
+		selection.clear()
+		refresh()
+	}
+
+	var actionsBtn *widget.Button
+	actionsBtn = widget.NewButtonWithIcon(tr("Actions"), theme.MenuIcon(), func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem(tr("Move to task…"), func() { showBulkTaskDialog(timer, selection, done) }),
+			fyne.NewMenuItem(tr("Add tag…"), func() { showBulkTagDialog(timer, selection, true, done) }),
+			fyne.NewMenuItem(tr("Remove tag…"), func() { showBulkTagDialog(timer, selection, false, done) }),
+			fyne.NewMenuItem(tr("Mark billable"), func() { confirmBulkBillable(timer, selection, true, done) }),
+			fyne.NewMenuItem(tr("Mark not billable"), func() { confirmBulkBillable(timer, selection, false, done) }),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem(tr("Delete…"), func() { confirmBulkDelete(timer, selection, done) }),
+		)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(actionsBtn)
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(actionsBtn)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, position.AddXY(0, actionsBtn.Size().Height))
+	})
+	selectPageBtn := widget.NewButton(tr("Select Page"), func() {
+		for _, id := range pageIDs() {
+			selection[id] = true
+		}
+		refresh()
+	})
+	clearBtn := widget.NewButtonWithIcon(tr("Clear"), theme.ContentClearIcon(), done)
+
+	box := container.NewBorder(nil, nil, countLabel, container.NewHBox(selectPageBtn, clearBtn, actionsBtn))
+	update = func() {
+		if len(selection) == 0 {
+			box.Hide()
+			return
+		}
+		countLabel.SetText(tr("{{.Count}} selected", map[string]any{"Count": len(selection)}))
+		box.Show()
+	}
+	update()
+	return box, update
+}
+
+// bulkEdit changes the selected entries as one action under label. edit
+// reports whether it changed an entry, so those left alone aren't saved.
+func bulkEdit(timer *TaskTimer, selection entrySelection, label string, edit func(*Entry) bool) error {
+	return changeEntries(timer, label, func() error {
+		for _, entry := range selection.entries(timer) {
+			entry.Tags = slices.Clone(entry.Tags)
+			if !edit(&entry) {
+				continue
+			}
+			if err := updateEntry(timer, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// finishBulkAction reports err, if any, and otherwise clears the selection.
+func finishBulkAction(timer *TaskTimer, err error, done func()) {
+	if err != nil {
+		dialog.ShowError(err, timer.window)
+		return
+	}
+	done()
+}
+
+func bulkCountText(selection entrySelection) string {
+	return tr("Apply to {{.Count}} entries", map[string]any{"Count": len(selection)})
+}
+
+func showBulkTaskDialog(timer *TaskTimer, selection entrySelection, done func()) {
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.PlaceHolder = tr("Task")
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Task"), taskInput),
+		widget.NewFormItem("", widget.NewLabel(bulkCountText(selection))),
+	}
+	d := dialog.NewForm(tr("Move to Task"), tr("Move"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected || taskName == tr(NoTaskSelected) {
+			dialog.ShowInformation(tr("Move to Task"), tr("Choose a task."), timer.window)
+			return
+		}
+		timer.tasks.Ensure(taskName)
+		err := bulkEdit(timer, selection, tr("Moved to {{.Task}}", map[string]any{"Task": taskName}), func(entry *Entry) bool {
+			if entry.Task == taskName {
+				return false
+			}
+			entry.Task = taskName
+			return true
+		})
+		finishBulkAction(timer, err, done)
+	}, timer.window)
+	d.Resize(fyne.NewSize(340, 0))
+	d.Show()
+}
+
+// showBulkTagDialog adds a tag to the selected entries, or removes it from
+// them if add is false.
+func showBulkTagDialog(timer *TaskTimer, selection entrySelection, add bool, done func()) {
+	tagInput := widget.NewEntry()
+	tagInput.PlaceHolder = tr("e.g. meeting")
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Tag"), tagInput),
+		widget.NewFormItem("", widget.NewLabel(bulkCountText(selection))),
+	}
+	title, confirm, label := tr("Add Tag"), tr("Add"), tr("Tag added")
+	if !add {
+		title, confirm, label = tr("Remove Tag"), tr("Remove"), tr("Tag removed")
+	}
+	d := dialog.NewForm(title, confirm, tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		tags := parseTags(tagInput.Text)
+		if len(tags) == 0 {
+			return
+		}
+		err := bulkEdit(timer, selection, label, func(entry *Entry) bool {
+			changed := false
+			for _, tag := range tags {
+				switch {
+				case add && !contains(entry.Tags, tag):
+					entry.Tags = append(entry.Tags, tag)
+					changed = true
+				case !add && contains(entry.Tags, tag):
+					entry.Tags = slices.DeleteFunc(entry.Tags, func(t string) bool { return t == tag })
+					changed = true
+				}
+			}
+			return changed
+		})
+		finishBulkAction(timer, err, done)
+	}, timer.window)
+	d.Resize(fyne.NewSize(340, 0))
+	d.Show()
+}
+
+func confirmBulkBillable(timer *TaskTimer, selection entrySelection, billable bool, done func()) {
+	message, label := tr("Mark {{.Count}} entries billable?", map[string]any{"Count": len(selection)}), tr("Entries marked billable")
+	if !billable {
+		message, label = tr("Mark {{.Count}} entries not billable?", map[string]any{"Count": len(selection)}), tr("Entries marked not billable")
+	}
+	dialog.ShowConfirm(tr("Billable"), message, func(ok bool) {
+		if !ok {
+			return
+		}
+		err := bulkEdit(timer, selection, label, func(entry *Entry) bool {
+			if entry.NonBillable == !billable {
+				return false
+			}
+			entry.NonBillable = !billable
+			return true
+		})
+		finishBulkAction(timer, err, done)
+	}, timer.window)
+}
+
+func confirmBulkDelete(timer *TaskTimer, selection entrySelection, done func()) {
+	message := tr("Delete {{.Count}} entries?", map[string]any{"Count": len(selection)})
+	dialog.ShowConfirm(tr("Delete Entries"), message, func(ok bool) {
+		if !ok {
+			return
+		}
+		err := changeEntries(timer, tr("Entries deleted"), func() error {
+			for _, entry := range selection.entries(timer) {
+				if err := deleteEntry(timer, entry.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		finishBulkAction(timer, err, done)
+	}, timer.window)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	EventIdleDetected = "idle.detected"
+	EventDayTargetHit = "day.target_hit"
+
+	PrefHookCommands  = "hookCommands"
+	PrefDayTarget     = "dayTarget"
+	PrefLastDayTarget = "lastDayTarget"
+
+	// HookTimeout is how long a hook command may run before it is stopped
+	HookTimeout = time.Minute
+
+	// DayTargetCheckInterval is how often the session in progress is
+	// checked against the daily target
+	DayTargetCheckInterval = time.Minute
+)
+
+// HookEvents are the events a command can be hooked to, in the order the
+// settings list them.
+var HookEvents = []string{EventTimerStarted, EventTimerStopped, EventIdleDetected, EventDayTargetHit}
+
+// hookEventName names an event for the settings.
+func hookEventName(event string) string {
+	switch event {
+	case EventTimerStarted:
+		return tr("Timer started")
+	case EventTimerStopped:
+		return tr("Timer stopped")
+	case EventIdleDetected:
+		return tr("Away detected")
+	default:
+		return tr("Daily target reached")
+	}
+}
+
+// hookCommands loads the shell command hooked to each event.
+func hookCommands() map[string]string {
+	commands := make(map[string]string)
+	raw := fyne.CurrentApp().Preferences().String(PrefHookCommands)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &commands); err != nil {
+			log.Printf("hooks: reading commands: %v", err)
+		}
+	}
+	return commands
+}
+
+func setHookCommands(commands map[string]string) {
+	raw, err := json.Marshal(commands)
+	if err != nil {
+		log.Printf("hooks: saving commands: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefHookCommands, string(raw))
+}
+
+// runHook runs the shell command hooked to an event, if any, in the
+// background. The command learns about the event from its environment:
+//
+//	GOTIME_EVENT            the event, e.g. timer.started
+//	GOTIME_TASK             the selected task, empty if none
+//	GOTIME_ELAPSED_SECONDS  the session's time so far
+//	GOTIME_TODAY_SECONDS    today's total, counting the session
+func runHook(timer *TaskTimer, event string) {
+	command := strings.TrimSpace(hookCommands()[event])
+	if command == "" {
+		return
+	}
+
+	taskName := ""
+	if timer.taskName != NoTaskSelected {
+		taskName = timer.taskName
+	}
+	var today time.Duration
+	for _, duration := range daySummaryTotals(timer, time.Now()) {
+		today += duration
+	}
+	env := append(os.Environ(),
+		"GOTIME_EVENT="+event,
+		"GOTIME_TASK="+taskName,
+		fmt.Sprintf("GOTIME_ELAPSED_SECONDS=%d", int64(timer.elapsedTime.Seconds())),
+		fmt.Sprintf("GOTIME_TODAY_SECONDS=%d", int64(today.Seconds())),
+	)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), HookTimeout)
+		defer cancel()
+		cmd := shellCommand(ctx, command)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			log.Printf("hooks: %s: %v: %s", event, err, strings.TrimSpace(string(output)))
+		}
+	}()
+}
+
+// shellCommand runs a command line through the platform's shell, so hooks
+// can use pipes and quoting.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// dayTarget returns the hours to track each day, zero if none is set.
+func dayTarget() time.Duration {
+	return time.Duration(fyne.CurrentApp().Preferences().Float(PrefDayTarget) * float64(time.Hour))
+}
+
+// watchDayTarget fires the daily target hook once a day, the first time
+// today's total reaches the target.
+func watchDayTarget(timer *TaskTimer) {
+	go func() {
+		for range time.Tick(DayTargetCheckInterval) {
+			fyne.Do(func() {
+				checkDayTarget(timer, time.Now())
+			})
+		}
+	}()
+}
+
+func checkDayTarget(timer *TaskTimer, now time.Time) {
+	target := dayTarget()
+	if target <= 0 || loadTimeOff().On(now) != "" {
+		return
+	}
+	prefs := fyne.CurrentApp().Preferences()
+	day := dayStart(now).Format(time.DateOnly)
+	if prefs.String(PrefLastDayTarget) == day {
+		return
+	}
+
+	var today time.Duration
+	for _, duration := range daySummaryTotals(timer, now) {
+		today += duration
+	}
+	if today < target {
+		return
+	}
+	prefs.SetString(PrefLastDayTarget, day)
+	runHook(timer, EventDayTargetHit)
+	playSound(timer, SoundDayTarget)
+}
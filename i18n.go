@@ -0,0 +1,115 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2/lang"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// translations holds a JSON file per language, named after its code, e.g.
+// de.json. The English text of each UI string is its key, so a string
+// missing from a file shows in English.
+//
+//go:embed translations
+var translations embed.FS
+
+// localizer translates into the system language. Until loadTranslations
+// runs, it leaves strings in English.
+var localizer = i18n.NewLocalizer(i18n.NewBundle(language.English))
+
+// loadTranslations picks the bundled translation for the system language.
+// It keeps its own bundle rather than Fyne's, which also holds Fyne's
+// languages and would report every string in those as missing.
+func loadTranslations() {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	files, err := translations.ReadDir("translations")
+	if err != nil {
+		log.Printf("translations: %v", err)
+	}
+	for _, file := range files {
+		if _, err := bundle.LoadMessageFileFS(translations, "translations/"+file.Name()); err != nil {
+			log.Printf("translations: %v", err)
+		}
+	}
+	localizer = i18n.NewLocalizer(bundle, lang.SystemLocale().LanguageString())
+}
+
+// tr translates a UI string, given in English. The string may be a template
+// such as "Page {{.Page}} of {{.Pages}}", filled in from data.
+func tr(message string, data ...map[string]any) string {
+	return trKey(message, message, data...)
+}
+
+// trKey translates the string with the given ID, for strings whose English
+// text alone would be ambiguous.
+func trKey(id, english string, data ...map[string]any) string {
+	config := &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{ID: id, Other: english},
+	}
+	if len(data) > 0 {
+		config.TemplateData = data[0]
+	}
+	// A string missing from the translation still comes back in English
+	translated, _ := localizer.Localize(config)
+	return translated
+}
+
+// monthAbbrev and weekdayAbbrev are the short names used in dates shown in
+// the UI, in the UI language.
+func monthAbbrev(month time.Month) string {
+	return trKey(fmt.Sprintf("month.short.%d", month), month.String()[:3])
+}
+
+func weekdayAbbrev(day time.Weekday) string {
+	return trKey(fmt.Sprintf("weekday.short.%d", day), day.String()[:3])
+}
+
+// monthName and weekdayName are the full names, for choosing a month or day.
+func monthName(month time.Month) string {
+	return trKey(fmt.Sprintf("month.%d", month), month.String())
+}
+
+func weekdayName(day time.Weekday) string {
+	return trKey(fmt.Sprintf("weekday.%d", day), day.String())
+}
+
+// formatDay renders a date without the year, e.g. "Jan 2" or "2. Jan.".
+// Inputs keep using YYYY-MM-DD, which is what they parse.
+func formatDay(t time.Time) string {
+	return tr("{{.Month}} {{.Day}}", map[string]any{
+		"Month": monthAbbrev(t.Month()),
+		"Day":   t.Day(),
+	})
+}
+
+// formatDate renders a date with the year, e.g. "Jan 2, 2006".
+func formatDate(t time.Time) string {
+	return tr("{{.Day}}, {{.Year}}", map[string]any{
+		"Day":  formatDay(t),
+		"Year": t.Year(),
+	})
+}
+
+// formatDayTime renders a date and time of day, e.g. "Jan 2 15:04".
+func formatDayTime(t time.Time) string {
+	return tr("{{.Day}} {{.Time}}", map[string]any{
+		"Day":  formatDay(t),
+		"Time": t.Format("15:04"),
+	})
+}
+
+// formatWeekdayTime renders a time with its day of the week, e.g.
+// "Mon 15:04", for times within a week.
+func formatWeekdayTime(t time.Time) string {
+	return tr("{{.Weekday}} {{.Time}}", map[string]any{
+		"Weekday": weekdayAbbrev(t.Weekday()),
+		"Time":    t.Format("15:04"),
+	})
+}
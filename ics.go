@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICSLineLimit is the longest line, in bytes, iCalendar allows before it
+// has to be folded.
+const ICSLineLimit = 75
+
+// writeEntriesICS writes entries as an iCalendar file with an event per
+// entry, for importing into a calendar app. Each event keeps the entry's ID
+// as its UID, so importing an overlapping export again updates the events
+// rather than duplicating them. The description names the project in the
+// export locale.
+func writeEntriesICS(w io.Writer, entries []Entry, locale ExportLocale) error {
+	out := bufio.NewWriter(w)
+	stamp := icsTime(time.Now())
+
+	writeLine := func(name, value string) {
+		line := name + ":" + value
+		// Folded lines continue with a space, after at most ICSLineLimit
+		// bytes and never inside a UTF-8 sequence
+		for len(line) > ICSLineLimit {
+			cut := ICSLineLimit
+			for cut > 0 && line[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			out.WriteString(line[:cut] + "\r\n")
+			line = " " + line[cut:]
+		}
+		out.WriteString(line + "\r\n")
+	}
+
+	writeLine("BEGIN", "VCALENDAR")
+	writeLine("VERSION", "2.0")
+	writeLine("PRODID", "-//gotime//gotime//EN")
+	writeLine("CALSCALE", "GREGORIAN")
+	for _, entry := range entries {
+		writeLine("BEGIN", "VEVENT")
+		writeLine("UID", icsText(entry.ID)+"@gotime")
+		writeLine("DTSTAMP", stamp)
+		writeLine("DTSTART", icsTime(entry.Start))
+		writeLine("DTEND", icsTime(entry.End))
+		writeLine("SUMMARY", icsText(entry.Task))
+		var description []string
+		if entry.Project != "" {
+			description = append(description, fmt.Sprintf("%s: %s", locale.Header("project"), entry.Project))
+		}
+		if entry.Notes != "" {
+			description = append(description, entry.Notes)
+		}
+		if len(description) > 0 {
+			writeLine("DESCRIPTION", icsText(strings.Join(description, "\n")))
+		}
+		if len(entry.Tags) > 0 {
+			var tags []string
+			for _, tag := range entry.Tags {
+				tags = append(tags, icsText(tag))
+			}
+			writeLine("CATEGORIES", strings.Join(tags, ","))
+		}
+		writeLine("END", "VEVENT")
+	}
+	writeLine("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// icsTime writes a time in UTC, which every calendar app reads without a
+// time zone definition.
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes a text value.
+func icsText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// InstanceSocketName is the socket the running app listens on, next to the
+// status file, so a second launch hands over to it instead of opening
+// another window on the same data.
+const InstanceSocketName = "instance.sock"
+
+var errNoInstance = errors.New("the app isn't running; open it first")
+
+// InstanceRequest is a command for the running app:
+//
+//	focus         brings its window to the front
+//	start [task]  starts the timer, on task if it isn't empty
+//	stop          pauses the timer
+//	switch task   moves the timer to task
+//
+// Starting or switching to a task can also set the session's project and
+// add tags to it.
+type InstanceRequest struct {
+	Command string   `json:"command"`
+	Task    string   `json:"task,omitempty"`
+	Project string   `json:"project,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// InstanceReply is the running app's answer, with the timer state after the
+// command. Queued means the app is still being unlocked, and runs the
+// command once it is.
+type InstanceReply struct {
+	Error  string `json:"error,omitempty"`
+	Queued bool   `json:"queued,omitempty"`
+	Status Status `json:"status"`
+}
+
+func instanceSocketPath() (string, error) {
+	path, err := statusFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), InstanceSocketName), nil
+}
+
+// callInstance sends a request to the running app. Without one, it returns
+// errNoInstance.
+func callInstance(request InstanceRequest) (InstanceReply, error) {
+	var reply InstanceReply
+	path, err := instanceSocketPath()
+	if err != nil {
+		return reply, err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return reply, errNoInstance
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(HTTPTimeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return reply, err
+	}
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return reply, err
+	}
+	if reply.Error != "" {
+		return reply, errors.New(reply.Error)
+	}
+	return reply, nil
+}
+
+// instanceRunning is whether another launch of the app is running.
+func instanceRunning() bool {
+	path, err := instanceSocketPath()
+	if err != nil {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// InstanceServer runs commands from later launches and from links. It
+// listens from the start, so they find it while the store is still being
+// unlocked; timer commands wait for the timer. The timer and the waiting
+// commands are only touched on the UI thread.
+type InstanceServer struct {
+	listener net.Listener
+	window   fyne.Window
+	timer    *TaskTimer
+	pending  []InstanceRequest
+}
+
+func NewInstanceServer(w fyne.Window) *InstanceServer {
+	return &InstanceServer{window: w}
+}
+
+// Listen claims the instance socket, taking over one left behind by an app
+// that didn't shut down cleanly.
+func (s *InstanceServer) Listen() error {
+	path, err := instanceSocketPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("instance: %w", err)
+	}
+	s.listener = listener
+	go s.serve()
+	return nil
+}
+
+// SetTimer lets timer commands through once the timer is set up, running
+// those that waited for it.
+func (s *InstanceServer) SetTimer(timer *TaskTimer) {
+	s.timer = timer
+	for _, request := range s.pending {
+		if err := s.run(request); err != nil {
+			dialog.ShowError(err, s.window)
+		}
+	}
+	s.pending = nil
+}
+
+// Wait keeps a command until the timer is set up, for the link the app was
+// launched with. It's called before the app runs.
+func (s *InstanceServer) Wait(request InstanceRequest) {
+	s.pending = append(s.pending, request)
+}
+
+// Open runs a command from a link, or keeps it until the timer is set up.
+func (s *InstanceServer) Open(request InstanceRequest) {
+	fyne.Do(func() {
+		if s.timer == nil {
+			s.pending = append(s.pending, request)
+			s.window.Show()
+			return
+		}
+		if err := s.run(request); err != nil {
+			dialog.ShowError(err, s.window)
+		}
+	})
+}
+
+// Close stops listening, which removes the socket.
+func (s *InstanceServer) Close() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+}
+
+func (s *InstanceServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("instance: %v", err)
+			}
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers one request, running it on the UI thread.
+func (s *InstanceServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(HTTPTimeout))
+
+	var request InstanceRequest
+	if err := json.NewDecoder(io.LimitReader(conn, 1<<16)).Decode(&request); err != nil {
+		json.NewEncoder(conn).Encode(InstanceReply{Error: err.Error()})
+		return
+	}
+	var reply InstanceReply
+	fyne.DoAndWait(func() {
+		if s.timer == nil && request.Command != "focus" {
+			s.pending = append(s.pending, request)
+			s.window.Show()
+			reply.Queued = true
+			return
+		}
+		if err := s.run(request); err != nil {
+			reply.Error = err.Error()
+		}
+		if s.timer != nil {
+			reply.Status = timerStatus(s.timer)
+			reply.Status.Today = nil
+		}
+	})
+	json.NewEncoder(conn).Encode(reply)
+}
+
+func (s *InstanceServer) run(request InstanceRequest) error {
+	taskName := strings.TrimSpace(request.Task)
+	s.window.Show()
+	s.window.RequestFocus()
+	if request.Command == "focus" {
+		return nil
+	}
+
+	timer := s.timer
+	switch request.Command {
+	case "start":
+		if err := startRemotely(timer, taskName); err != nil {
+			return err
+		}
+	case "stop":
+		if timer.isRunning {
+			pauseTimer(timer)
+		}
+		return nil
+	case "switch":
+		if taskName == "" {
+			return errTaskNameEmpty
+		}
+		switchTask(timer, taskName)
+	default:
+		return fmt.Errorf("unknown command %q", request.Command)
+	}
+
+	// The project and tags go on the session through a template of its own,
+	// as if it had been started from one
+	if request.Project == "" && len(request.Tags) == 0 {
+		return nil
+	}
+	template := TaskTemplate{Name: timer.taskName, Billable: true}
+	if timer.template.Name == timer.taskName {
+		template = timer.template
+	}
+	if project := strings.TrimSpace(request.Project); project != "" {
+		template.Project = project
+	}
+	for _, tag := range request.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !contains(template.Tags, tag) {
+			template.Tags = append(template.Tags, tag)
+		}
+	}
+	timer.template = template
+	return nil
+}
+
+// runTimerCommand forwards start, stop or switch to the running app and
+// prints the timer state afterwards, as gotime status does.
+func runTimerCommand(command string, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	request := InstanceRequest{Command: command, Task: strings.Join(flags.Args(), " ")}
+	if command == "switch" && strings.TrimSpace(request.Task) == "" {
+		fmt.Fprintln(stderr, "Usage: gotime switch TASK")
+		return 2
+	}
+	if command == "stop" && flags.NArg() > 0 {
+		fmt.Fprintln(stderr, "Usage: gotime stop")
+		return 2
+	}
+
+	reply, err := callInstance(request)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+	if reply.Queued {
+		fmt.Fprintln(stdout, "Queued until the app is unlocked")
+		return 0
+	}
+	printStatus(stdout, reply.Status)
+	return 0
+}
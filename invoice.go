@@ -0,0 +1,662 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefInvoiceTemplates = "invoiceTemplates"
+	PrefClients          = "clients"
+)
+
+// InvoiceColumns are the columns an invoice template can choose from, in the
+// order they are printed. Templates take copies, so it never changes.
+var InvoiceColumns = []string{"date", "task", "notes", "project", "hours", "rate", "amount"}
+
+// invoiceLabelFallbacks are used when the invoice language has no translation.
+var invoiceLabelFallbacks = map[string]string{
+	"invoice": "Invoice", "date": "Date", "task": "Task", "notes": "Notes", "project": "Project",
+	"hours": "Hours", "rate": "Rate", "amount": "Amount", "total": "Total",
+	"expenses": "Expenses", "description": "Description", "receipts": "Receipts",
+	"number": "Invoice no.", "issued": "Date of issue", "due": "Due by",
+	"subtotal": "Subtotal", "tax": "Tax",
+}
+
+// InvoiceTemplate is a named invoice layout that clients can be assigned.
+type InvoiceTemplate struct {
+	Name     string   `json:"name"`
+	LogoPath string   `json:"logoPath"`
+	Footer   string   `json:"footer"`
+	Language string   `json:"language"`
+	Columns  []string `json:"columns"`
+}
+
+// ClientSettings holds billing details for a client. Currency is empty for
+// a client billed in the home currency. TaxRate is a percentage added to
+// the hours, and PaymentDays how long after issue an invoice is due, none
+// if zero.
+type ClientSettings struct {
+	HourlyRate  float64 `json:"hourlyRate"`
+	Currency    string  `json:"currency,omitempty"`
+	TaxRate     float64 `json:"taxRate,omitempty"`
+	PaymentDays int     `json:"paymentDays,omitempty"`
+	Template    string  `json:"template"`
+}
+
+var defaultInvoiceTemplate = InvoiceTemplate{
+	Name:    "Default",
+	Columns: slices.Clone(InvoiceColumns),
+}
+
+func invoiceTemplates() []InvoiceTemplate {
+	var templates []InvoiceTemplate
+	raw := fyne.CurrentApp().Preferences().String(PrefInvoiceTemplates)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &templates); err != nil {
+			log.Printf("invoice: reading templates: %v", err)
+		}
+	}
+	if len(templates) == 0 {
+		templates = []InvoiceTemplate{defaultInvoiceTemplate}
+	}
+	return templates
+}
+
+func setInvoiceTemplates(templates []InvoiceTemplate) {
+	raw, err := json.Marshal(templates)
+	if err != nil {
+		log.Printf("invoice: saving templates: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefInvoiceTemplates, string(raw))
+}
+
+func invoiceTemplateByName(name string) InvoiceTemplate {
+	templates := invoiceTemplates()
+	for _, t := range templates {
+		if t.Name == name {
+			return t
+		}
+	}
+	return templates[0]
+}
+
+func clientSettings() map[string]ClientSettings {
+	clients := make(map[string]ClientSettings)
+	raw := fyne.CurrentApp().Preferences().String(PrefClients)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &clients); err != nil {
+			log.Printf("invoice: reading clients: %v", err)
+		}
+	}
+	return clients
+}
+
+func setClientSettings(clients map[string]ClientSettings) {
+	raw, err := json.Marshal(clients)
+	if err != nil {
+		log.Printf("invoice: saving clients: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefClients, string(raw))
+}
+
+// knownClients lists every client that appears on an entry or has settings.
+func knownClients(timer *TaskTimer) []string {
+	var clients []string
+	for client := range clientSettings() {
+		clients = append(clients, client)
+	}
+	for _, entry := range allEntries(timer) {
+		if entry.Client != "" && !contains(clients, entry.Client) {
+			clients = append(clients, entry.Client)
+		}
+	}
+	for _, expense := range expenses() {
+		if expense.Client != "" && !contains(clients, expense.Client) {
+			clients = append(clients, expense.Client)
+		}
+	}
+	sort.Strings(clients)
+	return clients
+}
+
+var invoiceHTML = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Number}} – {{.Client}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.logo { max-height: 80px; }
+.receipt { max-width: 100%; margin: 1em 0; display: block; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+tfoot td { font-weight: bold; }
+.details th, .details td { border: none; padding: 0 8px 0 0; }
+.summary { width: auto; margin-left: auto; }
+.summary tr:last-child td { font-weight: bold; }
+footer { margin-top: 2em; color: #555; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+{{if .Logo}}<img class="logo" src="{{.Logo}}" alt="">{{end}}
+<h1>{{.Title}}</h1>
+<p>{{.Client}}<br>{{.Period}}</p>
+<table class="details">
+{{range .Details}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+<table>
+<thead><tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+<tfoot><tr>{{range .Totals}}<td>{{.}}</td>{{end}}</tr></tfoot>
+</table>
+{{if .Summary}}<table class="summary">
+{{range .Summary}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}{{if .Expenses}}<h2>{{.ExpensesTitle}}</h2>
+<table>
+<thead><tr>{{range .ExpenseHeaders}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Expenses}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+<tfoot>{{range .ExpenseTotals}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tfoot>
+</table>
+{{end}}{{if .Receipts}}<h2>{{.ReceiptsTitle}}</h2>
+{{range .Receipts}}<img class="receipt" src="{{.}}" alt="">
+{{end}}{{end}}{{if .Footer}}<footer>{{.Footer}}</footer>{{end}}
+</body>
+</html>
+`))
+
+type invoiceView struct {
+	Lang    string
+	Title   string
+	Number  string
+	Client  string
+	Period  string
+	Details [][]string
+	Logo    template.URL
+	Headers []string
+	Rows    [][]string
+	Totals  []string
+	Summary [][]string
+	Footer  string
+
+	ExpensesTitle  string
+	ExpenseHeaders []string
+	Expenses       [][]string
+	ExpenseTotals  [][]string
+	ReceiptsTitle  string
+	Receipts       []template.URL
+}
+
+// writeInvoice renders an HTML invoice for a client's entries and
+// reimbursable expenses using the client's template, hourly rate and
+// currency, with the number, dates and tax of invoice.
+func writeInvoice(w io.Writer, invoice IssuedInvoice, entries []Entry, expenses []Expense) error {
+	client, from, to := invoice.Client, invoice.From, invoice.To
+	settings := clientSettings()[client]
+	currency := invoice.Currency
+	tmpl := invoiceTemplateByName(settings.Template)
+	locale := exportLocaleByTag(tmpl.Language)
+
+	label := func(key string) string {
+		if header, ok := locale.Headers[key]; ok {
+			return header
+		}
+		return invoiceLabelFallbacks[key]
+	}
+
+	view := invoiceView{
+		Lang:   locale.Tag,
+		Title:  label("invoice"),
+		Number: invoice.Number,
+		Client: client,
+		Period: locale.FormatDay(from) + " – " + locale.FormatDay(to.AddDate(0, 0, -1)),
+		Footer: tmpl.Footer,
+	}
+	view.Details = append(view.Details,
+		[]string{label("number"), invoice.Number},
+		[]string{label("issued"), locale.FormatDay(invoice.Issued)})
+	if !invoice.Due.IsZero() {
+		view.Details = append(view.Details, []string{label("due"), locale.FormatDay(invoice.Due)})
+	}
+
+	if tmpl.LogoPath != "" {
+		logo, err := os.ReadFile(tmpl.LogoPath)
+		if err != nil {
+			return fmt.Errorf("invoice: reading logo: %w", err)
+		}
+		view.Logo = template.URL(dataURI(tmpl.LogoPath, logo))
+	}
+
+	var columns []string
+	for _, column := range InvoiceColumns {
+		if contains(tmpl.Columns, column) {
+			columns = append(columns, column)
+			view.Headers = append(view.Headers, label(column))
+		}
+	}
+
+	var totalHours float64
+	for _, entry := range entries {
+		hours := entry.Duration.Hours()
+		totalHours += hours
+
+		var row []string
+		for _, column := range columns {
+			switch column {
+			case "date":
+				row = append(row, locale.FormatDay(entry.Start))
+			case "task":
+				row = append(row, entry.Task)
+			case "notes":
+				row = append(row, entry.Notes)
+			case "project":
+				row = append(row, entry.Project)
+			case "hours":
+				row = append(row, locale.FormatDecimal(hours))
+			case "rate":
+				row = append(row, locale.FormatDecimal(settings.HourlyRate)+" "+currency)
+			case "amount":
+				row = append(row, locale.FormatDecimal(hours*settings.HourlyRate)+" "+currency)
+			}
+		}
+		view.Rows = append(view.Rows, row)
+	}
+
+	// The totals row carries the label in the first column and sums under
+	// the hours and amount columns. With tax, they're the subtotal and the
+	// tax and total follow below.
+	totalLabel := label("total")
+	if invoice.TaxRate > 0 {
+		totalLabel = label("subtotal")
+		view.Summary = [][]string{
+			{label("subtotal"), locale.FormatDecimal(invoice.Net) + " " + currency},
+			{label("tax") + " " + locale.FormatDecimal(invoice.TaxRate) + " %", locale.FormatDecimal(invoice.Tax) + " " + currency},
+			{label("total"), locale.FormatDecimal(invoice.Total()) + " " + currency},
+		}
+	}
+	for i, column := range columns {
+		switch {
+		case column == "hours":
+			view.Totals = append(view.Totals, locale.FormatDecimal(totalHours))
+		case column == "amount":
+			view.Totals = append(view.Totals, locale.FormatDecimal(invoice.Net)+" "+currency)
+		case i == 0:
+			view.Totals = append(view.Totals, totalLabel)
+		default:
+			view.Totals = append(view.Totals, "")
+		}
+	}
+
+	// Expenses are listed with their own currency and totalled per currency
+	view.ExpensesTitle = label("expenses")
+	view.ExpenseHeaders = []string{label("date"), label("description"), label("project"), label("amount")}
+	view.ReceiptsTitle = label("receipts")
+	expenseTotals := make(map[string]float64)
+	for _, expense := range expenses {
+		view.Expenses = append(view.Expenses, []string{
+			locale.FormatDay(expense.Date),
+			expense.Description,
+			expense.Project,
+			strings.TrimSpace(locale.FormatDecimal(expense.Amount) + " " + expense.Currency),
+		})
+		expenseTotals[expense.Currency] += expense.Amount
+
+		if image, ok := receiptImage(expense); ok {
+			view.Receipts = append(view.Receipts, template.URL(image))
+		}
+	}
+	currencies := make([]string, 0, len(expenseTotals))
+	for currency := range expenseTotals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		view.ExpenseTotals = append(view.ExpenseTotals, []string{
+			label("total"), "", "",
+			strings.TrimSpace(locale.FormatDecimal(expenseTotals[currency]) + " " + currency),
+		})
+	}
+
+	return invoiceHTML.Execute(w, view)
+}
+
+// dataURI embeds a file's contents, typed by its extension.
+func dataURI(path string, raw []byte) string {
+	mediaType := mime.TypeByExtension(filepath.Ext(path))
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(raw)
+}
+
+// showCreateInvoiceDialog picks a client and period and saves the invoice.
+func showCreateInvoiceDialog(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Invoice")) {
+		return
+	}
+
+	clients := knownClients(timer)
+	if len(clients) == 0 {
+		dialog.ShowInformation(tr("Invoice"), tr("No entries or expenses have a client yet."), timer.window)
+		return
+	}
+
+	now := time.Now()
+	clientSelect := widget.NewSelect(clients, nil)
+	clientSelect.SetSelectedIndex(0)
+	fromInput := widget.NewEntry()
+	fromInput.SetText(time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02"))
+	toInput := widget.NewEntry()
+	toInput.SetText(time.Date(now.Year(), now.Month(), 0, 0, 0, 0, 0, now.Location()).Format("2006-01-02"))
+	numberInput := widget.NewEntry()
+	numberInput.SetText(nextInvoiceNumber())
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Number"), numberInput),
+		widget.NewFormItem(tr("Client"), clientSelect),
+		widget.NewFormItem(tr("Period"), newReportPeriodSelect(fromInput, toInput)),
+		widget.NewFormItem(tr("From"), fromInput),
+		widget.NewFormItem(tr("To"), toInput),
+	}
+	dialog.ShowForm(tr("Create Invoice"), tr("Create"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		from, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+
+		// Include the whole of the final day
+		to = to.AddDate(0, 0, 1)
+
+		number := strings.TrimSpace(numberInput.Text)
+		if number == "" {
+			dialog.ShowInformation(tr("Invoice"), tr("Enter an invoice number."), timer.window)
+			return
+		}
+		for _, issued := range issuedInvoices() {
+			if issued.Number == number {
+				dialog.ShowInformation(tr("Invoice"), tr("Invoice {{.Number}} was already issued.", map[string]any{"Number": number}), timer.window)
+				return
+			}
+		}
+
+		client := clientSelect.Selected
+		entries, unreviewed := invoiceEntries(timer, client, from, to)
+
+		// The invoice is only entered in the ledger, taking its number, once
+		// it's written
+		invoice := newInvoice(number, client, from, to, now, entries)
+		fileName := fmt.Sprintf("invoice-%s-%s.html", number, client)
+		create := func() {
+			saveExport(timer, fileName, func(w io.Writer) error {
+				if err := writeInvoice(w, invoice, entries, expensesForClient(client, from, to)); err != nil {
+					return err
+				}
+				return issueInvoice(invoice)
+			})
+		}
+		confirmUnreviewed(timer, unreviewed, tr("{{.Count}} entries in this period still need review and are left out. Create the invoice anyway?", map[string]any{
+			"Count": unreviewed,
+		}), create)
+	}, timer.window)
+}
+
+// invoiceEntries returns a client's billable entries between from and to,
+// rounded for billing, and how many were left out for waiting in Review.
+func invoiceEntries(timer *TaskTimer, client string, from, to time.Time) (entries []Entry, unreviewed int) {
+	for _, entry := range entriesBetween(timer, from, to) {
+		if entry.Client != client || entry.NonBillable {
+			continue
+		}
+		// Held back until approved in Review
+		if needsReview(entry) {
+			unreviewed++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return roundEntries(entries), unreviewed
+}
+
+// confirmUnreviewed runs create, first asking with message whether to go
+// ahead without the entries still waiting in Review, if there are any.
+func confirmUnreviewed(timer *TaskTimer, unreviewed int, message string, create func()) {
+	if unreviewed == 0 {
+		create()
+		return
+	}
+	dialog.ShowConfirm(tr("Invoice"), message, func(ok bool) {
+		if ok {
+			create()
+		}
+	}, timer.window)
+}
+
+// showInvoiceTemplateDialog edits a template; an empty name creates one.
+func showInvoiceTemplateDialog(timer *TaskTimer, name string) {
+	tmpl := InvoiceTemplate{Columns: slices.Clone(InvoiceColumns)}
+	if name != "" {
+		tmpl = invoiceTemplateByName(name)
+	}
+
+	nameInput := widget.NewEntry()
+	nameInput.SetText(tmpl.Name)
+	logoInput := widget.NewEntry()
+	logoInput.PlaceHolder = "/path/to/logo.png"
+	logoInput.SetText(tmpl.LogoPath)
+	logoBtn := widget.NewButton(tr("Browse…"), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err == nil && reader != nil {
+				logoInput.SetText(reader.URI().Path())
+				reader.Close()
+			}
+		}, timer.window)
+	})
+	footerInput := widget.NewMultiLineEntry()
+	footerInput.SetText(tmpl.Footer)
+	languageSelect := widget.NewSelect(exportLocaleNames(), nil)
+	languageSelect.SetSelected(tr(exportLocaleByTag(tmpl.Language).Name))
+	// The check group changes its slices in place as boxes are ticked
+	columnChecks := widget.NewCheckGroup(slices.Clone(InvoiceColumns), nil)
+	columnChecks.Horizontal = true
+	columnChecks.SetSelected(slices.Clone(tmpl.Columns))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Name"), nameInput),
+		widget.NewFormItem(tr("Logo"), container.NewBorder(nil, nil, nil, logoBtn, logoInput)),
+		widget.NewFormItem(tr("Footer"), footerInput),
+		widget.NewFormItem(tr("Language"), languageSelect),
+		widget.NewFormItem(tr("Columns"), columnChecks),
+	}
+	d := dialog.NewForm(tr("Invoice Template"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+		newName := strings.TrimSpace(nameInput.Text)
+		if !ok || newName == "" {
+			return
+		}
+
+		edited := InvoiceTemplate{
+			Name:     newName,
+			LogoPath: strings.TrimSpace(logoInput.Text),
+			Footer:   footerInput.Text,
+			Language: exportLocaleByName(languageSelect.Selected).Tag,
+			Columns:  selectedInvoiceColumns(columnChecks.Selected),
+		}
+		var templates []InvoiceTemplate
+		for _, t := range invoiceTemplates() {
+			if t.Name != name && t.Name != newName {
+				templates = append(templates, t)
+			}
+		}
+		setInvoiceTemplates(append(templates, edited))
+
+		// Keep clients assigned to a renamed template
+		if name != "" && name != newName {
+			clients := clientSettings()
+			for client, settings := range clients {
+				if settings.Template == name {
+					settings.Template = newName
+					clients[client] = settings
+				}
+			}
+			setClientSettings(clients)
+		}
+		showView(timer, timer.currentView)
+	}, timer.window)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+}
+
+// selectedInvoiceColumns copies the ticked columns in printing order.
+func selectedInvoiceColumns(selected []string) []string {
+	var columns []string
+	for _, column := range InvoiceColumns {
+		if contains(selected, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// showClientsDialog sets each client's hourly rate, currency, tax rate,
+// payment terms and invoice template.
+func showClientsDialog(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Clients")) {
+		return
+	}
+
+	clients := clientSettings()
+	var templateNames []string
+	for _, t := range invoiceTemplates() {
+		templateNames = append(templateNames, t.Name)
+	}
+
+	type clientInputs struct {
+		rate     *widget.Entry
+		currency *widget.Entry
+		tax      *widget.Entry
+		terms    *widget.Entry
+		template *widget.Select
+	}
+	inputs := make(map[string]clientInputs)
+
+	form := container.NewVBox()
+	for _, client := range knownClients(timer) {
+		settings := clients[client]
+		rateInput := widget.NewEntry()
+		rateInput.PlaceHolder = tr("hourly rate")
+		if settings.HourlyRate > 0 {
+			rateInput.SetText(strconv.FormatFloat(settings.HourlyRate, 'f', -1, 64))
+		}
+		currencyInput := widget.NewEntry()
+		currencyInput.PlaceHolder = homeCurrency()
+		currencyInput.SetText(settings.Currency)
+		taxInput := widget.NewEntry()
+		taxInput.PlaceHolder = tr("e.g. 19 for 19%")
+		if settings.TaxRate > 0 {
+			taxInput.SetText(strconv.FormatFloat(settings.TaxRate, 'f', -1, 64))
+		}
+		termsInput := widget.NewEntry()
+		termsInput.PlaceHolder = tr("days, e.g. 14")
+		if settings.PaymentDays > 0 {
+			termsInput.SetText(strconv.Itoa(settings.PaymentDays))
+		}
+		templateSelect := widget.NewSelect(templateNames, nil)
+		templateSelect.SetSelected(invoiceTemplateByName(settings.Template).Name)
+		inputs[client] = clientInputs{rate: rateInput, currency: currencyInput, tax: taxInput, terms: termsInput, template: templateSelect}
+
+		form.Add(widget.NewLabelWithStyle(client, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		form.Add(widget.NewForm(
+			widget.NewFormItem(tr("Rate"), rateInput),
+			widget.NewFormItem(tr("Currency"), currencyInput),
+			widget.NewFormItem(tr("Tax rate"), taxInput),
+			widget.NewFormItem(tr("Payment terms"), termsInput),
+			widget.NewFormItem(tr("Template"), templateSelect),
+		))
+	}
+	if len(inputs) == 0 {
+		form.Add(widget.NewLabel(tr("No entries have a client yet.")))
+	}
+
+	d := dialog.NewCustomConfirm(tr("Clients"), tr("Save"), tr("Cancel"), container.NewVScroll(form), func(ok bool) {
+		if !ok {
+			return
+		}
+		for client, input := range inputs {
+			rate, _ := strconv.ParseFloat(strings.TrimSpace(input.rate.Text), 64)
+			currency := normalizeCurrency(input.currency.Text)
+			if currency == homeCurrency() {
+				currency = ""
+			}
+			taxRate, _ := strconv.ParseFloat(strings.Replace(strings.TrimSpace(input.tax.Text), ",", ".", 1), 64)
+			paymentDays, _ := strconv.Atoi(strings.TrimSpace(input.terms.Text))
+			clients[client] = ClientSettings{
+				HourlyRate:  rate,
+				Currency:    currency,
+				TaxRate:     max(taxRate, 0),
+				PaymentDays: max(paymentDays, 0),
+				Template:    input.template.Selected,
+			}
+		}
+		setClientSettings(clients)
+	}, timer.window)
+	d.Resize(fyne.NewSize(380, 480))
+	d.Show()
+}
+
+// createInvoiceTemplatesList shows the templates with edit and remove buttons.
+func createInvoiceTemplatesList(timer *TaskTimer) *fyne.Container {
+	list := container.NewVBox()
+	templates := invoiceTemplates()
+	for _, t := range templates {
+		name := t.Name
+		editBtn := widget.NewButton(tr("Edit"), func() {
+			showInvoiceTemplateDialog(timer, name)
+		})
+		removeBtn := widget.NewButton(tr("Remove"), func() {
+			var kept []InvoiceTemplate
+			for _, t := range invoiceTemplates() {
+				if t.Name != name {
+					kept = append(kept, t)
+				}
+			}
+			setInvoiceTemplates(kept)
+			showView(timer, timer.currentView)
+		})
+		if len(templates) == 1 {
+			removeBtn.Disable()
+		}
+		list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(editBtn, removeBtn), widget.NewLabel(name)))
+	}
+	return list
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const PrefIssuedInvoices = "issuedInvoices"
+
+// FirstInvoiceNumber is offered until an invoice has been issued; later
+// numbers count on from the last one.
+const FirstInvoiceNumber = "INV-0001"
+
+// IssuedInvoice is an invoice recorded in the ledger when it's saved. From
+// and To bound the period billed, To being the day after the last. Net and
+// Tax cover the hours; expenses are listed on the invoice but not here. Paid
+// is zero until the invoice is marked paid.
+type IssuedInvoice struct {
+	Number   string    `json:"number"`
+	Client   string    `json:"client"`
+	Issued   time.Time `json:"issued"`
+	Due      time.Time `json:"due"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Currency string    `json:"currency"`
+	Net      float64   `json:"net"`
+	TaxRate  float64   `json:"taxRate,omitempty"`
+	Tax      float64   `json:"tax,omitempty"`
+	Paid     time.Time `json:"paid"`
+}
+
+// Total is what the client owes for the invoice.
+func (i IssuedInvoice) Total() float64 {
+	return i.Net + i.Tax
+}
+
+// Overdue reports whether the invoice is still unpaid after its due day.
+func (i IssuedInvoice) Overdue(now time.Time) bool {
+	return i.Paid.IsZero() && !i.Due.IsZero() && !now.Before(addDays(i.Due, 1))
+}
+
+func issuedInvoices() []IssuedInvoice {
+	var invoices []IssuedInvoice
+	raw := fyne.CurrentApp().Preferences().String(PrefIssuedInvoices)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &invoices); err != nil {
+			log.Printf("invoice: reading ledger: %v", err)
+		}
+	}
+	return invoices
+}
+
+func setIssuedInvoices(invoices []IssuedInvoice) {
+	raw, err := json.Marshal(invoices)
+	if err != nil {
+		log.Printf("invoice: saving ledger: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefIssuedInvoices, string(raw))
+}
+
+// nextInvoiceNumber counts on from the last invoice issued.
+func nextInvoiceNumber() string {
+	invoices := issuedInvoices()
+	if len(invoices) == 0 {
+		return FirstInvoiceNumber
+	}
+	return invoiceNumberAfter(invoices[len(invoices)-1].Number)
+}
+
+// invoiceNumberAfter keeps a number's prefix and zero padding, e.g.
+// INV-0041 is followed by INV-0042.
+func invoiceNumberAfter(last string) string {
+	prefix := strings.TrimRight(last, "0123456789")
+	digits := last[len(prefix):]
+	if digits == "" {
+		return last + "-2"
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return last + "-2"
+	}
+	return fmt.Sprintf("%s%0*d", prefix, len(digits), n+1)
+}
+
+// newInvoice prices a client's entries for the period from from to to at
+// their rate and tax rate, due after their payment terms.
+func newInvoice(number, client string, from, to, issued time.Time, entries []Entry) IssuedInvoice {
+	settings := clientSettings()[client]
+	var hours float64
+	for _, entry := range entries {
+		hours += entry.Duration.Hours()
+	}
+	net := roundMoney(hours * settings.HourlyRate)
+	invoice := IssuedInvoice{
+		Number:   number,
+		Client:   client,
+		Issued:   issued,
+		From:     from,
+		To:       to,
+		Currency: clientCurrency(settings),
+		Net:      net,
+		TaxRate:  settings.TaxRate,
+		Tax:      roundMoney(net * settings.TaxRate / 100),
+	}
+	if settings.PaymentDays > 0 {
+		day := time.Date(issued.Year(), issued.Month(), issued.Day(), 0, 0, 0, 0, issued.Location())
+		invoice.Due = addDays(day, settings.PaymentDays)
+	}
+	return invoice
+}
+
+// roundMoney rounds an amount to cents.
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// issueInvoice records an invoice in the ledger. Numbers must be unique.
+func issueInvoice(invoice IssuedInvoice) error {
+	invoices := issuedInvoices()
+	for _, issued := range invoices {
+		if issued.Number == invoice.Number {
+			return fmt.Errorf("invoice: %s was already issued", invoice.Number)
+		}
+	}
+	setIssuedInvoices(append(invoices, invoice))
+	return nil
+}
+
+// setInvoicePaid marks an invoice paid at paid, or unpaid if paid is zero.
+func setInvoicePaid(number string, paid time.Time) {
+	invoices := issuedInvoices()
+	for i := range invoices {
+		if invoices[i].Number == number {
+			invoices[i].Paid = paid
+		}
+	}
+	setIssuedInvoices(invoices)
+}
+
+// outstandingByCurrency adds up the unpaid invoices in each currency.
+func outstandingByCurrency(invoices []IssuedInvoice) map[string]float64 {
+	outstanding := make(map[string]float64)
+	for _, invoice := range invoices {
+		if invoice.Paid.IsZero() {
+			outstanding[invoice.Currency] += invoice.Total()
+		}
+	}
+	return outstanding
+}
+
+// showInvoiceLedgerDialog lists the invoices issued, newest first, with
+// whether each is paid, unpaid or overdue, and what's still owed.
+func showInvoiceLedgerDialog(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Invoice Ledger")) {
+		return
+	}
+
+	list := container.NewVBox()
+	outstandingLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	unpaidOnly := widget.NewCheck(tr("Unpaid only"), nil)
+
+	var render func()
+	render = func() {
+		list.RemoveAll()
+		invoices := issuedInvoices()
+		now := time.Now()
+
+		outstanding := outstandingByCurrency(invoices)
+		var amounts []string
+		for currency, amount := range outstanding {
+			amounts = append(amounts, formatMoney(amount, currency))
+		}
+		sort.Strings(amounts)
+		if len(amounts) == 0 {
+			outstandingLabel.SetText(tr("Nothing outstanding"))
+		} else {
+			outstandingLabel.SetText(tr("Outstanding: {{.Amounts}}", map[string]any{"Amounts": strings.Join(amounts, ", ")}))
+		}
+
+		shown := 0
+		for _, invoice := range slices.Backward(invoices) {
+			if unpaidOnly.Checked && !invoice.Paid.IsZero() {
+				continue
+			}
+			shown++
+
+			summary := fmt.Sprintf("%s  %s  %s  %s", invoice.Number, formatDate(invoice.Issued),
+				invoice.Client, formatMoney(invoice.Total(), invoice.Currency))
+			label := widget.NewLabel(summary)
+			label.Truncation = fyne.TextTruncateEllipsis
+
+			var status *widget.Label
+			number := invoice.Number
+			var toggleBtn *widget.Button
+			switch {
+			case !invoice.Paid.IsZero():
+				status = widget.NewLabel(tr("Paid {{.Date}}", map[string]any{"Date": formatDate(invoice.Paid)}))
+				toggleBtn = widget.NewButton(tr("Mark Unpaid"), func() {
+					setInvoicePaid(number, time.Time{})
+					render()
+				})
+			case invoice.Overdue(now):
+				status = widget.NewLabelWithStyle(tr("Overdue since {{.Date}}", map[string]any{"Date": formatDate(invoice.Due)}), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			case !invoice.Due.IsZero():
+				status = widget.NewLabel(tr("Due {{.Date}}", map[string]any{"Date": formatDate(invoice.Due)}))
+			default:
+				status = widget.NewLabel(tr("Unpaid"))
+			}
+			if toggleBtn == nil {
+				toggleBtn = widget.NewButtonWithIcon(tr("Mark Paid"), theme.ConfirmIcon(), func() {
+					setInvoicePaid(number, time.Now())
+					render()
+				})
+			}
+			list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(status, toggleBtn), label))
+		}
+		if shown == 0 {
+			list.Add(widget.NewLabel(tr("No invoices issued yet")))
+		}
+	}
+	unpaidOnly.OnChanged = func(bool) { render() }
+	render()
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(560, 360))
+	content := container.NewBorder(container.NewVBox(outstandingLabel, unpaidOnly), nil, nil, nil, scroll)
+	dialog.NewCustom(tr("Invoice Ledger"), tr("Close"), content, timer.window).Show()
+}
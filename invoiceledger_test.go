@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestInvoiceNumberAfter(t *testing.T) {
+	tests := []struct {
+		last, want string
+	}{
+		{"INV-0041", "INV-0042"},
+		{"INV-0099", "INV-0100"},
+		{"INV-9999", "INV-10000"},
+		{"2026-7", "2026-8"},
+		{"17", "18"},
+		{"INV-A", "INV-A-2"},
+	}
+	for _, tc := range tests {
+		if got := invoiceNumberAfter(tc.last); got != tc.want {
+			t.Errorf("invoiceNumberAfter(%q) = %q, want %q", tc.last, got, tc.want)
+		}
+	}
+}
+
+func TestNewInvoice(t *testing.T) {
+	test.NewTempApp(t)
+	loc := inZone(t, "Europe/Berlin")
+	setClientSettings(map[string]ClientSettings{
+		"Acme":    {HourlyRate: 95, Currency: "EUR", TaxRate: 19, PaymentDays: 14},
+		"Globex":  {HourlyRate: 33.33, Currency: "USD", TaxRate: 7.5},
+		"Initech": {HourlyRate: 80},
+	})
+	issued := time.Date(2026, 3, 20, 15, 30, 0, 0, loc)
+	entries := func(durations ...time.Duration) []Entry {
+		var entries []Entry
+		for _, duration := range durations {
+			entries = append(entries, Entry{Task: "Work", Duration: duration})
+		}
+		return entries
+	}
+
+	tests := []struct {
+		client   string
+		entries  []Entry
+		net, tax float64
+		currency string
+		due      time.Time
+	}{
+		{"Acme", entries(90*time.Minute, time.Hour), 237.5, 45.13, "EUR", time.Date(2026, 4, 3, 0, 0, 0, 0, loc)},
+		{"Globex", entries(20 * time.Minute), 11.11, 0.83, "USD", time.Time{}},
+		{"Initech", entries(45 * time.Minute), 60, 0, homeCurrency(), time.Time{}},
+		{"Unknown", entries(time.Hour), 0, 0, homeCurrency(), time.Time{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.client, func(t *testing.T) {
+			invoice := newInvoice("INV-0001", tc.client, issued.AddDate(0, -1, 0), issued, issued, tc.entries)
+			if invoice.Net != tc.net || invoice.Tax != tc.tax {
+				t.Errorf("net %v, tax %v; want %v, %v", invoice.Net, invoice.Tax, tc.net, tc.tax)
+			}
+			if got := invoice.Total(); got != tc.net+tc.tax {
+				t.Errorf("total %v, want %v", got, tc.net+tc.tax)
+			}
+			if invoice.Currency != tc.currency {
+				t.Errorf("currency %q, want %q", invoice.Currency, tc.currency)
+			}
+			if !invoice.Due.Equal(tc.due) {
+				t.Errorf("due %v, want %v", invoice.Due, tc.due)
+			}
+		})
+	}
+}
+
+func TestInvoiceOverdue(t *testing.T) {
+	due := time.Date(2026, 4, 3, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		invoice IssuedInvoice
+		now     time.Time
+		want    bool
+	}{
+		{"on the due day", IssuedInvoice{Due: due}, due.Add(23 * time.Hour), false},
+		{"the day after", IssuedInvoice{Due: due}, addDays(due, 1), true},
+		{"paid", IssuedInvoice{Due: due, Paid: due}, addDays(due, 30), false},
+		{"no payment terms", IssuedInvoice{}, addDays(due, 30), false},
+	}
+	for _, tc := range tests {
+		if got := tc.invoice.Overdue(tc.now); got != tc.want {
+			t.Errorf("%s: Overdue = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestIssueInvoice(t *testing.T) {
+	test.NewTempApp(t)
+	if got := nextInvoiceNumber(); got != FirstInvoiceNumber {
+		t.Errorf("first number %q, want %q", got, FirstInvoiceNumber)
+	}
+	for _, invoice := range []IssuedInvoice{
+		{Number: "INV-0001", Currency: "EUR", Net: 100, Tax: 19},
+		{Number: "INV-0002", Currency: "USD", Net: 50},
+		{Number: "INV-0003", Currency: "EUR", Net: 10, Tax: 1.9},
+	} {
+		if err := issueInvoice(invoice); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := issueInvoice(IssuedInvoice{Number: "INV-0002"}); err == nil {
+		t.Error("a number was issued twice")
+	}
+	if got := nextInvoiceNumber(); got != "INV-0004" {
+		t.Errorf("next number %q, want INV-0004", got)
+	}
+
+	setInvoicePaid("INV-0001", time.Now())
+	outstanding := outstandingByCurrency(issuedInvoices())
+	if len(outstanding) != 2 || outstanding["EUR"] != 11.9 || outstanding["USD"] != 50 {
+		t.Errorf("outstanding %v, want EUR 11.9 and USD 50", outstanding)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefJiraEnabled = "jiraEnabled"
+	PrefJiraURL     = "jiraURL"
+	PrefJiraEmail   = "jiraEmail"
+	// PrefJiraToken held the API token before it moved to the keychain
+	PrefJiraToken    = "jiraToken"
+	PrefJiraTaskKeys = "jiraTaskKeys"
+
+	// Jira rejects worklogs shorter than a minute
+	jiraMinWorklogSeconds = 60
+)
+
+var jiraIssueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// jiraTaskKeys loads the per-task issue key overrides.
+func jiraTaskKeys() map[string]string {
+	keys := make(map[string]string)
+	raw := fyne.CurrentApp().Preferences().String(PrefJiraTaskKeys)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+			log.Printf("jira: reading task mappings: %v", err)
+		}
+	}
+	return keys
+}
+
+func setJiraTaskKeys(keys map[string]string) {
+	raw, err := json.Marshal(keys)
+	if err != nil {
+		log.Printf("jira: saving task mappings: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefJiraTaskKeys, string(raw))
+}
+
+// jiraIssueKey returns the issue a task logs work to: the mapped key if one is
+// configured, otherwise the first issue key found in the task name.
+func jiraIssueKey(taskName string) string {
+	if key, ok := jiraTaskKeys()[taskName]; ok {
+		return key
+	}
+	return jiraIssueKeyPattern.FindString(taskName)
+}
+
+type jiraWorklog struct {
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Started          string `json:"started"`
+	Comment          string `json:"comment,omitempty"`
+}
+
+// pushJiraWorklog logs a recorded entry against its Jira issue in the
+// background, if the integration is enabled and the task maps to an issue.
+func pushJiraWorklog(timer *TaskTimer, entry Entry) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefJiraEnabled) {
+		return
+	}
+	key := jiraIssueKey(entry.Task)
+	if key == "" {
+		return
+	}
+
+	baseURL := strings.TrimRight(prefs.String(PrefJiraURL), "/")
+	email := prefs.String(PrefJiraEmail)
+	token := keychainSecret(KeyringJiraToken)
+
+	go func() {
+		err := postJiraWorklog(baseURL, email, token, key, entry)
+		if err != nil {
+			log.Print(err)
+			fyne.Do(func() {
+				dialog.ShowError(err, timer.window)
+			})
+		}
+	}()
+}
+
+func postJiraWorklog(baseURL, email, token, key string, entry Entry) error {
+	seconds := int(entry.Duration.Seconds())
+	if seconds < jiraMinWorklogSeconds {
+		seconds = jiraMinWorklogSeconds
+	}
+
+	body, err := json.Marshal(jiraWorklog{
+		TimeSpentSeconds: seconds,
+		Started:          entry.Start.Format("2006-01-02T15:04:05.000-0700"),
+		Comment:          entry.Notes,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/worklog", baseURL, key)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(email, token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira: logging work on %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("jira: logging work on %s: %s", key, resp.Status)
+	}
+	return nil
+}
+
+// showJiraMappingDialog lets each task be pointed at a specific issue key.
+// Leaving a key blank falls back to detecting it from the task name.
+func showJiraMappingDialog(timer *TaskTimer) {
+	keys := jiraTaskKeys()
+	inputs := make(map[string]*widget.Entry)
+
+	form := container.NewVBox()
+	for _, taskName := range timer.tasks.Tasks() {
+		input := widget.NewEntry()
+		input.PlaceHolder = jiraIssueKeyPattern.FindString(taskName)
+		input.SetText(keys[taskName])
+		inputs[taskName] = input
+		form.Add(widget.NewForm(widget.NewFormItem(taskName, input)))
+	}
+	if len(inputs) == 0 {
+		form.Add(widget.NewLabel(tr("Add a task first")))
+	}
+
+	d := dialog.NewCustomConfirm(tr("Jira Issue Mapping"), tr("Save"), tr("Cancel"), container.NewVScroll(form), func(ok bool) {
+		if !ok {
+			return
+		}
+		for taskName, input := range inputs {
+			if key := strings.TrimSpace(input.Text); key != "" {
+				keys[taskName] = key
+			} else {
+				delete(keys, taskName)
+			}
+		}
+		setJiraTaskKeys(keys)
+	}, timer.window)
+	d.Resize(fyne.NewSize(350, 400))
+	d.Show()
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// JSONStore keeps all data in one JSON file, rewritten on every change.
+// With a passphrase, the file is encrypted.
+type JSONStore struct {
+	mu     sync.Mutex
+	path   string
+	sealer *sealer
+	data   jsonStoreData
+}
+
+type jsonStoreData struct {
+	Entries    []Entry              `json:"entries"`
+	Tombstones map[string]time.Time `json:"tombstones"`
+	Tasks      []string             `json:"tasks"`
+	Archived   []string             `json:"archived"`
+	Settings   map[string]string    `json:"settings"`
+	Audit      []AuditRecord        `json:"audit,omitempty"`
+}
+
+// OpenJSONStore reads the file at path, which doesn't have to exist yet. An
+// empty passphrase means the file isn't encrypted.
+func OpenJSONStore(path, passphrase string) (*JSONStore, error) {
+	s := &JSONStore{path: path}
+	raw, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	if passphrase != "" {
+		if len(raw) > 0 {
+			s.sealer, err = newSealerFor(passphrase, raw)
+			if err == nil {
+				raw, err = s.sealer.open(raw)
+			}
+		} else {
+			s.sealer, err = newSealer(passphrase, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &s.data); err != nil {
+			return nil, fmt.Errorf("store: reading %s: %w", path, err)
+		}
+	}
+	if s.data.Tombstones == nil {
+		s.data.Tombstones = make(map[string]time.Time)
+	}
+	if s.data.Settings == nil {
+		s.data.Settings = make(map[string]string)
+	}
+	return s, nil
+}
+
+func (s *JSONStore) Entries() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Entry(nil), s.data.Entries...), nil
+}
+
+func (s *JSONStore) PutEntry(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Entries {
+		if s.data.Entries[i].ID == entry.ID {
+			s.data.Entries[i] = entry
+			return s.saveLocked()
+		}
+	}
+	s.data.Entries = append(s.data.Entries, entry)
+	return s.saveLocked()
+}
+
+func (s *JSONStore) DeleteEntry(id string, deleted time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Entries {
+		if s.data.Entries[i].ID == id {
+			s.data.Entries = append(s.data.Entries[:i], s.data.Entries[i+1:]...)
+			break
+		}
+	}
+	if deleted.After(s.data.Tombstones[id]) {
+		s.data.Tombstones[id] = deleted
+	}
+	return s.saveLocked()
+}
+
+func (s *JSONStore) Tombstones() (map[string]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.data.Tombstones), nil
+}
+
+func (s *JSONStore) PutTombstones(tombstones map[string]time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, deleted := range tombstones {
+		if deleted.After(s.data.Tombstones[id]) {
+			s.data.Tombstones[id] = deleted
+		}
+	}
+	return s.saveLocked()
+}
+
+func (s *JSONStore) AuditLog() ([]AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.data.Audit), nil
+}
+
+func (s *JSONStore) AppendAudit(record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Audit = append(s.data.Audit, record)
+	return s.saveLocked()
+}
+
+func (s *JSONStore) Tasks() (tasks, archived []string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.data.Tasks...), append([]string(nil), s.data.Archived...), nil
+}
+
+func (s *JSONStore) SetTasks(tasks, archived []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Tasks = append([]string(nil), tasks...)
+	s.data.Archived = append([]string(nil), archived...)
+	return s.saveLocked()
+}
+
+func (s *JSONStore) Setting(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data.Settings[key], nil
+}
+
+func (s *JSONStore) SetSetting(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Settings[key] = value
+	return s.saveLocked()
+}
+
+func (s *JSONStore) Close() error {
+	return nil
+}
+
+// saveLocked writes through a temporary file so a crash never leaves a
+// truncated file behind.
+func (s *JSONStore) saveLocked() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.sealer != nil {
+		raw = s.sealer.seal(raw)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
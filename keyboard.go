@@ -0,0 +1,174 @@
+package main
+
+import (
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// navItem is an entry in the sidebar. The View menu lists the same entries,
+// reachable with Ctrl (Cmd on macOS) and the entry's position.
+type navItem struct {
+	label string
+	view  string // empty for the weekly review, which opens a dialog
+}
+
+func navItems() []navItem {
+	return []navItem{
+		{tr("⏱ Timer"), "timer"},
+		{tr("📊 Daily Stats"), "stats"},
+		{tr("🕘 History"), "history"},
+		{tr("✅ Review"), "review"},
+		{tr("📅 Timeline"), "timeline"},
+		{tr("🧾 Timesheet"), "timesheet"},
+		{tr("📋 Tasks"), "tasks"},
+		{tr("🗓 Weekly Review"), ""},
+		{tr("⚙ Settings"), "settings"},
+	}
+}
+
+func openNavItem(timer *TaskTimer, item navItem) {
+	if item.view == "" {
+		showWeeklyReview(timer)
+		return
+	}
+	showView(timer, item.view)
+}
+
+// setUpKeyboard makes the app usable without a mouse: a main menu whose
+// shortcuts work even while typing in an entry, and Enter or Space to start
+// and pause the timer. sidebarEnd is the last control in the sidebar, which
+// the first control of each view follows in tab order.
+func setUpKeyboard(timer *TaskTimer, sidebarEnd fyne.Focusable) {
+	toggleItem := fyne.NewMenuItem(tr("Start or Pause"), func() { toggleTimer(timer) })
+	toggleItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyReturn, Modifier: fyne.KeyModifierShortcutDefault}
+	stopItem := fyne.NewMenuItem(tr("Stop"), func() { stopSession(timer) })
+	splitItem := fyne.NewMenuItem(tr("Split Session…"), func() { showSplitSessionDialog(timer) })
+	summaryItem := fyne.NewMenuItem(tr("Today's Summary"), func() { showDaySummary(timer) })
+	weeklyReportItem := fyne.NewMenuItem(tr("Weekly Report…"), func() { showWeeklyReportDialog(timer) })
+	billableItem := fyne.NewMenuItem(tr("Billable vs. Internal…"), func() { showBillableReport(timer) })
+	profitabilityItem := fyne.NewMenuItem(tr("Project Profitability…"), func() { showProfitabilityReport(timer) })
+	utilizationItem := fyne.NewMenuItem(tr("Utilization…"), func() { showUtilizationReport(timer) })
+	capacityItem := fyne.NewMenuItem(tr("Capacity Plan…"), func() { showCapacityPlan(timer) })
+	timerMenu := fyne.NewMenu(tr("Timer"), toggleItem, stopItem, splitItem, summaryItem, weeklyReportItem, billableItem, profitabilityItem, utilizationItem, capacityItem)
+	if !fyne.CurrentDevice().IsMobile() {
+		timerMenu.Items = append(timerMenu.Items, fyne.NewMenuItem(tr("Mini Timer"), func() { showMiniTimer(timer) }))
+	}
+
+	// Ctrl+Z and Ctrl+Y reach the menu before the focused widget, so they
+	// are passed on to a text field being typed in
+	undoItem := fyne.NewMenuItem(tr("Undo"), nil)
+	undoItem.Shortcut = &fyne.ShortcutUndo{}
+	undoItem.Action = func() {
+		if !typedShortcut(timer, undoItem.Shortcut) {
+			undoLast(timer)
+		}
+	}
+	redoItem := fyne.NewMenuItem(tr("Redo"), nil)
+	redoItem.Shortcut = &fyne.ShortcutRedo{}
+	redoItem.Action = func() {
+		if !typedShortcut(timer, redoItem.Shortcut) {
+			redoLast(timer)
+		}
+	}
+	editMenu := fyne.NewMenu(tr("Edit"), undoItem, redoItem)
+	timer.window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, func(fyne.Shortcut) {
+		redoLast(timer)
+	})
+
+	viewMenu := fyne.NewMenu(tr("View"))
+	for i, item := range navItems() {
+		menuItem := fyne.NewMenuItem(item.label, func() {
+			openNavItem(timer, item)
+			if item.view != "" {
+				focusView(timer, sidebarEnd)
+			}
+		})
+		menuItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyName(strconv.Itoa(i + 1)), Modifier: fyne.KeyModifierShortcutDefault}
+		viewMenu.Items = append(viewMenu.Items, menuItem)
+	}
+	paletteItem := fyne.NewMenuItem(tr("Command Palette…"), func() { showCommandPalette(timer) })
+	paletteItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: fyne.KeyModifierShortcutDefault}
+	viewMenu.Items = append(viewMenu.Items, fyne.NewMenuItemSeparator(), paletteItem)
+	mainMenu := fyne.NewMainMenu(timerMenu, editMenu, viewMenu)
+	timer.window.SetMainMenu(mainMenu)
+
+	relabel := func() {
+		undoLabel, redoLabel := timer.undo.Labels()
+		undoItem.Label, undoItem.Disabled = tr("Undo"), undoLabel == ""
+		if undoLabel != "" {
+			undoItem.Label = tr("Undo: {{.Action}}", map[string]any{"Action": undoLabel})
+		}
+		redoItem.Label, redoItem.Disabled = tr("Redo"), redoLabel == ""
+		if redoLabel != "" {
+			redoItem.Label = tr("Redo: {{.Action}}", map[string]any{"Action": redoLabel})
+		}
+		mainMenu.Refresh()
+	}
+	timer.undo.OnChanged = func() { fyne.Do(relabel) }
+	relabel()
+
+	// Keys only reach the canvas when no widget has focus, so this doesn't
+	// get in the way of typing notes or pressing a focused button
+	timer.window.Canvas().SetOnTypedKey(func(e *fyne.KeyEvent) {
+		switch e.Name {
+		case fyne.KeyReturn, fyne.KeyEnter, fyne.KeySpace:
+			if timer.currentView == "timer" {
+				toggleTimer(timer)
+			}
+		}
+	})
+}
+
+// typedShortcut hands a shortcut to the focused widget, such as a text field,
+// if it takes shortcuts, and reports whether it did.
+func typedShortcut(timer *TaskTimer, shortcut fyne.Shortcut) bool {
+	focused, ok := timer.window.Canvas().Focused().(fyne.Shortcutable)
+	if !ok {
+		return false
+	}
+	if d, ok := focused.(fyne.Disableable); ok && d.Disabled() {
+		return false
+	}
+	focused.TypedShortcut(shortcut)
+	return true
+}
+
+// focusView moves keyboard focus into the view just opened. The timer view
+// leaves nothing focused so Enter and Space go to the timer; other views
+// focus their first control.
+func focusView(timer *TaskTimer, sidebarEnd fyne.Focusable) {
+	// Queued behind updateContentView, so the view is already in place
+	fyne.Do(func() {
+		c := timer.window.Canvas()
+		if timer.currentView == "timer" {
+			c.Unfocus()
+			return
+		}
+		c.Focus(sidebarEnd)
+		c.FocusNext()
+	})
+}
+
+// keyEntry is a search field that also takes the keys for moving through
+// the list of results below it: onKey sees each key first and reports
+// whether it handled it.
+type keyEntry struct {
+	widget.Entry
+	onKey func(name fyne.KeyName) bool
+}
+
+func newKeyEntry() *keyEntry {
+	e := &keyEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *keyEntry) TypedKey(key *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(key.Name) {
+		return
+	}
+	e.Entry.TypedKey(key)
+}
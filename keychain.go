@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/zalando/go-keyring"
+)
+
+// PrefKeychainAccounts lists the accounts secrets are saved under in the
+// system keychain, so the keychain isn't asked for the others
+const PrefKeychainAccounts = "keychainAccounts"
+
+// Keyring* name the integrations' secrets in the keychain, under
+// KeyringService.
+const (
+	KeyringJiraToken          = "jira token"
+	KeyringSlackToken         = "slack token"
+	KeyringGoogleClientSecret = "google client secret"
+	KeyringGoogleToken        = "google token"
+	KeyringGitHubToken        = "github token"
+	KeyringGitLabToken        = "gitlab token"
+	KeyringAzureDevOpsToken   = "azure devops token"
+	KeyringSMTPPassword       = "smtp password"
+	KeyringFreshBooksToken    = "freshbooks token"
+	KeyringTeamToken          = "team token"
+)
+
+// legacySecretPrefs are where older versions saved each secret, keyed by its
+// keychain account.
+var legacySecretPrefs = map[string]string{
+	KeyringJiraToken:          PrefJiraToken,
+	KeyringSlackToken:         PrefSlackToken,
+	KeyringGoogleClientSecret: PrefGoogleClientSecret,
+	KeyringGoogleToken:        PrefGoogleToken,
+	KeyringGitHubToken:        PrefGitHubToken,
+	KeyringGitLabToken:        PrefGitLabToken,
+	KeyringAzureDevOpsToken:   PrefAzureDevOpsToken,
+	KeyringSMTPPassword:       PrefSMTPPassword,
+	KeyringFreshBooksToken:    PrefFreshBooksToken,
+	KeyringTeamToken:          PrefTeamToken,
+}
+
+// keychainCache spares the keychain a lookup on every request an
+// integration makes.
+var keychainCache = struct {
+	sync.Mutex
+	values map[string]string
+}{values: make(map[string]string)}
+
+// keychainSecret returns a secret from the system keychain, "" if none is
+// saved. One older versions saved is used until it can be moved.
+func keychainSecret(account string) string {
+	prefs := fyne.CurrentApp().Preferences()
+	if legacy := prefs.String(legacySecretPrefs[account]); legacy != "" {
+		return legacy
+	}
+	if !slices.Contains(prefs.StringList(PrefKeychainAccounts), account) {
+		return ""
+	}
+
+	keychainCache.Lock()
+	defer keychainCache.Unlock()
+	if value, ok := keychainCache.values[account]; ok {
+		return value
+	}
+	value, err := keyring.Get(KeyringService, account)
+	if err != nil {
+		if !errors.Is(err, keyring.ErrNotFound) {
+			log.Printf("keychain: %v", err)
+		}
+		return ""
+	}
+	keychainCache.values[account] = value
+	return value
+}
+
+// setKeychainSecrets saves secrets, keyed by keychain account, in the system
+// keychain, and drops the copies older versions kept in the preferences.
+// Empty ones are removed from it.
+func setKeychainSecrets(secrets map[string]string) error {
+	prefs := fyne.CurrentApp().Preferences()
+	keychainCache.Lock()
+	defer keychainCache.Unlock()
+
+	accounts := prefs.StringList(PrefKeychainAccounts)
+	defer func() { prefs.SetStringList(PrefKeychainAccounts, accounts) }()
+	for account, value := range secrets {
+		delete(keychainCache.values, account)
+		if value == "" {
+			if err := keyring.Delete(KeyringService, account); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+				return fmt.Errorf("keychain: %w", err)
+			}
+		} else if err := keyring.Set(KeyringService, account, value); err != nil {
+			return fmt.Errorf("keychain: %w", err)
+		}
+		accounts = slices.DeleteFunc(accounts, func(saved string) bool { return saved == account })
+		if value != "" {
+			accounts = append(accounts, account)
+		}
+		if key, ok := legacySecretPrefs[account]; ok {
+			prefs.RemoveValue(key)
+		}
+	}
+	return nil
+}
+
+// moveKeychainSecrets moves secrets saved in the preferences by older
+// versions to the system keychain. They stay put if it can't be reached.
+func moveKeychainSecrets(prefs fyne.Preferences) {
+	secrets := make(map[string]string)
+	for account, key := range legacySecretPrefs {
+		if value := prefs.String(key); value != "" {
+			secrets[account] = value
+		}
+	}
+	if len(secrets) == 0 {
+		return
+	}
+	if err := setKeychainSecrets(secrets); err != nil {
+		log.Printf("moving secrets to the keychain: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/zalando/go-keyring"
+)
+
+func TestMoveKeychainSecrets(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	keyring.MockInit()
+	for account, key := range legacySecretPrefs {
+		prefs.SetString(key, account+" secret")
+	}
+
+	moveKeychainSecrets(prefs)
+	for account, key := range legacySecretPrefs {
+		if got := prefs.String(key); got != "" {
+			t.Errorf("%s is still in the preferences: %q", key, got)
+		}
+		if got, want := keychainSecret(account), account+" secret"; got != want {
+			t.Errorf("keychain %s = %q, want %q", account, got, want)
+		}
+		if got, err := keyring.Get(KeyringService, account); err != nil || got != account+" secret" {
+			t.Errorf("keychain %s holds %q, %v", account, got, err)
+		}
+	}
+
+	if err := setKeychainSecrets(map[string]string{KeyringJiraToken: ""}); err != nil {
+		t.Fatal(err)
+	}
+	if got := keychainSecret(KeyringJiraToken); got != "" {
+		t.Errorf("cleared secret still read as %q", got)
+	}
+	if _, err := keyring.Get(KeyringService, KeyringJiraToken); err != keyring.ErrNotFound {
+		t.Errorf("secret left in the keychain: %v", err)
+	}
+}
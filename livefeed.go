@@ -0,0 +1,299 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// PrefLiveFeedEnabled turns the live timer feed on, and PrefLiveFeedKey
+	// is the key a page needs to connect to it
+	PrefLiveFeedEnabled = "liveFeedEnabled"
+	PrefLiveFeedKey     = "liveFeedKey"
+
+	// LiveFeedPort is where overlays and dashboards find the feed, on the
+	// loopback interface only
+	LiveFeedPort = 47616
+
+	// LiveFeedResync is how often the state is sent again while pages are
+	// connected, which keeps their clocks honest and the connections alive
+	LiveFeedResync = 15 * time.Second
+)
+
+// LiveState is what the live feed sends. Pages tick the clocks themselves:
+// while Running, ElapsedMS and TodayMS grow with the time since At.
+type LiveState struct {
+	Task      string    `json:"task"`
+	Running   bool      `json:"running"`
+	ElapsedMS int64     `json:"elapsed_ms"`
+	TodayMS   int64     `json:"today_ms"`
+	At        time.Time `json:"at"`
+}
+
+// liveStateOf turns a status into the feed's state. Today's total takes in
+// the session in progress.
+func liveStateOf(status Status) LiveState {
+	today := status.Elapsed
+	for _, total := range status.Today {
+		today += total.Duration
+	}
+	return LiveState{
+		Task:      status.Task,
+		Running:   status.Running,
+		ElapsedMS: status.Elapsed.Milliseconds(),
+		TodayMS:   today.Milliseconds(),
+		At:        status.UpdatedAt,
+	}
+}
+
+// LiveFeed pushes the timer to pages on this machine over a WebSocket, for
+// overlays and dashboards that show a live clock:
+//
+//	GET /live?key=...     WebSocket sending a LiveState on every change
+//	GET /overlay?key=...  a transparent clock page, e.g. for an OBS browser source
+//
+// The state is sent as soon as a page connects, then whenever the timer
+// starts, stops, switches task or is adjusted.
+type LiveFeed struct {
+	timer   *TaskTimer
+	mu      sync.Mutex
+	server  *http.Server
+	stop    chan struct{}
+	state   LiveState
+	clients map[chan LiveState]struct{}
+}
+
+func NewLiveFeed(timer *TaskTimer) *LiveFeed {
+	return &LiveFeed{timer: timer, clients: make(map[chan LiveState]struct{})}
+}
+
+// liveFeedKey returns the feed's key, making one the first time.
+func liveFeedKey() string {
+	prefs := fyne.CurrentApp().Preferences()
+	key := prefs.String(PrefLiveFeedKey)
+	if key == "" {
+		key = rand.Text()
+		prefs.SetString(PrefLiveFeedKey, key)
+	}
+	return key
+}
+
+// liveFeedURL returns the address of a feed path, with the key.
+func liveFeedURL(scheme, path string) string {
+	return fmt.Sprintf("%s://localhost:%d%s?%s", scheme, LiveFeedPort, path, url.Values{"key": {liveFeedKey()}}.Encode())
+}
+
+// Apply starts or stops the server to match the setting.
+func (f *LiveFeed) Apply() error {
+	if !fyne.CurrentApp().Preferences().Bool(PrefLiveFeedEnabled) {
+		f.Close()
+		return nil
+	}
+	if err := f.start(); err != nil {
+		return fmt.Errorf("live feed: %w", err)
+	}
+	return nil
+}
+
+func (f *LiveFeed) start() error {
+	key := liveFeedKey()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.server != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", LiveFeedPort))
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.Handle("GET /live", websocket.Server{
+		// Any page may connect with the key, whatever its origin; OBS
+		// browser sources and local files don't send a useful one
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if r.URL.Query().Get("key") != key {
+				return errors.New("wrong key")
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) { f.serveClient(ws, stop) },
+	})
+	mux.HandleFunc("GET /overlay", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		io.WriteString(w, liveOverlayPage)
+	})
+	f.server = &http.Server{Handler: f.guard(mux), ReadHeaderTimeout: 10 * time.Second}
+	f.stop = stop
+	go f.server.Serve(listener)
+	go f.resync(stop)
+	return nil
+}
+
+// Close stops the server and hangs up on connected pages.
+func (f *LiveFeed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.server != nil {
+		f.server.Close()
+		close(f.stop)
+		f.server, f.stop = nil, nil
+	}
+}
+
+// guard turns away pages reaching the port through a rebound DNS name.
+func (f *LiveFeed) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.Host)
+		if host != "127.0.0.1" && host != "localhost" {
+			http.Error(w, "unexpected host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Publish sends a new state to every connected page. A page that hasn't
+// taken the last one yet only gets the newest.
+func (f *LiveFeed) Publish(state LiveState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = state
+	for client := range f.clients {
+		select {
+		case <-client:
+		default:
+		}
+		client <- state
+	}
+}
+
+// resync publishes the timer's state straight away and then every
+// LiveFeedResync while pages are connected, until the server stops.
+func (f *LiveFeed) resync(stop chan struct{}) {
+	ticker := time.NewTicker(LiveFeedResync)
+	defer ticker.Stop()
+
+	for {
+		f.mu.Lock()
+		connected := len(f.clients) > 0 || f.state.At.IsZero()
+		f.mu.Unlock()
+		if connected {
+			var status Status
+			fyne.DoAndWait(func() {
+				status = timerStatus(f.timer)
+			})
+			f.Publish(liveStateOf(status))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// serveClient sends one page the current state and every one after it,
+// until the page or the server hangs up. Anything the page sends is
+// ignored.
+func (f *LiveFeed) serveClient(ws *websocket.Conn, stop chan struct{}) {
+	defer ws.Close()
+
+	client := make(chan LiveState, 1)
+	f.mu.Lock()
+	f.clients[client] = struct{}{}
+	client <- f.state
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		delete(f.clients, client)
+		f.mu.Unlock()
+	}()
+
+	gone := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, ws)
+		close(gone)
+	}()
+
+	for {
+		select {
+		case state := <-client:
+			ws.SetWriteDeadline(time.Now().Add(HTTPTimeout))
+			if err := websocket.JSON.Send(ws, state); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// liveOverlayPage shows the task and a ticking clock on a transparent
+// background. It reconnects when the app restarts, and adding
+// &today=1 to its address shows today's total as well.
+const liveOverlayPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gotime</title>
+<style>
+  html, body { margin: 0; background: transparent; color: #fff; font-family: sans-serif; text-shadow: 0 0 6px #000; }
+  #clock { font-size: 64px; font-variant-numeric: tabular-nums; font-weight: bold; }
+  #task { font-size: 28px; }
+  #today { font-size: 20px; opacity: 0.8; }
+  .paused #clock { opacity: 0.5; }
+</style>
+</head>
+<body>
+<div id="task"></div>
+<div id="clock"></div>
+<div id="today"></div>
+<script>
+const params = new URLSearchParams(location.search);
+const showToday = params.has("today");
+let state = null;
+
+function format(ms) {
+  const s = Math.floor(ms / 1000);
+  const pad = n => String(n).padStart(2, "0");
+  return Math.floor(s / 3600) + ":" + pad(Math.floor(s / 60) % 60) + ":" + pad(s % 60);
+}
+
+function render() {
+  if (!state) return;
+  const since = state.running ? Math.max(Date.now() - Date.parse(state.at), 0) : 0;
+  document.body.className = state.running ? "" : "paused";
+  document.getElementById("task").textContent = state.task;
+  document.getElementById("clock").textContent = format(state.elapsed_ms + since);
+  document.getElementById("today").textContent = showToday ? "Today " + format(state.today_ms + since) : "";
+}
+
+function connect() {
+  const ws = new WebSocket("ws://" + location.host + "/live?key=" + encodeURIComponent(params.get("key") || ""));
+  ws.onmessage = event => { state = JSON.parse(event.data); render(); };
+  ws.onclose = () => setTimeout(connect, 3000);
+}
+
+connect();
+setInterval(render, 250);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefLongSessionHours is how long a session runs before the user is
+	// asked whether they're still working; zero turns the check off
+	PrefLongSessionHours = "longSessionHours"
+
+	// PrefLongSessionWait is how many minutes the question waits for an
+	// answer before the timer is paused
+	PrefLongSessionWait = "longSessionWait"
+
+	DefaultLongSessionWait = 15
+
+	// LongSessionCheckInterval is how often the running session is checked
+	LongSessionCheckInterval = time.Minute
+)
+
+var (
+	LongSessionLimits = []int{0, 2, 3, 4, 6, 8}
+	LongSessionWaits  = []int{5, 10, 15, 30}
+)
+
+// longSessionCheck follows the question for the running session.
+type longSessionCheck struct {
+	session time.Time
+	askAt   time.Duration
+
+	// While the question is open
+	prompt      dialog.Dialog
+	promptedAt  time.Time
+	elapsedThen time.Duration
+}
+
+// watchLongSessions asks whether the user is still working once a session
+// passes the configured limit. Left unanswered, the timer is paused, the
+// session up to the question is recorded, and the time since is kept as its
+// own entry, flagged for review.
+func watchLongSessions(timer *TaskTimer) {
+	check := &longSessionCheck{}
+	go func() {
+		for now := range time.Tick(LongSessionCheckInterval) {
+			fyne.Do(func() {
+				check.run(timer, now)
+			})
+		}
+	}()
+}
+
+func (c *longSessionCheck) run(timer *TaskTimer, now time.Time) {
+	prefs := fyne.CurrentApp().Preferences()
+	limit := time.Duration(prefs.Int(PrefLongSessionHours)) * time.Hour
+	if !timer.isRunning || limit <= 0 {
+		c.close()
+		return
+	}
+	if !timer.sessionStart.Equal(c.session) {
+		c.close()
+		c.session, c.askAt = timer.sessionStart, limit
+	}
+
+	wait := time.Duration(prefs.IntWithFallback(PrefLongSessionWait, DefaultLongSessionWait)) * time.Minute
+	switch {
+	case c.prompt != nil && now.Sub(c.promptedAt) >= wait:
+		c.close()
+		c.askAt = timer.elapsedTime + limit
+		c.pause(timer)
+	case c.prompt == nil && timer.elapsedTime >= c.askAt:
+		c.ask(timer, limit, now)
+	}
+}
+
+func (c *longSessionCheck) close() {
+	if c.prompt != nil {
+		c.prompt.Hide()
+		c.prompt = nil
+	}
+}
+
+func (c *longSessionCheck) ask(timer *TaskTimer, limit time.Duration, now time.Time) {
+	message := tr("Are you still working on {{.Task}}? The timer has been running for {{.Duration}}.", map[string]any{
+		"Task":     timer.taskName,
+		"Duration": formatShortDuration(timer.elapsedTime.Truncate(time.Minute)),
+	})
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(tr("Still Working?"), message))
+	playSound(timer, SoundLongSession)
+
+	var d dialog.Dialog
+	buttons := container.NewHBox(
+		widget.NewButton(tr("Yes, keep going"), func() {
+			c.prompt = nil
+			c.askAt = timer.elapsedTime + limit
+			d.Hide()
+		}),
+		widget.NewButton(tr("Stop and record"), func() {
+			c.prompt = nil
+			d.Hide()
+			stopSession(timer)
+		}),
+	)
+	label := widget.NewLabel(message)
+	label.Wrapping = fyne.TextWrapWord
+	d = dialog.NewCustomWithoutButtons(tr("Still Working?"), container.NewVBox(label, buttons), timer.window)
+	d.Resize(fyne.NewSize(360, 0))
+	d.Show()
+
+	c.prompt, c.promptedAt, c.elapsedThen = d, now, timer.elapsedTime
+}
+
+// pause pauses the timer and splits the session where the question went
+// unanswered. The part before is recorded; the tail stays as the paused
+// session, which is recorded as its own entry waiting for review.
+func (c *longSessionCheck) pause(timer *TaskTimer) {
+	pauseTimer(timer)
+	tail := max(timer.elapsedTime-c.elapsedThen, 0)
+	setElapsed(timer, c.elapsedThen)
+	stopSessionAt(timer, c.promptedAt)
+	timer.sessionStart = c.promptedAt
+	setElapsed(timer, tail)
+	timer.review = ReviewUnanswered
+	writeStatus(timer)
+
+	dialog.ShowInformation(tr("Timer Paused"), tr("Nobody answered whether you were still working on {{.Task}} at {{.Time}}, so the timer was paused. The session up to then was recorded; the time since is kept apart and waits in Review.", map[string]any{
+		"Task": timer.taskName,
+		"Time": c.promptedAt.Format("15:04"),
+	}), timer.window)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestLongSessionPauseKeepsTail(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	app := test.NewTempApp(t)
+	store, err := OpenJSONStore(filepath.Join(t.TempDir(), "gotime.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	timer := &TaskTimer{
+		taskName:    "Code",
+		taskList:    make(map[string]time.Duration),
+		dailyTotals: make(map[time.Time]map[string]time.Duration),
+		stopTicker:  make(chan bool, 1),
+		window:      test.NewWindow(nil),
+		slack:       NewSlackStatus(),
+		presence:    NewTeamPresence(),
+		store:       store,
+		tasks:       NewTaskStore(store, app.Preferences()),
+		contentBox:  container.NewVBox(),
+		notesInput:  widget.NewEntry(),
+	}
+	timer.calendar = NewCalendarSync(timer)
+	timer.liveFeed = NewLiveFeed(timer)
+	newTimerBindings(timer)
+
+	// Asked after four hours, and left unanswered for one, in a zone where
+	// it's around noon so the session doesn't run past midnight
+	now := time.Now()
+	local := time.Local
+	time.Local = time.FixedZone("Noon", (12-now.UTC().Hour())*3600)
+	t.Cleanup(func() { time.Local = local })
+	asked := now.Add(-time.Hour)
+	timer.sessionStart = now.Add(-5 * time.Hour)
+	timer.isRunning, timer.tickedAt = true, now
+	setElapsed(timer, 5*time.Hour)
+	check := &longSessionCheck{promptedAt: asked, elapsedThen: 4 * time.Hour}
+	check.pause(timer)
+
+	if timer.isRunning {
+		t.Error("the timer is still running")
+	}
+	entries := allEntries(timer)
+	if len(entries) != 1 {
+		t.Fatalf("%d entries recorded at the pause, want 1", len(entries))
+	}
+	head := entries[0]
+	if !head.End.Equal(asked) || head.Duration != 4*time.Hour || needsReview(head) {
+		t.Errorf("the session up to the question = %+v, want 4h ending at %v, not waiting for review", head, asked)
+	}
+	if !timer.sessionStart.Equal(asked) || timer.elapsedTime < time.Hour || timer.elapsedTime > time.Hour+time.Since(now) {
+		t.Errorf("paused session from %v for %v, want from %v for an hour", timer.sessionStart, timer.elapsedTime, asked)
+	}
+
+	stopSession(timer)
+	entries = allEntries(timer)
+	if len(entries) != 2 {
+		t.Fatalf("%d entries after stopping, want 2", len(entries))
+	}
+	tail := entries[1]
+	if tail.Task != "Code" || !tail.Start.Equal(asked) || tail.Review != ReviewUnanswered {
+		t.Errorf("tail = %+v, want a Code entry from %v waiting for review", tail, asked)
+	}
+}
@@ -1,110 +1,338 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"io"
+	"log"
+	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
 type TaskTimer struct {
-	taskName        string
-	elapsedTime     time.Duration
-	isRunning       bool
-	ticker          *time.Ticker
-	taskList        map[string]time.Duration
-	taskListMutex   sync.Mutex
-	timeLabel       *widget.Label
-	richTimeLabel   *canvas.Text
-	pauseResumeBtn  *widget.Button
-	taskSelector    *widget.Select
-	statsUpdateFunc func()
-	stopTicker      chan bool
-	currentView     string
-	contentBox      *fyne.Container
+	taskName       string
+	elapsedTime    time.Duration
+	isRunning      bool
+	tickedAt       time.Time
+	taskList       map[string]time.Duration
+	dailyTotals    map[time.Time]map[string]time.Duration
+	entries        []Entry
+	store          Store
+	audit          *AuditLog
+	cloudSync      *CloudSync
+	teamSync       *TeamSync
+	loaded         bool
+	taskListMutex  sync.Mutex
+	sessionStart   time.Time
+	awaySince      time.Time
+	pauseResumeBtn *widget.Button
+	taskPicker     *TaskPicker
+	tasks          *TaskStore
+	notesInput     *widget.Entry
+	elapsed        binding.String
+	taskTitle      binding.String
+	running        binding.Bool
+	sessionOpen    binding.Bool
+	history        binding.Item[[]Entry]
+	templates      binding.Item[[]TaskTemplate]
+	estimates      binding.Item[TaskEstimates]
+	styles         binding.Item[TaskStyles]
+	template       TaskTemplate
+	autoStopped    bool
+	review         string
+	adjustment     time.Duration
+	viewListeners  []func()
+	stopTicker     chan bool
+	currentView    string
+	contentBox     *fyne.Container
+	contentScroll  *container.Scroll
+	timerView      fyne.CanvasObject
+	window         fyne.Window
+	undo           UndoStack
+	undoToast      *widget.PopUp
+	mini           *MiniTimer
+	alerts         *AlertScheduler
+	calendar       *CalendarSync
+	slack          *SlackStatus
+	presence       *TeamPresence
+	companion      *BrowserCompanion
+	liveFeed       *LiveFeed
 }
 
 const (
-	TickInterval = 100 * time.Millisecond
+	// TickInterval is how often the clock is refreshed, PrecisionTickInterval
+	// how often in precision mode, and BackgroundTickInterval the most often
+	// while no window of the app has focus
+	TickInterval           = time.Second
+	PrecisionTickInterval  = 100 * time.Millisecond
+	BackgroundTickInterval = time.Second
+
+	// NoTaskSelected is the selector placeholder; time is never recorded
+	// against it. It's shown translated but kept in English internally.
+	NoTaskSelected = "Select a task"
 )
 
 func main() {
-	myApp := app.New()
-	w := myApp.NewWindow("Task Timer")
+	minimized := len(os.Args) == 2 && os.Args[1] == MinimizedFlag
+	var launchRequest *InstanceRequest
+	if len(os.Args) == 2 && isTimerURL(os.Args[1]) {
+		request, err := parseTimerURL(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gotime: %v\n", err)
+			os.Exit(2)
+		}
+		launchRequest = &request
+	} else if len(os.Args) > 1 && !minimized {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
 
-	// Set window size to be tall and narrow
-	w.Resize(fyne.NewSize(400, 900))
+	// A second launch brings the running app's window forward, or hands it
+	// the link it was opened with, instead of opening another window on the
+	// same data; one on login leaves it be
+	if minimized {
+		if instanceRunning() {
+			return
+		}
+	} else {
+		request := InstanceRequest{Command: "focus"}
+		if launchRequest != nil {
+			request = *launchRequest
+		}
+		_, err := callInstance(request)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, errNoInstance) {
+			fmt.Fprintf(os.Stderr, "gotime: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	myApp := app.NewWithID("io.github.0jc1.gotime")
+	applyTimeZone(myApp.Preferences())
+	applyDurationFormat(myApp.Preferences())
+	applyPrecisionMode(myApp.Preferences())
+	moveKeychainSecrets(myApp.Preferences())
+	loadTranslations()
+	w := myApp.NewWindow(tr("Task Timer"))
+	w.SetMaster()
+	if minimized {
+		minimizeOnStart(myApp, w)
+	}
 
+	// Phones and tablets always run full screen
+	if fyne.CurrentDevice().IsMobile() {
+		myApp.Settings().SetTheme(touchTheme{theme.DefaultTheme()})
+	} else {
+		w.Resize(fyne.NewSize(640, 720))
+	}
+
+	instances := NewInstanceServer(w)
+	if err := instances.Listen(); err != nil {
+		log.Print(err)
+	}
+	if launchRequest != nil {
+		instances.Wait(*launchRequest)
+	}
+	watchURLEvents(instances)
+
+	// An encrypted store may have to be unlocked first, so the timer is set
+	// up once the store is open
+	var store Store
+	unlockStore(myApp, w, func(opened Store) {
+		store = opened
+		instances.SetTimer(setUpTimer(myApp, w, syncTrackingStore{opened}))
+	})
+	w.ShowAndRun()
+	instances.Close()
+	if store != nil {
+		store.Close()
+	}
+	setFocusMode(false)
+	clearStatus()
+}
+
+// setUpTimer builds the timer and its window content on top of an open
+// store.
+func setUpTimer(myApp fyne.App, w fyne.Window, store Store) *TaskTimer {
 	// Create task timer instance
 	timer := &TaskTimer{
-		taskName:      "Select a task",
-		elapsedTime:   0,
-		isRunning:     false,
-		taskList:      make(map[string]time.Duration),
-		stopTicker:    make(chan bool, 1),
-		currentView:   "timer",
+		taskName:    NoTaskSelected,
+		elapsedTime: 0,
+		isRunning:   false,
+		taskList:    make(map[string]time.Duration),
+		dailyTotals: make(map[time.Time]map[string]time.Duration),
+		stopTicker:  make(chan bool, 1),
+		currentView: "timer",
+		window:      w,
+		alerts:      NewAlertScheduler(w),
+		slack:       NewSlackStatus(),
+		presence:    NewTeamPresence(),
+		store:       store,
+		audit:       NewAuditLog(store),
+		tasks:       NewTaskStore(store, myApp.Preferences()),
 	}
+	newTimerBindings(timer)
+	myApp.Lifecycle().SetOnExitedForeground(func() { appInBackground.Store(true) })
+	myApp.Lifecycle().SetOnEnteredForeground(func() { appInBackground.Store(false) })
+	timer.calendar = NewCalendarSync(timer)
+	timer.cloudSync = NewCloudSync(timer)
+	timer.teamSync = NewTeamSync(timer)
+	timer.companion = NewBrowserCompanion(timer)
+	timer.liveFeed = NewLiveFeed(timer)
+	timer.tasks.AddObserver(func() {
+		timer.taskPicker.Refresh()
+	})
 
-	// Create the three main containers
-	timerContainer := createTimerContainer(timer)
-	dailyStatsContainer := createDailyStatsContainer(timer)
-	addTaskContainer := createAddTaskContainer(timer)
+	// The timer view is kept alive because the ticker updates it in the
+	// background; the other views are built on demand in updateContentView
+	timer.timerView = createTimerContainer(timer)
 
 	// Create content box that will hold the current view
 	timer.contentBox = container.NewVBox()
-	updateContentView(timer, timerContainer, dailyStatsContainer, addTaskContainer)
+	updateContentView(timer)
 
-	// Create sidebar with navigation buttons
-	sidebarContainer := container.NewVBox(
-		widget.NewButton("⏱ Timer", func() {
-			timer.currentView = "timer"
-			updateContentView(timer, timerContainer, dailyStatsContainer, addTaskContainer)
-		}),
-		widget.NewButton("📊 Daily Stats", func() {
-			timer.currentView = "stats"
-			updateContentView(timer, timerContainer, dailyStatsContainer, addTaskContainer)
-		}),
-		widget.NewButton("➕ Add Task", func() {
-			timer.currentView = "addtask"
-			updateContentView(timer, timerContainer, dailyStatsContainer, addTaskContainer)
-		}),
-	)
-
-	// Create main layout with sidebar and content
-	mainLayout := container.NewHBox(
-		container.NewVBox(
-			widget.NewSeparator(),
-			sidebarContainer,
-		),
-		timer.contentBox,
-	)
+	// Create navigation buttons, one per view
+	navButtons := container.NewVBox()
+	for _, item := range navItems() {
+		button := widget.NewButton(item.label, func() {
+			openNavItem(timer, item)
+		})
+		if item.view == "review" {
+			watchReviewQueue(timer, button, item.label)
+		}
+		navButtons.Add(button)
+	}
+	guestCheck := newGuestModeCheck(timer)
+
+	// The view scrolls when the window is smaller than it
+	timer.contentScroll = container.NewVScroll(timer.contentBox)
+
+	// Phones get the navigation as a bar along the bottom, within reach of
+	// a thumb; elsewhere it's a sidebar
+	var mainLayout *fyne.Container
+	if fyne.CurrentDevice().IsMobile() {
+		navButtons.Layout = layout.NewGridLayoutWithColumns(3)
+		mainLayout = container.NewBorder(guestCheck, navButtons, nil, nil, timer.contentScroll)
+	} else {
+		navButtons.Add(guestCheck)
+		sidebar := container.NewVBox(widget.NewSeparator(), navButtons)
+		mainLayout = container.NewBorder(nil, nil, sidebar, nil, timer.contentScroll)
+	}
 
 	w.SetContent(mainLayout)
-	w.ShowAndRun()
+	setUpKeyboard(timer, guestCheck)
+	watchBudgets(timer)
+	watchDaySummary(timer)
+	watchDayTarget(timer)
+	watchBarClock(timer)
+	watchWorkSchedule(timer)
+	watchAutoStop(timer)
+	watchLongSessions(timer)
+	go timer.calendar.Run()
+	go timer.presence.Run()
+	go startDBusService(timer)
+	go watchGitBranch(timer)
+	go watchActiveWindow(timer)
+	if err := timer.companion.Apply(); err != nil {
+		log.Print(err)
+	}
+	if err := timer.liveFeed.Apply(); err != nil {
+		log.Print(err)
+	}
+
+	// Pause while the machine sleeps or the screen is locked
+	powerEvents := make(chan PowerEvent)
+	go watchPowerEvents(powerEvents)
+	go handlePowerEvents(timer, powerEvents)
+	loadEntriesAsync(timer)
+	return timer
 }
 
-func updateContentView(timer *TaskTimer, timerContainer, dailyStatsContainer, addTaskContainer fyne.CanvasObject) {
+// showView navigates to one of the sidebar views.
+func showView(timer *TaskTimer, view string) {
+	timer.currentView = view
+	updateContentView(timer)
+}
+
+// updateContentView swaps the content box to the current view. Views other
+// than the timer are rebuilt each time they are shown and dropped when hidden,
+// so a long-running instance doesn't hold on to widget trees it isn't showing.
+func updateContentView(timer *TaskTimer) {
 	fyne.Do(func() {
 		timer.contentBox.RemoveAll()
 
+		unbindView(timer)
+		if timer.contentScroll != nil {
+			timer.contentScroll.ScrollToTop()
+		}
+
+		// Views built from the history wait until it has loaded
+		if !timer.loaded && (timer.currentView == "stats" || timer.currentView == "history" || timer.currentView == "review" || timer.currentView == "timeline" || timer.currentView == "timesheet") {
+			timer.contentBox.Add(createLoadingContainer())
+			return
+		}
+
 		switch timer.currentView {
 		case "timer":
-			timer.contentBox.Add(timerContainer)
+			timer.contentBox.Add(timer.timerView)
 		case "stats":
 			timer.contentBox.Add(container.NewVBox(
-				widget.NewLabel("📊 Daily Stats"),
-				dailyStatsContainer,
+				widget.NewLabel(tr("📊 Daily Stats")),
+				createDailyStatsContainer(timer),
+			))
+		case "history":
+			timer.contentBox.Add(container.NewVBox(
+				widget.NewLabel(tr("🕘 History")),
+				createHistoryContainer(timer),
+			))
+		case "review":
+			timer.contentBox.Add(container.NewVBox(
+				widget.NewLabel(tr("✅ Review")),
+				createReviewContainer(timer),
+			))
+		case "timeline":
+			timer.contentBox.Add(container.NewVBox(
+				widget.NewLabel(tr("📅 Timeline")),
+				createTimelineContainer(timer),
+			))
+		case "timesheet":
+			timer.contentBox.Add(container.NewVBox(
+				widget.NewLabel(tr("🧾 Timesheet")),
+				createTimesheetContainer(timer),
 			))
-		case "addtask":
+		case "tasks":
 			timer.contentBox.Add(container.NewVBox(
-				widget.NewLabel("➕ Add New Task"),
-				addTaskContainer,
+				widget.NewLabel(tr("➕ Add New Task")),
+				createAddTaskContainer(timer),
+				widget.NewSeparator(),
+				widget.NewLabel(tr("📋 Tasks")),
+				createTaskListContainer(timer),
+				widget.NewSeparator(),
+				widget.NewLabel(tr("📑 Templates")),
+				createTemplateListContainer(timer),
+				widget.NewSeparator(),
+				widget.NewLabel(tr("💰 Budgets")),
+				createBudgetListContainer(timer),
+			))
+		case "settings":
+			timer.contentBox.Add(container.NewVBox(
+				widget.NewLabel(tr("⚙ Settings")),
+				createSettingsContainer(timer),
 			))
 		}
 	})
@@ -112,20 +340,18 @@ func updateContentView(timer *TaskTimer, timerContainer, dailyStatsContainer, ad
 
 func createTimerContainer(timer *TaskTimer) *fyne.Container {
 	// Task name display
-	taskNameLabel := widget.NewLabel(timer.taskName)
+	taskNameLabel := widget.NewLabelWithData(timer.taskTitle)
 	taskNameLabel.Alignment = fyne.TextAlignCenter
 
-	// Elapsed time display (HH:MM:SS format)
-	timer.timeLabel = widget.NewLabel("00:00:00")
-	timer.timeLabel.Alignment = fyne.TextAlignCenter
-
-	// Create a rich text for larger, styled time display
-	richTimeLabel := canvas.NewText("00:00:00", color.White)
+	// Create a rich text for larger, styled time display (HH:MM:SS format)
+	richTimeLabel := canvas.NewText("", color.White)
 	richTimeLabel.TextSize = 56
 	richTimeLabel.Alignment = fyne.TextAlignCenter
+	bindText(richTimeLabel, timer.elapsed)
 
-	// Create black rounded rectangle background
+	// Black rounded rectangle background, in the task's color if it has one
 	blackBg := canvas.NewRectangle(color.RGBA{0, 0, 0, 255})
+	bindTaskColor(timer, blackBg, color.RGBA{0, 0, 0, 255})
 
 	// Create container with padding for the time label with background
 	timeLabelWithBg := container.NewStack(
@@ -133,151 +359,533 @@ func createTimerContainer(timer *TaskTimer) *fyne.Container {
 		container.NewCenter(richTimeLabel),
 	)
 
-	// Store reference to the rich text label for updates
-	timer.richTimeLabel = richTimeLabel
-
-	// Task selector dropdown
-	timer.taskSelector = widget.NewSelect([]string{"Select a task"}, func(value string) {
-		timer.taskName = value
-		taskNameLabel.SetText(value)
-	})
-	timer.taskSelector.PlaceHolder = "Select a task"
-	timer.taskSelector.SetSelected("Select a task")
-
-	// Pause/Resume button
-	timer.pauseResumeBtn = widget.NewButton("▶ Start", func() {
-		if timer.isRunning {
-			// Pause
-			timer.isRunning = false
-			timer.pauseResumeBtn.SetText("▶ Start")
-			timer.stopTicker <- true
+	// Task picker, searchable so it stays usable with many tasks
+	timer.taskPicker = NewTaskPicker(timer)
+	timer.taskPicker.OnChanged = func(taskName string) {
+		if taskName == NoTaskSelected {
+			timer.taskTitle.Set(tr(NoTaskSelected))
 		} else {
-			// Resume/Start
-			timer.isRunning = true
-			timer.pauseResumeBtn.SetText("⏸ Pause")
-			go startTimer(timer)
+			timer.taskTitle.Set(taskOption(timer, taskName))
 		}
-	})
+		timer.taskName = taskName
+		updateFocusMode(timer)
+		writeStatus(timer)
+	}
+	timer.taskPicker.SetSelected(NoTaskSelected)
 
-	// Reset button
-	resetBtn := widget.NewButton("↻ Reset", func() {
-		if timer.isRunning {
-			timer.isRunning = false
-			timer.pauseResumeBtn.SetText("▶ Start")
-			timer.stopTicker <- true
-		}
+	// Pause/Resume button
+	timer.pauseResumeBtn = widget.NewButton("", func() {
+		toggleTimer(timer)
+	})
+	bindToggleButton(timer.pauseResumeBtn, timer.running, timer.sessionOpen)
 
-		// Add elapsed time to task list before resetting
-		if timer.taskName != "Select a task" && timer.elapsedTime > 0 {
-			timer.taskListMutex.Lock()
-			timer.taskList[timer.taskName] += timer.elapsedTime
-			timer.taskListMutex.Unlock()
-			
-			if timer.statsUpdateFunc != nil {
-				timer.statsUpdateFunc()
-			}
-		}
+	// Stop button
+	stopBtn := widget.NewButton(tr("■ Stop"), func() {
+		stopSession(timer)
+	})
 
-		timer.elapsedTime = 0
-		timer.timeLabel.SetText("00:00:00")
-		timer.richTimeLabel.Text = "00:00:00"
-		timer.richTimeLabel.Refresh()
+	// Split button, for a session that should have been switched partway
+	splitBtn := widget.NewButton(tr("✂ Split"), func() {
+		showSplitSessionDialog(timer)
 	})
 
 	buttonContainer := container.NewHBox(
 		timer.pauseResumeBtn,
-		resetBtn,
+		stopBtn,
+		splitBtn,
 	)
 
+	// Detaches the clock into a small window that stays on top. Phones
+	// show one window at a time, so they don't get it
+	if !fyne.CurrentDevice().IsMobile() {
+		buttonContainer.Add(widget.NewButton(tr("⧉ Mini"), func() {
+			showMiniTimer(timer)
+		}))
+	}
+
+	// Notes are saved on the entry when the session is stopped
+	timer.notesInput = widget.NewMultiLineEntry()
+	timer.notesInput.PlaceHolder = tr("What are you working on?")
+	timer.notesInput.Wrapping = fyne.TextWrapWord
+	timer.notesInput.SetMinRowsVisible(3)
+
 	return container.NewVBox(
 		taskNameLabel,
-		timeLabelWithBg,
-		timer.taskSelector,
+		createAdjustButtons(timer, timeLabelWithBg),
+		createEstimateProgress(timer),
+		createGitHubIssueLink(timer),
+		timer.taskPicker,
 		buttonContainer,
+		timer.notesInput,
+		createTodayContainer(timer),
+		createTeamPresenceContainer(timer),
 	)
 }
 
+// toggleTimer pauses the timer if it is running and starts or resumes it
+// otherwise.
+func toggleTimer(timer *TaskTimer) {
+	if timer.isRunning {
+		pauseTimer(timer)
+	} else {
+		resumeTimer(timer)
+	}
+}
+
+// resumeTimer starts the timer, opening a new session if none is in progress.
+func resumeTimer(timer *TaskTimer) {
+	if timer.elapsedTime == 0 {
+		timer.sessionStart = time.Now()
+	}
+
+	timer.isRunning = true
+	timer.running.Set(true)
+	timer.tickedAt = time.Now()
+	go startTimer(timer)
+	sendWebhooks(timer, EventTimerStarted)
+	runHook(timer, EventTimerStarted)
+	updateFocusMode(timer)
+	timer.slack.Working(timer.taskName)
+	timer.presence.Working(timer.taskName)
+	writeStatus(timer)
+}
+
+func pauseTimer(timer *TaskTimer) {
+	if timer.isRunning {
+		advanceElapsed(timer, time.Now())
+	}
+	timer.isRunning = false
+	timer.running.Set(false)
+	timer.stopTicker <- true
+	sendWebhooks(timer, EventTimerStopped)
+	runHook(timer, EventTimerStopped)
+	updateFocusMode(timer)
+	timer.slack.Clear()
+	timer.presence.Idle()
+	writeStatus(timer)
+}
+
+// stopSession ends the session: the timer stops and the session is recorded
+// against the current task. Pausing, by contrast, keeps the session open, and
+// the time spent paused shows in History as the gap between the entry's span
+// and its duration.
+func stopSession(timer *TaskTimer) {
+	stopSessionAt(timer, time.Now())
+}
+
+// stopSessionAt is stopSession with the session ending at a given time.
+func stopSessionAt(timer *TaskTimer, end time.Time) {
+	if timer.isRunning {
+		pauseTimer(timer)
+	}
+
+	// A template only applies to the session it started, and an automatic
+	// stop, time away left out or an adjustment to the session it was made in
+	defer func() {
+		timer.template = TaskTemplate{}
+		timer.autoStopped = false
+		timer.review = ""
+		timer.adjustment = 0
+	}()
+
+	// Add elapsed time to task list before closing the session
+	if timer.taskName != NoTaskSelected && timer.elapsedTime > 0 {
+		entry := Entry{
+			Task:        timer.taskName,
+			Start:       timer.sessionStart,
+			End:         end,
+			Duration:    timer.elapsedTime,
+			Notes:       strings.TrimSpace(timer.notesInput.Text),
+			NonBillable: isInternalTask(timer.taskName),
+			AutoStopped: timer.autoStopped,
+			Review:      timer.review,
+			Adjustment:  timer.adjustment,
+		}
+		if timer.template.Name == entry.Task {
+			timer.template.Apply(&entry)
+		}
+		applyRules(&entry)
+
+		// Sessions that ran past midnight are recorded as one entry per day
+		var recorded []Entry
+		for _, part := range splitAtMidnight(entry) {
+			if part.Duration <= 0 {
+				continue
+			}
+			part = recordEntry(timer, part)
+			pushJiraWorklog(timer, part)
+			pushGitLabSpentTime(timer, part)
+			pushAzureDevOpsWork(timer, part)
+			timer.calendar.Push(part)
+			recorded = append(recorded, part)
+		}
+		if len(recorded) > 0 {
+			pushGitHubComment(timer, entry)
+		}
+
+		timer.notesInput.SetText("")
+		pushUndo(timer, tr("Recorded {{.Duration}} on {{.Task}}", map[string]any{
+			"Duration": formatDuration(entry.Duration),
+			"Task":     entry.Task,
+		}), func() {
+			undoStop(timer, recorded)
+		}, nil)
+	}
+
+	setElapsed(timer, 0)
+	writeStatus(timer)
+}
+
+// splitSession divides the session in progress at a point in time: the part
+// before is recorded against firstTask and the session carries on from there
+// on secondTask, running if it was. As with splitting an entry, any paused
+// time is left in the second part, and any adjustment stays with the first.
+func splitSession(timer *TaskTimer, at time.Time, firstTask, secondTask string) error {
+	if timer.elapsedTime == 0 {
+		return errors.New("no session is in progress")
+	}
+	if !at.After(timer.sessionStart) || !at.Before(time.Now()) {
+		return errors.New("the split time must fall inside the session")
+	}
+
+	wasRunning := timer.isRunning
+	if wasRunning {
+		pauseTimer(timer)
+	}
+	first := min(timer.elapsedTime, at.Sub(timer.sessionStart))
+	rest := timer.elapsedTime - first
+
+	selectTask(timer, firstTask)
+	setElapsed(timer, first)
+	stopSessionAt(timer, at)
+
+	selectTask(timer, secondTask)
+	timer.sessionStart = at
+	setElapsed(timer, rest)
+	if wasRunning {
+		resumeTimer(timer)
+	}
+	return nil
+}
+
+// startTimer refreshes the running session's clock until told to stop. The
+// session's state belongs to the UI thread, where buttons and dialogs change
+// it too, so each tick is handed over to it.
 func startTimer(timer *TaskTimer) {
-	timer.ticker = time.NewTicker(TickInterval)
-	defer timer.ticker.Stop()
+	interval := tickInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Round(0) drops the monotonic reading, which stands still while the
+	// machine sleeps, so a jump in wall-clock time between ticks means it slept
+	lastTick := time.Now().Round(0)
 
 	for {
 		select {
 		case <-timer.stopTicker:
 			return
-		case <-timer.ticker.C:
-			if timer.isRunning {
-				timer.elapsedTime += TickInterval
-				hours := timer.elapsedTime / time.Hour
-				minutes := (timer.elapsedTime % time.Hour) / time.Minute
-				seconds := (timer.elapsedTime % time.Minute) / time.Second
-				timeStr := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-				
-				fyne.Do(func() {
-					timer.richTimeLabel.Text = timeStr
-					timer.richTimeLabel.Refresh()
-				})
+		case <-ticker.C:
+			now := time.Now()
+			asleepSince := lastTick
+			slept := now.Round(0).Sub(lastTick) > SleepDetectThreshold
+			lastTick = now.Round(0)
+			fyne.Do(func() {
+				if slept {
+					// The time asleep is for the user to count or not
+					timer.tickedAt = now
+					suspendForAway(timer, asleepSince)
+					promptAfterAway(timer, now.Round(0))
+				}
+				if timer.isRunning {
+					advanceElapsed(timer, now)
+				}
+			})
+
+			// Precision mode or the app's focus changed while running
+			if next := tickInterval(); next != interval {
+				interval = next
+				ticker.Reset(interval)
 			}
 		}
 	}
 }
 
+// advanceElapsed adds the time since the clock was last refreshed to the
+// running session, so the session stays exact however seldom that happens.
+func advanceElapsed(timer *TaskTimer, now time.Time) {
+	// A tick that waited for the UI thread while the timer was paused and
+	// resumed is older than the resume
+	if !now.After(timer.tickedAt) {
+		return
+	}
+	step := now.Sub(timer.tickedAt)
+	timer.tickedAt = now
+	if pomodoroDone(timer.elapsedTime, timer.elapsedTime+step) {
+		playSound(timer, SoundPomodoro)
+	}
+	setElapsed(timer, timer.elapsedTime+step)
+}
+
+// appInBackground is whether no window of the app has focus.
+var appInBackground atomic.Bool
+
+// tickInterval is how often the clock is refreshed: ten times a second in
+// precision mode, but no more than once a second while the app is in the
+// background, to spare the battery.
+func tickInterval() time.Duration {
+	interval := TickInterval
+	if precisionMode.Load() {
+		interval = PrecisionTickInterval
+	}
+	if appInBackground.Load() {
+		interval = max(interval, BackgroundTickInterval)
+	}
+	return interval
+}
+
 func createDailyStatsContainer(timer *TaskTimer) fyne.CanvasObject {
 	// Container to display daily stats
 	statsBox := container.NewVBox()
 
+	// Filters
+	searchInput := widget.NewEntry()
+	searchInput.PlaceHolder = tr("Search tasks")
+	allProjects, allTags := tr(AllProjects), tr(AllTags)
+	projectSelect := widget.NewSelect(append([]string{allProjects}, knownProjects(timer)...), nil)
+	projectSelect.SetSelected(allProjects)
+	tagSelect := widget.NewSelect(append([]string{allTags}, knownTags(timer)...), nil)
+	tagSelect.SetSelected(allTags)
+	fromInput := widget.NewEntry()
+	fromInput.PlaceHolder = tr("From (YYYY-MM-DD)")
+	toInput := widget.NewEntry()
+	toInput.PlaceHolder = tr("To (YYYY-MM-DD)")
+	periodSelect := newReportPeriodSelect(fromInput, toInput)
+
+	// Show a single day, today by default, and step between days
+	showDay := func(day time.Time) {
+		fromInput.SetText(day.Format("2006-01-02"))
+		toInput.SetText(day.Format("2006-01-02"))
+	}
+	stepDay := func(days int) {
+		day, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			day = dayStart(time.Now())
+		}
+		showDay(addDays(day, days))
+	}
+	showDay(dayStart(time.Now()))
+	dayNav := container.NewHBox(
+		widget.NewButtonWithIcon(tr("Previous"), theme.NavigateBackIcon(), func() { stepDay(-1) }),
+		widget.NewButton(tr("Today"), func() { showDay(dayStart(time.Now())) }),
+		widget.NewButtonWithIcon(tr("Next"), theme.NavigateNextIcon(), func() { stepDay(1) }),
+		widget.NewButton(tr("All time"), func() {
+			fromInput.SetText("")
+			toInput.SetText("")
+		}),
+	)
+
+	// Sort order, remembered across restarts
+	prefs := fyne.CurrentApp().Preferences()
+	sortSelect := widget.NewSelect(statsSortLabels(), nil)
+	sortSelect.SetSelected(tr(statsSortByKey(prefs.StringWithFallback(PrefStatsSort, StatsSortDuration)).Label))
+
+	// Grouping, remembered the same way
+	groupSelect := widget.NewSelect(statsGroupLabels(), nil)
+	groupSelect.SetSelected(tr(statsGroupByKey(prefs.StringWithFallback(PrefStatsGroup, StatsGroupTask)).Label))
+
+	// The entries shown, for exporting them as a timesheet or to a calendar
+	var shown []Entry
+	var shownFilter EntryFilter
+	exportButtons := container.NewGridWithColumns(2,
+		widget.NewButtonWithIcon(tr("Export timesheet…"), theme.DocumentSaveIcon(), func() {
+			saveExport(timer, "timesheet.xlsx", func(w io.Writer) error {
+				return writeEntriesXLSX(w, roundEntries(shown), currentExportLocale())
+			})
+		}),
+		widget.NewButtonWithIcon(tr("Export to calendar…"), theme.DocumentSaveIcon(), func() {
+			saveExport(timer, "sessions.ics", func(w io.Writer) error {
+				return writeEntriesICS(w, shown, currentExportLocale())
+			})
+		}),
+	)
+	if teamServerURL() != "" {
+		exportButtons.Add(widget.NewButtonWithIcon(tr("Share link…"), theme.MailForwardIcon(), func() {
+			showShareReportDialog(timer, shownFilter)
+		}))
+	}
+
 	// Update function
-	timer.statsUpdateFunc = func() {
+	update := func() {
+		filter := EntryFilter{Query: searchInput.Text}
+		if projectSelect.Selected != allProjects {
+			filter.Project = projectSelect.Selected
+		}
+		if tagSelect.Selected != allTags {
+			filter.Tag = tagSelect.Selected
+		}
+		filter.From, _ = time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local); err == nil {
+			filter.To = to.AddDate(0, 0, 1)
+		}
+		history, _ := timer.history.Get()
+		entries := filter.Apply(history)
+		shown, shownFilter = entries, filter
+		totals := totalsByTask(entries)
+		order := statsSortByLabel(sortSelect.Selected)
+		group := statsGroupByLabel(groupSelect.Selected)
+		var total time.Duration
+		for _, duration := range totals {
+			total += duration
+		}
+
+		// The total, the daily average, the billable share and, for a period,
+		// the change on the one before
+		summary := tr("Total {{.Total}} · {{.Average}} a day", map[string]any{
+			"Total":   formatDuration(total),
+			"Average": formatDuration(dailyAverage(entries)),
+		})
+		if billable, internal := billableSplit(entries); internal > 0 {
+			summary += " · " + tr("{{.Percent}}% billable", map[string]any{"Percent": billablePercent(billable, internal)})
+		}
+		if previous, ok := filter.Previous(); ok {
+			var last time.Duration
+			before := previous.Apply(history)
+			for _, entry := range before {
+				last += entry.Duration
+			}
+			summary += " · " + periodChange(total, last)
+			summary += " · " + tr("average {{.Change}}", map[string]any{"Change": periodChange(dailyAverage(entries), dailyAverage(before))})
+		}
+
 		fyne.Do(func() {
 			statsBox.RemoveAll()
-			
-			timer.taskListMutex.Lock()
-			defer timer.taskListMutex.Unlock()
-			
-			if len(timer.taskList) == 0 {
-				statsBox.Add(widget.NewLabel("No tasks completed yet"))
+			if len(totals) > 0 {
+				statsBox.Add(widget.NewLabelWithStyle(summary, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			}
+
+			if len(totals) == 0 && filter.IsZero() {
+				statsBox.Add(widget.NewLabel(tr("No tasks completed yet")))
+			} else if len(totals) == 0 && filter.Query == "" && filter.Project == "" && filter.Tag == "" {
+				statsBox.Add(widget.NewLabel(tr("Nothing tracked in this period")))
+			} else if len(totals) == 0 {
+				statsBox.Add(widget.NewLabel(tr("No matching tasks")))
+			} else if group.Groups != nil {
+				statsBox.Add(newStatsGroups(timer, entries, group, order))
 			} else {
-				for taskName, duration := range timer.taskList {
-					hours := duration / time.Hour
-					minutes := (duration % time.Hour) / time.Minute
-					seconds := (duration % time.Minute) / time.Second
-					timeStr := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-					statLabel := widget.NewLabel(fmt.Sprintf("%s: %s", taskName, timeStr))
-					statsBox.Add(statLabel)
+				for _, taskName := range order.Sort(totals, entries) {
+					statsBox.Add(newStatsRow(timer, taskName, totals[taskName], total))
 				}
 			}
 		})
 	}
+	searchInput.OnChanged = func(string) { update() }
+	projectSelect.OnChanged = func(string) { update() }
+	tagSelect.OnChanged = func(string) { update() }
+	fromInput.OnChanged = func(string) { update() }
+	toInput.OnChanged = func(string) { update() }
+	sortSelect.OnChanged = func(label string) {
+		prefs.SetString(PrefStatsSort, statsSortByLabel(label).Key)
+		update()
+	}
+	groupSelect.OnChanged = func(label string) {
+		prefs.SetString(PrefStatsGroup, statsGroupByLabel(label).Key)
+		update()
+	}
+
+	// Populates the view straight away, then follows changes to the history
+	// and to task styles
+	listenWhileShown(timer, timer.history, update)
+	listenWhileShown(timer, timer.styles, update)
+
+	return container.NewVBox(
+		createBadgesContainer(timer),
+		createFlexitimeContainer(timer),
+		createBudgetUsageContainer(timer),
+		createEstimatesContainer(timer),
+		createTrendsContainer(timer),
+		widget.NewSeparator(),
+		dayNav,
+		searchInput,
+		container.NewGridWithColumns(2, projectSelect, tagSelect),
+		container.NewGridWithColumns(2, fromInput, toInput),
+		widget.NewForm(
+			widget.NewFormItem(tr("Period"), periodSelect),
+			widget.NewFormItem(tr("Sort by"), sortSelect),
+			widget.NewFormItem(tr("Group by"), groupSelect),
+		),
+		exportButtons,
+		statsBox,
+	)
+}
 
-	// Initialize with empty state
-	statsBox.Add(widget.NewLabel("No tasks completed yet"))
+// snapshotTaskTotals copies the task totals so views can render them without
+// holding the task list lock.
+func snapshotTaskTotals(timer *TaskTimer) map[string]time.Duration {
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
 
-	return container.NewScroll(statsBox)
+	totals := make(map[string]time.Duration, len(timer.taskList))
+	for taskName, duration := range timer.taskList {
+		totals[taskName] = duration
+	}
+	return totals
 }
 
 func createAddTaskContainer(timer *TaskTimer) *fyne.Container {
 	taskNameInput := widget.NewEntry()
-	taskNameInput.PlaceHolder = "Enter task name (e.g., 'Write code')"
-
-	addBtn := widget.NewButton("Add Task", func() {
-		taskName := taskNameInput.Text
-		if taskName != "" && taskName != "Select a task" {
-			// Update task selector
-			options := timer.taskSelector.Options
-			if !contains(options, taskName) {
-				options = append(options, taskName)
-				timer.taskSelector.Options = options
-			}
-			taskNameInput.SetText("")
+	taskNameInput.PlaceHolder = tr("Enter task name (e.g., 'Write code')")
+
+	addBtn := widget.NewButton(tr("Add Task"), func() {
+		if err := timer.tasks.Add(taskNameInput.Text); err != nil {
+			dialog.ShowInformation(tr("Add Task"), tr(err.Error()), timer.window)
+			return
 		}
+		taskNameInput.SetText("")
+
+		// Rebuild so the task list below picks up the new task
+		showView(timer, timer.currentView)
+	})
+	taskNameInput.OnSubmitted = func(string) {
+		addBtn.OnTapped()
+	}
+
+	githubBtn := widget.NewButton(tr("From GitHub issue…"), func() {
+		showGitHubIssueDialog(timer)
 	})
 
 	return container.NewVBox(
 		taskNameInput,
-		addBtn,
+		container.NewGridWithColumns(2, addBtn, githubBtn),
 	)
 }
 
+// undoStop takes a recorded session, which may have been split at midnight,
+// back out of the totals and puts it on the timer again, as long as no new
+// session has started meanwhile. Worklogs and calendar events already sent
+// for it are left in place.
+func undoStop(timer *TaskTimer, recorded []Entry) {
+	if timer.isRunning || timer.elapsedTime > 0 {
+		dialog.ShowInformation(tr("Undo"), tr("Stop the current session before undoing."), timer.window)
+		return
+	}
+
+	var elapsed time.Duration
+	for _, entry := range recorded {
+		if err := deleteEntry(timer, entry.ID); err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		elapsed += entry.Duration
+	}
+
+	first := recorded[0]
+	timer.taskPicker.SetSelected(first.Task)
+	timer.sessionStart = first.Start
+	setElapsed(timer, elapsed)
+	timer.notesInput.SetText(first.Notes)
+	writeStatus(timer)
+}
+
 func contains(slice []string, item string) bool {
 	for _, v := range slice {
 		if v == item {
@@ -285,4 +893,4 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"image/color"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver"
+	"fyne.io/fyne/v2/widget"
+)
+
+// errOnTopUnsupported is returned where a window can't be kept on top, such
+// as under Wayland, where only the compositor decides stacking.
+var errOnTopUnsupported = errors.New("keeping a window on top isn't supported here")
+
+// MiniTimer is a small window with the task, the elapsed time and a pause
+// button, kept above other windows so the clock stays in view while working
+// full-screen in another app.
+type MiniTimer struct {
+	window   fyne.Window
+	task     *widget.Label
+	time     *canvas.Text
+	pauseBtn *widget.Button
+}
+
+// showMiniTimer opens the mini timer, or brings it forward if it's open.
+func showMiniTimer(timer *TaskTimer) {
+	if timer.mini != nil {
+		timer.mini.window.RequestFocus()
+		return
+	}
+
+	mini := &MiniTimer{
+		window: fyne.CurrentApp().NewWindow(tr("Mini Timer")),
+		task:   widget.NewLabelWithData(timer.taskTitle),
+		time:   canvas.NewText("", color.White),
+	}
+	mini.task.Alignment = fyne.TextAlignCenter
+	mini.task.Truncation = fyne.TextTruncateEllipsis
+	mini.time.TextSize = 28
+	mini.time.Alignment = fyne.TextAlignCenter
+	mini.pauseBtn = widget.NewButton("", func() {
+		toggleTimer(timer)
+	})
+	timeListener := bindText(mini.time, timer.elapsed)
+	pauseListener := bindToggleButton(mini.pauseBtn, timer.running, timer.sessionOpen)
+
+	mini.window.SetContent(container.NewVBox(
+		mini.task,
+		container.NewStack(
+			canvas.NewRectangle(color.Black),
+			container.NewCenter(mini.time),
+		),
+		mini.pauseBtn,
+	))
+	mini.window.Resize(fyne.NewSize(220, 0))
+	mini.window.SetOnClosed(func() {
+		mini.task.Unbind()
+		timer.elapsed.RemoveListener(timeListener)
+		timer.running.RemoveListener(pauseListener)
+		timer.sessionOpen.RemoveListener(pauseListener)
+		timer.mini = nil
+	})
+	timer.mini = mini
+	mini.window.Show()
+	keepOnTop(mini.window)
+}
+
+// keepOnTop asks the platform to keep a window above all others. Failing
+// that, the window still opens as an ordinary one.
+func keepOnTop(w fyne.Window) {
+	native, ok := w.(driver.NativeWindow)
+	if !ok {
+		return
+	}
+	native.RunNative(func(context any) {
+		if err := setAlwaysOnTop(context); err != nil {
+			log.Printf("mini timer: %v", err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// touchTheme pads the inside of buttons, entries and selects more than the
+// default theme, so on phones and tablets they make comfortable touch
+// targets of about 48dp.
+type touchTheme struct {
+	fyne.Theme
+}
+
+func (t touchTheme) Size(name fyne.ThemeSizeName) float32 {
+	if name == theme.SizeNameInnerPadding {
+		return 1.5 * t.Theme.Size(name)
+	}
+	return t.Theme.Size(name)
+}
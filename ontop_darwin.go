@@ -0,0 +1,27 @@
+//go:build darwin && !ios
+
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#import <AppKit/AppKit.h>
+
+static void setFloating(uintptr_t window) {
+	[(NSWindow *)window setLevel:NSFloatingWindowLevel];
+}
+*/
+import "C"
+
+import "fyne.io/fyne/v2/driver"
+
+// setAlwaysOnTop raises a window to the floating level, where utility
+// panels live above ordinary windows.
+func setAlwaysOnTop(context any) error {
+	mac, ok := context.(driver.MacWindowContext)
+	if !ok || mac.NSWindow == 0 {
+		return errOnTopUnsupported
+	}
+	C.setFloating(C.uintptr_t(mac.NSWindow))
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+// setAlwaysOnTop asks the window manager to keep an X11 window above others,
+// through wmctrl, which has to be installed.
+func setAlwaysOnTop(context any) error {
+	x11, ok := context.(driver.X11WindowContext)
+	if !ok || x11.WindowHandle == 0 {
+		return errOnTopUnsupported
+	}
+	window := fmt.Sprintf("0x%x", x11.WindowHandle)
+	if err := exec.Command("wmctrl", "-i", "-r", window, "-b", "add,above").Run(); err != nil {
+		return fmt.Errorf("wmctrl: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows && (!darwin || ios)
+
+package main
+
+// setAlwaysOnTop has no native implementation on this platform.
+func setAlwaysOnTop(context any) error {
+	return errOnTopUnsupported
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"syscall"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+var setWindowPos = syscall.NewLazyDLL("user32.dll").NewProc("SetWindowPos")
+
+const (
+	hwndTopmost   = ^uintptr(0) // HWND_TOPMOST, (HWND)-1
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+	swpNoActivate = 0x0010
+)
+
+// setAlwaysOnTop moves a window into the topmost band, above all windows
+// that aren't topmost themselves.
+func setAlwaysOnTop(context any) error {
+	win, ok := context.(driver.WindowsWindowContext)
+	if !ok || win.HWND == 0 {
+		return errOnTopUnsupported
+	}
+	// The returned error is always set, so success is read from the result
+	if ok, _, err := setWindowPos.Call(win.HWND, hwndTopmost, 0, 0, 0, 0, swpNoMove|swpNoSize|swpNoActivate); ok == 0 {
+		return err
+	}
+	return nil
+}
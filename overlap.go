@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PrefAllowOverlaps turns off the overlap checks, for people who log work
+// done side by side, e.g. a build running while they write.
+const PrefAllowOverlaps = "allowOverlaps"
+
+func overlapsAllowed() bool {
+	return fyne.CurrentApp().Preferences().Bool(PrefAllowOverlaps)
+}
+
+// overlapping returns the entries other than entry itself that share some
+// of its time. Entries that only meet, one ending as the other starts,
+// don't overlap.
+func overlapping(entries []Entry, entry Entry) []Entry {
+	var found []Entry
+	for _, other := range entries {
+		if other.ID != entry.ID && other.Start.Before(entry.End) && entry.Start.Before(other.End) {
+			found = append(found, other)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Start.Before(found[j].Start)
+	})
+	return found
+}
+
+// findOverlaps maps the ID of each entry that overlaps others to those
+// others.
+func findOverlaps(entries []Entry) map[string][]Entry {
+	sorted := slices.Clone(entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	overlaps := make(map[string][]Entry)
+	// Entries that started earlier and haven't ended by the one at hand
+	var open []Entry
+	for _, entry := range sorted {
+		open = slices.DeleteFunc(open, func(o Entry) bool {
+			return !o.End.After(entry.Start)
+		})
+		for _, o := range open {
+			overlaps[o.ID] = append(overlaps[o.ID], entry)
+			overlaps[entry.ID] = append(overlaps[entry.ID], o)
+		}
+		open = append(open, entry)
+	}
+	return overlaps
+}
+
+// trimAround cuts other back to the time keep doesn't cover. It returns no
+// entries if keep covers all of other and two if other spans keep, the first
+// keeping other's ID.
+func trimAround(other, keep Entry) []Entry {
+	startsBefore := other.Start.Before(keep.Start)
+	endsAfter := other.End.After(keep.End)
+	switch {
+	case startsBefore && endsAfter:
+		first, rest := cutEntry(other, keep.Start)
+		_, second := cutEntry(rest, keep.End)
+		return []Entry{first, second}
+	case startsBefore:
+		first, _ := cutEntry(other, keep.Start)
+		return []Entry{first}
+	case endsAfter:
+		_, second := cutEntry(other, keep.End)
+		second.ID = other.ID
+		second.Adjustment = other.Adjustment
+		return []Entry{second}
+	}
+	return nil
+}
+
+// trimOverlaps cuts the stored entries that overlap keep back to the time
+// around it, deleting those it covers.
+func trimOverlaps(timer *TaskTimer, keep Entry) error {
+	for _, other := range overlapping(allEntries(timer), keep) {
+		parts := trimAround(other, keep)
+		if len(parts) == 0 {
+			if err := deleteEntry(timer, other.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := updateEntry(timer, parts[0]); err != nil {
+			return err
+		}
+		for _, part := range parts[1:] {
+			recorded := recordEntry(timer, part)
+			auditEntry(timer, recorded.ID, AuditSplit, &other, &recorded)
+		}
+	}
+	return nil
+}
+
+// describeOverlaps lists entries one per line, for the overlap warnings.
+func describeOverlaps(entries []Entry) string {
+	var lines []string
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%s – %s  %s",
+			formatDayTime(entry.Start), entry.End.Format("15:04"), entry.Task))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// saveWithoutOverlaps runs save, which adds or changes entry, under label.
+// If entry would overlap others, it first asks whether to trim them around
+// it, save it anyway or go back to the form. The trimming and the save are
+// undone together; saved runs once they're done.
+func saveWithoutOverlaps(timer *TaskTimer, label string, entry Entry, save func() error, saved func()) {
+	run := func(trim bool) {
+		err := changeEntries(timer, label, func() error {
+			if trim {
+				if err := trimOverlaps(timer, entry); err != nil {
+					return err
+				}
+			}
+			return save()
+		})
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		saved()
+	}
+
+	others := overlapping(allEntries(timer), entry)
+	if len(others) == 0 || overlapsAllowed() {
+		run(false)
+		return
+	}
+
+	message := widget.NewLabel(tr("This entry overlaps:") + "\n" + describeOverlaps(others))
+	message.Wrapping = fyne.TextWrapWord
+	var d dialog.Dialog
+	buttons := container.NewHBox(
+		widget.NewButton(tr("Trim the others"), func() {
+			d.Hide()
+			run(true)
+		}),
+		widget.NewButton(tr("Save anyway"), func() {
+			d.Hide()
+			run(false)
+		}),
+		widget.NewButton(tr("Cancel"), func() {
+			d.Hide()
+		}),
+	)
+	d = dialog.NewCustomWithoutButtons(tr("Overlapping Entries"), container.NewVBox(message, buttons), timer.window)
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// paletteCommand is one thing the command palette can do.
+type paletteCommand struct {
+	label string
+	run   func()
+}
+
+// paletteCommands lists what the palette offers: the timer controls, a
+// start for each active task and template, every view and the exports.
+func paletteCommands(timer *TaskTimer) []paletteCommand {
+	command := func(group, label string, run func()) paletteCommand {
+		return paletteCommand{label: group + ": " + label, run: run}
+	}
+	timerGroup := tr("Timer")
+	commands := []paletteCommand{
+		command(timerGroup, tr("Start or Pause"), func() { toggleTimer(timer) }),
+		command(timerGroup, tr("Stop"), func() { stopSession(timer) }),
+		command(timerGroup, tr("Split Session…"), func() { showSplitSessionDialog(timer) }),
+		command(timerGroup, tr("Today's Summary"), func() { showDaySummary(timer) }),
+		command(timerGroup, tr("Weekly Report…"), func() { showWeeklyReportDialog(timer) }),
+		command(timerGroup, tr("Billable vs. Internal…"), func() { showBillableReport(timer) }),
+		command(timerGroup, tr("Project Profitability…"), func() { showProfitabilityReport(timer) }),
+		command(timerGroup, tr("Utilization…"), func() { showUtilizationReport(timer) }),
+		command(timerGroup, tr("Capacity Plan…"), func() { showCapacityPlan(timer) }),
+	}
+	if !fyne.CurrentDevice().IsMobile() {
+		commands = append(commands, command(timerGroup, tr("Mini Timer"), func() { showMiniTimer(timer) }))
+	}
+	editGroup := tr("Edit")
+	commands = append(commands,
+		command(editGroup, tr("Undo"), func() { undoLast(timer) }),
+		command(editGroup, tr("Redo"), func() { redoLast(timer) }),
+	)
+
+	startGroup := tr("Start timer")
+	for _, taskName := range timer.tasks.Active() {
+		commands = append(commands, command(startGroup, taskOption(timer, taskName), func() { startTask(timer, taskName) }))
+	}
+	templateGroup := tr("Template")
+	for _, template := range loadTaskTemplates() {
+		commands = append(commands, command(templateGroup, template.Name, func() {
+			startTemplate(timer, template)
+			showView(timer, "timer")
+		}))
+	}
+
+	goGroup := tr("Go to")
+	for _, item := range navItems() {
+		commands = append(commands, command(goGroup, item.label, func() { openNavItem(timer, item) }))
+	}
+
+	exportGroup := tr("Export")
+	now := time.Now()
+	commands = append(commands,
+		command(exportGroup, tr("Today as CSV…"), func() {
+			saveExport(timer, "gotime-"+now.Format("2006-01-02")+".csv", func(w io.Writer) error {
+				entries := entriesBetween(timer, dayStart(now), addDays(now, 1))
+				return writeEntriesCSV(w, roundEntries(entries), currentExportLocale())
+			})
+		}),
+		command(exportGroup, tr("This week's timesheet as Excel…"), func() {
+			week := weekStart(now)
+			saveExport(timer, "timesheet-"+weekFileKey(week)+".xlsx", func(w io.Writer) error {
+				sheet := newTimesheet(entriesBetween(timer, week, addDays(week, 7)), week, nil)
+				return writeTimesheetXLSX(w, sheet, currentExportLocale())
+			})
+		}),
+		command(exportGroup, tr("All entries as CSV…"), func() {
+			saveExport(timer, "gotime.csv", func(w io.Writer) error {
+				return writeEntriesCSV(w, roundEntries(allEntries(timer)), currentExportLocale())
+			})
+		}),
+		command(exportGroup, tr("All entries to calendar…"), func() {
+			saveExport(timer, "sessions.ics", func(w io.Writer) error {
+				return writeEntriesICS(w, allEntries(timer), currentExportLocale())
+			})
+		}),
+		command(exportGroup, tr("Invoice…"), func() { showCreateInvoiceDialog(timer) }),
+		command(exportGroup, tr("Accounting export…"), func() { showAccountingExportDialog(timer) }),
+	)
+	return commands
+}
+
+// filterPaletteCommands keeps the commands whose label has every word of
+// query in it, ignoring case.
+func filterPaletteCommands(commands []paletteCommand, query string) []paletteCommand {
+	words := strings.Fields(strings.ToLower(query))
+	var matched []paletteCommand
+	for _, command := range commands {
+		label := strings.ToLower(command.label)
+		ok := true
+		for _, word := range words {
+			if !strings.Contains(label, word) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, command)
+		}
+	}
+	return matched
+}
+
+// showCommandPalette opens a search over paletteCommands. Up and Down move
+// through the matches, Enter runs the highlighted one and Escape closes it.
+func showCommandPalette(timer *TaskTimer) {
+	commands := paletteCommands(timer)
+	matched := commands
+	selected := 0
+
+	var popUp *widget.PopUp
+	run := func(command paletteCommand) {
+		popUp.Hide()
+		command.run()
+	}
+
+	list := widget.NewList(
+		func() int { return len(matched) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			label := item.(*widget.Label)
+			label.TextStyle.Bold = id == selected
+			label.SetText(matched[id].label)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		run(matched[id])
+	}
+
+	search := newKeyEntry()
+	search.PlaceHolder = tr("Type a command or task")
+	search.OnChanged = func(query string) {
+		matched = filterPaletteCommands(commands, query)
+		selected = 0
+		list.Refresh()
+		list.ScrollToTop()
+	}
+	search.OnSubmitted = func(string) {
+		if selected < len(matched) {
+			run(matched[selected])
+		}
+	}
+	search.onKey = func(name fyne.KeyName) bool {
+		switch name {
+		case fyne.KeyDown:
+			selected = max(min(selected+1, len(matched)-1), 0)
+		case fyne.KeyUp:
+			selected = max(selected-1, 0)
+		case fyne.KeyEscape:
+			popUp.Hide()
+			return true
+		default:
+			return false
+		}
+		list.Refresh()
+		list.ScrollTo(selected)
+		return true
+	}
+
+	content := container.NewBorder(search, nil, nil, nil, list)
+	popUp = widget.NewModalPopUp(content, timer.window.Canvas())
+	popUp.Resize(fyne.NewSize(480, 360))
+	popUp.Show()
+	timer.window.Canvas().Focus(search)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefFirstWeekday         = "firstWeekday"
+	PrefFiscalYearStartMonth = "fiscalYearStartMonth"
+
+	// PrefISOWeeks numbers weeks the ISO 8601 way, starting on Monday, as
+	// European payroll weeks are
+	PrefISOWeeks = "isoWeeks"
+)
+
+// firstWeekday is the day weeks start on for goals, reviews and reports.
+// ISO weeks always start on Monday.
+func firstWeekday() time.Weekday {
+	if isoWeeks() {
+		return time.Monday
+	}
+	return time.Weekday(fyne.CurrentApp().Preferences().IntWithFallback(PrefFirstWeekday, int(time.Monday)))
+}
+
+func isoWeeks() bool {
+	return fyne.CurrentApp().Preferences().Bool(PrefISOWeeks)
+}
+
+// isoWeekLabel writes the ISO week t falls in, e.g. 2026-W42.
+func isoWeekLabel(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// weekTitle names the week starting at start, by its number with ISO weeks
+// and by its first day otherwise.
+func weekTitle(start time.Time) string {
+	if isoWeeks() {
+		year, week := start.ISOWeek()
+		return tr("Week {{.Number}}, {{.Year}}", map[string]any{"Number": week, "Year": year})
+	}
+	return tr("Week of {{.Week}}", map[string]any{"Week": formatDate(start)})
+}
+
+// weekFileKey names the week starting at start in file names.
+func weekFileKey(start time.Time) string {
+	if isoWeeks() {
+		return isoWeekLabel(start)
+	}
+	return weekKey(start)
+}
+
+// lastWeekday is the day weeks end on.
+func lastWeekday() time.Weekday {
+	return (firstWeekday() + 6) % 7
+}
+
+// fiscalYearStartMonth is the month the company's fiscal year starts in.
+func fiscalYearStartMonth() time.Month {
+	return time.Month(fyne.CurrentApp().Preferences().IntWithFallback(PrefFiscalYearStartMonth, int(time.January)))
+}
+
+// fiscalYearStart returns midnight on the first day of the fiscal year
+// containing t.
+func fiscalYearStart(t time.Time) time.Time {
+	start := fiscalYearStartMonth()
+	year := t.Year()
+	if t.Month() < start {
+		year--
+	}
+	return time.Date(year, start, 1, 0, 0, 0, 0, t.Location())
+}
+
+// fiscalQuarterStart returns midnight on the first day of the fiscal quarter
+// containing t.
+func fiscalQuarterStart(t time.Time) time.Time {
+	yearStart := fiscalYearStart(t)
+	months := (t.Year()-yearStart.Year())*12 + int(t.Month()-yearStart.Month())
+	return yearStart.AddDate(0, months/3*3, 0)
+}
+
+// ReportPeriod is a preset date range for reports, relative to now. Range
+// returns [from, to).
+type ReportPeriod struct {
+	Name  string
+	Range func(now time.Time) (from, to time.Time)
+}
+
+var reportPeriods = []ReportPeriod{
+	{"This week", func(now time.Time) (time.Time, time.Time) {
+		start := weekStart(now)
+		return start, addDays(start, 7)
+	}},
+	{"Last week", func(now time.Time) (time.Time, time.Time) {
+		start := weekStart(now)
+		return addDays(start, -7), start
+	}},
+	{"This month", func(now time.Time) (time.Time, time.Time) {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}},
+	{"Last month", func(now time.Time) (time.Time, time.Time) {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start.AddDate(0, -1, 0), start
+	}},
+	{"This quarter", func(now time.Time) (time.Time, time.Time) {
+		start := fiscalQuarterStart(now)
+		return start, start.AddDate(0, 3, 0)
+	}},
+	{"Last quarter", func(now time.Time) (time.Time, time.Time) {
+		start := fiscalQuarterStart(now)
+		return start.AddDate(0, -3, 0), start
+	}},
+	{"This fiscal year", func(now time.Time) (time.Time, time.Time) {
+		start := fiscalYearStart(now)
+		return start, start.AddDate(1, 0, 0)
+	}},
+	{"Last fiscal year", func(now time.Time) (time.Time, time.Time) {
+		start := fiscalYearStart(now)
+		return start.AddDate(-1, 0, 0), start
+	}},
+}
+
+func reportPeriodNames() []string {
+	names := make([]string, len(reportPeriods))
+	for i, period := range reportPeriods {
+		names[i] = tr(period.Name)
+	}
+	return names
+}
+
+// newReportPeriodSelect offers the period presets and fills in a pair of
+// inclusive YYYY-MM-DD date inputs with the chosen one.
+func newReportPeriodSelect(fromInput, toInput *widget.Entry) *widget.Select {
+	periodSelect := widget.NewSelect(reportPeriodNames(), func(name string) {
+		for _, period := range reportPeriods {
+			if tr(period.Name) == name {
+				from, to := period.Range(time.Now())
+				fromInput.SetText(from.Format("2006-01-02"))
+				toInput.SetText(to.AddDate(0, 0, -1).Format("2006-01-02"))
+			}
+		}
+	})
+	periodSelect.PlaceHolder = tr("Custom")
+	return periodSelect
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PowerEvent reports the machine going to sleep or the screen locking, and
+// the matching wake or unlock.
+type PowerEvent int
+
+const (
+	PowerSuspend PowerEvent = iota
+	PowerResume
+)
+
+// SleepDetectThreshold is the largest wall-clock jump between two ticks that
+// is not treated as the machine having been asleep.
+const SleepDetectThreshold = 30 * time.Second
+
+// handlePowerEvents pauses the timer when the machine sleeps or locks and asks
+// what to do with the time away once it is back.
+func handlePowerEvents(timer *TaskTimer, events <-chan PowerEvent) {
+	for event := range events {
+		now := time.Now()
+		fyne.Do(func() {
+			switch event {
+			case PowerSuspend:
+				suspendForAway(timer, now)
+			case PowerResume:
+				promptAfterAway(timer, now)
+			}
+		})
+	}
+}
+
+// suspendForAway pauses a running timer and remembers when the user left.
+func suspendForAway(timer *TaskTimer, since time.Time) {
+	if !timer.isRunning || !timer.awaySince.IsZero() {
+		return
+	}
+	timer.awaySince = since
+	runHook(timer, EventIdleDetected)
+	pauseTimer(timer)
+}
+
+// promptAfterAway offers to count the time away, resume without it, or keep
+// the timer paused. A session the time away is left out of waits for review
+// once recorded.
+func promptAfterAway(timer *TaskTimer, now time.Time) {
+	since := timer.awaySince
+	if since.IsZero() {
+		return
+	}
+	timer.awaySince = time.Time{}
+	gap := now.Sub(since)
+
+	message := tr("The timer was paused while you were away from {{.From}} to {{.To}} ({{.Duration}}).", map[string]any{
+		"From":     since.Format("15:04"),
+		"To":       now.Format("15:04"),
+		"Duration": formatDuration(gap),
+	})
+
+	var d dialog.Dialog
+	buttons := container.NewHBox(
+		widget.NewButton(tr("Count it"), func() {
+			setElapsed(timer, timer.elapsedTime+gap)
+			resumeTimer(timer)
+			d.Hide()
+		}),
+		widget.NewButton(tr("Discard gap"), func() {
+			timer.review = ReviewIdle
+			resumeTimer(timer)
+			d.Hide()
+		}),
+		widget.NewButton(tr("Keep paused"), func() {
+			timer.review = ReviewIdle
+			d.Hide()
+		}),
+	)
+
+	label := widget.NewLabel(message)
+	label.Wrapping = fyne.TextWrapWord
+	d = dialog.NewCustomWithoutButtons(tr("Welcome Back"), container.NewVBox(label, buttons), timer.window)
+	d.Resize(fyne.NewSize(360, 0))
+	d.Show()
+}
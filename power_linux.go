@@ -0,0 +1,57 @@
+//go:build linux
+
+package main
+
+import (
+	"log"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// watchPowerEvents listens for logind sleep notifications on the system bus
+// and screen saver lock changes on the session bus. Both signals carry a
+// single boolean that is true when the machine is going away.
+func watchPowerEvents(events chan<- PowerEvent) {
+	signals := make(chan *dbus.Signal, 16)
+
+	if conn, err := dbus.ConnectSystemBus(); err != nil {
+		log.Printf("power: system bus unavailable: %v", err)
+	} else {
+		if err := conn.AddMatchSignal(
+			dbus.WithMatchInterface("org.freedesktop.login1.Manager"),
+			dbus.WithMatchMember("PrepareForSleep"),
+		); err != nil {
+			log.Printf("power: watching sleep: %v", err)
+		}
+		conn.Signal(signals)
+	}
+
+	if conn, err := dbus.ConnectSessionBus(); err != nil {
+		log.Printf("power: session bus unavailable: %v", err)
+	} else {
+		for _, iface := range []string{"org.freedesktop.ScreenSaver", "org.gnome.ScreenSaver"} {
+			if err := conn.AddMatchSignal(
+				dbus.WithMatchInterface(iface),
+				dbus.WithMatchMember("ActiveChanged"),
+			); err != nil {
+				log.Printf("power: watching %s: %v", iface, err)
+			}
+		}
+		conn.Signal(signals)
+	}
+
+	for signal := range signals {
+		if len(signal.Body) == 0 {
+			continue
+		}
+		away, ok := signal.Body[0].(bool)
+		if !ok {
+			continue
+		}
+		if away {
+			events <- PowerSuspend
+		} else {
+			events <- PowerResume
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package main
+
+// watchPowerEvents has no native source on this platform; sleep is still
+// caught by the wall-clock check in startTimer.
+func watchPowerEvents(events chan<- PowerEvent) {}
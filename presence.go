@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefTeamServerURL = "teamServerURL"
+	// PrefTeamToken held the token before it moved to the keychain
+	PrefTeamToken         = "teamToken"
+	PrefTeamUserName      = "teamUserName"
+	PrefTeamSharePresence = "teamSharePresence"
+
+	// TeamReconnectDelay is how long to wait before reopening a dropped
+	// event stream, or checking again whether a server has been configured
+	TeamReconnectDelay = 30 * time.Second
+)
+
+// Teammate is one workspace member's presence. An empty Task means they
+// aren't timing anything.
+type Teammate struct {
+	User  string    `json:"user"`
+	Task  string    `json:"task"`
+	Since time.Time `json:"since"`
+}
+
+// streamClient has no overall timeout because the event stream stays open.
+var streamClient = &http.Client{}
+
+// TeamPresence shares the running task with a team workspace and follows
+// what teammates are timing through the server's event stream. Sharing is
+// opt-in; following only needs the server to be configured.
+type TeamPresence struct {
+	mu       sync.Mutex
+	members  map[string]Teammate
+	desired  *Teammate
+	wake     chan struct{}
+	onChange func()
+}
+
+func NewTeamPresence() *TeamPresence {
+	p := &TeamPresence{
+		members: make(map[string]Teammate),
+		wake:    make(chan struct{}, 1),
+	}
+	go p.publishLoop()
+	return p
+}
+
+func teamServerURL() string {
+	return strings.TrimRight(fyne.CurrentApp().Preferences().String(PrefTeamServerURL), "/")
+}
+
+// Working shares that the user started a task, if they opted in.
+func (p *TeamPresence) Working(taskName string) {
+	p.publish(taskName)
+}
+
+// Idle shares that the user stopped timing, if they opted in.
+func (p *TeamPresence) Idle() {
+	p.publish("")
+}
+
+func (p *TeamPresence) publish(taskName string) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefTeamSharePresence) || teamServerURL() == "" {
+		return
+	}
+
+	p.mu.Lock()
+	p.desired = &Teammate{User: prefs.String(PrefTeamUserName), Task: taskName, Since: time.Now()}
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// publishLoop sends only the latest presence, so quick start/pause toggles
+// collapse into a single request.
+func (p *TeamPresence) publishLoop() {
+	for range p.wake {
+		p.mu.Lock()
+		presence := p.desired
+		p.desired = nil
+		p.mu.Unlock()
+		if presence == nil {
+			continue
+		}
+
+		if err := postPresence(presence); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+func postPresence(presence *Teammate) error {
+	body, err := json.Marshal(presence)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, teamServerURL()+"/presence", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+keychainSecret(KeyringTeamToken))
+
+	resp, err := teamHTTPClient(false).Do(req)
+	if err != nil {
+		return fmt.Errorf("team: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("team: publishing presence: %s", resp.Status)
+	}
+	return nil
+}
+
+// Run follows the workspace event stream until the app exits, reconnecting
+// whenever it drops.
+func (p *TeamPresence) Run() {
+	for {
+		if teamServerURL() != "" {
+			if err := p.follow(); err != nil {
+				log.Print(err)
+			}
+		}
+		time.Sleep(TeamReconnectDelay)
+	}
+}
+
+// follow reads presence events from the server-sent event stream. Each
+// event's data is a Teammate.
+func (p *TeamPresence) follow() error {
+	req, err := http.NewRequest(http.MethodGet, teamServerURL()+"/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Authorization", "Bearer "+keychainSecret(KeyringTeamToken))
+
+	resp, err := teamHTTPClient(true).Do(req)
+	if err != nil {
+		return fmt.Errorf("team: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("team: opening event stream: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		var teammate Teammate
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &teammate); err != nil {
+			log.Printf("team: bad presence event: %v", err)
+			continue
+		}
+		p.update(teammate)
+	}
+	return scanner.Err()
+}
+
+func (p *TeamPresence) update(teammate Teammate) {
+	// Our own presence comes back on the stream too
+	if teammate.User == "" || teammate.User == fyne.CurrentApp().Preferences().String(PrefTeamUserName) {
+		return
+	}
+
+	p.mu.Lock()
+	p.members[teammate.User] = teammate
+	onChange := p.onChange
+	p.mu.Unlock()
+
+	if onChange != nil {
+		fyne.Do(onChange)
+	}
+}
+
+// OnChange sets the function called on the main goroutine whenever a
+// teammate's presence changes.
+func (p *TeamPresence) OnChange(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onChange = fn
+}
+
+// Teammates returns everyone seen on the stream, sorted by name.
+func (p *TeamPresence) Teammates() []Teammate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	teammates := make([]Teammate, 0, len(p.members))
+	for _, teammate := range p.members {
+		teammates = append(teammates, teammate)
+	}
+	sort.Slice(teammates, func(i, j int) bool {
+		return teammates[i].User < teammates[j].User
+	})
+	return teammates
+}
+
+// createTeamPresenceContainer lists what each teammate is timing. It stays
+// empty until a teammate shows up on the stream.
+func createTeamPresenceContainer(timer *TaskTimer) *fyne.Container {
+	box := container.NewVBox()
+
+	render := func() {
+		box.RemoveAll()
+		teammates := timer.presence.Teammates()
+		if len(teammates) == 0 {
+			return
+		}
+
+		box.Add(widget.NewLabelWithStyle(tr("Team"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, teammate := range teammates {
+			status := tr("Idle")
+			if teammate.Task != "" {
+				status = tr("{{.Task}} (since {{.Time}})", map[string]any{
+					"Task": teammate.Task,
+					"Time": teammate.Since.Local().Format("15:04"),
+				})
+			}
+			box.Add(widget.NewLabel(teammate.User + ": " + status))
+		}
+	}
+
+	timer.presence.OnChange(render)
+	render()
+
+	return box
+}
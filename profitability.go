@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefProjectPrices = "projectPrices"
+	PrefTargetRate    = "targetRate"
+)
+
+// ProjectPrice is a fixed price agreed for a whole project, in Currency.
+type ProjectPrice struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func projectPrices() map[string]ProjectPrice {
+	prices := make(map[string]ProjectPrice)
+	raw := fyne.CurrentApp().Preferences().String(PrefProjectPrices)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &prices); err != nil {
+			log.Printf("profitability: reading project prices: %v", err)
+		}
+	}
+	return prices
+}
+
+func setProjectPrices(prices map[string]ProjectPrice) {
+	raw, err := json.Marshal(prices)
+	if err != nil {
+		log.Printf("profitability: saving project prices: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefProjectPrices, string(raw))
+}
+
+// projectProfit is a project's row in the profitability report, with money
+// in the home currency. Missing is set if some of the revenue couldn't be
+// converted for want of an exchange rate and is left out.
+type projectProfit struct {
+	Project  string
+	Hours    float64
+	Billable float64
+	Revenue  float64
+	Fixed    bool
+	Missing  bool
+}
+
+// EffectiveRate is what each hour tracked on the project earned, billable
+// or not.
+func (p projectProfit) EffectiveRate() float64 {
+	if p.Hours <= 0 {
+		return 0
+	}
+	return p.Revenue / p.Hours
+}
+
+// projectProfits works out each project's revenue from the entries of a
+// period. Hourly work earns its client's rate for the billable hours. A
+// fixed price is spread over all the hours ever tracked on the project, from
+// all, so a period gets the share its hours make up. Entries waiting for
+// review count in neither. Projects are sorted by effective rate, best first.
+func projectProfits(entries, all []Entry, clients map[string]ClientSettings, prices map[string]ProjectPrice, home string, rates ExchangeRates) []projectProfit {
+	byProject := make(map[string]*projectProfit)
+	for _, entry := range roundEntries(reviewedEntries(entries)) {
+		p, ok := byProject[entry.Project]
+		if !ok {
+			p = &projectProfit{Project: entry.Project}
+			byProject[entry.Project] = p
+		}
+		hours := entry.Duration.Hours()
+		p.Hours += hours
+		if entry.NonBillable {
+			continue
+		}
+		p.Billable += hours
+		if _, fixed := prices[entry.Project]; fixed && entry.Project != "" {
+			continue
+		}
+		settings := clients[entry.Client]
+		amount, ok := rates.Convert(hours*settings.HourlyRate, clientCurrency(settings), home)
+		if !ok {
+			p.Missing = true
+			continue
+		}
+		p.Revenue += amount
+	}
+
+	lifetime := make(map[string]float64)
+	for _, entry := range roundEntries(reviewedEntries(all)) {
+		lifetime[entry.Project] += entry.Duration.Hours()
+	}
+
+	var profits []projectProfit
+	for project, p := range byProject {
+		if price, fixed := prices[project]; fixed && project != "" {
+			p.Fixed = true
+			amount, ok := rates.Convert(price.Amount, price.Currency, home)
+			switch {
+			case !ok:
+				p.Missing = true
+			case lifetime[project] > 0:
+				p.Revenue = amount * p.Hours / lifetime[project]
+			}
+		}
+		profits = append(profits, *p)
+	}
+	sort.Slice(profits, func(i, j int) bool {
+		if profits[i].EffectiveRate() != profits[j].EffectiveRate() {
+			return profits[i].EffectiveRate() > profits[j].EffectiveRate()
+		}
+		return profits[i].Project < profits[j].Project
+	})
+	return profits
+}
+
+// targetRate is the hourly rate projects are measured against, in the home
+// currency, or zero if none is set.
+func targetRate() float64 {
+	return fyne.CurrentApp().Preferences().Float(PrefTargetRate)
+}
+
+// formatTargetDifference says how far an effective rate is above or below
+// the target, as a percentage.
+func formatTargetDifference(rate, target float64) string {
+	if target <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%+.0f%%", (rate-target)/target*100)
+}
+
+// showProfitabilityReport shows each project's hours, revenue and effective
+// hourly rate over a period, against a target rate.
+func showProfitabilityReport(timer *TaskTimer) {
+	if blockedInGuestMode(timer, tr("Project Profitability")) {
+		return
+	}
+
+	fromInput := widget.NewEntry()
+	fromInput.PlaceHolder = "YYYY-MM-DD"
+	toInput := widget.NewEntry()
+	toInput.PlaceHolder = "YYYY-MM-DD"
+	periodSelect := newReportPeriodSelect(fromInput, toInput)
+	targetInput := widget.NewEntry()
+	targetInput.PlaceHolder = tr("per hour")
+	if target := targetRate(); target > 0 {
+		targetInput.SetText(strconv.FormatFloat(target, 'f', -1, 64))
+	}
+	rows := container.NewVBox()
+	var profits []projectProfit
+
+	var render func()
+	render = func() {
+		rows.RemoveAll()
+		profits = nil
+		from, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		if err != nil {
+			return
+		}
+		home := homeCurrency()
+		target := targetRate()
+		prices := projectPrices()
+		all := allEntries(timer)
+		profits = projectProfits(entriesBetween(timer, from, to.AddDate(0, 0, 1)), all, clientSettings(), prices, home, exchangeRates())
+		if len(profits) == 0 {
+			rows.Add(widget.NewLabel(tr("Nothing tracked in this period")))
+			return
+		}
+
+		bold := fyne.TextStyle{Bold: true}
+		rows.Add(container.NewGridWithColumns(6,
+			widget.NewLabelWithStyle(tr("Project"), fyne.TextAlignLeading, bold),
+			widget.NewLabelWithStyle(tr("Hours"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("Revenue"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("Per hour"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("vs. target"), fyne.TextAlignTrailing, bold),
+			widget.NewLabel(""),
+		))
+		var hours, revenue float64
+		missing := false
+		for _, p := range profits {
+			hours += p.Hours
+			revenue += p.Revenue
+			missing = missing || p.Missing
+
+			name := p.Project
+			if name == "" {
+				name = tr("No project")
+			}
+			if p.Fixed {
+				name += " · " + tr("fixed price")
+			}
+			nameLabel := widget.NewLabel(name)
+			nameLabel.Truncation = fyne.TextTruncateEllipsis
+			revenueText := formatMoney(p.Revenue, home)
+			if p.Missing {
+				revenueText += " *"
+			}
+
+			var priceBtn fyne.CanvasObject = widget.NewLabel("")
+			if p.Project != "" {
+				project := p.Project
+				priceBtn = widget.NewButton(tr("Price…"), func() {
+					showProjectPriceDialog(timer, project, render)
+				})
+			}
+			rows.Add(container.NewGridWithColumns(6,
+				nameLabel,
+				widget.NewLabelWithStyle(strconv.FormatFloat(p.Hours, 'f', 1, 64), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(revenueText, fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatMoney(p.EffectiveRate(), home), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatTargetDifference(p.EffectiveRate(), target), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				priceBtn,
+			))
+		}
+
+		overall := projectProfit{Hours: hours, Revenue: revenue}
+		rows.Add(widget.NewSeparator())
+		rows.Add(container.NewGridWithColumns(6,
+			widget.NewLabelWithStyle(tr("Total"), fyne.TextAlignLeading, bold),
+			widget.NewLabelWithStyle(strconv.FormatFloat(hours, 'f', 1, 64), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(formatMoney(revenue, home), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(formatMoney(overall.EffectiveRate(), home), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(formatTargetDifference(overall.EffectiveRate(), target), fyne.TextAlignTrailing, bold),
+			widget.NewLabel(""),
+		))
+		if missing {
+			rows.Add(widget.NewLabel(tr("* Some revenue is left out for want of an exchange rate.")))
+		}
+	}
+
+	fromInput.OnChanged = func(string) { render() }
+	toInput.OnChanged = func(string) { render() }
+	targetInput.OnChanged = func(text string) {
+		target, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(text), ",", ".", 1), 64)
+		if err != nil || target < 0 {
+			target = 0
+		}
+		fyne.CurrentApp().Preferences().SetFloat(PrefTargetRate, target)
+		render()
+	}
+	periodSelect.SetSelectedIndex(2)
+
+	exportBtn := widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+		saveExport(timer, "profitability.csv", func(w io.Writer) error {
+			return writeProfitabilityCSV(w, profits, homeCurrency(), currentExportLocale())
+		})
+	})
+
+	top := widget.NewForm(
+		widget.NewFormItem(tr("Period"), periodSelect),
+		widget.NewFormItem(tr("From"), fromInput),
+		widget.NewFormItem(tr("To"), toInput),
+		widget.NewFormItem(tr("Target rate"), targetInput),
+	)
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(680, 320))
+	content := container.NewBorder(top, exportBtn, nil, nil, scroll)
+	dialog.NewCustom(tr("Project Profitability"), tr("Close"), content, timer.window).Show()
+}
+
+// showProjectPriceDialog sets or clears a project's fixed price. saved is
+// called after it's stored.
+func showProjectPriceDialog(timer *TaskTimer, project string, saved func()) {
+	prices := projectPrices()
+	price, fixed := prices[project]
+
+	amountInput := widget.NewEntry()
+	amountInput.PlaceHolder = tr("none, billed by the hour")
+	if fixed {
+		amountInput.SetText(strconv.FormatFloat(price.Amount, 'f', -1, 64))
+	}
+	currencyInput := widget.NewEntry()
+	currencyInput.SetText(price.Currency)
+	currencyInput.PlaceHolder = homeCurrency()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Fixed price"), amountInput),
+		widget.NewFormItem(tr("Currency"), currencyInput),
+	}
+	d := dialog.NewForm(project, tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		amount, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(amountInput.Text), ",", ".", 1), 64)
+		if err != nil || amount <= 0 {
+			delete(prices, project)
+		} else {
+			currency := normalizeCurrency(currencyInput.Text)
+			if currency == "" {
+				currency = homeCurrency()
+			}
+			prices[project] = ProjectPrice{Amount: amount, Currency: currency}
+		}
+		setProjectPrices(prices)
+		saved()
+	}, timer.window)
+	d.Resize(fyne.NewSize(340, 0))
+	d.Show()
+}
+
+// writeProfitabilityCSV writes the report with money in the home currency,
+// formatted for the export locale.
+func writeProfitabilityCSV(w io.Writer, profits []projectProfit, home string, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+	if err := out.Write([]string{"project", "pricing", "hours", "billable_hours", "revenue", "per_hour", "currency"}); err != nil {
+		return err
+	}
+	for _, p := range profits {
+		pricing := "hourly"
+		if p.Fixed {
+			pricing = "fixed"
+		}
+		if err := out.Write([]string{
+			p.Project,
+			pricing,
+			locale.FormatDecimal(p.Hours),
+			locale.FormatDecimal(p.Billable),
+			locale.FormatDecimal(p.Revenue),
+			locale.FormatDecimal(p.EffectiveRate()),
+			home,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
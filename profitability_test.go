@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestProjectProfits(t *testing.T) {
+	test.NewTempApp(t)
+	clients := map[string]ClientSettings{
+		"Acme":   {HourlyRate: 100, Currency: "EUR"},
+		"Globex": {HourlyRate: 120, Currency: "USD"},
+		"Hooli":  {HourlyRate: 90, Currency: "GBP"},
+	}
+	prices := map[string]ProjectPrice{
+		"Website": {Amount: 6000, Currency: "EUR"},
+		"Audit":   {Amount: 1000, Currency: "CHF"},
+	}
+	rates := ExchangeRates{Base: "EUR", Rates: map[string]float64{"USD": 1.2}}
+	entry := func(project, client string, hours float64, billable bool) Entry {
+		return Entry{
+			Project:     project,
+			Client:      client,
+			Duration:    time.Duration(hours * float64(time.Hour)),
+			NonBillable: !billable,
+		}
+	}
+	// Stopped automatically and not yet reviewed
+	unreviewed := entry("Website", "Acme", 8, true)
+	unreviewed.AutoStopped = true
+	period := []Entry{
+		// Hourly, with an hour of internal time diluting the rate
+		entry("Support", "Acme", 3, true),
+		entry("Support", "Acme", 1, false),
+		// Hourly in dollars, converted
+		entry("API", "Globex", 2, true),
+		// 10 of the 40 hours tracked on a fixed price of 6000
+		entry("Website", "Acme", 10, true),
+		// No rate to convert pounds with
+		entry("Reports", "Hooli", 2, true),
+		// A fixed price in francs, which can't be converted either
+		entry("Audit", "Acme", 5, true),
+		unreviewed,
+	}
+	all := slices.Concat(period, []Entry{
+		entry("Website", "Acme", 30, true),
+		entry("Audit", "Acme", 5, true),
+	})
+
+	tests := map[string]struct {
+		hours, billable, revenue, rate float64
+		fixed, missing                 bool
+	}{
+		"Website": {hours: 10, billable: 10, revenue: 1500, rate: 150, fixed: true},
+		"API":     {hours: 2, billable: 2, revenue: 200, rate: 100},
+		"Support": {hours: 4, billable: 3, revenue: 300, rate: 75},
+		"Reports": {hours: 2, billable: 2, missing: true},
+		"Audit":   {hours: 5, billable: 5, fixed: true, missing: true},
+	}
+	profits := projectProfits(period, all, clients, prices, "EUR", rates)
+	if len(profits) != len(tests) {
+		t.Fatalf("got %d projects, want %d: %+v", len(profits), len(tests), profits)
+	}
+	for _, p := range profits {
+		want, ok := tests[p.Project]
+		if !ok {
+			t.Errorf("unexpected project %q", p.Project)
+			continue
+		}
+		if p.Hours != want.hours || p.Billable != want.billable || p.Fixed != want.fixed || p.Missing != want.missing {
+			t.Errorf("%s: %+v, want %+v", p.Project, p, want)
+		}
+		if math.Abs(p.Revenue-want.revenue) > 1e-9 || math.Abs(p.EffectiveRate()-want.rate) > 1e-9 {
+			t.Errorf("%s: revenue %v at %v an hour, want %v at %v", p.Project, p.Revenue, p.EffectiveRate(), want.revenue, want.rate)
+		}
+	}
+	// Best rate first, ties by name
+	for i, project := range []string{"Website", "API", "Support", "Audit", "Reports"} {
+		if profits[i].Project != project {
+			t.Errorf("profit %d is %s, want %s", i, profits[i].Project, project)
+		}
+	}
+}
+
+func TestFormatTargetDifference(t *testing.T) {
+	tests := []struct {
+		rate, target float64
+		want         string
+	}{
+		{150, 100, "+50%"},
+		{75, 100, "-25%"},
+		{100, 100, "+0%"},
+		{80, 0, "—"},
+	}
+	for _, tc := range tests {
+		if got := formatTargetDifference(tc.rate, tc.target); got != tc.want {
+			t.Errorf("formatTargetDifference(%v, %v) = %q, want %q", tc.rate, tc.target, got, tc.want)
+		}
+	}
+}
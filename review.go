@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefLastWeeklyReview holds the week key of the last week that was planned
+	PrefLastWeeklyReview = "lastWeeklyReview"
+
+	// ReviewGapThreshold is the shortest untracked stretch reported as a gap
+	ReviewGapThreshold = 30 * time.Minute
+)
+
+// reviewWeeks returns the week to review and the week to plan. On the last
+// day of the week the week that is ending is reviewed; on other days it's the
+// previous week.
+func reviewWeeks(now time.Time) (reviewed, planned time.Time) {
+	planned = weekStart(now)
+	if now.Weekday() == lastWeekday() {
+		planned = addDays(planned, 7)
+	}
+	return addDays(planned, -7), planned
+}
+
+// maybePromptWeeklyReview offers the review on the last and first days of the
+// week if the coming week hasn't been planned yet.
+func maybePromptWeeklyReview(timer *TaskTimer) {
+	now := time.Now()
+	if now.Weekday() != lastWeekday() && now.Weekday() != firstWeekday() {
+		return
+	}
+	_, planned := reviewWeeks(now)
+	if fyne.CurrentApp().Preferences().String(PrefLastWeeklyReview) == weekKey(planned) {
+		return
+	}
+
+	dialog.ShowConfirm(tr("Weekly Review"), tr("Review last week and set goals for the week ahead?"), func(ok bool) {
+		if ok {
+			showWeeklyReview(timer)
+		}
+	}, timer.window)
+}
+
+// trackingGap is an untracked stretch between two sessions on the same day.
+type trackingGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// findTrackingGaps reports untracked stretches of at least min between
+// consecutive sessions that fall on the same day.
+func findTrackingGaps(entries []Entry, min time.Duration) []trackingGap {
+	sorted := append([]Entry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var gaps []trackingGap
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1], sorted[i]
+		if prev.End.YearDay() != next.Start.YearDay() || prev.End.Year() != next.Start.Year() {
+			continue
+		}
+		if next.Start.Sub(prev.End) >= min {
+			gaps = append(gaps, trackingGap{Start: prev.End, End: next.Start})
+		}
+	}
+	return gaps
+}
+
+// sortedTaskNames orders tasks by total duration, longest first.
+func sortedTaskNames(totals map[string]time.Duration) []string {
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if totals[names[i]] != totals[names[j]] {
+			return totals[names[i]] > totals[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// showWeeklyReview walks through last week's totals, tracking gaps and goal
+// performance, and finishes by setting goals for the week ahead.
+func showWeeklyReview(timer *TaskTimer) {
+	if !timer.loaded {
+		dialog.ShowInformation(tr("Weekly Review"), tr("Your history is still loading."), timer.window)
+		return
+	}
+	reviewed, planned := reviewWeeks(time.Now())
+	entries := entriesBetween(timer, reviewed, planned)
+	totals := totalsBetween(timer, reviewed, planned)
+	pastGoals := weeklyGoals(reviewed)
+
+	goalInputs := make(map[string]*widget.Entry)
+	steps := []fyne.CanvasObject{
+		reviewTotalsStep(reviewed, totals),
+		reviewGapsStep(entries),
+		reviewGoalsStep(reviewed, pastGoals, totals),
+		reviewPlanStep(timer, planned, pastGoals, totals, goalInputs),
+	}
+
+	content := container.NewStack()
+	backBtn := widget.NewButton(tr("Back"), nil)
+	nextBtn := widget.NewButton(tr("Next"), nil)
+	closeBtn := widget.NewButton(tr("Close"), nil)
+
+	var d dialog.Dialog
+	step := 0
+	showStep := func() {
+		content.Objects = []fyne.CanvasObject{container.NewVScroll(steps[step])}
+		content.Refresh()
+		if step == 0 {
+			backBtn.Disable()
+		} else {
+			backBtn.Enable()
+		}
+		if step == len(steps)-1 {
+			nextBtn.SetText(tr("Finish"))
+		} else {
+			nextBtn.SetText(tr("Next"))
+		}
+	}
+
+	backBtn.OnTapped = func() {
+		step--
+		showStep()
+	}
+	nextBtn.OnTapped = func() {
+		if step < len(steps)-1 {
+			step++
+			showStep()
+			return
+		}
+
+		goals := WeeklyGoals{}
+		for taskName, input := range goalInputs {
+			// Accept a decimal comma as well as a point
+			hours, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(input.Text), ",", ".", 1), 64)
+			if err == nil && hours > 0 {
+				goals[taskName] = time.Duration(hours * float64(time.Hour))
+			}
+		}
+		setWeeklyGoals(planned, goals)
+		fyne.CurrentApp().Preferences().SetString(PrefLastWeeklyReview, weekKey(planned))
+		d.Hide()
+	}
+	closeBtn.OnTapped = func() {
+		d.Hide()
+	}
+
+	layout := container.NewBorder(nil, container.NewHBox(closeBtn, backBtn, nextBtn), nil, nil, content)
+	d = dialog.NewCustomWithoutButtons(tr("Weekly Review"), layout, timer.window)
+	d.Resize(fyne.NewSize(380, 520))
+	showStep()
+	d.Show()
+}
+
+func reviewTotalsStep(reviewed time.Time, totals map[string]time.Duration) fyne.CanvasObject {
+	var total time.Duration
+	for _, duration := range totals {
+		total += duration
+	}
+
+	box := container.NewVBox(
+		widget.NewLabelWithStyle(tr("1. Last week's totals"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(fmt.Sprintf("%s: %s", weekTitle(reviewed), formatDuration(total))),
+	)
+	if len(totals) == 0 {
+		box.Add(widget.NewLabel(tr("Nothing was tracked.")))
+	}
+	for _, taskName := range sortedTaskNames(totals) {
+		box.Add(widget.NewLabel(fmt.Sprintf("%s: %s", taskName, formatDuration(totals[taskName]))))
+	}
+	return box
+}
+
+func reviewGapsStep(entries []Entry) fyne.CanvasObject {
+	box := container.NewVBox(
+		widget.NewLabelWithStyle(tr("2. Untracked gaps"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(tr("Breaks of {{.Gap}} or more between sessions:", map[string]any{"Gap": formatShortDuration(ReviewGapThreshold)})),
+	)
+
+	gaps := findTrackingGaps(entries, ReviewGapThreshold)
+	if len(gaps) == 0 {
+		box.Add(widget.NewLabel(tr("No gaps found.")))
+	}
+	for _, gap := range gaps {
+		box.Add(widget.NewLabel(fmt.Sprintf("%s – %s (%s)",
+			formatWeekdayTime(gap.Start),
+			gap.End.Format("15:04"),
+			formatDuration(gap.End.Sub(gap.Start)),
+		)))
+	}
+	return box
+}
+
+// reviewGoalsStep compares the week's totals with its goals, scaled down
+// for the working days taken off.
+func reviewGoalsStep(reviewed time.Time, goals WeeklyGoals, totals map[string]time.Duration) fyne.CanvasObject {
+	box := container.NewVBox(
+		widget.NewLabelWithStyle(tr("3. Goal performance"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	if len(goals) == 0 {
+		box.Add(widget.NewLabel(tr("No goals were set for this week.")))
+	}
+
+	off := loadTimeOff()
+	for i, taskName := range sortedTaskNames(goals) {
+		goal, daysOff := weekGoal(goals[taskName], reviewed, off)
+		if i == 0 && daysOff > 0 {
+			box.Add(widget.NewLabel(tr("Goals are scaled down for {{.Days}} working days off.", map[string]any{"Days": daysOff})))
+		}
+		actual := totals[taskName]
+		progress := widget.NewProgressBar()
+		progress.SetValue(1)
+		if goal > 0 {
+			progress.SetValue(min(float64(actual)/float64(goal), 1))
+		}
+		box.Add(widget.NewLabel(tr("{{.Task}}: {{.Actual}} of {{.Goal}}", map[string]any{
+			"Task":   taskName,
+			"Actual": formatDuration(actual),
+			"Goal":   formatDuration(goal),
+		})))
+		box.Add(progress)
+	}
+	return box
+}
+
+// reviewPlanStep asks for next week's goal per task in hours, prefilled with
+// any goals already set, then last week's goals.
+func reviewPlanStep(timer *TaskTimer, planned time.Time, pastGoals WeeklyGoals, totals map[string]time.Duration, inputs map[string]*widget.Entry) fyne.CanvasObject {
+	goals := weeklyGoals(planned)
+	if len(goals) == 0 {
+		goals = pastGoals
+	}
+
+	tasks := timer.tasks.Active()
+	for taskName := range totals {
+		if !contains(tasks, taskName) {
+			tasks = append(tasks, taskName)
+		}
+	}
+
+	form := widget.NewForm()
+	for _, taskName := range tasks {
+		input := widget.NewEntry()
+		input.PlaceHolder = tr("hours")
+		if goal, ok := goals[taskName]; ok {
+			input.SetText(strconv.FormatFloat(goal.Hours(), 'f', -1, 64))
+		}
+		inputs[taskName] = input
+		form.Append(taskName, input)
+	}
+
+	box := container.NewVBox(
+		widget.NewLabelWithStyle(tr("4. Goals for the week of {{.Week}}", map[string]any{"Week": formatDay(planned)}), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		form,
+	)
+	if len(tasks) == 0 {
+		box.Add(widget.NewLabel(tr("Add a task to set goals.")))
+	}
+	return box
+}
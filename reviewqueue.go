@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Why an entry waits for review, other than having been stopped
+// automatically, which AutoStopped records.
+const (
+	ReviewIdle       = "idle"
+	ReviewImported   = "imported"
+	ReviewUnanswered = "unanswered"
+)
+
+// needsReview reports whether an entry was recorded without the user
+// watching and hasn't been approved or edited since. Such entries are left
+// off invoices and reports.
+func needsReview(entry Entry) bool {
+	return entry.AutoStopped || entry.Review != ""
+}
+
+// approveEntry clears what flagged an entry for review.
+func approveEntry(entry Entry) Entry {
+	entry.AutoStopped = false
+	entry.Review = ""
+	return entry
+}
+
+// reviewReason says why an entry waits for review, for its flag in History.
+func reviewReason(entry Entry) string {
+	switch {
+	case entry.AutoStopped:
+		return tr("⚠ Stopped automatically; check the times.")
+	case entry.Review == ReviewIdle:
+		return tr("⚠ Time away was left out; check the times.")
+	case entry.Review == ReviewImported:
+		return tr("⚠ Imported; check the task and times.")
+	case entry.Review == ReviewUnanswered:
+		return tr("⚠ Tracked after nobody answered whether you were still working; check the times.")
+	}
+	return tr("⚠ Needs review.")
+}
+
+// reviewedEntries drops the entries waiting for review.
+func reviewedEntries(entries []Entry) []Entry {
+	var reviewed []Entry
+	for _, entry := range entries {
+		if !needsReview(entry) {
+			reviewed = append(reviewed, entry)
+		}
+	}
+	return reviewed
+}
+
+func unreviewedEntries(entries []Entry) []Entry {
+	var unreviewed []Entry
+	for _, entry := range entries {
+		if needsReview(entry) {
+			unreviewed = append(unreviewed, entry)
+		}
+	}
+	return unreviewed
+}
+
+// createReviewContainer lists the entries waiting for review, oldest first,
+// each to be approved or edited as in History, or all approved at once.
+func createReviewContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	approveAllBtn := widget.NewButtonWithIcon(tr("Approve All"), theme.ConfirmIcon(), nil)
+
+	var render func()
+	render = func() {
+		all := allEntries(timer)
+		entries := unreviewedEntries(all)
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Start.Before(entries[j].Start)
+		})
+		var overlaps map[string][]Entry
+		if !overlapsAllowed() {
+			overlaps = findOverlaps(all)
+		}
+
+		list.RemoveAll()
+		if len(entries) == 0 {
+			list.Add(widget.NewLabel(tr("Nothing to review")))
+			approveAllBtn.Disable()
+			return
+		}
+		for _, entry := range entries {
+			list.Add(newHistoryRow(timer, entry, overlaps[entry.ID], nil, render))
+		}
+		approveAllBtn.Enable()
+	}
+	approveAllBtn.OnTapped = func() {
+		entries := unreviewedEntries(allEntries(timer))
+		dialog.ShowConfirm(tr("Approve All"), tr("Approve {{.Count}} entries as they are?", map[string]any{"Count": len(entries)}), func(ok bool) {
+			if !ok {
+				return
+			}
+			err := changeEntries(timer, tr("Entries approved"), func() error {
+				for _, entry := range entries {
+					if err := updateEntry(timer, approveEntry(entry)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+			}
+			render()
+		}, timer.window)
+	}
+	render()
+
+	intro := widget.NewLabel(tr("Entries stopped automatically, left short by time away or imported wait here, and off invoices and the weekly report, until approved or edited."))
+	intro.Wrapping = fyne.TextWrapWord
+	return container.NewVBox(intro, list, container.NewHBox(approveAllBtn))
+}
+
+// watchReviewQueue keeps the sidebar button for the review queue showing
+// how many entries wait in it.
+func watchReviewQueue(timer *TaskTimer, button *widget.Button, label string) {
+	timer.history.AddListener(binding.NewDataListener(func() {
+		entries, _ := timer.history.Get()
+		if count := len(unreviewedEntries(entries)); count > 0 {
+			button.SetText(fmt.Sprintf("%s (%d)", label, count))
+			button.Importance = widget.WarningImportance
+		} else {
+			button.SetText(label)
+			button.Importance = widget.MediumImportance
+		}
+		button.Refresh()
+	}))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefRoundingMinutes = "roundingMinutes"
+	PrefRoundingMode    = "roundingMode"
+
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// RoundingSteps are the minutes durations can be rounded to; 0 is off.
+var RoundingSteps = []int{0, 5, 6, 15}
+
+var RoundingModes = []string{RoundNearest, RoundUp, RoundDown}
+
+// Rounding rounds each entry's duration in exports and invoices to a
+// multiple of Step. The store always keeps the tracked duration.
+type Rounding struct {
+	Step time.Duration
+	Mode string
+}
+
+// currentRounding returns the rounding chosen in settings.
+func currentRounding() Rounding {
+	prefs := fyne.CurrentApp().Preferences()
+	return Rounding{
+		Step: time.Duration(prefs.Int(PrefRoundingMinutes)) * time.Minute,
+		Mode: prefs.StringWithFallback(PrefRoundingMode, RoundNearest),
+	}
+}
+
+// Apply rounds d to the step, leaving it as is when rounding is off.
+func (r Rounding) Apply(d time.Duration) time.Duration {
+	if r.Step <= 0 {
+		return d
+	}
+	switch r.Mode {
+	case RoundUp:
+		return (d + r.Step - 1).Truncate(r.Step)
+	case RoundDown:
+		return d.Truncate(r.Step)
+	default:
+		return d.Round(r.Step)
+	}
+}
+
+// roundEntries returns copies of entries with their durations rounded for
+// a report, leaving the entries themselves untouched.
+func roundEntries(entries []Entry) []Entry {
+	rounding := currentRounding()
+	rounded := make([]Entry, len(entries))
+	for i, entry := range entries {
+		entry.Duration = rounding.Apply(entry.Duration)
+		rounded[i] = entry
+	}
+	return rounded
+}
+
+// roundingModeName names a rounding mode in the UI language.
+func roundingModeName(mode string) string {
+	switch mode {
+	case RoundUp:
+		return tr("Up")
+	case RoundDown:
+		return tr("Down")
+	default:
+		return tr("Nearest")
+	}
+}
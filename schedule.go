@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefWorkSchedule = "workSchedule"
+
+	// ScheduleReminderGrace is how far into a work block the timer may still
+	// be stopped before the reminder
+	ScheduleReminderGrace = 15 * time.Minute
+
+	// ScheduleCheckInterval is how often the schedule is checked
+	ScheduleCheckInterval = time.Minute
+)
+
+// scheduleWeekdays are the day names blocks are written with.
+var scheduleWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// WorkBlock is a stretch of the working week, e.g. Mon–Fri 09:00–17:00.
+type WorkBlock struct {
+	Days  [7]bool
+	Start time.Duration // since midnight
+	End   time.Duration
+}
+
+// parseWorkBlock reads a block written as days and times, e.g.
+// "Mon-Fri 09:00-17:00", "Sat 10:00-14:00" or "Mon,Wed 13:00-17:30".
+func parseWorkBlock(line string) (WorkBlock, error) {
+	var block WorkBlock
+	days, times, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok {
+		return block, fmt.Errorf("schedule: %q: write the days, then the times", line)
+	}
+	for _, part := range strings.Split(strings.ToLower(days), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, last := scheduleWeekday(from), scheduleWeekday(to)
+		if !isRange {
+			last = first
+		}
+		if first < 0 || last < 0 {
+			return block, fmt.Errorf("schedule: %q: unknown day in %q", line, part)
+		}
+		// Ranges may wrap around the weekend, e.g. Sat-Mon
+		for day := first; ; day = (day + 1) % 7 {
+			block.Days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+
+	from, to, ok := strings.Cut(strings.ReplaceAll(times, " ", ""), "-")
+	start, err := time.Parse("15:04", from)
+	if ok && err == nil {
+		var end time.Time
+		if end, err = time.Parse("15:04", to); err == nil && end.After(start) {
+			block.Start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+			block.End = time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+			return block, nil
+		}
+	}
+	return block, fmt.Errorf("schedule: %q: write the times as 09:00-17:00", line)
+}
+
+func scheduleWeekday(name string) int {
+	name = strings.TrimSpace(name)
+	for i, day := range scheduleWeekdays {
+		if len(name) >= 3 && strings.HasPrefix(name, day) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseWorkSchedule reads a schedule of a block per line, skipping blank
+// lines.
+func parseWorkSchedule(lines []string) ([]WorkBlock, error) {
+	var blocks []WorkBlock
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		block, err := parseWorkBlock(line)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// watchWorkSchedule reminds the user to start a timer when they are
+// ScheduleReminderGrace into a block of their work schedule with none
+// running. Each block is checked once a day, so starting the timer late, or
+// pausing it later in the block, isn't nagged about again.
+func watchWorkSchedule(timer *TaskTimer) {
+	checked := make(map[string]bool)
+	go func() {
+		for now := range time.Tick(ScheduleCheckInterval) {
+			fyne.Do(func() {
+				checkWorkSchedule(timer, now, checked)
+			})
+		}
+	}()
+}
+
+func checkWorkSchedule(timer *TaskTimer, now time.Time, checked map[string]bool) {
+	blocks, err := parseWorkSchedule(fyne.CurrentApp().Preferences().StringList(PrefWorkSchedule))
+	if err != nil {
+		return
+	}
+	today := dayStart(now)
+	for _, block := range blocks {
+		// Counted in wall clock time, which Add isn't on days the clocks change
+		start := time.Date(today.Year(), today.Month(), today.Day(), 0, int(block.Start.Minutes()), 0, 0, today.Location())
+		end := time.Date(today.Year(), today.Month(), today.Day(), 0, int(block.End.Minutes()), 0, 0, today.Location())
+		key := start.Format(time.DateTime)
+		if !block.Days[today.Weekday()] || checked[key] || now.Before(start.Add(ScheduleReminderGrace)) || !now.Before(end) {
+			continue
+		}
+		checked[key] = true
+		if timer.isRunning {
+			continue
+		}
+		fyne.CurrentApp().SendNotification(fyne.NewNotification(tr("Start Tracking?"),
+			tr("You're {{.Minutes}} minutes into your {{.Start}}–{{.End}} work block and no timer is running.", map[string]any{
+				"Minutes": int(now.Sub(start).Minutes()),
+				"Start":   start.Format("15:04"),
+				"End":     end.Format("15:04"),
+			})))
+	}
+}
+
+// scheduledCapacity adds up the working time planned between from and to:
+// the length of the schedule's blocks, or without a schedule, the weekly
+// hours spread over the working days. Days off plan nothing.
+func scheduledCapacity(from, to time.Time) time.Duration {
+	blocks, _ := parseWorkSchedule(fyne.CurrentApp().Preferences().StringList(PrefWorkSchedule))
+	days := workdays()
+	count := 0
+	for _, works := range days {
+		if works {
+			count++
+		}
+	}
+	off := loadTimeOff()
+
+	var capacity time.Duration
+	for day := dayStart(from); day.Before(to); day = addDays(day, 1) {
+		if off.On(day) != "" {
+			continue
+		}
+		if len(blocks) == 0 {
+			if days[day.Weekday()] {
+				capacity += weeklyHours() / time.Duration(count)
+			}
+			continue
+		}
+		for _, block := range blocks {
+			if block.Days[day.Weekday()] {
+				capacity += block.End - block.Start
+			}
+		}
+	}
+	return capacity
+}
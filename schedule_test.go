@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkBlock(t *testing.T) {
+	const (
+		sun = 1 << iota
+		mon
+		tue
+		wed
+		thu
+		fri
+		sat
+	)
+	tests := []struct {
+		line       string
+		days       int
+		start, end time.Duration
+		ok         bool
+	}{
+		{"Mon-Fri 09:00-17:00", mon | tue | wed | thu | fri, 9 * time.Hour, 17 * time.Hour, true},
+		{"Sat 10:00-14:00", sat, 10 * time.Hour, 14 * time.Hour, true},
+		{"Mon,Wed 13:00-17:30", mon | wed, 13 * time.Hour, 17*time.Hour + 30*time.Minute, true},
+		{"  monday-tuesday 08:15 - 12:00 ", mon | tue, 8*time.Hour + 15*time.Minute, 12 * time.Hour, true},
+		// Around the weekend
+		{"Sat-Mon 10:00-12:00", sat | sun | mon, 10 * time.Hour, 12 * time.Hour, true},
+		{"Mon-Fri", 0, 0, 0, false},
+		{"Mo 09:00-17:00", 0, 0, 0, false},
+		{"Mon-Xyz 09:00-17:00", 0, 0, 0, false},
+		{"Mon 9am-5pm", 0, 0, 0, false},
+		{"Mon 17:00-09:00", 0, 0, 0, false},
+		{"Mon 09:00", 0, 0, 0, false},
+	}
+	for _, tc := range tests {
+		block, err := parseWorkBlock(tc.line)
+		if (err == nil) != tc.ok {
+			t.Errorf("parseWorkBlock(%q): error %v, want ok %v", tc.line, err, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		var days int
+		for day, on := range block.Days {
+			if on {
+				days |= 1 << day
+			}
+		}
+		if days != tc.days || block.Start != tc.start || block.End != tc.end {
+			t.Errorf("parseWorkBlock(%q) = days %07b, %v-%v; want days %07b, %v-%v",
+				tc.line, days, block.Start, block.End, tc.days, tc.start, tc.end)
+		}
+	}
+}
@@ -0,0 +1,1013 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+func createSettingsContainer(timer *TaskTimer) *fyne.Container {
+	prefs := fyne.CurrentApp().Preferences()
+
+	// Webhook URLs, one per line
+	webhookInput := widget.NewMultiLineEntry()
+	webhookInput.PlaceHolder = "https://example.com/hook"
+	webhookInput.SetText(strings.Join(prefs.StringList(PrefWebhookURLs), "\n"))
+
+	// Jira worklog push
+	jiraEnabled := widget.NewCheck(tr("Log work to Jira when a session is recorded"), nil)
+	jiraEnabled.SetChecked(prefs.Bool(PrefJiraEnabled))
+	jiraURLInput := widget.NewEntry()
+	jiraURLInput.PlaceHolder = "https://your-company.atlassian.net"
+	jiraURLInput.SetText(prefs.String(PrefJiraURL))
+	jiraEmailInput := widget.NewEntry()
+	jiraEmailInput.SetText(prefs.String(PrefJiraEmail))
+	jiraTokenInput := widget.NewPasswordEntry()
+	jiraTokenInput.SetText(keychainSecret(KeyringJiraToken))
+	jiraMappingBtn := widget.NewButton(tr("Task → issue mapping…"), func() {
+		showJiraMappingDialog(timer)
+	})
+
+	// GitLab time tracking push
+	gitlabEnabled := widget.NewCheck(tr("Add time spent to GitLab issues when a session is recorded"), nil)
+	gitlabEnabled.SetChecked(prefs.Bool(PrefGitLabEnabled))
+	gitlabURLInput := widget.NewEntry()
+	gitlabURLInput.PlaceHolder = GitLabDefaultURL
+	gitlabURLInput.SetText(prefs.String(PrefGitLabURL))
+	gitlabTokenInput := widget.NewPasswordEntry()
+	gitlabTokenInput.SetText(keychainSecret(KeyringGitLabToken))
+	gitlabProjectInput := widget.NewEntry()
+	gitlabProjectInput.PlaceHolder = "group/project"
+	gitlabProjectInput.SetText(prefs.String(PrefGitLabProject))
+
+	// Azure DevOps completed work push
+	azureEnabled := widget.NewCheck(tr("Add completed work to Azure DevOps work items when a session is recorded"), nil)
+	azureEnabled.SetChecked(prefs.Bool(PrefAzureDevOpsEnabled))
+	azureURLInput := widget.NewEntry()
+	azureURLInput.PlaceHolder = "https://dev.azure.com/your-organization"
+	azureURLInput.SetText(prefs.String(PrefAzureDevOpsURL))
+	azureTokenInput := widget.NewPasswordEntry()
+	azureTokenInput.SetText(keychainSecret(KeyringAzureDevOpsToken))
+
+	// GitHub issues
+	githubTokenInput := widget.NewPasswordEntry()
+	githubTokenInput.SetText(keychainSecret(KeyringGitHubToken))
+	githubRepoInput := widget.NewEntry()
+	githubRepoInput.PlaceHolder = "owner/repo"
+	githubRepoInput.SetText(prefs.String(PrefGitHubRepo))
+	githubComment := widget.NewCheck(tr("Comment tracked time on the issue when a session is recorded"), nil)
+	githubComment.SetChecked(prefs.Bool(PrefGitHubComment))
+
+	// Google Calendar sync
+	googleClientIDInput := widget.NewEntry()
+	googleClientIDInput.SetText(prefs.String(PrefGoogleClientID))
+	googleClientSecretInput := widget.NewPasswordEntry()
+	googleClientSecretInput.SetText(keychainSecret(KeyringGoogleClientSecret))
+	googleCalendarIDInput := widget.NewEntry()
+	googleCalendarIDInput.PlaceHolder = googleDefaultCalendarID
+	googleCalendarIDInput.SetText(prefs.String(PrefGoogleCalendarID))
+	googleCreateEvents := widget.NewCheck(tr("Create events for completed sessions"), nil)
+	googleCreateEvents.SetChecked(prefs.Bool(PrefGoogleCreateEvents))
+	googleSuggestEvents := widget.NewCheck(tr("Suggest today's events as tasks"), nil)
+	googleSuggestEvents.SetChecked(prefs.Bool(PrefGoogleSuggestEvents))
+	googleStartMeetings := widget.NewCheck(tr("Start timing meetings when they begin"), nil)
+	googleStartMeetings.SetChecked(prefs.Bool(PrefGoogleStartMeetings))
+
+	googleConnectBtn := widget.NewButton("", nil)
+	updateGoogleConnectBtn := func() {
+		if timer.calendar.Connected() {
+			googleConnectBtn.SetText(tr("Disconnect Google account"))
+		} else {
+			googleConnectBtn.SetText(tr("Connect Google account…"))
+		}
+	}
+	googleConnectBtn.OnTapped = func() {
+		if timer.calendar.Connected() {
+			if err := timer.calendar.Disconnect(); err != nil {
+				dialog.ShowError(err, timer.window)
+			}
+			updateGoogleConnectBtn()
+			return
+		}
+
+		// The client credentials are needed before the consent page can open
+		prefs.SetString(PrefGoogleClientID, strings.TrimSpace(googleClientIDInput.Text))
+		if err := setKeychainSecrets(map[string]string{KeyringGoogleClientSecret: googleClientSecretInput.Text}); err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		go func() {
+			err := timer.calendar.Connect()
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+				}
+				updateGoogleConnectBtn()
+			})
+		}()
+	}
+	updateGoogleConnectBtn()
+
+	// Slack status
+	slackEnabled := widget.NewCheck(tr("Show the running task as my Slack status"), nil)
+	slackEnabled.SetChecked(prefs.Bool(PrefSlackEnabled))
+	slackTokenInput := widget.NewPasswordEntry()
+	slackTokenInput.PlaceHolder = "xoxp-…"
+	slackTokenInput.SetText(keychainSecret(KeyringSlackToken))
+	slackOptOutBtn := widget.NewButton(tr("Tasks to keep private…"), func() {
+		showSlackOptOutDialog(timer)
+	})
+
+	// Team workspace presence
+	teamServerInput := widget.NewEntry()
+	teamServerInput.PlaceHolder = "https://sync.example.com/workspaces/acme"
+	teamServerInput.SetText(prefs.String(PrefTeamServerURL))
+	teamTokenInput := widget.NewPasswordEntry()
+	teamTokenInput.SetText(keychainSecret(KeyringTeamToken))
+	teamUserInput := widget.NewEntry()
+	teamUserInput.SetText(prefs.String(PrefTeamUserName))
+	teamFingerprintInput := widget.NewEntry()
+	teamFingerprintInput.PlaceHolder = tr("For a self-signed certificate")
+	teamFingerprintInput.SetText(prefs.String(PrefTeamCertFingerprint))
+	teamShare := widget.NewCheck(tr("Show teammates what I'm timing"), nil)
+	teamShare.SetChecked(prefs.Bool(PrefTeamSharePresence))
+	teamSyncEntries := widget.NewCheck(tr("Sync my entries with the workspace"), nil)
+	teamSyncEntries.SetChecked(prefs.Bool(PrefTeamSyncEntries))
+	teamSyncBtn := widget.NewButton(tr("Sync now"), func() {
+		teamSyncNow(timer)
+	})
+	teamTokensBtn := widget.NewButton(tr("Tokens…"), func() {
+		showTeamTokensDialog(timer)
+	})
+	teamReportBtn := widget.NewButton(tr("Team report…"), func() {
+		showTeamReportDialog(timer)
+	})
+	if !prefs.Bool(PrefTeamManager) {
+		teamReportBtn.Hide()
+	}
+
+	// Task suggestions from the checked-out Git branch
+	gitRepoInput := widget.NewEntry()
+	gitRepoInput.PlaceHolder = "/path/to/repository"
+	gitRepoInput.SetText(prefs.String(PrefGitRepoPath))
+	gitAutoStart := widget.NewCheck(tr("Start the branch's task without asking"), nil)
+	gitAutoStart.SetChecked(prefs.Bool(PrefGitAutoStart))
+	gitTicketTasks := widget.NewCheck(tr("Name the task after the ticket in the branch, e.g. PROJ-42 or #123"), nil)
+	gitTicketTasks.SetChecked(prefs.Bool(PrefGitTicketTasks))
+
+	// Do Not Disturb during deep work
+	focusModeEnabled := widget.NewCheck(tr("Turn on Do Not Disturb while a session with this tag runs"), nil)
+	focusModeEnabled.SetChecked(prefs.Bool(PrefFocusMode))
+	focusModeTagInput := widget.NewEntry()
+	focusModeTagInput.PlaceHolder = DefaultFocusModeTag
+	focusModeTagInput.SetText(prefs.String(PrefFocusModeTag))
+
+	// Suggestions from the focused window, off unless opted into
+	activityEnabled := widget.NewCheck(tr("Suggest a task when I use an app for a while without a timer"), nil)
+	activityEnabled.SetChecked(prefs.Bool(PrefActivitySuggestions))
+	activityEnabled.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefActivitySuggestions, enabled)
+	}
+	activityHelp := widget.NewLabel(tr("Window titles are read every 30 seconds and never stored. Apps are only remembered with the task you accept for them."))
+	activityHelp.Wrapping = fyne.TextWrapWord
+	activityForgetBtn := widget.NewButton(tr("Forget apps' tasks"), func() {
+		setActivityTasks(nil)
+	})
+
+	// Browser extension companion, which starts or stops as it is ticked
+	companionEnabled := widget.NewCheck(tr("Let paired browser extensions control the timer"), nil)
+	companionEnabled.SetChecked(prefs.Bool(PrefCompanionEnabled))
+	companionEnabled.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefCompanionEnabled, enabled)
+		if err := timer.companion.Apply(); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	}
+	companionForgetBtn := widget.NewButton(tr("Forget paired extensions"), func() {
+		dialog.ShowConfirm(tr("Forget paired extensions"), tr("Extensions will have to pair again before they can control the timer."), func(ok bool) {
+			if ok {
+				setCompanionPairings(nil)
+			}
+		}, timer.window)
+	})
+
+	// Live timer feed for overlays and dashboards, which starts or stops as
+	// it is ticked
+	liveFeedEnabled := widget.NewCheck(tr("Push the timer to overlays and dashboards"), nil)
+	liveFeedEnabled.SetChecked(prefs.Bool(PrefLiveFeedEnabled))
+	liveFeedEnabled.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefLiveFeedEnabled, enabled)
+		if err := timer.liveFeed.Apply(); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	}
+	liveOverlayBtn := widget.NewButton(tr("Copy overlay URL"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(liveFeedURL("http", "/overlay"))
+	})
+	liveFeedBtn := widget.NewButton(tr("Copy WebSocket URL"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(liveFeedURL("ws", "/live"))
+	})
+	liveFeedKeyBtn := widget.NewButton(tr("New key"), func() {
+		dialog.ShowConfirm(tr("New key"), tr("Overlays and dashboards will need the new URL to connect."), func(ok bool) {
+			if !ok {
+				return
+			}
+			prefs.SetString(PrefLiveFeedKey, "")
+			liveFeedKey()
+			timer.liveFeed.Close()
+			if err := timer.liveFeed.Apply(); err != nil {
+				dialog.ShowError(err, timer.window)
+			}
+		}, timer.window)
+	})
+	liveFeedHelp := widget.NewLabel(tr("Add the overlay URL as a browser source in OBS. Only pages on this computer can connect."))
+	liveFeedHelp.Wrapping = fyne.TextWrapWord
+
+	// Cloud sync between devices
+	syncURLInput := widget.NewEntry()
+	syncURLInput.SetText(prefs.String(PrefSyncURL))
+	syncOff := tr("Off")
+	syncProviderSelect := widget.NewSelect(append([]string{syncOff}, syncProviders...), func(provider string) {
+		syncURLInput.SetPlaceHolder(syncURLHint(provider))
+	})
+	syncProviderSelect.SetSelected(prefs.StringWithFallback(PrefSyncProvider, syncOff))
+	syncUserInput := widget.NewEntry()
+	syncUserInput.SetText(syncCredential(KeyringSyncUser))
+	syncSecretInput := widget.NewPasswordEntry()
+	syncSecretInput.SetText(syncCredential(KeyringSyncSecret))
+	syncRegionInput := widget.NewEntry()
+	syncRegionInput.PlaceHolder = "eu-central-1"
+	syncRegionInput.SetText(prefs.String(PrefSyncRegion))
+	syncPassphraseInput := widget.NewPasswordEntry()
+	syncPassphraseInput.SetText(syncCredential(KeyringSyncPassphrase))
+	syncNowBtn := widget.NewButton(tr("Sync now"), func() {
+		syncNow(timer)
+	})
+
+	// End-of-day summary
+	daySummaryEnabled := widget.NewCheck(tr("Summarize my day in a notification"), nil)
+	daySummaryEnabled.SetChecked(prefs.Bool(PrefDaySummaryEnabled))
+	daySummaryTimeSelect := widget.NewSelect(daySummaryTimes(), nil)
+	daySummaryTimeSelect.SetSelected(prefs.StringWithFallback(PrefDaySummaryTime, DefaultDaySummaryTime))
+	daySummaryEmailInput := widget.NewEntry()
+	daySummaryEmailInput.PlaceHolder = "me@example.com"
+	daySummaryEmailInput.SetText(prefs.String(PrefDaySummaryEmail))
+
+	// Mail server for emailed reports
+	smtpServerInput := widget.NewEntry()
+	smtpServerInput.PlaceHolder = "smtp.example.com:587"
+	smtpServerInput.SetText(prefs.String(PrefSMTPServer))
+	smtpUserInput := widget.NewEntry()
+	smtpUserInput.SetText(prefs.String(PrefSMTPUser))
+	smtpPasswordInput := widget.NewPasswordEntry()
+	smtpPasswordInput.SetText(keychainSecret(KeyringSMTPPassword))
+	smtpFromInput := widget.NewEntry()
+	smtpFromInput.PlaceHolder = tr("Same as user")
+	smtpFromInput.SetText(prefs.String(PrefSMTPFrom))
+	reportEmailInput := widget.NewEntry()
+	reportEmailInput.PlaceHolder = "me@example.com, boss@example.com"
+	reportEmailInput.SetText(prefs.String(PrefReportEmail))
+	weeklyReportBtn := widget.NewButton(tr("Weekly report…"), func() {
+		showWeeklyReportDialog(timer)
+	})
+
+	// Shell commands run on timer events, and the daily target one of them
+	// fires on
+	dayTargetInput := widget.NewEntry()
+	dayTargetInput.PlaceHolder = tr("Hours, blank for none")
+	if target := dayTarget(); target > 0 {
+		dayTargetInput.SetText(formatHours(target))
+	}
+	commands := hookCommands()
+	hookInputs := make(map[string]*widget.Entry)
+	hookForm := widget.NewForm(widget.NewFormItem(tr("Daily target"), dayTargetInput))
+	for _, event := range HookEvents {
+		input := widget.NewEntry()
+		input.PlaceHolder = "notify-send \"$GOTIME_TASK\""
+		input.SetText(commands[event])
+		hookInputs[event] = input
+		hookForm.Append(hookEventName(event), input)
+	}
+	hookHelp := widget.NewLabel(tr("Commands get GOTIME_EVENT, GOTIME_TASK, GOTIME_ELAPSED_SECONDS and GOTIME_TODAY_SECONDS in their environment."))
+	hookHelp.Wrapping = fyne.TextWrapWord
+
+	// Contractual hours the flexitime balance is kept against
+	weeklyHoursInput := widget.NewEntry()
+	weeklyHoursInput.PlaceHolder = tr("Hours, blank for none")
+	if hours := weeklyHours(); hours > 0 {
+		weeklyHoursInput.SetText(formatHours(hours))
+	}
+	flexStartInput := widget.NewEntry()
+	flexStartInput.PlaceHolder = "YYYY-MM-DD"
+	flexStartInput.SetText(prefs.String(PrefFlexStart))
+
+	// Work schedule, a block per line
+	scheduleInput := widget.NewMultiLineEntry()
+	scheduleInput.PlaceHolder = "Mon-Fri 09:00-17:00"
+	scheduleInput.SetText(strings.Join(prefs.StringList(PrefWorkSchedule), "\n"))
+
+	// Zone days and weeks are counted in
+	timeZoneInput := widget.NewSelectEntry([]string{
+		"America/Los_Angeles", "America/New_York", "Europe/London", "Europe/Berlin",
+		"Asia/Kolkata", "Asia/Tokyo", "Australia/Sydney", "UTC",
+	})
+	timeZoneInput.PlaceHolder = tr("System")
+	timeZoneInput.SetText(prefs.String(PrefTimeZone))
+
+	saveBtn := widget.NewButton(tr("Save"), func() {
+		timeZone := strings.TrimSpace(timeZoneInput.Text)
+		if timeZone != "" {
+			if _, err := time.LoadLocation(timeZone); err != nil {
+				dialog.ShowError(errors.New(tr("Unknown time zone \"{{.Zone}}\"", map[string]any{"Zone": timeZone})), timer.window)
+				return
+			}
+		}
+		if _, err := parseWorkSchedule(strings.Split(scheduleInput.Text, "\n")); err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		var target time.Duration
+		if text := strings.TrimSpace(dayTargetInput.Text); text != "" {
+			var err error
+			if target, err = parseHours(text); err != nil || target < 0 {
+				dialog.ShowInformation(tr("Daily target"), tr("Enter the daily target as a number of hours."), timer.window)
+				return
+			}
+		}
+		var hours time.Duration
+		if text := strings.TrimSpace(weeklyHoursInput.Text); text != "" {
+			var err error
+			if hours, err = parseHours(text); err != nil || hours < 0 {
+				dialog.ShowInformation(tr("Flexitime"), tr("Enter the weekly hours as a number of hours."), timer.window)
+				return
+			}
+		}
+		flexFrom := strings.TrimSpace(flexStartInput.Text)
+		if flexFrom == "" && hours > 0 {
+			// The balance starts from the week the hours are first set
+			flexFrom = weekStart(time.Now()).Format("2006-01-02")
+			flexStartInput.SetText(flexFrom)
+		}
+		if flexFrom != "" {
+			if _, err := time.ParseInLocation("2006-01-02", flexFrom, time.Local); err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+		}
+		if timeZone != prefs.String(PrefTimeZone) {
+			prefs.SetString(PrefTimeZone, timeZone)
+			dialog.ShowInformation(tr("Time Zone"), tr("The new time zone takes effect when the app restarts."), timer.window)
+		}
+
+		prefs.SetStringList(PrefWebhookURLs, strings.Split(webhookInput.Text, "\n"))
+		prefs.SetFloat(PrefDayTarget, target.Hours())
+		prefs.SetFloat(PrefWeeklyHours, hours.Hours())
+		prefs.SetString(PrefFlexStart, flexFrom)
+		prefs.SetStringList(PrefWorkSchedule, strings.Split(scheduleInput.Text, "\n"))
+		for event, input := range hookInputs {
+			commands[event] = strings.TrimSpace(input.Text)
+		}
+		setHookCommands(commands)
+		prefs.SetBool(PrefJiraEnabled, jiraEnabled.Checked)
+		prefs.SetString(PrefJiraURL, strings.TrimSpace(jiraURLInput.Text))
+		prefs.SetString(PrefJiraEmail, strings.TrimSpace(jiraEmailInput.Text))
+		prefs.SetBool(PrefGitLabEnabled, gitlabEnabled.Checked)
+		prefs.SetString(PrefGitLabURL, strings.TrimSpace(gitlabURLInput.Text))
+		prefs.SetString(PrefGitLabProject, strings.TrimSpace(gitlabProjectInput.Text))
+		prefs.SetBool(PrefAzureDevOpsEnabled, azureEnabled.Checked)
+		prefs.SetString(PrefAzureDevOpsURL, strings.TrimSpace(azureURLInput.Text))
+		prefs.SetString(PrefGitHubRepo, strings.TrimSpace(githubRepoInput.Text))
+		prefs.SetBool(PrefGitHubComment, githubComment.Checked)
+		prefs.SetString(PrefGoogleClientID, strings.TrimSpace(googleClientIDInput.Text))
+		prefs.SetString(PrefGoogleCalendarID, strings.TrimSpace(googleCalendarIDInput.Text))
+		prefs.SetBool(PrefGoogleCreateEvents, googleCreateEvents.Checked)
+		prefs.SetBool(PrefGoogleSuggestEvents, googleSuggestEvents.Checked)
+		prefs.SetBool(PrefGoogleStartMeetings, googleStartMeetings.Checked)
+		prefs.SetBool(PrefSlackEnabled, slackEnabled.Checked)
+		prefs.SetString(PrefTeamServerURL, strings.TrimSpace(teamServerInput.Text))
+		prefs.SetString(PrefTeamUserName, strings.TrimSpace(teamUserInput.Text))
+		prefs.SetString(PrefTeamCertFingerprint, strings.TrimSpace(teamFingerprintInput.Text))
+		prefs.SetBool(PrefTeamSharePresence, teamShare.Checked)
+		prefs.SetBool(PrefTeamSyncEntries, teamSyncEntries.Checked)
+		prefs.SetString(PrefGitRepoPath, strings.TrimSpace(gitRepoInput.Text))
+		prefs.SetBool(PrefDaySummaryEnabled, daySummaryEnabled.Checked)
+		prefs.SetString(PrefDaySummaryTime, daySummaryTimeSelect.Selected)
+		prefs.SetString(PrefDaySummaryEmail, strings.TrimSpace(daySummaryEmailInput.Text))
+		prefs.SetString(PrefSMTPServer, strings.TrimSpace(smtpServerInput.Text))
+		prefs.SetString(PrefSMTPUser, strings.TrimSpace(smtpUserInput.Text))
+		prefs.SetString(PrefSMTPFrom, strings.TrimSpace(smtpFromInput.Text))
+		prefs.SetString(PrefReportEmail, strings.TrimSpace(reportEmailInput.Text))
+		prefs.SetBool(PrefGitAutoStart, gitAutoStart.Checked)
+		prefs.SetBool(PrefGitTicketTasks, gitTicketTasks.Checked)
+		prefs.SetBool(PrefFocusMode, focusModeEnabled.Checked)
+		prefs.SetString(PrefFocusModeTag, strings.TrimSpace(focusModeTagInput.Text))
+		updateFocusMode(timer)
+		if syncProviderSelect.Selected == syncOff {
+			prefs.SetString(PrefSyncProvider, "")
+		} else {
+			prefs.SetString(PrefSyncProvider, syncProviderSelect.Selected)
+		}
+		prefs.SetString(PrefSyncURL, strings.TrimSpace(syncURLInput.Text))
+		prefs.SetString(PrefSyncRegion, strings.TrimSpace(syncRegionInput.Text))
+		// Kept out of the preferences, which are a plain file
+		if err := setKeychainSecrets(map[string]string{
+			KeyringJiraToken:          jiraTokenInput.Text,
+			KeyringSlackToken:         slackTokenInput.Text,
+			KeyringGoogleClientSecret: googleClientSecretInput.Text,
+			KeyringGitHubToken:        githubTokenInput.Text,
+			KeyringGitLabToken:        gitlabTokenInput.Text,
+			KeyringAzureDevOpsToken:   azureTokenInput.Text,
+			KeyringSMTPPassword:       smtpPasswordInput.Text,
+			KeyringTeamToken:          teamTokenInput.Text,
+		}); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+		if err := setSyncCredentials(map[string]string{
+			KeyringSyncPassphrase: syncPassphraseInput.Text,
+			KeyringSyncUser:       strings.TrimSpace(syncUserInput.Text),
+			KeyringSyncSecret:     syncSecretInput.Text,
+		}); err != nil {
+			dialog.ShowError(err, timer.window)
+		} else {
+			for _, key := range legacySyncPrefs {
+				prefs.RemoveValue(key)
+			}
+		}
+	})
+
+	// Number, date and header format of exported files
+	exportLocaleSelect := widget.NewSelect(exportLocaleNames(), func(name string) {
+		prefs.SetString(PrefExportLocale, exportLocaleByName(name).Tag)
+	})
+	exportLocaleSelect.SetSelected(tr(currentExportLocale().Name))
+
+	// How durations are written, on screen and in exports
+	var durationFormats []string
+	for _, format := range DurationFormats {
+		durationFormats = append(durationFormats, durationFormatName(format))
+	}
+	durationFormatSelect := widget.NewSelect(durationFormats, nil)
+	durationFormatSelect.SetSelectedIndex(max(slices.Index(DurationFormats, currentDurationFormat()), 0))
+	durationFormatSelect.OnChanged = func(string) {
+		prefs.SetString(PrefDurationFormat, DurationFormats[durationFormatSelect.SelectedIndex()])
+		applyDurationFormat(prefs)
+		setElapsed(timer, timer.elapsedTime)
+	}
+
+	barClockCheck := widget.NewCheck(tr("Show the clock in the menu bar or on the taskbar"), nil)
+	barClockCheck.SetChecked(prefs.BoolWithFallback(PrefBarClock, true))
+	barClockCheck.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefBarClock, enabled)
+		updateBarClock(timer)
+	}
+	autostartCheck := widget.NewCheck(tr("Start on login, minimized"), nil)
+	autostartCheck.SetChecked(autostartInstalled())
+	var onAutostartChanged func(bool)
+	onAutostartChanged = func(enabled bool) {
+		if err := setAutostart(enabled); err != nil {
+			dialog.ShowError(err, timer.window)
+			autostartCheck.OnChanged = nil
+			autostartCheck.SetChecked(!enabled)
+			autostartCheck.OnChanged = onAutostartChanged
+		}
+	}
+	autostartCheck.OnChanged = onAutostartChanged
+	urlSchemeCheck := widget.NewCheck(tr("Open gotime:// links with this app"), nil)
+	urlSchemeCheck.SetChecked(urlSchemeRegistered())
+	var onURLSchemeChanged func(bool)
+	onURLSchemeChanged = func(enabled bool) {
+		if err := setURLScheme(enabled); err != nil {
+			dialog.ShowError(err, timer.window)
+			urlSchemeCheck.OnChanged = nil
+			urlSchemeCheck.SetChecked(!enabled)
+			urlSchemeCheck.OnChanged = onURLSchemeChanged
+		}
+	}
+	urlSchemeCheck.OnChanged = onURLSchemeChanged
+	if fyne.CurrentDevice().IsMobile() {
+		autostartCheck.Hide()
+		urlSchemeCheck.Hide()
+	}
+
+	// Tenths of a second, for timing short experiments
+	precisionModeCheck := widget.NewCheck(tr("Precision mode: show tenths of a second and export milliseconds"), nil)
+	precisionModeCheck.SetChecked(prefs.Bool(PrefPrecisionMode))
+	precisionModeCheck.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefPrecisionMode, enabled)
+		applyPrecisionMode(prefs)
+		setElapsed(timer, timer.elapsedTime)
+	}
+
+	// Rounding of durations in exports and invoices
+	var roundingSteps []string
+	for _, minutes := range RoundingSteps {
+		if minutes == 0 {
+			roundingSteps = append(roundingSteps, tr("No rounding"))
+		} else {
+			roundingSteps = append(roundingSteps, tr("{{.Minutes}} minutes", map[string]any{"Minutes": minutes}))
+		}
+	}
+	roundingStepSelect := widget.NewSelect(roundingSteps, nil)
+	roundingStepSelect.SetSelectedIndex(max(slices.Index(RoundingSteps, prefs.Int(PrefRoundingMinutes)), 0))
+	roundingStepSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefRoundingMinutes, RoundingSteps[roundingStepSelect.SelectedIndex()])
+	}
+	var roundingModes []string
+	for _, mode := range RoundingModes {
+		roundingModes = append(roundingModes, roundingModeName(mode))
+	}
+	roundingModeSelect := widget.NewSelect(roundingModes, nil)
+	roundingModeSelect.SetSelectedIndex(max(slices.Index(RoundingModes, currentRounding().Mode), 0))
+	roundingModeSelect.OnChanged = func(string) {
+		prefs.SetString(PrefRoundingMode, RoundingModes[roundingModeSelect.SelectedIndex()])
+	}
+
+	// End-of-day stop for forgotten timers
+	autoStops := append([]string{"", AutoStopSchedule}, autoStopChoices()...)
+	autoStopNames := append([]string{tr("Off"), tr("End of work schedule")}, autoStopChoices()...)
+	autoStopSelect := widget.NewSelect(autoStopNames, nil)
+	autoStopSelect.SetSelectedIndex(max(slices.Index(autoStops, prefs.String(PrefAutoStop)), 0))
+	autoStopSelect.OnChanged = func(string) {
+		prefs.SetString(PrefAutoStop, autoStops[autoStopSelect.SelectedIndex()])
+	}
+
+	// Check on sessions running suspiciously long
+	var longSessionLimits []string
+	for _, hours := range LongSessionLimits {
+		if hours == 0 {
+			longSessionLimits = append(longSessionLimits, tr("Never"))
+		} else {
+			longSessionLimits = append(longSessionLimits, formatShortDuration(time.Duration(hours)*time.Hour))
+		}
+	}
+	longSessionSelect := widget.NewSelect(longSessionLimits, nil)
+	longSessionSelect.SetSelectedIndex(max(slices.Index(LongSessionLimits, prefs.Int(PrefLongSessionHours)), 0))
+	longSessionSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefLongSessionHours, LongSessionLimits[longSessionSelect.SelectedIndex()])
+	}
+	var longSessionWaits []string
+	for _, minutes := range LongSessionWaits {
+		longSessionWaits = append(longSessionWaits, tr("{{.Minutes}} minutes", map[string]any{"Minutes": minutes}))
+	}
+	longSessionWaitSelect := widget.NewSelect(longSessionWaits, nil)
+	longSessionWaitSelect.SetSelectedIndex(max(slices.Index(LongSessionWaits, prefs.IntWithFallback(PrefLongSessionWait, DefaultLongSessionWait)), 0))
+	longSessionWaitSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefLongSessionWait, LongSessionWaits[longSessionWaitSelect.SelectedIndex()])
+	}
+
+	// Step of the buttons beside the clock
+	var adjustSteps []string
+	for _, minutes := range AdjustSteps {
+		adjustSteps = append(adjustSteps, formatShortDuration(time.Duration(minutes)*time.Minute))
+	}
+	adjustStepSelect := widget.NewSelect(adjustSteps, nil)
+	adjustStepSelect.SetSelectedIndex(max(slices.Index(AdjustSteps, prefs.IntWithFallback(PrefAdjustStep, DefaultAdjustStep)), 0))
+	adjustStepSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefAdjustStep, AdjustSteps[adjustStepSelect.SelectedIndex()])
+	}
+
+	// Entries sharing time are otherwise flagged and trimmed on request
+	allowOverlapsCheck := widget.NewCheck(tr("Allow them, for work done side by side"), func(allowed bool) {
+		prefs.SetBool(PrefAllowOverlaps, allowed)
+	})
+	allowOverlapsCheck.SetChecked(overlapsAllowed())
+
+	// Where tasks and entries are kept
+	backends := []string{StorageSQLite, StorageJSON, StorageEncrypted}
+	storageSelect := widget.NewSelect([]string{"SQLite", tr("JSON file"), tr("Encrypted file")}, nil)
+	storageSelect.SetSelectedIndex(slices.Index(backends, storageBackend(prefs)))
+	storageSelect.OnChanged = func(string) {
+		backend := backends[storageSelect.SelectedIndex()]
+		if backend == storageBackend(prefs) {
+			return
+		}
+		switchStorageBackend(prefs, backend)
+		message := tr("Your data moves to the new storage when the app restarts.")
+		if backend == StorageEncrypted {
+			message = tr("At the next start you'll choose a passphrase, and your data moves into the encrypted file.")
+		}
+		dialog.ShowInformation(tr("Storage"), message, timer.window)
+	}
+	forgetPassphraseBtn := widget.NewButton(tr("Forget saved passphrase"), func() {
+		forgetPassphrase()
+	})
+	if storageBackend(prefs) != StorageEncrypted {
+		forgetPassphraseBtn.Hide()
+	}
+
+	// Reporting periods
+	var weekdays []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		weekdays = append(weekdays, weekdayName(day))
+	}
+	firstWeekdaySelect := widget.NewSelect(weekdays, nil)
+	firstWeekdaySelect.SetSelectedIndex(int(firstWeekday()))
+	firstWeekdaySelect.OnChanged = func(string) {
+		if !isoWeeks() {
+			prefs.SetInt(PrefFirstWeekday, firstWeekdaySelect.SelectedIndex())
+		}
+	}
+	// ISO weeks start on Monday whatever the first weekday is set to, which
+	// is kept for when they're turned off
+	isoWeeksCheck := widget.NewCheck(tr("Number weeks the ISO 8601 way"), nil)
+	isoWeeksCheck.SetChecked(isoWeeks())
+	if isoWeeks() {
+		firstWeekdaySelect.Disable()
+	}
+	isoWeeksCheck.OnChanged = func(enabled bool) {
+		prefs.SetBool(PrefISOWeeks, enabled)
+		firstWeekdaySelect.SetSelectedIndex(int(firstWeekday()))
+		if enabled {
+			firstWeekdaySelect.Disable()
+		} else {
+			firstWeekdaySelect.Enable()
+		}
+	}
+	var months []string
+	for month := time.January; month <= time.December; month++ {
+		months = append(months, monthName(month))
+	}
+	fiscalYearSelect := widget.NewSelect(months, nil)
+	fiscalYearSelect.SetSelectedIndex(int(fiscalYearStartMonth()) - 1)
+	fiscalYearSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefFiscalYearStartMonth, fiscalYearSelect.SelectedIndex()+1)
+	}
+
+	// Importers for other trackers
+	togglCSVBtn := widget.NewButton(tr("Import Toggl CSV…"), func() {
+		showTogglCSVImportDialog(timer)
+	})
+	togglAPIBtn := widget.NewButton(tr("Import from Toggl API…"), func() {
+		showTogglAPIImportDialog(timer)
+	})
+	timewarriorBtn := widget.NewButton(tr("Import Timewarrior data…"), func() {
+		showTimewarriorImportDialog(timer)
+	})
+	hamsterBtn := widget.NewButton(tr("Import Hamster database…"), func() {
+		showHamsterImportDialog(timer)
+	})
+
+	return container.NewVBox(
+		widget.NewLabel(tr("Webhook URLs (one per line)")),
+		webhookInput,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Event hooks")),
+		hookForm,
+		hookHelp,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Flexitime")),
+		widget.NewForm(
+			widget.NewFormItem(tr("Weekly hours"), weeklyHoursInput),
+			widget.NewFormItem(tr("Counting from"), flexStartInput),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Sounds")),
+		createSoundsContainer(timer),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Focus mode")),
+		focusModeEnabled,
+		widget.NewForm(widget.NewFormItem(tr("Tag"), focusModeTagInput)),
+		widget.NewSeparator(),
+		widget.NewLabel("Jira"),
+		jiraEnabled,
+		widget.NewForm(
+			widget.NewFormItem(tr("Site URL"), jiraURLInput),
+			widget.NewFormItem(tr("Email"), jiraEmailInput),
+			widget.NewFormItem(tr("API token"), jiraTokenInput),
+		),
+		jiraMappingBtn,
+		widget.NewSeparator(),
+		widget.NewLabel("GitLab"),
+		gitlabEnabled,
+		widget.NewForm(
+			widget.NewFormItem(tr("Server URL"), gitlabURLInput),
+			widget.NewFormItem(tr("Access token"), gitlabTokenInput),
+			widget.NewFormItem(tr("Default project"), gitlabProjectInput),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel("Azure DevOps"),
+		azureEnabled,
+		widget.NewForm(
+			widget.NewFormItem(tr("Organization URL"), azureURLInput),
+			widget.NewFormItem(tr("Personal access token"), azureTokenInput),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel("GitHub"),
+		widget.NewForm(
+			widget.NewFormItem(tr("Access token"), githubTokenInput),
+			widget.NewFormItem(tr("Default repository"), githubRepoInput),
+		),
+		githubComment,
+		widget.NewSeparator(),
+		widget.NewLabel("Google Calendar"),
+		widget.NewForm(
+			widget.NewFormItem(tr("OAuth client ID"), googleClientIDInput),
+			widget.NewFormItem(tr("Client secret"), googleClientSecretInput),
+			widget.NewFormItem(tr("Calendar ID"), googleCalendarIDInput),
+		),
+		googleCreateEvents,
+		googleSuggestEvents,
+		googleStartMeetings,
+		googleConnectBtn,
+		widget.NewSeparator(),
+		widget.NewLabel("Slack"),
+		slackEnabled,
+		widget.NewForm(widget.NewFormItem(tr("User token"), slackTokenInput)),
+		slackOptOutBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Team workspace")),
+		widget.NewForm(
+			widget.NewFormItem(tr("Server URL"), teamServerInput),
+			widget.NewFormItem(tr("Token"), teamTokenInput),
+			widget.NewFormItem(tr("Display name"), teamUserInput),
+			widget.NewFormItem(tr("Certificate fingerprint"), teamFingerprintInput),
+		),
+		teamShare,
+		teamSyncEntries,
+		container.NewHBox(teamSyncBtn, teamTokensBtn, teamReportBtn),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Git branch")),
+		widget.NewForm(widget.NewFormItem(tr("Repository"), gitRepoInput)),
+		gitAutoStart,
+		gitTicketTasks,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("App activity")),
+		activityEnabled,
+		activityHelp,
+		activityForgetBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Browser extension")),
+		companionEnabled,
+		widget.NewLabel(tr("Extensions connect to http://localhost:{{.Port}}", map[string]any{"Port": CompanionPort})),
+		companionForgetBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Live timer feed")),
+		liveFeedEnabled,
+		liveFeedHelp,
+		container.NewHBox(liveOverlayBtn, liveFeedBtn, liveFeedKeyBtn),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Cloud sync")),
+		widget.NewForm(
+			widget.NewFormItem(tr("Provider"), syncProviderSelect),
+			widget.NewFormItem(tr("URL"), syncURLInput),
+			widget.NewFormItem(tr("User / access key"), syncUserInput),
+			widget.NewFormItem(tr("Password / token"), syncSecretInput),
+			widget.NewFormItem(tr("S3 region"), syncRegionInput),
+			widget.NewFormItem(tr("Encryption passphrase"), syncPassphraseInput),
+		),
+		syncNowBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Daily summary")),
+		daySummaryEnabled,
+		widget.NewForm(
+			widget.NewFormItem(tr("Send at"), daySummaryTimeSelect),
+			widget.NewFormItem(tr("Email to"), daySummaryEmailInput),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Work schedule (one block per line)")),
+		scheduleInput,
+		widget.NewLabel(tr("You're reminded to start a timer 15 minutes into a block.")),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Email reports")),
+		widget.NewForm(
+			widget.NewFormItem(tr("Mail server"), smtpServerInput),
+			widget.NewFormItem(tr("User"), smtpUserInput),
+			widget.NewFormItem(tr("Password"), smtpPasswordInput),
+			widget.NewFormItem(tr("From"), smtpFromInput),
+			widget.NewFormItem(tr("Send reports to"), reportEmailInput),
+		),
+		weeklyReportBtn,
+		widget.NewSeparator(),
+		widget.NewForm(widget.NewFormItem(tr("Time zone"), timeZoneInput)),
+		saveBtn,
+		widget.NewSeparator(),
+		autostartCheck,
+		urlSchemeCheck,
+		widget.NewForm(
+			widget.NewFormItem(tr("Storage"), container.NewVBox(storageSelect, forgetPassphraseBtn)),
+			widget.NewFormItem(tr("Show durations as"), container.NewVBox(durationFormatSelect, precisionModeCheck, barClockCheck)),
+			widget.NewFormItem(tr("Export locale"), exportLocaleSelect),
+			widget.NewFormItem(tr("Round exports to"), container.NewGridWithColumns(2, roundingStepSelect, roundingModeSelect)),
+			widget.NewFormItem(tr("Stop a forgotten timer at"), autoStopSelect),
+			widget.NewFormItem(tr("Ask if I'm still working after"), longSessionSelect),
+			widget.NewFormItem(tr("Pause if unanswered for"), longSessionWaitSelect),
+			widget.NewFormItem(tr("Adjust the clock by"), adjustStepSelect),
+			widget.NewFormItem(tr("Overlapping entries"), allowOverlapsCheck),
+			widget.NewFormItem(tr("Week starts on"), container.NewVBox(firstWeekdaySelect, isoWeeksCheck)),
+			widget.NewFormItem(tr("Fiscal year starts in"), fiscalYearSelect),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Invoices")),
+		createInvoiceTemplatesList(timer),
+		container.NewHBox(
+			widget.NewButton(tr("New template…"), func() {
+				showInvoiceTemplateDialog(timer, "")
+			}),
+			widget.NewButton(tr("Clients…"), func() {
+				showClientsDialog(timer)
+			}),
+			widget.NewButton(tr("Create invoice…"), func() {
+				showCreateInvoiceDialog(timer)
+			}),
+		),
+		container.NewHBox(
+			widget.NewButton(tr("Expenses…"), func() {
+				showExpensesDialog(timer)
+			}),
+			widget.NewButton(tr("Invoice Ledger…"), func() {
+				showInvoiceLedgerDialog(timer)
+			}),
+			widget.NewButton(tr("Earnings…"), func() {
+				showEarningsDialog(timer)
+			}),
+			widget.NewButton(tr("Accounting export…"), func() {
+				showAccountingExportDialog(timer)
+			}),
+		),
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Import")),
+		togglCSVBtn,
+		togglAPIBtn,
+		timewarriorBtn,
+		hamsterBtn,
+		widget.NewSeparator(),
+		widget.NewLabel(tr("Extensions")),
+		createExtensionsList(timer),
+		container.NewHBox(
+			widget.NewButton(tr("Install from file…"), func() {
+				showInstallExtensionFromFileDialog(timer)
+			}),
+			widget.NewButton(tr("Install from URL…"), func() {
+				showInstallExtensionFromURLDialog(timer)
+			}),
+		),
+	)
+}
+
+func showTogglCSVImportDialog(timer *TaskTimer) {
+	d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		entries, err := parseTogglCSV(reader)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		finishImport(timer, entries)
+	}, timer.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+	d.Show()
+}
+
+func showTogglAPIImportDialog(timer *TaskTimer) {
+	tokenInput := widget.NewPasswordEntry()
+	sinceInput := widget.NewEntry()
+	sinceInput.SetText(time.Now().AddDate(0, -1, 0).Format("2006-01-02"))
+	untilInput := widget.NewEntry()
+	untilInput.SetText(time.Now().Format("2006-01-02"))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("API token"), tokenInput),
+		widget.NewFormItem(tr("From"), sinceInput),
+		widget.NewFormItem(tr("To"), untilInput),
+	}
+	dialog.ShowForm(tr("Import from Toggl"), tr("Import"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		since, err := time.ParseInLocation("2006-01-02", sinceInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		until, err := time.ParseInLocation("2006-01-02", untilInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+
+		// Include the whole of the final day
+		until = until.AddDate(0, 0, 1)
+
+		go func() {
+			entries, err := fetchTogglEntries(tokenInput.Text, since, until)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				finishImport(timer, entries)
+			})
+		}()
+	}, timer.window)
+}
+
+// showTimewarriorImportDialog imports every monthly file in a Timewarrior
+// data folder, usually ~/.timewarrior/data.
+func showTimewarriorImportDialog(timer *TaskTimer) {
+	dialog.ShowFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if folder == nil {
+			return
+		}
+
+		files, err := folder.List()
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		var entries []Entry
+		for _, file := range files {
+			if file.Extension() != ".data" {
+				continue
+			}
+			parsed, err := readTimewarriorFile(file)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %w", file.Name(), err), timer.window)
+				return
+			}
+			entries = append(entries, parsed...)
+		}
+		if len(entries) == 0 {
+			dialog.ShowInformation(tr("Import"), tr("No Timewarrior data files were found in this folder."), timer.window)
+			return
+		}
+		finishImport(timer, entries)
+	}, timer.window)
+}
+
+func readTimewarriorFile(file fyne.URI) ([]Entry, error) {
+	reader, err := storage.Reader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return parseTimewarriorData(reader)
+}
+
+// showHamsterImportDialog imports a Hamster database. It reads a copy, so
+// the database may be anywhere the file dialog can reach and Hamster may
+// keep running.
+func showHamsterImportDialog(timer *TaskTimer) {
+	d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		entries, err := readHamsterCopy(reader)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		finishImport(timer, entries)
+	}, timer.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".db"}))
+	d.Show()
+}
+
+func readHamsterCopy(reader io.Reader) ([]Entry, error) {
+	copied, err := os.CreateTemp("", "hamster-*.db")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(copied.Name())
+	_, err = io.Copy(copied, reader)
+	if closeErr := copied.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return readHamsterDB(copied.Name())
+}
+
+func finishImport(timer *TaskTimer, entries []Entry) {
+	importEntries(timer, entries)
+	dialog.ShowInformation(tr("Import"), tr("Imported {{.Count}} entries.", map[string]any{"Count": len(entries)}), timer.window)
+}
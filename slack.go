@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefSlackEnabled = "slackEnabled"
+	// PrefSlackToken held the token before it moved to the keychain
+	PrefSlackToken       = "slackToken"
+	PrefSlackOptOutTasks = "slackOptOutTasks"
+
+	SlackProfileSetURL = "https://slack.com/api/users.profile.set"
+	SlackStatusEmoji   = ":stopwatch:"
+
+	// slackDefaultRetryWait is used when a 429 response has no Retry-After
+	slackDefaultRetryWait = 30 * time.Second
+)
+
+type slackProfile struct {
+	StatusText       string `json:"status_text"`
+	StatusEmoji      string `json:"status_emoji"`
+	StatusExpiration int64  `json:"status_expiration"`
+}
+
+// SlackStatus mirrors the running task into the user's Slack status. Updates
+// are sent by a single worker that only ever sends the latest wanted status,
+// so quick start/pause toggles collapse into one call and rate limiting is
+// handled in one place.
+type SlackStatus struct {
+	mu      sync.Mutex
+	desired *slackProfile
+	// statusSet is true while the last status we asked for is a non-empty one
+	statusSet bool
+	wake      chan struct{}
+}
+
+func NewSlackStatus() *SlackStatus {
+	s := &SlackStatus{wake: make(chan struct{}, 1)}
+	go s.run()
+	return s
+}
+
+// Working sets the status for a started task unless the task opted out.
+func (s *SlackStatus) Working(taskName string) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefSlackEnabled) || contains(prefs.StringList(PrefSlackOptOutTasks), taskName) {
+		s.Clear()
+		return
+	}
+
+	s.set(&slackProfile{
+		StatusText:  "Working on " + taskName,
+		StatusEmoji: SlackStatusEmoji,
+	})
+}
+
+// Clear removes a status previously set by Working.
+func (s *SlackStatus) Clear() {
+	s.mu.Lock()
+	statusSet := s.statusSet
+	s.mu.Unlock()
+
+	if statusSet {
+		s.set(&slackProfile{})
+	}
+}
+
+func (s *SlackStatus) set(profile *slackProfile) {
+	s.mu.Lock()
+	s.desired = profile
+	s.statusSet = profile.StatusText != ""
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *SlackStatus) run() {
+	for range s.wake {
+		for {
+			s.mu.Lock()
+			profile := s.desired
+			s.desired = nil
+			s.mu.Unlock()
+			if profile == nil {
+				break
+			}
+
+			retryAfter, err := postSlackProfile(profile)
+			if err != nil {
+				log.Print(err)
+			}
+			if retryAfter > 0 {
+				// Put the update back unless a newer one arrived meanwhile
+				s.mu.Lock()
+				if s.desired == nil {
+					s.desired = profile
+				}
+				s.mu.Unlock()
+				time.Sleep(retryAfter)
+			}
+		}
+	}
+}
+
+// postSlackProfile sends a profile update. A non-zero wait means Slack is
+// rate limiting and the call should be retried after it.
+func postSlackProfile(profile *slackProfile) (time.Duration, error) {
+	body, err := json.Marshal(map[string]*slackProfile{"profile": profile})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, SlackProfileSetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+keychainSecret(KeyringSlackToken))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := slackDefaultRetryWait
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return wait, fmt.Errorf("slack: rate limited, retrying in %s", wait)
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("slack: %w", err)
+	}
+	if !result.OK {
+		return 0, fmt.Errorf("slack: users.profile.set: %s", result.Error)
+	}
+	return 0, nil
+}
+
+// showSlackOptOutDialog picks the tasks that should never show in Slack.
+func showSlackOptOutDialog(timer *TaskTimer) {
+	prefs := fyne.CurrentApp().Preferences()
+	optedOut := prefs.StringList(PrefSlackOptOutTasks)
+
+	checks := widget.NewCheckGroup(timer.tasks.Tasks(), nil)
+	checks.SetSelected(optedOut)
+
+	content := container.NewBorder(
+		widget.NewLabel(tr("Don't update my Slack status for:")), nil, nil, nil,
+		container.NewVScroll(checks),
+	)
+	d := dialog.NewCustomConfirm(tr("Slack Status"), tr("Save"), tr("Cancel"), content, func(ok bool) {
+		if ok {
+			prefs.SetStringList(PrefSlackOptOutTasks, checks.Selected)
+		}
+	}, timer.window)
+	d.Resize(fyne.NewSize(350, 400))
+	d.Show()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefSMTPServer = "smtpServer"
+	PrefSMTPUser   = "smtpUser"
+	// PrefSMTPPassword held the password before it moved to the keychain
+	PrefSMTPPassword = "smtpPassword"
+	PrefSMTPFrom     = "smtpFrom"
+	PrefReportEmail  = "reportEmail"
+)
+
+var errSMTPNotConfigured = errors.New("set up the mail server and a recipient in Settings → Email reports")
+
+// smtpConfigured reports whether reports can be emailed.
+func smtpConfigured() bool {
+	prefs := fyne.CurrentApp().Preferences()
+	return prefs.String(PrefSMTPServer) != "" && prefs.String(PrefReportEmail) != ""
+}
+
+// sendReportEmail mails a report to the address set in settings through the
+// configured server, which is given as host:port. The server is expected to
+// offer STARTTLS, as on the usual submission port 587, before credentials
+// are sent.
+func sendReportEmail(subject, contentType, body string) error {
+	if !smtpConfigured() {
+		return errSMTPNotConfigured
+	}
+	prefs := fyne.CurrentApp().Preferences()
+	server := prefs.String(PrefSMTPServer)
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return fmt.Errorf("email: mail server %q: %w", server, err)
+	}
+
+	from := prefs.StringWithFallback(PrefSMTPFrom, prefs.String(PrefSMTPUser))
+	var to []string
+	for _, address := range strings.Split(prefs.String(PrefReportEmail), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			to = append(to, address)
+		}
+	}
+
+	var auth smtp.Auth
+	if user := prefs.String(PrefSMTPUser); user != "" {
+		auth = smtp.PlainAuth("", user, keychainSecret(KeyringSMTPPassword), host)
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: %s; charset=utf-8\r\n\r\n", contentType)
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(server, auth, from, to, []byte(message.String())); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	SoundPomodoro    = "pomodoro"
+	SoundDayTarget   = "dayTarget"
+	SoundLongSession = "longSession"
+
+	// PrefSoundPrefix keys the sound for each event: empty for none, the
+	// name of a bundled sound, or the path of a sound file
+	PrefSoundPrefix = "sound."
+
+	// PrefSoundVolume is the volume in percent
+	PrefSoundVolume = "soundVolume"
+
+	// PrefSoundMuteInMeetings keeps quiet while a calendar event is on
+	PrefSoundMuteInMeetings = "soundMuteInMeetings"
+
+	DefaultSoundVolume = 80
+
+	// SoundTimeout is how long a sound may play before it is stopped
+	SoundTimeout = 30 * time.Second
+
+	soundSampleRate = 22050
+)
+
+// SoundEvents are the events a sound can be played on, in the order the
+// settings list them.
+var SoundEvents = []string{SoundPomodoro, SoundDayTarget, SoundLongSession}
+
+// soundEventName names an event for the settings.
+func soundEventName(event string) string {
+	switch event {
+	case SoundPomodoro:
+		return tr("Pomodoro done")
+	case SoundDayTarget:
+		return tr("Daily target reached")
+	default:
+		return tr("Still Working?")
+	}
+}
+
+// tone is a note of a bundled sound, fading out over its length.
+type tone struct {
+	freq   float64
+	length time.Duration
+}
+
+// BundledSounds are the sounds that come with the app, by name.
+var BundledSounds = map[string][]tone{
+	"chime": {{880, 150 * time.Millisecond}, {1318.5, 400 * time.Millisecond}},
+	"bell":  {{660, 900 * time.Millisecond}},
+	"beep":  {{1000, 120 * time.Millisecond}, {0, 80 * time.Millisecond}, {1000, 120 * time.Millisecond}},
+}
+
+// bundledSoundNames lists the bundled sounds in the order they're offered.
+var bundledSoundNames = []string{"chime", "bell", "beep"}
+
+// bundledSoundLabel names a bundled sound for the settings.
+func bundledSoundLabel(name string) string {
+	switch name {
+	case "chime":
+		return tr("Chime")
+	case "bell":
+		return tr("Bell")
+	default:
+		return tr("Beep")
+	}
+}
+
+// soundWAV renders tones as a 16-bit mono WAV file at volume, 0 to 1.
+func soundWAV(tones []tone, volume float64) []byte {
+	var samples []int16
+	for _, t := range tones {
+		n := int(t.length.Seconds() * soundSampleRate)
+		for i := range n {
+			if t.freq == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			at := float64(i) / soundSampleRate
+			fade := math.Exp(-4 * float64(i) / float64(n))
+			// A quiet octave above gives the tone some ring
+			wave := math.Sin(2*math.Pi*t.freq*at) + 0.3*math.Sin(4*math.Pi*t.freq*at)
+			samples = append(samples, int16(wave/1.3*fade*volume*math.MaxInt16))
+		}
+	}
+
+	size := uint32(len(samples) * 2)
+	header := struct {
+		RIFF          [4]byte
+		Size          uint32
+		WAVE, Fmt     [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + size, [4]byte{'W', 'A', 'V', 'E'}, [4]byte{'f', 'm', 't', ' '},
+		16, 1, 1, soundSampleRate, soundSampleRate * 2, 2, 16,
+		[4]byte{'d', 'a', 't', 'a'}, size,
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
+
+// soundVolume returns the volume from 0 to 1.
+func soundVolume() float64 {
+	return float64(fyne.CurrentApp().Preferences().IntWithFallback(PrefSoundVolume, DefaultSoundVolume)) / 100
+}
+
+// playSound plays the sound chosen for an event, if any, unless a meeting
+// is on and sounds are muted for meetings.
+func playSound(timer *TaskTimer, event string) {
+	prefs := fyne.CurrentApp().Preferences()
+	sound := prefs.String(PrefSoundPrefix + event)
+	if sound == "" {
+		return
+	}
+	if prefs.Bool(PrefSoundMuteInMeetings) && timer.calendar.InMeeting(time.Now()) {
+		return
+	}
+	go func() {
+		if err := playSoundFile(sound, soundVolume()); err != nil {
+			log.Printf("sounds: %s: %v", event, err)
+		}
+	}()
+}
+
+// playSoundFile plays a bundled sound or a sound file through the
+// platform's player, waiting until it's done. Bundled sounds are rendered
+// at the volume; files are handed to players that take a volume.
+func playSoundFile(sound string, volume float64) error {
+	path := sound
+	if tones, ok := BundledSounds[sound]; ok {
+		file, err := os.CreateTemp("", "gotime-*.wav")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+		_, err = file.Write(soundWAV(tones, volume))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		path, volume = file.Name(), 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SoundTimeout)
+	defer cancel()
+	cmd, err := soundCommand(ctx, path, volume)
+	if err != nil {
+		return err
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// soundCommand returns the command playing a file on this platform.
+func soundCommand(ctx context.Context, path string, volume float64) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "afplay", "-v", fmt.Sprintf("%.2f", volume), path), nil
+	case "windows":
+		// SoundPlayer has no volume; it plays WAV files only
+		script := fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", strings.ReplaceAll(path, "'", "''"))
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script), nil
+	}
+	if _, err := exec.LookPath("paplay"); err == nil {
+		return exec.CommandContext(ctx, "paplay", fmt.Sprintf("--volume=%d", int(volume*65536)), path), nil
+	}
+	if _, err := exec.LookPath("aplay"); err == nil {
+		return exec.CommandContext(ctx, "aplay", "-q", path), nil
+	}
+	return nil, errors.New("no sound player found, install paplay or aplay")
+}
+
+// pomodoroDone reports whether the session passed the end of a Pomodoro
+// going from before to after.
+func pomodoroDone(before, after time.Duration) bool {
+	return after/PomodoroLength > before/PomodoroLength
+}
+
+// createSoundsContainer picks the sound for each event, from the bundled
+// ones or a file, along with the volume and muting during meetings.
+func createSoundsContainer(timer *TaskTimer) fyne.CanvasObject {
+	prefs := fyne.CurrentApp().Preferences()
+	form := widget.NewForm()
+	for _, event := range SoundEvents {
+		key := PrefSoundPrefix + event
+		soundSelect := widget.NewSelect(nil, nil)
+		// The choices are off, the bundled sounds and the file chosen, if any
+		var sounds []string
+		refresh := func() {
+			sounds = append([]string{""}, bundledSoundNames...)
+			labels := []string{tr("Off")}
+			for _, name := range bundledSoundNames {
+				labels = append(labels, bundledSoundLabel(name))
+			}
+			current := prefs.String(key)
+			if _, ok := BundledSounds[current]; !ok && current != "" {
+				sounds = append(sounds, current)
+				labels = append(labels, filepath.Base(current))
+			}
+			soundSelect.OnChanged = nil
+			soundSelect.SetOptions(labels)
+			for i, sound := range sounds {
+				if sound == current {
+					soundSelect.SetSelectedIndex(i)
+				}
+			}
+			soundSelect.OnChanged = func(string) {
+				prefs.SetString(key, sounds[soundSelect.SelectedIndex()])
+			}
+		}
+		refresh()
+
+		fileBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+			d := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				if reader == nil {
+					return
+				}
+				reader.Close()
+				prefs.SetString(key, reader.URI().Path())
+				refresh()
+			}, timer.window)
+			d.SetFilter(storage.NewExtensionFileFilter([]string{".wav", ".mp3", ".ogg", ".aiff"}))
+			d.Show()
+		})
+		playBtn := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+			sound := prefs.String(key)
+			if sound == "" {
+				return
+			}
+			go func() {
+				if err := playSoundFile(sound, soundVolume()); err != nil {
+					fyne.Do(func() {
+						dialog.ShowError(err, timer.window)
+					})
+				}
+			}()
+		})
+		form.Append(soundEventName(event), container.NewBorder(nil, nil, nil, container.NewHBox(fileBtn, playBtn), soundSelect))
+	}
+
+	volumeSlider := widget.NewSlider(0, 100)
+	volumeSlider.Step = 5
+	volumeSlider.SetValue(float64(prefs.IntWithFallback(PrefSoundVolume, DefaultSoundVolume)))
+	volumeSlider.OnChangeEnded = func(volume float64) {
+		prefs.SetInt(PrefSoundVolume, int(volume))
+	}
+	form.Append(tr("Volume"), volumeSlider)
+
+	muteCheck := widget.NewCheck(tr("Mute during calendar meetings"), nil)
+	muteCheck.SetChecked(prefs.Bool(PrefSoundMuteInMeetings))
+	muteCheck.OnChanged = func(muted bool) {
+		prefs.SetBool(PrefSoundMuteInMeetings, muted)
+	}
+
+	return container.NewVBox(form, muteCheck)
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations bring a database up to the current schema. The database's
+// user_version is the number of migrations applied, so new schema changes
+// are appended here and never edited once released.
+var sqliteMigrations = []string{
+	`CREATE TABLE entries (
+		id         TEXT PRIMARY KEY,
+		task       TEXT NOT NULL,
+		project    TEXT NOT NULL DEFAULT '',
+		client     TEXT NOT NULL DEFAULT '',
+		start_time TEXT NOT NULL,
+		end_time   TEXT NOT NULL,
+		duration   INTEGER NOT NULL,
+		notes      TEXT NOT NULL DEFAULT '',
+		tags       TEXT NOT NULL DEFAULT '[]'
+	);
+	CREATE INDEX entries_start_time ON entries (start_time);
+	CREATE TABLE tasks (
+		name     TEXT PRIMARY KEY,
+		position INTEGER NOT NULL,
+		archived INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE settings (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`,
+	`ALTER TABLE entries ADD COLUMN updated_at TEXT NOT NULL DEFAULT '';
+	CREATE TABLE tombstones (
+		id         TEXT PRIMARY KEY,
+		deleted_at TEXT NOT NULL
+	);`,
+	`ALTER TABLE entries ADD COLUMN non_billable INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE entries ADD COLUMN auto_stopped INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE entries ADD COLUMN adjustment INTEGER NOT NULL DEFAULT 0;`,
+	`CREATE TABLE audit_log (
+		seq      INTEGER PRIMARY KEY AUTOINCREMENT,
+		entry_id TEXT NOT NULL,
+		at       TEXT NOT NULL,
+		user     TEXT NOT NULL,
+		device   TEXT NOT NULL,
+		action   TEXT NOT NULL,
+		before   TEXT NOT NULL DEFAULT '',
+		after    TEXT NOT NULL DEFAULT '',
+		hash     TEXT NOT NULL
+	);
+	CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'the audit log is append-only'); END;
+	CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'the audit log is append-only'); END;`,
+	`ALTER TABLE entries ADD COLUMN review TEXT NOT NULL DEFAULT '';`,
+}
+
+// tombstoneLayout is fixed-width UTC, so deletion times compare correctly as
+// text in SQL.
+const tombstoneLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SQLiteStore keeps the data in a SQLite database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLiteStore opens or creates the database at path and migrates it to
+// the current schema.
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	// SQLite allows one writer at a time; a single connection avoids
+	// "database is locked" errors between our own goroutines
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("store: the database was written by a newer version of gotime (schema %d)", version)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("store: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("store: migration %d: %w", i+1, err)
+		}
+		// PRAGMA doesn't take bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("store: migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("store: migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Entries() ([]Entry, error) {
+	rows, err := s.db.Query(`SELECT id, task, project, client, start_time, end_time, duration, notes, tags, updated_at, non_billable, auto_stopped, adjustment, review
+		FROM entries ORDER BY start_time`)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var start, end, tags, updated string
+		if err := rows.Scan(&entry.ID, &entry.Task, &entry.Project, &entry.Client,
+			&start, &end, &entry.Duration, &entry.Notes, &tags, &updated, &entry.NonBillable, &entry.AutoStopped, &entry.Adjustment, &entry.Review); err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		if entry.Start, err = time.Parse(time.RFC3339Nano, start); err != nil {
+			return nil, fmt.Errorf("store: entry %s: %w", entry.ID, err)
+		}
+		if entry.End, err = time.Parse(time.RFC3339Nano, end); err != nil {
+			return nil, fmt.Errorf("store: entry %s: %w", entry.ID, err)
+		}
+		if err := json.Unmarshal([]byte(tags), &entry.Tags); err != nil {
+			return nil, fmt.Errorf("store: entry %s: %w", entry.ID, err)
+		}
+		// Entries written before schema 2 have no update time
+		if updated != "" {
+			if entry.Updated, err = time.Parse(time.RFC3339Nano, updated); err != nil {
+				return nil, fmt.Errorf("store: entry %s: %w", entry.ID, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *SQLiteStore) PutEntry(entry Entry) error {
+	tags, err := json.Marshal(entry.Tags)
+	if err != nil {
+		return err
+	}
+	var updated string
+	if !entry.Updated.IsZero() {
+		updated = entry.Updated.Format(time.RFC3339Nano)
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO entries
+		(id, task, project, client, start_time, end_time, duration, notes, tags, updated_at, non_billable, auto_stopped, adjustment, review)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.Task, entry.Project, entry.Client,
+		entry.Start.Format(time.RFC3339Nano), entry.End.Format(time.RFC3339Nano),
+		int64(entry.Duration), entry.Notes, string(tags), updated, entry.NonBillable, entry.AutoStopped, int64(entry.Adjustment), entry.Review)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteEntry(id string, deleted time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM entries WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO tombstones (id, deleted_at) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET deleted_at = max(deleted_at, excluded.deleted_at)`,
+		id, deleted.UTC().Format(tombstoneLayout)); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Tombstones() (map[string]time.Time, error) {
+	rows, err := s.db.Query(`SELECT id, deleted_at FROM tombstones`)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer rows.Close()
+
+	tombstones := make(map[string]time.Time)
+	for rows.Next() {
+		var id, deleted string
+		if err := rows.Scan(&id, &deleted); err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		if tombstones[id], err = time.Parse(time.RFC3339Nano, deleted); err != nil {
+			return nil, fmt.Errorf("store: tombstone %s: %w", id, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return tombstones, nil
+}
+
+// PutTombstones keeps the later deletion time of any entry deleted on both
+// sides.
+func (s *SQLiteStore) PutTombstones(tombstones map[string]time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id, deleted := range tombstones {
+		if _, err := tx.Exec(`INSERT INTO tombstones (id, deleted_at) VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET deleted_at = max(deleted_at, excluded.deleted_at)`,
+			id, deleted.UTC().Format(tombstoneLayout)); err != nil {
+			return fmt.Errorf("store: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) AuditLog() ([]AuditRecord, error) {
+	rows, err := s.db.Query(`SELECT entry_id, at, user, device, action, before, after, hash
+		FROM audit_log ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	defer rows.Close()
+
+	var records []AuditRecord
+	for rows.Next() {
+		var record AuditRecord
+		var at, before, after string
+		if err := rows.Scan(&record.EntryID, &at, &record.User, &record.Device, &record.Action, &before, &after, &record.Hash); err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+		if record.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+			return nil, fmt.Errorf("store: audit log: %w", err)
+		}
+		if record.Before, err = unmarshalAuditEntry(before); err != nil {
+			return nil, fmt.Errorf("store: audit log: %w", err)
+		}
+		if record.After, err = unmarshalAuditEntry(after); err != nil {
+			return nil, fmt.Errorf("store: audit log: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return records, nil
+}
+
+func (s *SQLiteStore) AppendAudit(record AuditRecord) error {
+	before, err := auditEntryJSON(record.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditEntryJSON(record.After)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO audit_log (entry_id, at, user, device, action, before, after, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.EntryID, record.At.Format(time.RFC3339Nano), record.User, record.Device, record.Action, before, after, record.Hash)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+// unmarshalAuditEntry reads an entry as written by auditEntryJSON.
+func unmarshalAuditEntry(raw string) (*Entry, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var entry Entry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *SQLiteStore) Tasks() (tasks, archived []string, err error) {
+	rows, err := s.db.Query(`SELECT name, archived FROM tasks ORDER BY position`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("store: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var isArchived bool
+		if err := rows.Scan(&name, &isArchived); err != nil {
+			return nil, nil, fmt.Errorf("store: %w", err)
+		}
+		tasks = append(tasks, name)
+		if isArchived {
+			archived = append(archived, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("store: %w", err)
+	}
+	return tasks, archived, nil
+}
+
+// SetTasks replaces the whole list, which is short enough that tracking
+// individual changes isn't worth it.
+func (s *SQLiteStore) SetTasks(tasks, archived []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM tasks`); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	for i, name := range tasks {
+		if _, err := tx.Exec(`INSERT INTO tasks (name, position, archived) VALUES (?, ?, ?)`,
+			name, i, contains(archived, name)); err != nil {
+			return fmt.Errorf("store: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Setting(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("store: %w", err)
+	}
+	return value, nil
+}
+
+func (s *SQLiteStore) SetSetting(key, value string) error {
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)`, key, value); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
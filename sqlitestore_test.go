@@ -0,0 +1,65 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSQLiteAutoStopped round-trips the AutoStopped flag, including through
+// the migration that added it.
+func TestSQLiteAutoStopped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotime.db")
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+
+	// A database from before the flag, with an entry in it
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const before = 3
+	for _, migration := range sqliteMigrations[:before] {
+		if _, err := db.Exec(migration); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", before)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO entries (id, task, start_time, end_time, duration) VALUES ('old', 'Code', ?, ?, ?)`,
+		start.Format(time.RFC3339Nano), start.Add(time.Hour).Format(time.RFC3339Nano), int64(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := Entry{ID: "stopped", Task: "Code", Start: start.Add(2 * time.Hour), End: start.Add(9 * time.Hour), Duration: 7 * time.Hour, Updated: start, AutoStopped: true}
+	if err := store.PutEntry(stopped); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"old": false, "stopped": true}
+	if len(entries) != len(want) {
+		t.Fatalf("loaded %d entries, want %d", len(entries), len(want))
+	}
+	for _, entry := range entries {
+		if entry.AutoStopped != want[entry.ID] {
+			t.Errorf("entry %s loaded with AutoStopped %v, want %v", entry.ID, entry.AutoStopped, want[entry.ID])
+		}
+	}
+}
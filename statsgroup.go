@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	PrefStatsGroup = "statsGroup"
+
+	StatsGroupTask    = "task"
+	StatsGroupProject = "project"
+	StatsGroupClient  = "client"
+	StatsGroupTag     = "tag"
+	StatsGroupWeekday = "weekday"
+)
+
+// StatsGroup is what the stats view can roll task totals up by.
+type StatsGroup struct {
+	Key string
+	// Label is the English name, translated where it's shown
+	Label string
+	// Groups returns the groups an entry counts towards, nil when tasks
+	// aren't grouped. An entry with several tags counts towards each.
+	Groups func(entry Entry) []string
+}
+
+var statsGroups = []StatsGroup{
+	{Key: StatsGroupTask, Label: "Task"},
+	{
+		Key:   StatsGroupProject,
+		Label: "Project",
+		Groups: func(entry Entry) []string {
+			if entry.Project == "" {
+				return []string{tr("No project")}
+			}
+			return []string{entry.Project}
+		},
+	},
+	{
+		Key:   StatsGroupClient,
+		Label: "Client",
+		Groups: func(entry Entry) []string {
+			if entry.Client == "" {
+				return []string{tr("No client")}
+			}
+			return []string{entry.Client}
+		},
+	},
+	{
+		Key:   StatsGroupTag,
+		Label: "Tag",
+		Groups: func(entry Entry) []string {
+			if len(entry.Tags) == 0 {
+				return []string{tr("No tag")}
+			}
+			return entry.Tags
+		},
+	},
+	{
+		Key:   StatsGroupWeekday,
+		Label: "Weekday",
+		Groups: func(entry Entry) []string {
+			return []string{weekdayName(entry.Start.Weekday())}
+		},
+	},
+}
+
+func statsGroupLabels() []string {
+	labels := make([]string, len(statsGroups))
+	for i, g := range statsGroups {
+		labels[i] = tr(g.Label)
+	}
+	return labels
+}
+
+// statsGroupByKey returns the grouping saved under key, defaulting to none.
+func statsGroupByKey(key string) StatsGroup {
+	for _, g := range statsGroups {
+		if g.Key == key {
+			return g
+		}
+	}
+	return statsGroups[0]
+}
+
+// statsGroupByLabel finds a grouping by its translated label.
+func statsGroupByLabel(label string) StatsGroup {
+	for _, g := range statsGroups {
+		if tr(g.Label) == label {
+			return g
+		}
+	}
+	return statsGroups[0]
+}
+
+// groupEntries sorts entries into their groups.
+func groupEntries(entries []Entry, group StatsGroup) map[string][]Entry {
+	groups := make(map[string][]Entry)
+	for _, entry := range entries {
+		for _, name := range group.Groups(entry) {
+			groups[name] = append(groups[name], entry)
+		}
+	}
+	return groups
+}
+
+// orderGroups lists the groups largest first, apart from weekdays, which
+// follow the week.
+func orderGroups(groups map[string][]Entry, group StatsGroup) []string {
+	if group.Key == StatsGroupWeekday {
+		var names []string
+		for i := range 7 {
+			name := weekdayName((firstWeekday() + time.Weekday(i)) % 7)
+			if _, ok := groups[name]; ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	totals := make(map[string]time.Duration)
+	for name, entries := range groups {
+		for _, entry := range entries {
+			totals[name] += entry.Duration
+		}
+	}
+	return sortedTaskNames(totals)
+}
+
+// newStatsGroups lists each group with its total, opening to the totals of
+// the tasks in it, in the chosen order.
+func newStatsGroups(timer *TaskTimer, entries []Entry, group StatsGroup, order StatsSort) fyne.CanvasObject {
+	var shown time.Duration
+	for _, entry := range entries {
+		shown += entry.Duration
+	}
+	groups := groupEntries(entries, group)
+	accordion := widget.NewAccordion()
+	accordion.MultiOpen = true
+	for _, name := range orderGroups(groups, group) {
+		totals := totalsByTask(groups[name])
+		var total time.Duration
+		for _, duration := range totals {
+			total += duration
+		}
+
+		rows := container.NewVBox()
+		for _, taskName := range order.Sort(totals, groups[name]) {
+			rows.Add(newStatsRow(timer, taskName, totals[taskName], shown))
+		}
+		accordion.Append(widget.NewAccordionItem(fmt.Sprintf("%s: %s  %s", name, formatDuration(total), formatShare(total, shown)), rows))
+	}
+	return accordion
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// statsRow is a task line in the stats view. Right-click or long-press opens
+// a menu of actions for that task, as does the button beside it, which also
+// makes the menu reachable from the keyboard.
+type statsRow struct {
+	widget.Label
+	timer    *TaskTimer
+	taskName string
+}
+
+// newStatsRow shows a task's time and its share of total, the time of every
+// task shown.
+func newStatsRow(timer *TaskTimer, taskName string, duration, total time.Duration) fyne.CanvasObject {
+	style := taskStyles(timer)[taskName]
+	row := &statsRow{timer: timer, taskName: taskName}
+	row.Text = fmt.Sprintf("%s: %s  %s", style.Label(taskName), formatDuration(duration), formatShare(duration, total))
+	row.ExtendBaseWidget(row)
+
+	// A stripe in the task's color, blank for tasks without one
+	stripe := canvas.NewRectangle(color.Transparent)
+	if fill, ok := style.Fill(); ok {
+		stripe.FillColor = fill
+	}
+	stripe.SetMinSize(fyne.NewSize(theme.Padding(), 0))
+
+	var actionsBtn *widget.Button
+	actionsBtn = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(actionsBtn)
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(actionsBtn)
+		row.showMenu(canvas, position.AddXY(0, actionsBtn.Size().Height))
+	})
+	return container.NewBorder(nil, nil, stripe, actionsBtn, row)
+}
+
+func (r *statsRow) TappedSecondary(e *fyne.PointEvent) {
+	r.showMenu(fyne.CurrentApp().Driver().CanvasForObject(r), e.AbsolutePosition)
+}
+
+func (r *statsRow) showMenu(canvas fyne.Canvas, position fyne.Position) {
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(tr("Start timer"), func() { startTask(r.timer, r.taskName) }),
+		fyne.NewMenuItem(tr("View entries"), func() { showEntriesDialog(r.timer, r.taskName) }),
+		fyne.NewMenuItem(tr("Edit"), func() { showEditTaskDialog(r.timer, r.taskName, nil) }),
+		fyne.NewMenuItem(tr("Merge"), func() { showMergeTaskDialog(r.timer, r.taskName, nil) }),
+		r.exportMenuItem(),
+	)
+	if issue, ok := githubIssueForTask(r.taskName); ok {
+		menu.Items = append(menu.Items, fyne.NewMenuItem(tr("Open GitHub issue"), func() {
+			if err := fyne.CurrentApp().OpenURL(issue.URL()); err != nil {
+				dialog.ShowError(err, r.timer.window)
+			}
+		}))
+	}
+	widget.ShowPopUpMenuAtPosition(menu, canvas, position)
+}
+
+// exportMenuItem offers CSV, Excel, iCalendar and every installed exporter
+// extension.
+func (r *statsRow) exportMenuItem() *fyne.MenuItem {
+	item := fyne.NewMenuItem(tr("Export"), nil)
+	item.ChildMenu = fyne.NewMenu("",
+		fyne.NewMenuItem("CSV", func() { showExportTaskDialog(r.timer, r.taskName) }),
+		fyne.NewMenuItem("Excel (XLSX)", func() {
+			saveExport(r.timer, r.taskName+".xlsx", func(w io.Writer) error {
+				return writeEntriesXLSX(w, roundEntries(entriesForTask(r.timer, r.taskName)), currentExportLocale())
+			})
+		}),
+		fyne.NewMenuItem("iCalendar (ICS)", func() {
+			saveExport(r.timer, r.taskName+".ics", func(w io.Writer) error {
+				return writeEntriesICS(w, entriesForTask(r.timer, r.taskName), currentExportLocale())
+			})
+		}),
+	)
+	exporters := installedExporters()
+	for _, exporter := range exporters {
+		item.ChildMenu.Items = append(item.ChildMenu.Items, fyne.NewMenuItem(exporter.Name, func() {
+			saveExport(r.timer, r.taskName+exporter.Extension, func(w io.Writer) error {
+				return exporter.Render(w, r.taskName, roundEntries(entriesForTask(r.timer, r.taskName)))
+			})
+		}))
+	}
+	return item
+}
+
+// startTask switches the timer to a task and starts it, recording any session
+// in progress on another task first, and shows the timer.
+func startTask(timer *TaskTimer, taskName string) {
+	switchTask(timer, taskName)
+	showView(timer, "timer")
+}
+
+// switchTask is startTask without leaving the current view.
+func switchTask(timer *TaskTimer, taskName string) {
+	if timer.taskName != taskName {
+		stopSession(timer)
+		selectTask(timer, taskName)
+	}
+
+	if !timer.isRunning {
+		resumeTimer(timer)
+	}
+}
+
+// selectTask puts a task on the timer, adding it or bringing it back from
+// the archive as needed.
+func selectTask(timer *TaskTimer, taskName string) {
+	timer.tasks.Ensure(taskName)
+	if timer.tasks.IsArchived(taskName) {
+		timer.tasks.SetArchived(taskName, false)
+	}
+	timer.taskPicker.SetSelected(taskName)
+}
+
+func showEntriesDialog(timer *TaskTimer, taskName string) {
+	entryList := container.NewVBox()
+	entries := entriesForTask(timer, taskName)
+	if len(entries) == 0 {
+		entryList.Add(widget.NewLabel(tr("No entries recorded")))
+	}
+
+	// Newest first
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		entryList.Add(widget.NewLabel(fmt.Sprintf("%s – %s  %s",
+			formatDayTime(entry.Start),
+			entry.End.Format("15:04"),
+			formatDuration(entry.Duration),
+		)))
+	}
+
+	d := dialog.NewCustom(taskName, tr("Close"), container.NewVScroll(entryList), timer.window)
+	d.Resize(fyne.NewSize(350, 400))
+	d.Show()
+}
+
+// showEditTaskDialog renames a task and sets its estimate, style and whether
+// its new entries are billable. saved, if not nil, is called after the
+// changes are made.
+func showEditTaskDialog(timer *TaskTimer, taskName string, saved func()) {
+	nameInput := widget.NewEntry()
+	nameInput.SetText(taskName)
+	estimateInput := widget.NewEntry()
+	estimateInput.PlaceHolder = tr("Hours, blank for none")
+	if estimate, ok := taskEstimates(timer)[taskName]; ok {
+		estimateInput.SetText(formatHours(estimate))
+	}
+
+	style := taskStyles(timer)[taskName]
+	iconInput := widget.NewEntry()
+	iconInput.PlaceHolder = tr("Emoji, blank for none")
+	iconInput.SetText(style.Icon)
+	colorNames := []string{tr("No color")}
+	for _, key := range TaskColors {
+		colorNames = append(colorNames, taskColorName(key))
+	}
+	colorSelect := widget.NewSelect(colorNames, nil)
+	colorSelect.SetSelectedIndex(slices.Index(TaskColors, style.Color) + 1)
+	billableCheck := widget.NewCheck(tr("New entries are billable"), nil)
+	billableCheck.SetChecked(!isInternalTask(taskName))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Name"), nameInput),
+		widget.NewFormItem(tr("Estimate"), estimateInput),
+		widget.NewFormItem(tr("Icon"), iconInput),
+		widget.NewFormItem(tr("Color"), colorSelect),
+		widget.NewFormItem("", billableCheck),
+	}
+	dialog.ShowForm(tr("Edit Task"), tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var estimate time.Duration
+		if strings.TrimSpace(estimateInput.Text) != "" {
+			var err error
+			estimate, err = parseHours(estimateInput.Text)
+			if err != nil || estimate <= 0 {
+				dialog.ShowInformation(tr("Edit Task"), tr("Enter the estimate as a number of hours."), timer.window)
+				return
+			}
+		}
+		// Set before renaming, which carries the estimate and style over
+		setTaskEstimate(timer, taskName, estimate)
+		style := TaskStyle{Icon: iconInput.Text}
+		if i := colorSelect.SelectedIndex(); i > 0 {
+			style.Color = TaskColors[i-1]
+		}
+		setTaskStyle(timer, taskName, style)
+		setTaskInternal(taskName, !billableCheck.Checked)
+
+		newName := nameInput.Text
+		if newName != "" && newName != NoTaskSelected && newName != tr(NoTaskSelected) {
+			renameTask(timer, taskName, newName)
+		}
+		if saved != nil {
+			saved()
+		}
+	}, timer.window)
+}
+
+// showMergeTaskDialog moves the entries of several tasks into one, e.g.
+// "emails" and "E-mail" into "Email". Tasks whose names only differ from
+// taskName in case, punctuation or a plural start ticked, and the preview
+// adds up what each brings. merged, if not nil, is called after the merge.
+func showMergeTaskDialog(timer *TaskTimer, taskName string, merged func()) {
+	totals := snapshotTaskTotals(timer)
+	counts := make(map[string]int)
+	for _, entry := range allEntries(timer) {
+		counts[entry.Task]++
+	}
+	var others, duplicates []string
+	for _, other := range timer.tasks.Tasks() {
+		if _, ok := totals[other]; !ok {
+			totals[other] = 0
+		}
+	}
+	for other := range totals {
+		if other == taskName {
+			continue
+		}
+		others = append(others, other)
+		if taskNameKey(other) == taskNameKey(taskName) {
+			duplicates = append(duplicates, other)
+		}
+	}
+	if len(others) == 0 {
+		dialog.ShowInformation(tr("Merge"), tr("There are no other tasks to merge into."), timer.window)
+		return
+	}
+	slices.Sort(others)
+
+	intoInput := widget.NewSelectEntry(nil)
+	intoInput.SetText(taskName)
+	preview := widget.NewLabel("")
+	othersCheck := widget.NewCheckGroup(others, nil)
+	othersCheck.SetSelected(duplicates)
+
+	chosen := func() []string {
+		return append([]string{taskName}, othersCheck.Selected...)
+	}
+	updatePreview := func() {
+		var lines []string
+		var total time.Duration
+		var entries int
+		for _, task := range chosen() {
+			lines = append(lines, tr("{{.Task}}: {{.Duration}}, {{.Count}} entries", map[string]any{
+				"Task":     task,
+				"Duration": formatDuration(totals[task]),
+				"Count":    counts[task],
+			}))
+			total += totals[task]
+			entries += counts[task]
+		}
+		lines = append(lines, tr("Together: {{.Duration}}, {{.Count}} entries", map[string]any{
+			"Duration": formatDuration(total),
+			"Count":    entries,
+		}))
+		preview.SetText(strings.Join(lines, "\n"))
+		intoInput.SetOptions(chosen())
+	}
+	othersCheck.OnChanged = func([]string) {
+		updatePreview()
+	}
+	updatePreview()
+
+	othersScroll := container.NewVScroll(othersCheck)
+	othersScroll.SetMinSize(fyne.NewSize(0, 160))
+	content := container.NewVBox(
+		widget.NewLabel(tr("Merge \"{{.Task}}\" with:", map[string]any{"Task": taskName})),
+		othersScroll,
+		widget.NewForm(widget.NewFormItem(tr("Into"), intoInput)),
+		preview,
+	)
+	d := dialog.NewCustomConfirm(tr("Merge Tasks"), tr("Merge"), tr("Cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		target := strings.TrimSpace(intoInput.Text)
+		if target == "" || target == NoTaskSelected || target == tr(NoTaskSelected) {
+			dialog.ShowInformation(tr("Merge Tasks"), tr("Choose the task to merge into."), timer.window)
+			return
+		}
+		var sources []string
+		for _, task := range chosen() {
+			if task != target {
+				sources = append(sources, task)
+			}
+		}
+		if len(sources) == 0 {
+			return
+		}
+		changeEntries(timer, tr("Merged into {{.Task}}", map[string]any{"Task": target}), func() error {
+			for _, task := range sources {
+				renameTask(timer, task, target)
+			}
+			return nil
+		})
+		if merged != nil {
+			merged()
+		}
+	}, timer.window)
+	d.Resize(fyne.NewSize(380, 0))
+	d.Show()
+}
+
+func showExportTaskDialog(timer *TaskTimer, taskName string) {
+	saveExport(timer, taskName+".csv", func(w io.Writer) error {
+		return writeEntriesCSV(w, roundEntries(entriesForTask(timer, taskName)), currentExportLocale())
+	})
+}
+
+// saveExport asks where to save a file and writes it with write.
+func saveExport(timer *TaskTimer, fileName string, write func(io.Writer) error) {
+	d := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		if err := write(writer); err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	}, timer.window)
+	d.SetFileName(fileName)
+	d.Show()
+}
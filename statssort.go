@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	PrefStatsSort = "statsSort"
+
+	StatsSortDuration = "duration"
+	StatsSortName     = "name"
+	StatsSortRecent   = "recent"
+)
+
+// StatsSort is an order the stats view can list tasks in.
+type StatsSort struct {
+	Key string
+	// Label is the English name, translated where it's shown
+	Label string
+	// Sort orders the tasks in totals, given the entries they were summed from
+	Sort func(totals map[string]time.Duration, entries []Entry) []string
+}
+
+var statsSorts = []StatsSort{
+	{
+		Key:   StatsSortDuration,
+		Label: "Duration",
+		Sort: func(totals map[string]time.Duration, _ []Entry) []string {
+			return sortedTaskNames(totals)
+		},
+	},
+	{
+		Key:   StatsSortName,
+		Label: "Name",
+		Sort: func(totals map[string]time.Duration, _ []Entry) []string {
+			names := make([]string, 0, len(totals))
+			for name := range totals {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				return strings.ToLower(names[i]) < strings.ToLower(names[j])
+			})
+			return names
+		},
+	},
+	{
+		Key:   StatsSortRecent,
+		Label: "Recently used",
+		Sort: func(totals map[string]time.Duration, entries []Entry) []string {
+			lastUsed := make(map[string]time.Time)
+			for _, entry := range entries {
+				if entry.End.After(lastUsed[entry.Task]) {
+					lastUsed[entry.Task] = entry.End
+				}
+			}
+
+			names := make([]string, 0, len(totals))
+			for name := range totals {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				if !lastUsed[names[i]].Equal(lastUsed[names[j]]) {
+					return lastUsed[names[i]].After(lastUsed[names[j]])
+				}
+				return names[i] < names[j]
+			})
+			return names
+		},
+	},
+}
+
+func statsSortLabels() []string {
+	labels := make([]string, len(statsSorts))
+	for i, s := range statsSorts {
+		labels[i] = tr(s.Label)
+	}
+	return labels
+}
+
+// statsSortByKey returns the sort saved under key, defaulting to duration.
+func statsSortByKey(key string) StatsSort {
+	for _, s := range statsSorts {
+		if s.Key == key {
+			return s
+		}
+	}
+	return statsSorts[0]
+}
+
+// statsSortByLabel finds a sort by its translated label.
+func statsSortByLabel(label string) StatsSort {
+	for _, s := range statsSorts {
+		if tr(s.Label) == label {
+			return s
+		}
+	}
+	return statsSorts[0]
+}
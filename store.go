@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefStorageBackend picks where tasks and entries are kept. It lives in
+	// the app preferences because it's needed before the store is opened.
+	PrefStorageBackend = "storageBackend"
+	// PrefStorageMovedFrom is the backend to move the data out of at the
+	// next start
+	PrefStorageMovedFrom = "storageMovedFrom"
+
+	StorageSQLite    = "sqlite"
+	StorageJSON      = "json"
+	StorageEncrypted = "encrypted"
+)
+
+// Store keeps the tracked data: entries, the task list and settings. SQLite
+// is the default; the JSON file is easier to inspect and sync by hand. Other
+// backends, such as Postgres or a cloud service, only need to implement this
+// interface and be added to openStore.
+type Store interface {
+	// Entries returns every recorded entry.
+	Entries() ([]Entry, error)
+	// PutEntry adds an entry, or replaces the one with the same ID.
+	PutEntry(entry Entry) error
+	// DeleteEntry removes an entry and leaves a tombstone dated deleted, so
+	// sync can tell an entry deleted here from one this device never had. A
+	// later tombstone for the same entry is kept.
+	DeleteEntry(id string, deleted time.Time) error
+	// Tombstones returns when each deleted entry was deleted, and
+	// PutTombstones adds ones recorded elsewhere.
+	Tombstones() (map[string]time.Time, error)
+	PutTombstones(tombstones map[string]time.Time) error
+	// AuditLog returns the record of entry changes, oldest first, and
+	// AppendAudit adds to it. Records are never changed or removed.
+	AuditLog() ([]AuditRecord, error)
+	AppendAudit(record AuditRecord) error
+
+	// Tasks returns the task names in the order they were added, and the
+	// archived ones among them.
+	Tasks() (tasks, archived []string, err error)
+	SetTasks(tasks, archived []string) error
+
+	// Setting returns "" for a key that was never set.
+	Setting(key string) (string, error)
+	SetSetting(key, value string) error
+
+	Close() error
+}
+
+// storageBackends maps each backend to its file in the app's storage folder.
+// Only the encrypted backend uses the passphrase.
+var storageBackends = map[string]struct {
+	file string
+	open func(path, passphrase string) (Store, error)
+}{
+	StorageSQLite: {"gotime.db", func(path, _ string) (Store, error) { return OpenSQLiteStore(path) }},
+	StorageJSON:   {"gotime.json", func(path, _ string) (Store, error) { return OpenJSONStore(path, "") }},
+	StorageEncrypted: {"gotime.enc", func(path, passphrase string) (Store, error) {
+		if passphrase == "" {
+			return nil, errWrongPassphrase
+		}
+		return OpenJSONStore(path, passphrase)
+	}},
+}
+
+func storageBackend(prefs fyne.Preferences) string {
+	backend := prefs.StringWithFallback(PrefStorageBackend, StorageSQLite)
+	if _, ok := storageBackends[backend]; !ok {
+		return StorageSQLite
+	}
+	return backend
+}
+
+// openStore opens the configured backend. After the backend was switched in
+// Settings, the data is moved over from the previous one and its file is
+// deleted, so no stale or unencrypted copy is left behind. Until the move
+// succeeds, the previous backend stays in use.
+func openStore(app fyne.App, passphrase string) (Store, error) {
+	dir := app.Storage().RootURI().Path()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+
+	prefs := app.Preferences()
+	backend := storageBackend(prefs)
+	store, err := storageBackends[backend].open(filepath.Join(dir, storageBackends[backend].file), passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, ok := storageBackends[prefs.String(PrefStorageMovedFrom)]
+	if !ok || prefs.String(PrefStorageMovedFrom) == backend {
+		return store, nil
+	}
+	previousPath := filepath.Join(dir, previous.file)
+	if _, err := os.Stat(previousPath); err == nil {
+		if err := copyFromBackend(store, previous.open, previousPath, passphrase); err != nil {
+			// The move is retried at the next start, from the previous
+			// backend with whatever is recorded there until then. Anything
+			// recorded in the new one would be lost when its stale copy is
+			// overwritten.
+			log.Printf("store: moving data from %s: %v", prefs.String(PrefStorageMovedFrom), err)
+			store.Close()
+			return previous.open(previousPath, passphrase)
+		}
+		if err := os.Remove(previousPath); err != nil {
+			log.Printf("store: %v", err)
+		}
+		if prefs.String(PrefStorageMovedFrom) == StorageEncrypted {
+			forgetPassphrase()
+		}
+	}
+	prefs.RemoveValue(PrefStorageMovedFrom)
+	return store, nil
+}
+
+// needsPassphrase reports whether opening the store involves the encrypted
+// backend, either as the current one or as the one data is moved from.
+func needsPassphrase(prefs fyne.Preferences) bool {
+	return storageBackend(prefs) == StorageEncrypted || prefs.String(PrefStorageMovedFrom) == StorageEncrypted
+}
+
+// switchStorageBackend picks the backend used from the next start.
+func switchStorageBackend(prefs fyne.Preferences, backend string) {
+	// Until the app restarts, the running backend is the one to move from
+	running := prefs.StringWithFallback(PrefStorageMovedFrom, storageBackend(prefs))
+	prefs.SetString(PrefStorageBackend, backend)
+	if backend == running {
+		prefs.RemoveValue(PrefStorageMovedFrom)
+	} else {
+		prefs.SetString(PrefStorageMovedFrom, running)
+	}
+}
+
+func copyFromBackend(dst Store, open func(path, passphrase string) (Store, error), path, passphrase string) error {
+	src, err := open(path, passphrase)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	entries, err := src.Entries()
+	if err != nil {
+		return err
+	}
+	// dst may hold an older copy from when it was last used
+	stale, err := dst.Entries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range stale {
+		if entryIndex(entries, entry.ID) < 0 {
+			if err := dst.DeleteEntry(entry.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, entry := range entries {
+		if err := dst.PutEntry(entry); err != nil {
+			return err
+		}
+	}
+	tombstones, err := src.Tombstones()
+	if err != nil {
+		return err
+	}
+	if err := dst.PutTombstones(tombstones); err != nil {
+		return err
+	}
+	// dst's log, if it has one, is the start of src's, which carried on
+	// from it
+	audit, err := src.AuditLog()
+	if err != nil {
+		return err
+	}
+	copied, err := dst.AuditLog()
+	if err != nil {
+		return err
+	}
+	for _, record := range audit[min(len(copied), len(audit)):] {
+		if err := dst.AppendAudit(record); err != nil {
+			return err
+		}
+	}
+	tasks, archived, err := src.Tasks()
+	if err != nil {
+		return err
+	}
+	return dst.SetTasks(tasks, archived)
+}
+
+// loadEntries reads the stored entries into the timer and its totals. It
+// runs in the background while the window is already up, so sessions
+// recorded in the meantime are kept rather than overwritten.
+func loadEntries(timer *TaskTimer) error {
+	entries, err := timer.store.Entries()
+	if err != nil {
+		return err
+	}
+
+	timer.taskListMutex.Lock()
+	defer timer.taskListMutex.Unlock()
+
+	recorded := timer.entries
+	timer.entries = make([]Entry, 0, len(entries)+len(recorded))
+	for _, entry := range entries {
+		if entryIndex(recorded, entry.ID) < 0 {
+			timer.entries = append(timer.entries, entry)
+			addToTotalsLocked(timer, entry, 1)
+		}
+	}
+	timer.entries = append(timer.entries, recorded...)
+	publishHistoryLocked(timer)
+	return nil
+}
+
+// loadEntriesAsync loads the history in the background, then shows the
+// views that were waiting for it. Only the timer view is built before the
+// window opens, so it appears straight away even with a long history.
+func loadEntriesAsync(timer *TaskTimer) {
+	go func() {
+		err := loadEntries(timer)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+			}
+			timer.loaded = true
+			updateContentView(timer)
+			maybePromptWeeklyReview(timer)
+			go timer.cloudSync.Run()
+			go timer.teamSync.Run()
+		})
+	}()
+}
+
+// createLoadingContainer stands in for views that need the history while
+// it's still loading.
+func createLoadingContainer() *fyne.Container {
+	return container.NewVBox(
+		widget.NewProgressBarInfinite(),
+		widget.NewLabel(tr("Loading history…")),
+	)
+}
+
+// storeEntry and unstoreEntry write entry changes through to the store.
+// Failures are logged rather than returned: the change is already in memory
+// and the next write of the entry retries it.
+func storeEntry(timer *TaskTimer, entry Entry) {
+	if err := timer.store.PutEntry(entry); err != nil {
+		log.Printf("store: saving entry: %v", err)
+	}
+}
+
+func unstoreEntry(timer *TaskTimer, id string, deleted time.Time) {
+	if err := timer.store.DeleteEntry(id, deleted); err != nil {
+		log.Printf("store: deleting entry: %v", err)
+	}
+}
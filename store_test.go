@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// failingStore can't take entries, like a disk that filled up mid-move.
+type failingStore struct{ Store }
+
+func (failingStore) PutEntry(Entry) error { return errors.New("disk full") }
+
+func TestOpenStoreRetriesFailedMove(t *testing.T) {
+	app := test.NewTempApp(t)
+	prefs := app.Preferences()
+	dir := app.Storage().RootURI().Path()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	entry := func(id string) Entry {
+		return Entry{ID: id, Task: "Writing", Start: start, Duration: time.Hour, Updated: start}
+	}
+
+	jsonPath := filepath.Join(dir, storageBackends[StorageJSON].file)
+	old, err := OpenJSONStore(jsonPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := old.PutEntry(entry("before")); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+	prefs.SetString(PrefStorageBackend, StorageJSON)
+	switchStorageBackend(prefs, StorageSQLite)
+
+	sqlite := storageBackends[StorageSQLite]
+	broken := sqlite
+	broken.open = func(path, passphrase string) (Store, error) {
+		store, err := sqlite.open(path, passphrase)
+		return failingStore{store}, err
+	}
+	storageBackends[StorageSQLite] = broken
+	t.Cleanup(func() { storageBackends[StorageSQLite] = sqlite })
+
+	store, err := openStore(app, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.(*JSONStore); !ok {
+		t.Fatalf("after a failed move the store is a %T, want the previous *JSONStore", store)
+	}
+	// Recorded while the move waits for the next start
+	if err := store.PutEntry(entry("during")); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	storageBackends[StorageSQLite] = sqlite
+	store, err = openStore(app, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	entries, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entryIDs(entries); len(got) != 2 || got[0] != "before" || got[1] != "during" {
+		t.Errorf("entries after the retried move = %v, want [before during]", got)
+	}
+	if prefs.String(PrefStorageMovedFrom) != "" {
+		t.Error("the move is still pending after it succeeded")
+	}
+	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
+		t.Errorf("the previous backend's file is left behind: %v", err)
+	}
+}
+
+// BenchmarkStartup times the work done before the window first shows, with
+// a large history, and reports how long the history takes to load behind it.
+// Drawing the window isn't included.
+func BenchmarkStartup(b *testing.B) {
+	app := test.NewTempApp(b)
+	dir := app.Storage().RootURI().Path()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		b.Fatal(err)
+	}
+	store, err := OpenSQLiteStore(filepath.Join(dir, storageBackends[StorageSQLite].file))
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Ten years of a few sessions a day
+	start := time.Date(2015, time.January, 1, 9, 0, 0, 0, time.UTC)
+	for i := range 20000 {
+		at := start.Add(time.Duration(i) * 4 * time.Hour)
+		entry := Entry{ID: strconv.Itoa(i), Task: "Task " + strconv.Itoa(i%40), Start: at, Duration: time.Hour, Updated: at}
+		if err := store.PutEntry(entry); err != nil {
+			b.Fatal(err)
+		}
+	}
+	store.Close()
+
+	var loading time.Duration
+	b.ResetTimer()
+	for range b.N {
+		started := time.Now()
+		store, err := openStore(app, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		timer := setUpTimer(app, test.NewWindow(nil), syncTrackingStore{store})
+
+		b.StopTimer()
+		for !timer.loaded {
+			time.Sleep(time.Millisecond)
+		}
+		loading += time.Since(started)
+		store.Close()
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(loading.Milliseconds())/float64(b.N), "ms-to-history")
+}
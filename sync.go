@@ -0,0 +1,351 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// PrefSyncCredentials is set while sync credentials are saved in the
+	// system keychain, so the keychain isn't asked for them otherwise
+	PrefSyncCredentials = "syncCredentials"
+	// PrefSyncPassphrase held the passphrase in the preferences before it
+	// was moved to the keychain
+	PrefSyncPassphrase = "syncPassphrase"
+
+	// KeyringSyncPassphrase, KeyringSyncUser and KeyringSyncSecret name the
+	// sync passphrase and the remote's user and secret in the keychain
+	KeyringSyncPassphrase = "sync passphrase"
+	KeyringSyncUser       = "sync user"
+	KeyringSyncSecret     = "sync secret"
+	// PrefSyncTasksChanged is when the task list last changed on this
+	// device, as an RFC 3339 time. The newer of two task lists wins.
+	PrefSyncTasksChanged = "syncTasksChanged"
+
+	// SyncInterval is how often changes are pushed and pulled
+	SyncInterval = 5 * time.Minute
+)
+
+var errSyncOff = errors.New("sync: choose a provider in Settings first")
+
+// syncSnapshot is the content of the synced file before it's encrypted.
+type syncSnapshot struct {
+	SavedAt      time.Time            `json:"saved_at"`
+	Device       string               `json:"device"`
+	Entries      []Entry              `json:"entries"`
+	Tombstones   map[string]time.Time `json:"tombstones"`
+	Tasks        []string             `json:"tasks"`
+	Archived     []string             `json:"archived"`
+	TasksChanged time.Time            `json:"tasks_changed"`
+}
+
+// CloudSync keeps the data in step with other devices through an encrypted
+// file on a WebDAV, Dropbox or S3 remote. Each sync merges the file with the
+// local data entry by entry, so devices that tracked time offline reconcile
+// without losing or duplicating any of it.
+type CloudSync struct {
+	// mu serializes syncs, so the timer and the Sync now button can't
+	// interleave a pull with a push
+	mu    sync.Mutex
+	timer *TaskTimer
+}
+
+func NewCloudSync(timer *TaskTimer) *CloudSync {
+	moveSyncCredentials(fyne.CurrentApp().Preferences())
+	return &CloudSync{timer: timer}
+}
+
+// legacySyncPrefs are where older versions saved each credential.
+var legacySyncPrefs = map[string]string{
+	KeyringSyncPassphrase: PrefSyncPassphrase,
+	KeyringSyncUser:       PrefSyncUser,
+	KeyringSyncSecret:     PrefSyncSecret,
+}
+
+// syncCredential returns a sync credential from the system keychain, "" if
+// none is saved. One older versions saved is used until it can be moved.
+func syncCredential(account string) string {
+	prefs := fyne.CurrentApp().Preferences()
+	if legacy := prefs.String(legacySyncPrefs[account]); legacy != "" {
+		return legacy
+	}
+	if !prefs.Bool(PrefSyncCredentials) {
+		return ""
+	}
+	value, err := keyring.Get(KeyringService, account)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		log.Printf("keychain: %v", err)
+	}
+	return value
+}
+
+// setSyncCredentials saves the sync credentials, keyed by keychain account,
+// in the system keychain. Empty ones are removed from it.
+func setSyncCredentials(credentials map[string]string) error {
+	saved := false
+	for account, value := range credentials {
+		if value == "" {
+			if err := keyring.Delete(KeyringService, account); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+				return fmt.Errorf("keychain: %w", err)
+			}
+			continue
+		}
+		if err := keyring.Set(KeyringService, account, value); err != nil {
+			return fmt.Errorf("keychain: %w", err)
+		}
+		saved = true
+	}
+	fyne.CurrentApp().Preferences().SetBool(PrefSyncCredentials, saved)
+	return nil
+}
+
+// moveSyncCredentials moves credentials saved in the preferences by older
+// versions to the system keychain. They stay put if it can't be reached.
+func moveSyncCredentials(prefs fyne.Preferences) {
+	credentials := make(map[string]string)
+	found := false
+	for account, key := range legacySyncPrefs {
+		credentials[account] = prefs.String(key)
+		found = found || credentials[account] != ""
+	}
+	if !found {
+		return
+	}
+	if err := setSyncCredentials(credentials); err != nil {
+		log.Printf("sync: moving credentials: %v", err)
+		return
+	}
+	for _, key := range legacySyncPrefs {
+		prefs.RemoveValue(key)
+	}
+}
+
+// Run syncs immediately and then every SyncInterval. It starts once the
+// history has loaded, so a first push never sends an empty file.
+func (c *CloudSync) Run() {
+	ticker := time.NewTicker(SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Sync(); err != nil && !errors.Is(err, errSyncOff) {
+			log.Print(err)
+		}
+		<-ticker.C
+	}
+}
+
+// Sync merges the remote file into the local data and pushes the result if
+// the remote was missing anything.
+func (c *CloudSync) Sync() error {
+	remote := configuredSyncRemote()
+	if remote == nil {
+		return errSyncOff
+	}
+	passphrase := syncCredential(KeyringSyncPassphrase)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	local, err := c.localSnapshot()
+	if err != nil {
+		return err
+	}
+
+	var remoteSnapshot syncSnapshot
+	sealed, err := remote.Download()
+	switch {
+	case errors.Is(err, errRemoteMissing):
+	case err != nil:
+		return err
+	default:
+		raw, err := openSealed(passphrase, sealed)
+		if err != nil {
+			return fmt.Errorf("sync: %w", err)
+		}
+		if err := json.Unmarshal(raw, &remoteSnapshot); err != nil {
+			return fmt.Errorf("sync: reading the synced file: %w", err)
+		}
+	}
+
+	merged := mergeSnapshots(local, remoteSnapshot)
+	if !sameSnapshot(merged, local) {
+		if err := c.apply(local, merged, remoteSnapshot.Device); err != nil {
+			return err
+		}
+		// Push what's here now, which includes anything changed during the
+		// download
+		if merged, err = c.localSnapshot(); err != nil {
+			return err
+		}
+	}
+	if sealed != nil && sameSnapshot(merged, remoteSnapshot) {
+		return nil
+	}
+
+	merged.SavedAt = time.Now().UTC()
+	merged.Device, _ = os.Hostname()
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	if sealed, err = sealData(passphrase, raw); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	return remote.Upload(sealed)
+}
+
+func (c *CloudSync) localSnapshot() (syncSnapshot, error) {
+	tombstones, err := c.timer.store.Tombstones()
+	if err != nil {
+		return syncSnapshot{}, err
+	}
+	return syncSnapshot{
+		Entries:      allEntries(c.timer),
+		Tombstones:   tombstones,
+		Tasks:        c.timer.tasks.Tasks(),
+		Archived:     c.timer.tasks.Archived(),
+		TasksChanged: syncTime(PrefSyncTasksChanged),
+	}, nil
+}
+
+// mergeSnapshots combines two devices' data. Entries are matched by ID and
+// the most recently updated copy wins. A deleted entry stays deleted unless
+// it was updated after the deletion, e.g. restored with undo. The task list
+// is taken whole from the device that changed it last, plus any task that a
+// merged entry uses.
+func mergeSnapshots(a, b syncSnapshot) syncSnapshot {
+	merged := syncSnapshot{Tombstones: make(map[string]time.Time)}
+	for _, tombstones := range []map[string]time.Time{a.Tombstones, b.Tombstones} {
+		for id, deleted := range tombstones {
+			if deleted.After(merged.Tombstones[id]) {
+				merged.Tombstones[id] = deleted
+			}
+		}
+	}
+	merged.Entries = mergeEntrySets(merged.Tombstones, a.Entries, b.Entries)
+
+	tasksFrom := a
+	if b.TasksChanged.After(a.TasksChanged) {
+		tasksFrom = b
+	}
+	merged.Tasks = slices.Clone(tasksFrom.Tasks)
+	merged.Archived = slices.Clone(tasksFrom.Archived)
+	merged.TasksChanged = tasksFrom.TasksChanged
+	for _, entry := range merged.Entries {
+		if !contains(merged.Tasks, entry.Task) {
+			merged.Tasks = append(merged.Tasks, entry.Task)
+		}
+	}
+	return merged
+}
+
+// mergeEntrySets combines sets of entries by ID, the most recently updated
+// copy of each winning, and leaves out those deleted after their last
+// update. The result is in order of start.
+func mergeEntrySets(tombstones map[string]time.Time, sets ...[]Entry) []Entry {
+	newest := make(map[string]Entry)
+	for _, entry := range slices.Concat(sets...) {
+		if current, ok := newest[entry.ID]; !ok || entry.Updated.After(current.Updated) {
+			newest[entry.ID] = entry
+		}
+	}
+	var merged []Entry
+	for _, entry := range newest {
+		if deleted, ok := tombstones[entry.ID]; !ok || entry.Updated.After(deleted) {
+			merged = append(merged, entry)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Start.Before(merged[j].Start)
+	})
+	return merged
+}
+
+// sameSnapshot reports whether two snapshots hold the same data, ignoring
+// who saved them and when.
+func sameSnapshot(a, b syncSnapshot) bool {
+	if len(a.Entries) != len(b.Entries) || len(a.Tombstones) != len(b.Tombstones) {
+		return false
+	}
+	updated := make(map[string]time.Time, len(a.Entries))
+	for _, entry := range a.Entries {
+		updated[entry.ID] = entry.Updated
+	}
+	for _, entry := range b.Entries {
+		if at, ok := updated[entry.ID]; !ok || !at.Equal(entry.Updated) {
+			return false
+		}
+	}
+	for id, deleted := range b.Tombstones {
+		if at, ok := a.Tombstones[id]; !ok || !at.Equal(deleted) {
+			return false
+		}
+	}
+	return slices.Equal(a.Tasks, b.Tasks) && slices.Equal(a.Archived, b.Archived)
+}
+
+// apply merges the merged data into the local data, which may have changed
+// since local was taken. device last saved the synced file.
+func (c *CloudSync) apply(local, merged syncSnapshot, device string) error {
+	if err := c.timer.store.PutTombstones(merged.Tombstones); err != nil {
+		return err
+	}
+	if err := mergeEntries(c.timer, merged.Entries, device); err != nil {
+		return err
+	}
+	fyne.DoAndWait(func() {
+		// A task list changed here during the sync is newer than either
+		if syncTime(PrefSyncTasksChanged).After(local.TasksChanged) {
+			for _, task := range merged.Tasks {
+				c.timer.tasks.Ensure(task)
+			}
+		} else {
+			c.timer.tasks.Replace(merged.Tasks, merged.Archived)
+			// Taking over the other device's list isn't a local change
+			fyne.CurrentApp().Preferences().SetString(PrefSyncTasksChanged, merged.TasksChanged.Format(time.RFC3339Nano))
+		}
+		updateContentView(c.timer)
+	})
+	return nil
+}
+
+func syncTime(key string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, fyne.CurrentApp().Preferences().String(key))
+	return t
+}
+
+// syncTrackingStore notes when the task list last changed, so the newer of
+// two devices' lists wins.
+type syncTrackingStore struct {
+	Store
+}
+
+func (s syncTrackingStore) SetTasks(tasks, archived []string) error {
+	fyne.CurrentApp().Preferences().SetString(PrefSyncTasksChanged, time.Now().UTC().Format(time.RFC3339Nano))
+	return s.Store.SetTasks(tasks, archived)
+}
+
+// syncNow runs a sync for the Settings button and reports how it went.
+func syncNow(timer *TaskTimer) {
+	go func() {
+		err := timer.cloudSync.Sync()
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			dialog.ShowInformation(tr("Sync"), tr("Everything is in sync."), timer.window)
+		})
+	}()
+}
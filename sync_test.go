@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"github.com/zalando/go-keyring"
+)
+
+func entryIDs(entries []Entry) []string {
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestMergeSnapshots(t *testing.T) {
+	day := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return day.Add(time.Duration(hours) * time.Hour) }
+	entry := func(id, task string, updated int) Entry {
+		return Entry{ID: id, Task: task, Start: day, Duration: time.Hour, Updated: at(updated)}
+	}
+
+	tests := []struct {
+		name      string
+		a, b      syncSnapshot
+		want      []Entry
+		wantTasks []string
+	}{
+		{
+			name: "entries from both sides",
+			a:    syncSnapshot{Entries: []Entry{entry("1", "Code", 1)}},
+			b:    syncSnapshot{Entries: []Entry{entry("2", "Code", 1)}},
+			want: []Entry{entry("1", "Code", 1), entry("2", "Code", 1)},
+		},
+		{
+			name: "newer copy wins",
+			a:    syncSnapshot{Entries: []Entry{entry("1", "Code", 2)}},
+			b:    syncSnapshot{Entries: []Entry{entry("1", "Review", 1)}},
+			want: []Entry{entry("1", "Code", 2)},
+		},
+		{
+			name: "deleted after its last update",
+			a:    syncSnapshot{Entries: []Entry{entry("1", "Code", 1)}},
+			b:    syncSnapshot{Tombstones: map[string]time.Time{"1": at(2)}},
+			want: nil,
+		},
+		{
+			name: "updated after it was deleted",
+			a:    syncSnapshot{Entries: []Entry{entry("1", "Code", 3)}},
+			b:    syncSnapshot{Tombstones: map[string]time.Time{"1": at(2)}},
+			want: []Entry{entry("1", "Code", 3)},
+		},
+		{
+			name:      "newer task list, plus the tasks entries use",
+			a:         syncSnapshot{Entries: []Entry{entry("1", "Code", 1)}, Tasks: []string{"Code", "Old"}, TasksChanged: at(1)},
+			b:         syncSnapshot{Tasks: []string{"Write"}, TasksChanged: at(2)},
+			want:      []Entry{entry("1", "Code", 1)},
+			wantTasks: []string{"Write", "Code"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, merged := range []syncSnapshot{mergeSnapshots(tc.a, tc.b), mergeSnapshots(tc.b, tc.a)} {
+				if got, want := entryIDs(merged.Entries), entryIDs(tc.want); !slices.Equal(got, want) {
+					t.Fatalf("entries = %v, want %v", got, want)
+				}
+				for _, want := range tc.want {
+					if got := merged.Entries[entryIndex(merged.Entries, want.ID)]; got.Task != want.Task {
+						t.Errorf("entry %s is on %s, want %s", want.ID, got.Task, want.Task)
+					}
+				}
+				if tc.wantTasks != nil && !slices.Equal(merged.Tasks, tc.wantTasks) {
+					t.Errorf("tasks = %v, want %v", merged.Tasks, tc.wantTasks)
+				}
+			}
+		})
+	}
+}
+
+// TestSyncKeepsLocalChanges records and deletes entries while the synced
+// file is downloading, which must neither lose them nor bring them back.
+func TestSyncKeepsLocalChanges(t *testing.T) {
+	app := test.NewTempApp(t)
+	keyring.MockInit()
+	prefs := app.Preferences()
+	store, err := OpenJSONStore(filepath.Join(t.TempDir(), "gotime.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	timer := &TaskTimer{
+		taskList:    make(map[string]time.Duration),
+		dailyTotals: make(map[time.Time]map[string]time.Duration),
+		store:       syncTrackingStore{store},
+		tasks:       NewTaskStore(store, prefs),
+		contentBox:  container.NewVBox(),
+	}
+	newTimerBindings(timer)
+
+	start := time.Now().Add(-3 * time.Hour)
+	kept := recordEntry(timer, Entry{Task: "Code", Start: start, Duration: time.Hour})
+	deletedThere := recordEntry(timer, Entry{Task: "Code", Start: start, Duration: time.Hour})
+	deletedHere := recordEntry(timer, Entry{Task: "Code", Start: start, Duration: time.Hour})
+
+	const passphrase = "correct horse"
+	deletedAt := time.Now().Add(time.Minute).UTC()
+	fromThere := Entry{ID: "there", Task: "Review", Start: start, Duration: time.Hour, Updated: time.Now().UTC()}
+	remote := syncSnapshot{
+		Entries:    []Entry{kept, deletedHere, fromThere},
+		Tombstones: map[string]time.Time{deletedThere.ID: deletedAt},
+	}
+	raw, err := json.Marshal(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := sealData(passphrase, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var added Entry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			// The user carries on while the file downloads
+			added = recordEntry(timer, Entry{Task: "Write", Start: time.Now(), Duration: time.Minute})
+			if err := deleteEntry(timer, deletedHere.ID); err != nil {
+				t.Error(err)
+			}
+			w.Write(file)
+		case http.MethodPut:
+			file, _ = io.ReadAll(r.Body)
+		}
+	}))
+	defer server.Close()
+	prefs.SetString(PrefSyncProvider, SyncProviderWebDAV)
+	prefs.SetString(PrefSyncURL, server.URL)
+	if err := setSyncCredentials(map[string]string{KeyringSyncPassphrase: passphrase}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCloudSync(timer).Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := entryIDs([]Entry{kept, added, fromThere})
+	if got := entryIDs(allEntries(timer)); !slices.Equal(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+	stored, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entryIDs(stored); !slices.Equal(got, want) {
+		t.Errorf("stored entries = %v, want %v", got, want)
+	}
+	tombstones, err := store.Tombstones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tombstones[added.ID]; ok {
+		t.Error("the entry added during the sync was tombstoned")
+	}
+	if got := tombstones[deletedThere.ID]; !got.Equal(deletedAt) {
+		t.Errorf("tombstone of the entry deleted elsewhere = %v, want %v", got, deletedAt)
+	}
+	if _, ok := tombstones[deletedHere.ID]; !ok {
+		t.Error("the entry deleted during the sync has no tombstone")
+	}
+
+	mu.Lock()
+	raw, err = openSealed(passphrase, file)
+	mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pushed syncSnapshot
+	if err := json.Unmarshal(raw, &pushed); err != nil {
+		t.Fatal(err)
+	}
+	if got := entryIDs(pushed.Entries); !slices.Equal(got, want) {
+		t.Errorf("pushed entries = %v, want %v", got, want)
+	}
+	if _, ok := pushed.Tombstones[deletedHere.ID]; !ok {
+		t.Error("the deletion during the sync wasn't pushed")
+	}
+}
+
+// TestSyncAuditsRemoteChanges takes over an edit and a deletion made on
+// another device, which go into this device's audit log as made there. The
+// log itself stays on this device.
+func TestSyncAuditsRemoteChanges(t *testing.T) {
+	app := test.NewTempApp(t)
+	keyring.MockInit()
+	prefs := app.Preferences()
+	store, err := OpenJSONStore(filepath.Join(t.TempDir(), "gotime.json"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	timer := &TaskTimer{
+		taskList:    make(map[string]time.Duration),
+		dailyTotals: make(map[time.Time]map[string]time.Duration),
+		store:       syncTrackingStore{store},
+		audit:       NewAuditLog(store),
+		tasks:       NewTaskStore(store, prefs),
+		contentBox:  container.NewVBox(),
+	}
+	newTimerBindings(timer)
+
+	start := time.Now().Add(-3 * time.Hour)
+	edited := recordEntry(timer, Entry{Task: "Code", Start: start, Duration: time.Hour})
+	deleted := recordEntry(timer, Entry{Task: "Code", Start: start, Duration: time.Hour})
+	if err := updateEntry(timer, Entry{ID: edited.ID, Task: "Code", Start: start, Duration: 2 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	local, err := store.AuditLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editedThere := edited
+	editedThere.Task = "Review"
+	editedThere.Updated = time.Now().Add(time.Minute).UTC()
+	deletedAt := time.Now().Add(2 * time.Minute).UTC()
+	remote := syncSnapshot{
+		Device:     "laptop",
+		Entries:    []Entry{editedThere},
+		Tombstones: map[string]time.Time{deleted.ID: deletedAt},
+	}
+	const passphrase = "correct horse"
+	raw, err := json.Marshal(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := sealData(passphrase, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write(file)
+		case http.MethodPut:
+			file, _ = io.ReadAll(r.Body)
+		}
+	}))
+	defer server.Close()
+	prefs.SetString(PrefSyncProvider, SyncProviderWebDAV)
+	prefs.SetString(PrefSyncURL, server.URL)
+	if err := setSyncCredentials(map[string]string{KeyringSyncPassphrase: passphrase}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCloudSync(timer).Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := store.AuditLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := verifyAuditLog(records); i >= 0 {
+		t.Fatalf("the audit log is broken from record %d", i)
+	}
+	synced := records[len(local):]
+	if len(synced) != 2 {
+		t.Fatalf("%d records added by the sync, want 2", len(synced))
+	}
+	for _, record := range synced {
+		if record.Device != "laptop" {
+			t.Errorf("%s record is from device %q, want laptop", record.Action, record.Device)
+		}
+		switch record.Action {
+		case AuditEdited:
+			if record.EntryID != edited.ID || record.After.Task != "Review" || !record.At.Equal(editedThere.Updated) {
+				t.Errorf("edit record = %+v", record)
+			}
+		case AuditDeleted:
+			if record.EntryID != deleted.ID || record.After != nil || !record.At.Equal(deletedAt) {
+				t.Errorf("deletion record = %+v", record)
+			}
+		default:
+			t.Errorf("unexpected %s record", record.Action)
+		}
+	}
+
+	// The synced file carries entries, not this device's log
+	raw, err = openSealed(passphrase, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if strings.Contains(string(raw), record.Hash) {
+			t.Fatal("the audit log was pushed with the synced file")
+		}
+	}
+}
+
+func TestMoveSyncCredentials(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	keyring.MockInit()
+	prefs.SetString(PrefSyncPassphrase, "correct horse")
+	prefs.SetString(PrefSyncUser, "me")
+	prefs.SetString(PrefSyncSecret, "s3cret")
+
+	moveSyncCredentials(prefs)
+	for _, key := range []string{PrefSyncPassphrase, PrefSyncUser, PrefSyncSecret} {
+		if got := prefs.String(key); got != "" {
+			t.Errorf("%s is still in the preferences: %q", key, got)
+		}
+	}
+	for account, want := range map[string]string{
+		KeyringSyncPassphrase: "correct horse",
+		KeyringSyncUser:       "me",
+		KeyringSyncSecret:     "s3cret",
+	} {
+		if got := syncCredential(account); got != want {
+			t.Errorf("keychain %s = %q, want %q", account, got, want)
+		}
+	}
+
+	if err := setSyncCredentials(map[string]string{KeyringSyncPassphrase: "", KeyringSyncUser: "", KeyringSyncSecret: ""}); err != nil {
+		t.Fatal(err)
+	}
+	if prefs.Bool(PrefSyncCredentials) {
+		t.Error("credentials are still marked as saved after clearing them")
+	}
+	if _, err := keyring.Get(KeyringService, KeyringSyncSecret); err != keyring.ErrNotFound {
+		t.Errorf("secret left in the keychain: %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefSyncProvider = "syncProvider"
+	PrefSyncURL      = "syncURL"
+	PrefSyncRegion   = "syncRegion"
+	// PrefSyncUser and PrefSyncSecret held the remote's credentials before
+	// they were moved to the system keychain
+	PrefSyncUser   = "syncUser"
+	PrefSyncSecret = "syncSecret"
+
+	SyncProviderWebDAV  = "WebDAV"
+	SyncProviderDropbox = "Dropbox"
+	SyncProviderS3      = "S3"
+
+	// SyncFileName is the name of the synced file on every provider
+	SyncFileName = "gotime-sync.bin"
+
+	DropboxDownloadURL = "https://content.dropboxapi.com/2/files/download"
+	DropboxUploadURL   = "https://content.dropboxapi.com/2/files/upload"
+)
+
+var syncProviders = []string{SyncProviderWebDAV, SyncProviderDropbox, SyncProviderS3}
+
+// errRemoteMissing means nothing has been synced to the remote yet.
+var errRemoteMissing = errors.New("sync: no data on the server yet")
+
+// SyncRemote is where the synced file is kept.
+type SyncRemote interface {
+	// Download returns errRemoteMissing if the file doesn't exist.
+	Download() ([]byte, error)
+	Upload(data []byte) error
+}
+
+// configuredSyncRemote returns the remote set up in Settings, or nil if sync
+// is off.
+func configuredSyncRemote() SyncRemote {
+	prefs := fyne.CurrentApp().Preferences()
+	provider := prefs.String(PrefSyncProvider)
+	if provider == "" {
+		return nil
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(prefs.String(PrefSyncURL)), "/")
+	user, secret := syncCredential(KeyringSyncUser), syncCredential(KeyringSyncSecret)
+
+	switch provider {
+	case SyncProviderWebDAV:
+		return &webDAVRemote{fileURL: baseURL + "/" + SyncFileName, user: user, password: secret}
+	case SyncProviderDropbox:
+		return &dropboxRemote{token: secret}
+	case SyncProviderS3:
+		return &s3Remote{
+			objectURL: baseURL + "/" + SyncFileName,
+			region:    prefs.String(PrefSyncRegion),
+			accessKey: user,
+			secretKey: secret,
+		}
+	}
+	return nil
+}
+
+// syncResponse turns a response into the body, errRemoteMissing or an
+// error naming the provider.
+func syncResponse(provider string, resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, fmt.Errorf("sync: %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("sync: %s: %w", provider, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errRemoteMissing
+	}
+	if resp.StatusCode/100 != 2 {
+		return body, fmt.Errorf("sync: %s: %s", provider, resp.Status)
+	}
+	return body, nil
+}
+
+// webDAVRemote keeps the file in a WebDAV folder, e.g. on Nextcloud.
+type webDAVRemote struct {
+	fileURL  string
+	user     string
+	password string
+}
+
+func (r *webDAVRemote) do(method string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, r.fileURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(r.user, r.password)
+	resp, err := httpClient.Do(req)
+	return syncResponse("WebDAV", resp, err)
+}
+
+func (r *webDAVRemote) Download() ([]byte, error) {
+	return r.do(http.MethodGet, nil)
+}
+
+func (r *webDAVRemote) Upload(data []byte) error {
+	_, err := r.do(http.MethodPut, data)
+	return err
+}
+
+// dropboxRemote keeps the file in the root of the app's Dropbox folder.
+type dropboxRemote struct {
+	token string
+}
+
+func (r *dropboxRemote) do(endpoint string, arg map[string]string, body []byte) (*http.Response, error) {
+	rawArg, err := json.Marshal(arg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	req.Header.Set("Dropbox-API-Arg", string(rawArg))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	return httpClient.Do(req)
+}
+
+func (r *dropboxRemote) Download() ([]byte, error) {
+	resp, err := r.do(DropboxDownloadURL, map[string]string{"path": "/" + SyncFileName}, nil)
+	body, err := syncResponse("Dropbox", resp, err)
+	// Dropbox reports a missing file as a 409 with a path/not_found error
+	if err != nil && resp != nil && resp.StatusCode == http.StatusConflict && bytes.Contains(body, []byte("not_found")) {
+		return nil, errRemoteMissing
+	}
+	return body, err
+}
+
+func (r *dropboxRemote) Upload(data []byte) error {
+	resp, err := r.do(DropboxUploadURL, map[string]string{"path": "/" + SyncFileName, "mode": "overwrite"}, data)
+	_, err = syncResponse("Dropbox", resp, err)
+	return err
+}
+
+// s3Remote keeps the file in an S3 bucket, or any S3-compatible storage,
+// addressed path-style as https://endpoint/bucket/key.
+type s3Remote struct {
+	objectURL string
+	region    string
+	accessKey string
+	secretKey string
+}
+
+func (r *s3Remote) do(method string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, r.objectURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r.sign(req, body, time.Now().UTC())
+	resp, err := httpClient.Do(req)
+	return syncResponse("S3", resp, err)
+}
+
+func (r *s3Remote) Download() ([]byte, error) {
+	return r.do(http.MethodGet, nil)
+}
+
+func (r *s3Remote) Upload(data []byte) error {
+	_, err := r.do(http.MethodPut, data)
+	return err
+}
+
+// sign adds an AWS Signature Version 4 to a request without query
+// parameters.
+func (r *s3Remote) sign(req *http.Request, body []byte, now time.Time) {
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("x-amz-date", stamp)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + stamp,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + r.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", stamp, scope, sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+r.secretKey), date)
+	key = hmacSHA256(key, r.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// syncURLHint explains what the URL field means for each provider.
+func syncURLHint(provider string) string {
+	switch provider {
+	case SyncProviderWebDAV:
+		return "https://cloud.example.com/remote.php/dav/files/me/gotime"
+	case SyncProviderS3:
+		return "https://s3.eu-central-1.amazonaws.com/my-bucket"
+	}
+	return ""
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TaskPickerRows is how many matches the task picker shows before scrolling.
+const TaskPickerRows = 6
+
+// taskPick is a row in the task picker: a task, no task, or the task typed
+// in, to be created or brought back from the archive.
+type taskPick struct {
+	taskName string
+	create   bool
+	restore  bool
+}
+
+// taskBadges are the project and tags shown beside a task in the picker,
+// taken from its latest entry.
+type taskBadges struct {
+	project string
+	tags    []string
+}
+
+// TaskPicker chooses the timer's task. Typing searches the active tasks,
+// best match first and fuzzily, so "wrc" finds "Write code"; Up and Down
+// move through the matches and Enter picks one. A name that matches no task
+// can be created from the list. The button beside the search lists every
+// task.
+type TaskPicker struct {
+	widget.BaseWidget
+
+	// OnChanged is called with the task picked, NoTaskSelected for none.
+	OnChanged func(taskName string)
+
+	timer     *TaskTimer
+	selected  string
+	picks     []taskPick
+	badges    map[string]taskBadges
+	highlight int
+
+	search  *keyEntry
+	list    *widget.List
+	listBox *fyne.Container
+}
+
+func NewTaskPicker(timer *TaskTimer) *TaskPicker {
+	p := &TaskPicker{timer: timer, selected: NoTaskSelected}
+
+	p.search = newKeyEntry()
+	p.search.PlaceHolder = tr("Search or add a task")
+	p.search.ActionItem = widget.NewButtonWithIcon("", theme.MenuDropDownIcon(), func() {
+		if p.listBox.Visible() {
+			p.close()
+			return
+		}
+		p.open()
+	})
+	p.search.OnChanged = func(query string) {
+		if query == "" {
+			p.Refresh()
+			return
+		}
+		p.open()
+	}
+	p.search.OnSubmitted = func(string) {
+		if p.listBox.Visible() && p.highlight < len(p.picks) {
+			p.pick(p.picks[p.highlight])
+		}
+	}
+	p.search.onKey = func(name fyne.KeyName) bool {
+		switch name {
+		case fyne.KeyDown:
+			if !p.listBox.Visible() {
+				p.open()
+				return true
+			}
+			p.highlight = max(min(p.highlight+1, len(p.picks)-1), 0)
+		case fyne.KeyUp:
+			p.highlight = max(p.highlight-1, 0)
+		case fyne.KeyEscape:
+			if !p.listBox.Visible() {
+				return false
+			}
+			p.close()
+			return true
+		default:
+			return false
+		}
+		p.list.Refresh()
+		p.list.ScrollTo(p.highlight)
+		return true
+	}
+
+	p.list = widget.NewList(
+		func() int { return len(p.picks) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), container.NewHBox(), widget.NewLabel(""))
+		},
+		p.updateRow,
+	)
+	p.list.OnSelected = func(id widget.ListItemID) {
+		p.list.UnselectAll()
+		p.pick(p.picks[id])
+	}
+
+	// The list takes no height of its own, so it's given room for a few rows
+	sizer := canvas.NewRectangle(nil)
+	sizer.SetMinSize(fyne.NewSize(0, TaskPickerRows*(widget.NewLabel("").MinSize().Height+theme.SeparatorThicknessSize())))
+	p.listBox = container.NewStack(sizer, p.list)
+	p.listBox.Hide()
+
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+func (p *TaskPicker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVBox(p.search, p.listBox))
+}
+
+// Selected returns the task picked, NoTaskSelected for none.
+func (p *TaskPicker) Selected() string {
+	return p.selected
+}
+
+// SetSelected picks a task, as if from the list.
+func (p *TaskPicker) SetSelected(taskName string) {
+	p.selected = taskName
+	p.close()
+	if p.OnChanged != nil {
+		p.OnChanged(taskName)
+	}
+}
+
+// Refresh lists the tasks matching the search again, e.g. after the task
+// list or a task's icon changed.
+func (p *TaskPicker) Refresh() {
+	p.picks = p.matches(p.search.Text)
+	p.highlight = 0
+	if p.search.Text == "" {
+		// Start from the task picked, for Enter to keep it
+		p.highlight = max(slices.IndexFunc(p.picks, func(pick taskPick) bool {
+			return pick.taskName == p.selected
+		}), 0)
+	}
+	p.list.Refresh()
+	p.list.ScrollTo(p.highlight)
+	p.BaseWidget.Refresh()
+}
+
+func (p *TaskPicker) open() {
+	if !p.listBox.Visible() {
+		p.badges = latestTaskBadges(p.timer)
+		p.listBox.Show()
+	}
+	p.Refresh()
+}
+
+func (p *TaskPicker) close() {
+	p.search.SetText("")
+	p.listBox.Hide()
+	p.Refresh()
+}
+
+func (p *TaskPicker) pick(pick taskPick) {
+	if pick.create {
+		p.timer.tasks.Ensure(pick.taskName)
+	}
+	if pick.restore {
+		p.timer.tasks.SetArchived(pick.taskName, false)
+	}
+	p.SetSelected(pick.taskName)
+}
+
+// matches ranks the active tasks against query. With no query, every task
+// is listed after an option for no task.
+func (p *TaskPicker) matches(query string) []taskPick {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		picks := []taskPick{{taskName: NoTaskSelected}}
+		for _, taskName := range p.timer.tasks.Active() {
+			picks = append(picks, taskPick{taskName: taskName})
+		}
+		return picks
+	}
+
+	type ranked struct {
+		taskName string
+		rank     int
+	}
+	var matched []ranked
+	exact := false
+	for _, taskName := range p.timer.tasks.Active() {
+		if rank, ok := fuzzyRank(query, taskName); ok {
+			matched = append(matched, ranked{taskName, rank})
+			exact = exact || rank == 0
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].rank < matched[j].rank
+	})
+
+	var picks []taskPick
+	for _, m := range matched {
+		picks = append(picks, taskPick{taskName: m.taskName})
+	}
+	if !exact && query != NoTaskSelected {
+		// A task of that name may be archived, which picking it undoes
+		if i := slices.IndexFunc(p.timer.tasks.Tasks(), func(taskName string) bool {
+			return strings.EqualFold(taskName, query)
+		}); i >= 0 {
+			picks = append(picks, taskPick{taskName: p.timer.tasks.Tasks()[i], restore: true})
+		} else {
+			picks = append(picks, taskPick{taskName: query, create: true})
+		}
+	}
+	return picks
+}
+
+func (p *TaskPicker) updateRow(id widget.ListItemID, item fyne.CanvasObject) {
+	pick := p.picks[id]
+	row := item.(*fyne.Container)
+	label := row.Objects[0].(*widget.Label)
+	icon := row.Objects[1].(*widget.Icon)
+	badges := row.Objects[2].(*fyne.Container)
+
+	switch {
+	case pick.create:
+		label.SetText(tr("Add “{{.Task}}”", map[string]any{"Task": pick.taskName}))
+		icon.SetResource(theme.ContentAddIcon())
+	case pick.restore:
+		label.SetText(tr("Restore “{{.Task}}”", map[string]any{"Task": pick.taskName}))
+		icon.SetResource(theme.ContentUndoIcon())
+	case pick.taskName == NoTaskSelected:
+		label.SetText(tr(NoTaskSelected))
+		icon.SetResource(nil)
+	default:
+		label.SetText(taskOption(p.timer, pick.taskName))
+		icon.SetResource(nil)
+	}
+	if pick.taskName == p.selected && !pick.create && !pick.restore {
+		icon.SetResource(theme.ConfirmIcon())
+	}
+	label.TextStyle.Bold = id == p.highlight
+	label.Refresh()
+
+	badges.RemoveAll()
+	if pick.create || pick.restore {
+		return
+	}
+	info := p.badges[pick.taskName]
+	if info.project != "" {
+		badges.Add(newTaskBadge(info.project))
+	}
+	for _, tag := range info.tags {
+		badges.Add(newTaskBadge("#" + tag))
+	}
+}
+
+// newTaskBadge is a small rounded label for a project or tag.
+func newTaskBadge(text string) fyne.CanvasObject {
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	background.CornerRadius = theme.InputRadiusSize()
+	label := canvas.NewText(text, theme.Color(theme.ColorNameForeground))
+	label.TextSize = theme.CaptionTextSize()
+	return container.NewStack(background, container.NewPadded(label))
+}
+
+// latestTaskBadges takes each task's project and tags from its most recent
+// entry.
+func latestTaskBadges(timer *TaskTimer) map[string]taskBadges {
+	latest := make(map[string]Entry)
+	for _, entry := range allEntries(timer) {
+		if previous, ok := latest[entry.Task]; !ok || entry.Start.After(previous.Start) {
+			latest[entry.Task] = entry
+		}
+	}
+	badges := make(map[string]taskBadges, len(latest))
+	for taskName, entry := range latest {
+		badges[taskName] = taskBadges{project: entry.Project, tags: entry.Tags}
+	}
+	return badges
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PrefTasks and PrefArchivedTasks held the task list before it moved to the
+// Store. They are only read once, to carry an existing list over.
+const (
+	PrefTasks         = "tasks"
+	PrefArchivedTasks = "archivedTasks"
+)
+
+var (
+	errTaskNameEmpty = errors.New("enter a task name")
+	errTaskExists    = errors.New("a task with that name already exists")
+)
+
+// TaskStore is the list of known tasks. It persists the list in the Store
+// and tells its observers whenever the list changes, so the
+// selector and other views never need to be updated by hand.
+//
+// Archived tasks are hidden from the selector but keep their entries, so they
+// still count in stats and reports.
+type TaskStore struct {
+	mu        sync.Mutex
+	store     Store
+	tasks     []string
+	archived  []string
+	observers []*func()
+}
+
+// NewTaskStore loads the saved task list. A list still kept in the app
+// preferences by an older version is moved into the store.
+func NewTaskStore(store Store, prefs fyne.Preferences) *TaskStore {
+	s := &TaskStore{store: store}
+	tasks, archived, err := store.Tasks()
+	if err != nil {
+		log.Printf("store: loading tasks: %v", err)
+	}
+	s.tasks, s.archived = tasks, archived
+
+	if len(s.tasks) == 0 && len(prefs.StringList(PrefTasks)) > 0 {
+		s.tasks = prefs.StringList(PrefTasks)
+		s.archived = prefs.StringList(PrefArchivedTasks)
+		s.saveLocked()
+		prefs.RemoveValue(PrefTasks)
+		prefs.RemoveValue(PrefArchivedTasks)
+	}
+	return s
+}
+
+// Tasks returns a copy of the task names in the order they were added.
+func (s *TaskStore) Tasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.tasks...)
+}
+
+// Active returns the tasks that aren't archived.
+func (s *TaskStore) Active() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []string
+	for _, taskName := range s.tasks {
+		if !contains(s.archived, taskName) {
+			tasks = append(tasks, taskName)
+		}
+	}
+	return tasks
+}
+
+// Archived returns the archived tasks.
+func (s *TaskStore) Archived() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.archived...)
+}
+
+// IsArchived reports whether a task is archived.
+func (s *TaskStore) IsArchived(taskName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return contains(s.archived, taskName)
+}
+
+// SetArchived archives or restores a task.
+func (s *TaskStore) SetArchived(taskName string, archived bool) {
+	s.mu.Lock()
+	var kept []string
+	for _, name := range s.archived {
+		if name != taskName {
+			kept = append(kept, name)
+		}
+	}
+	if archived {
+		kept = append(kept, taskName)
+	}
+	s.archived = kept
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Contains reports whether a task exists.
+func (s *TaskStore) Contains(taskName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return contains(s.tasks, taskName)
+}
+
+// Add creates a task, rejecting blank names, the selector placeholder and
+// duplicates.
+func (s *TaskStore) Add(taskName string) error {
+	taskName = strings.TrimSpace(taskName)
+	if taskName == "" || taskName == NoTaskSelected || taskName == tr(NoTaskSelected) {
+		return errTaskNameEmpty
+	}
+
+	s.mu.Lock()
+	if contains(s.tasks, taskName) {
+		s.mu.Unlock()
+		return errTaskExists
+	}
+	s.tasks = append(s.tasks, taskName)
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// Ensure adds a task if it doesn't exist yet, for tasks that come from
+// recorded time rather than from the user creating them. Blank names and
+// existing tasks are silently skipped.
+func (s *TaskStore) Ensure(taskName string) {
+	s.Add(taskName)
+}
+
+// Rename renames a task. Renaming onto an existing task merges the two, and
+// the merged task keeps the target's archive state.
+func (s *TaskStore) Rename(from, to string) {
+	s.mu.Lock()
+	wasArchived := contains(s.archived, from) && !contains(s.tasks, to)
+	var archived []string
+	for _, taskName := range s.archived {
+		if taskName != from {
+			archived = append(archived, taskName)
+		}
+	}
+	if wasArchived {
+		archived = append(archived, to)
+	}
+	s.archived = archived
+
+	var tasks []string
+	for _, taskName := range s.tasks {
+		if taskName == from {
+			taskName = to
+		}
+		if !contains(tasks, taskName) {
+			tasks = append(tasks, taskName)
+		}
+	}
+	if !contains(tasks, to) {
+		tasks = append(tasks, to)
+	}
+	s.tasks = tasks
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// Replace swaps in a whole new task list, e.g. one synced from another
+// device.
+func (s *TaskStore) Replace(tasks, archived []string) {
+	s.mu.Lock()
+	s.tasks = append([]string(nil), tasks...)
+	s.archived = append([]string(nil), archived...)
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// AddObserver registers fn to be called after every change to the list.
+// Observers run on the goroutine that made the change, which for the UI is
+// always the main goroutine. It returns a function that unregisters fn.
+func (s *TaskStore) AddObserver(fn func()) (remove func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observer := &fn
+	s.observers = append(s.observers, observer)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.observers = slices.DeleteFunc(s.observers, func(o *func()) bool { return o == observer })
+	}
+}
+
+func (s *TaskStore) saveLocked() {
+	if err := s.store.SetTasks(s.tasks, s.archived); err != nil {
+		log.Printf("store: saving tasks: %v", err)
+	}
+}
+
+func (s *TaskStore) notify() {
+	s.mu.Lock()
+	observers := slices.Clone(s.observers)
+	s.mu.Unlock()
+
+	for _, fn := range observers {
+		(*fn)()
+	}
+}
+
+// fuzzyMatch reports whether the query's characters appear in order in name,
+// ignoring case, so "wrc" matches "Write code". An empty query matches
+// everything.
+func fuzzyMatch(query, name string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	name = strings.ToLower(name)
+	for _, r := range query {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(string(r)):]
+	}
+	return true
+}
+
+// fuzzyRank scores how well query matches name, lower being better: the
+// whole name, then its start, the start of a word in it, anywhere in it,
+// and last its characters in order. It reports false for no match.
+func fuzzyRank(query, name string) (int, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	lower := strings.ToLower(name)
+	switch {
+	case query == lower:
+		return 0, true
+	case strings.HasPrefix(lower, query):
+		return 1, true
+	case strings.Contains(" "+lower, " "+query):
+		return 2, true
+	case strings.Contains(lower, query):
+		return 3, true
+	case fuzzyMatch(query, name):
+		return 4, true
+	}
+	return 0, false
+}
+
+// taskNameKey reduces a task name to what's left once case, punctuation and
+// a plural s are set aside, so "Email", "emails" and "E-mail" share one.
+func taskNameKey(name string) string {
+	var key strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			key.WriteRune(r)
+		}
+	}
+	return strings.TrimSuffix(key.String(), "s")
+}
+
+// createTaskListContainer lists the tasks with a button to archive or restore
+// each one. Archived tasks are only listed when "Show archived" is checked.
+func createTaskListContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	showArchived := widget.NewCheck(tr("Show archived"), nil)
+
+	var render func()
+	render = func() {
+		list.RemoveAll()
+		for _, taskName := range timer.tasks.Tasks() {
+			archived := timer.tasks.IsArchived(taskName)
+			if archived && !showArchived.Checked {
+				continue
+			}
+
+			text := taskName
+			if estimate, ok := taskEstimates(timer)[taskName]; ok {
+				text += " · " + tr("{{.Estimate}} estimated", map[string]any{"Estimate": formatShortDuration(estimate)})
+			}
+			if isInternalTask(taskName) {
+				text += " · " + tr("internal")
+			}
+			label := widget.NewLabel(text)
+			label.Truncation = fyne.TextTruncateEllipsis
+			buttons := container.NewHBox()
+			if archived {
+				label.TextStyle = fyne.TextStyle{Italic: true}
+				buttons.Add(widget.NewButton(tr("Restore"), func() {
+					timer.tasks.SetArchived(taskName, false)
+					render()
+				}))
+			} else {
+				buttons.Add(widget.NewButton(tr("Edit"), func() {
+					showEditTaskDialog(timer, taskName, render)
+				}))
+				buttons.Add(widget.NewButton(tr("Merge"), func() {
+					showMergeTaskDialog(timer, taskName, render)
+				}))
+				buttons.Add(widget.NewButton(tr("Archive"), func() {
+					timer.tasks.SetArchived(taskName, true)
+					render()
+				}))
+			}
+			list.Add(container.NewBorder(nil, nil, nil, buttons, label))
+		}
+		if len(list.Objects) == 0 {
+			list.Add(widget.NewLabel(tr("No tasks yet")))
+		}
+	}
+	showArchived.OnChanged = func(bool) {
+		render()
+	}
+	render()
+
+	return container.NewVBox(showArchived, list)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"image/color"
+	"log"
+	"maps"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/data/binding"
+)
+
+const PrefTaskStyles = "taskStyles"
+
+// TaskStyle is how a task is marked out wherever it is listed: a color from
+// TaskColors and an emoji or other short icon, either of which may be blank.
+type TaskStyle struct {
+	Color string `json:"color,omitempty"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+// TaskStyles are the styles per task.
+type TaskStyles map[string]TaskStyle
+
+// TaskColors are the colors a task can be given, dark enough for white text
+// on top.
+var TaskColors = []string{"red", "orange", "green", "teal", "blue", "indigo", "purple", "pink", "brown", "gray"}
+
+var taskColorValues = map[string]color.NRGBA{
+	"red":    {0xC6, 0x28, 0x28, 0xFF},
+	"orange": {0xE6, 0x51, 0x00, 0xFF},
+	"green":  {0x2E, 0x7D, 0x32, 0xFF},
+	"teal":   {0x00, 0x79, 0x6B, 0xFF},
+	"blue":   {0x15, 0x65, 0xC0, 0xFF},
+	"indigo": {0x28, 0x35, 0x93, 0xFF},
+	"purple": {0x6A, 0x1B, 0x9A, 0xFF},
+	"pink":   {0xAD, 0x14, 0x57, 0xFF},
+	"brown":  {0x4E, 0x34, 0x2E, 0xFF},
+	"gray":   {0x45, 0x5A, 0x64, 0xFF},
+}
+
+// taskColorName names a color for the task editor.
+func taskColorName(key string) string {
+	switch key {
+	case "red":
+		return tr("Red")
+	case "orange":
+		return tr("Orange")
+	case "green":
+		return tr("Green")
+	case "teal":
+		return tr("Teal")
+	case "blue":
+		return tr("Blue")
+	case "indigo":
+		return tr("Indigo")
+	case "purple":
+		return tr("Purple")
+	case "pink":
+		return tr("Pink")
+	case "brown":
+		return tr("Brown")
+	default:
+		return tr("Gray")
+	}
+}
+
+// Fill returns the style's color, and whether it has one.
+func (s TaskStyle) Fill() (color.Color, bool) {
+	value, ok := taskColorValues[s.Color]
+	return value, ok
+}
+
+// Label puts the icon, if any, in front of a task name.
+func (s TaskStyle) Label(taskName string) string {
+	if s.Icon == "" {
+		return taskName
+	}
+	return s.Icon + " " + taskName
+}
+
+func loadTaskStyles() TaskStyles {
+	styles := TaskStyles{}
+	raw := fyne.CurrentApp().Preferences().String(PrefTaskStyles)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &styles); err != nil {
+			log.Printf("styles: reading styles: %v", err)
+		}
+	}
+	return styles
+}
+
+// saveTaskStyles saves the styles and hands them to bound views.
+func saveTaskStyles(timer *TaskTimer, styles TaskStyles) {
+	raw, err := json.Marshal(styles)
+	if err != nil {
+		log.Printf("styles: saving styles: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefTaskStyles, string(raw))
+	timer.styles.Set(styles)
+}
+
+// taskStyles returns the bound styles, which views must not modify.
+func taskStyles(timer *TaskTimer) TaskStyles {
+	styles, _ := timer.styles.Get()
+	return styles
+}
+
+// setTaskStyle sets a task's style, or removes it if it is blank. The task
+// picker and title are relabelled to match.
+func setTaskStyle(timer *TaskTimer, taskName string, style TaskStyle) {
+	style.Icon = strings.TrimSpace(style.Icon)
+	styles := maps.Clone(taskStyles(timer))
+	if style != (TaskStyle{}) {
+		styles[taskName] = style
+	} else {
+		delete(styles, taskName)
+	}
+	saveTaskStyles(timer, styles)
+
+	timer.taskPicker.SetSelected(timer.taskName)
+}
+
+// renameTaskStyle moves a style along with a renamed task. A task merged
+// into one with its own style keeps the target's.
+func renameTaskStyle(timer *TaskTimer, from, to string) {
+	styles := maps.Clone(taskStyles(timer))
+	style, ok := styles[from]
+	if !ok {
+		return
+	}
+	if _, exists := styles[to]; !exists {
+		styles[to] = style
+	}
+	delete(styles, from)
+	saveTaskStyles(timer, styles)
+}
+
+// taskOption is how a task is listed in the task picker, with its icon.
+func taskOption(timer *TaskTimer, taskName string) string {
+	return taskStyles(timer)[taskName].Label(taskName)
+}
+
+// bindTaskColor fills a rectangle with the selected task's color, or
+// fallback for tasks without one.
+func bindTaskColor(timer *TaskTimer, rect *canvas.Rectangle, fallback color.Color) {
+	listener := binding.NewDataListener(func() {
+		rect.FillColor = fallback
+		if fill, ok := taskStyles(timer)[timer.taskName].Fill(); ok {
+			rect.FillColor = fill
+		}
+		rect.Refresh()
+	})
+	timer.taskTitle.AddListener(listener)
+	timer.styles.AddListener(listener)
+}
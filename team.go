@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefTeamSyncEntries sends the user's entries to the team server
+	PrefTeamSyncEntries = "teamSyncEntries"
+
+	// PrefTeamProjects and PrefTeamManager keep what the server last said
+	// about the user, for when it can't be reached
+	PrefTeamProjects = "teamProjects"
+	PrefTeamManager  = "teamManager"
+)
+
+var errTeamSyncOff = errors.New("team: turn on syncing entries with the workspace in Settings first")
+
+// teamRequest calls the team server with the user's token, decoding the
+// JSON reply into reply if it isn't nil.
+func teamRequest(method, path string, body, reply any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, teamServerURL()+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+keychainSecret(KeyringTeamToken))
+
+	resp, err := teamHTTPClient(false).Do(req)
+	if err != nil {
+		return fmt.Errorf("team: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		if failure.Error != "" {
+			return fmt.Errorf("team: %s", failure.Error)
+		}
+		return fmt.Errorf("team: %s %s: %s", method, path, resp.Status)
+	}
+	if reply == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// teamProjects returns the projects shared with the team.
+func teamProjects() []string {
+	return fyne.CurrentApp().Preferences().StringList(PrefTeamProjects)
+}
+
+// TeamSync sends the user's entries to the team server and takes back the
+// ones their other devices sent, merged the way cloud sync merges. The
+// server only ever hands back the user's own entries.
+type TeamSync struct {
+	mu    sync.Mutex
+	timer *TaskTimer
+}
+
+func NewTeamSync(timer *TaskTimer) *TeamSync {
+	return &TeamSync{timer: timer}
+}
+
+// Run syncs immediately and then every SyncInterval, once the history has
+// loaded.
+func (s *TeamSync) Run() {
+	ticker := time.NewTicker(SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(); err != nil && !errors.Is(err, errTeamSyncOff) {
+			log.Print(err)
+		}
+		<-ticker.C
+	}
+}
+
+// Sync refreshes the user's profile and merges their entries with the
+// server's.
+func (s *TeamSync) Sync() error {
+	prefs := fyne.CurrentApp().Preferences()
+	if teamServerURL() == "" || !prefs.Bool(PrefTeamSyncEntries) {
+		return errTeamSyncOff
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var profile TeamProfile
+	if err := teamRequest(http.MethodGet, "/me", nil, &profile); err != nil {
+		return err
+	}
+	prefs.SetStringList(PrefTeamProjects, profile.Projects)
+	prefs.SetBool(PrefTeamManager, profile.Manager)
+
+	tombstones, err := s.timer.store.Tombstones()
+	if err != nil {
+		return err
+	}
+	local := syncSnapshot{Entries: allEntries(s.timer), Tombstones: tombstones}
+	var merged TeamEntries
+	if err := teamRequest(http.MethodPost, "/entries", TeamEntries{Entries: local.Entries, Tombstones: local.Tombstones}, &merged); err != nil {
+		return err
+	}
+	if sameSnapshot(syncSnapshot{Entries: merged.Entries, Tombstones: merged.Tombstones}, local) {
+		return nil
+	}
+
+	if err := s.timer.store.PutTombstones(merged.Tombstones); err != nil {
+		return err
+	}
+	if err := mergeEntries(s.timer, merged.Entries, teamServerURL()); err != nil {
+		return err
+	}
+	fyne.DoAndWait(func() {
+		for _, entry := range merged.Entries {
+			s.timer.tasks.Ensure(entry.Task)
+		}
+		updateContentView(s.timer)
+	})
+	return nil
+}
+
+// teamSyncNow runs a team sync for the Settings button and reports how it
+// went.
+func teamSyncNow(timer *TaskTimer) {
+	go func() {
+		err := timer.teamSync.Sync()
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(err, timer.window)
+				return
+			}
+			dialog.ShowInformation(tr("Team workspace"), tr("Your entries are in sync with the workspace."), timer.window)
+		})
+	}()
+}
+
+// showTeamReportDialog shows managers the team's time per member and per
+// shared project over a period.
+func showTeamReportDialog(timer *TaskTimer) {
+	fromInput := widget.NewEntry()
+	fromInput.PlaceHolder = "YYYY-MM-DD"
+	toInput := widget.NewEntry()
+	toInput.PlaceHolder = "YYYY-MM-DD"
+	periodSelect := newReportPeriodSelect(fromInput, toInput)
+	report := container.NewVBox()
+
+	load := func() {
+		from, err := time.ParseInLocation("2006-01-02", fromInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		to, err := time.ParseInLocation("2006-01-02", toInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		query := url.Values{
+			"from": {from.Format(time.RFC3339)},
+			"to":   {addDays(to, 1).Format(time.RFC3339)},
+		}
+		report.RemoveAll()
+		report.Add(widget.NewProgressBarInfinite())
+		go func() {
+			var rows []TeamReportRow
+			err := teamRequest(http.MethodGet, "/reports?"+query.Encode(), nil, &rows)
+			fyne.Do(func() {
+				report.RemoveAll()
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				renderTeamReport(report, rows)
+			})
+		}()
+	}
+	showBtn := widget.NewButton(tr("Show"), load)
+	periodSelect.SetSelectedIndex(0)
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(
+				widget.NewFormItem(tr("Period"), periodSelect),
+				widget.NewFormItem(tr("From"), fromInput),
+				widget.NewFormItem(tr("To"), toInput),
+			),
+			showBtn,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		container.NewVScroll(report),
+	)
+	d := dialog.NewCustom(tr("Team Report"), tr("Close"), content, timer.window)
+	d.Resize(fyne.NewSize(500, 550))
+	d.Show()
+	load()
+}
+
+// renderTeamReport lists the total per project across the team, then each
+// member's time with their projects under it.
+func renderTeamReport(box *fyne.Container, rows []TeamReportRow) {
+	if len(rows) == 0 {
+		box.Add(widget.NewLabel(tr("Nothing tracked in this period")))
+		return
+	}
+	projectName := func(project string) string {
+		if project == "" {
+			return tr("Other projects")
+		}
+		return project
+	}
+
+	byProject := make(map[string]time.Duration)
+	byUser := make(map[string]time.Duration)
+	var total time.Duration
+	for _, row := range rows {
+		byProject[projectName(row.Project)] += row.Duration
+		byUser[row.User] += row.Duration
+		total += row.Duration
+	}
+
+	box.Add(widget.NewLabelWithStyle(tr("Total: {{.Duration}}", map[string]any{"Duration": formatDuration(total)}), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	for _, project := range sortedTaskNames(byProject) {
+		box.Add(widget.NewLabel(fmt.Sprintf("%s: %s  %s", project, formatDuration(byProject[project]), formatShare(byProject[project], total))))
+	}
+
+	users := make([]string, 0, len(byUser))
+	for user := range byUser {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	accordion := widget.NewAccordion()
+	for _, user := range users {
+		lines := container.NewVBox()
+		for _, row := range rows {
+			if row.User == user {
+				lines.Add(widget.NewLabel(fmt.Sprintf("%s: %s", projectName(row.Project), formatDuration(row.Duration))))
+			}
+		}
+		accordion.Append(widget.NewAccordionItem(fmt.Sprintf("%s: %s", user, formatDuration(byUser[user])), lines))
+	}
+	box.Add(widget.NewSeparator())
+	box.Add(accordion)
+}
@@ -0,0 +1,637 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTeamServerAddr   = ":8750"
+	DefaultTeamServerConfig = "gotime-team.json"
+	DefaultTeamServerData   = "gotime-team"
+
+	// TeamSyncMaxBody bounds the entries a client may upload in one sync
+	TeamSyncMaxBody = 64 << 20
+
+	// TeamRateLimit is the requests per second an address may send, in
+	// bursts of up to TeamRateBurst
+	TeamRateLimit = 10
+	TeamRateBurst = 40
+
+	// An address may send TeamAuthFailureBurst unknown tokens, and one more
+	// every 1/TeamAuthFailureRate seconds, before it's turned away
+	TeamAuthFailureRate  = 1.0 / 60
+	TeamAuthFailureBurst = 10
+)
+
+// teamMemberName is what a member may be called; the name is also the name
+// of their data file.
+var teamMemberName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// TeamServerConfig lists who may use a team server and the projects the
+// team tracks into. It's a JSON file edited with gotime serve's -add-member
+// and -add-project flags, or by hand. The server rewrites it as tokens are
+// issued and revoked.
+type TeamServerConfig struct {
+	Members  []TeamMember `json:"members"`
+	Projects []string     `json:"projects"`
+}
+
+// TeamMember is a user of the team server, with a token per device or
+// app they use it from.
+type TeamMember struct {
+	Name    string      `json:"name"`
+	Manager bool        `json:"manager,omitempty"`
+	Tokens  []TeamToken `json:"tokens"`
+
+	// TokenHash is the single token of configs written before members had
+	// several; it's moved into Tokens on loading
+	TokenHash string `json:"token_hash,omitempty"`
+}
+
+// TeamToken lets a member in. Only a hash of the token is kept, so the
+// config file doesn't give anyone access.
+type TeamToken struct {
+	ID      string    `json:"id"`
+	Label   string    `json:"label"`
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+}
+
+// newTeamToken issues a token, returning it along with what's kept of it.
+func newTeamToken(label string) (string, TeamToken) {
+	token := rand.Text()
+	return token, TeamToken{
+		ID:      newTeamTokenID(),
+		Label:   label,
+		Hash:    teamTokenHash(token),
+		Created: time.Now().UTC(),
+	}
+}
+
+// newTeamTokenID names a token for listing and revoking it.
+func newTeamTokenID() string {
+	return strings.ToLower(rand.Text()[:10])
+}
+
+func loadTeamServerConfig(path string) (TeamServerConfig, error) {
+	var config TeamServerConfig
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return config, fmt.Errorf("reading %s: %w", path, err)
+	}
+	migrated := false
+	for i, member := range config.Members {
+		if member.TokenHash != "" {
+			legacy := TeamToken{ID: newTeamTokenID(), Label: "initial", Hash: member.TokenHash}
+			config.Members[i].Tokens = append(member.Tokens, legacy)
+			config.Members[i].TokenHash = ""
+			migrated = true
+		}
+	}
+	// Saved straight away so the IDs given to old tokens stay the same
+	if migrated {
+		if err := saveTeamServerConfig(path, config); err != nil {
+			log.Printf("team server: saving %s: %v", path, err)
+		}
+	}
+	return config, nil
+}
+
+func saveTeamServerConfig(path string, config TeamServerConfig) error {
+	raw, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func teamTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TeamEntries is a member's entries on the server, with the tombstones of
+// the ones they deleted. It's what a sync sends and gets back.
+type TeamEntries struct {
+	Entries    []Entry              `json:"entries"`
+	Tombstones map[string]time.Time `json:"tombstones"`
+}
+
+// TeamReportRow is the time a member tracked on a project. An empty project
+// holds the time on projects that aren't shared with the team.
+type TeamReportRow struct {
+	User     string        `json:"user"`
+	Project  string        `json:"project"`
+	Duration time.Duration `json:"duration"`
+}
+
+// TeamProfile is what the server tells a member about themselves.
+type TeamProfile struct {
+	User     string   `json:"user"`
+	Manager  bool     `json:"manager"`
+	Projects []string `json:"projects"`
+}
+
+// TeamServer keeps each member's entries in a file of their own under dir
+// and relays presence between members. Members only ever get their own
+// entries back; managers also get reports totalling everyone's time on the
+// shared projects.
+type TeamServer struct {
+	// configMu guards config, which is replaced whole on every change and
+	// saved to configPath
+	configMu   sync.Mutex
+	config     TeamServerConfig
+	configPath string
+	dir        string
+
+	// mu guards the data files, so two devices of one member can't
+	// interleave their syncs
+	mu sync.Mutex
+
+	presenceMu sync.Mutex
+	presence   map[string]Teammate
+	followers  map[chan Teammate]struct{}
+
+	// limiter bounds each address's requests, and failures its attempts
+	// at guessing a token
+	limiter  *rateLimiter
+	failures *rateLimiter
+}
+
+func NewTeamServer(configPath string, config TeamServerConfig, dir string) *TeamServer {
+	return &TeamServer{
+		config:     config,
+		configPath: configPath,
+		dir:        dir,
+		presence:   make(map[string]Teammate),
+		followers:  make(map[chan Teammate]struct{}),
+		limiter:    newRateLimiter(TeamRateLimit, TeamRateBurst),
+		failures:   newRateLimiter(TeamAuthFailureRate, TeamAuthFailureBurst),
+	}
+}
+
+func (s *TeamServer) currentConfig() TeamServerConfig {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	return s.config
+}
+
+// updateConfig changes a copy of the config and saves it, keeping the old
+// one if change or saving fails.
+func (s *TeamServer) updateConfig(change func(config *TeamServerConfig) error) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	config := TeamServerConfig{Projects: slices.Clone(s.config.Projects)}
+	for _, member := range s.config.Members {
+		member.Tokens = slices.Clone(member.Tokens)
+		config.Members = append(config.Members, member)
+	}
+	if err := change(&config); err != nil {
+		return err
+	}
+	if err := saveTeamServerConfig(s.configPath, config); err != nil {
+		return err
+	}
+	s.config = config
+	return nil
+}
+
+func (s *TeamServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /members", s.authorized(s.handleMembers))
+	mux.HandleFunc("POST /members/{name}/tokens", s.authorized(s.handleIssueToken))
+	mux.HandleFunc("DELETE /members/{name}/tokens/{id}", s.authorized(s.handleRevokeToken))
+	mux.HandleFunc("GET /me", s.authorized(s.handleMe))
+	mux.HandleFunc("POST /entries", s.authorized(s.handleEntries))
+	mux.HandleFunc("GET /reports", s.authorized(s.handleReports))
+	mux.HandleFunc("POST /presence", s.authorized(s.handlePresence))
+	mux.HandleFunc("GET /events", s.authorized(s.handleEvents))
+	mux.HandleFunc("POST /shares", s.authorized(s.handleCreateShare))
+	mux.HandleFunc("GET /shares", s.authorized(s.handleShares))
+	mux.HandleFunc("DELETE /shares/{token}", s.authorized(s.handleDeleteShare))
+	mux.HandleFunc("GET /r/{token}", s.handleSharedReport)
+	return s.limited(mux)
+}
+
+// teamHandler handles a request from a member, who signed in with the
+// token with ID tokenID.
+type teamHandler func(w http.ResponseWriter, r *http.Request, member TeamMember, tokenID string)
+
+// limited turns away addresses sending too many requests, or that have
+// sent too many unknown tokens lately.
+func (s *TeamServer) limited(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		now := time.Now()
+		if s.failures.Empty(host, now) || !s.limiter.Allow(host, now) {
+			w.Header().Set("Retry-After", "1")
+			companionError(w, http.StatusTooManyRequests, "too many requests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized looks the bearer token up among the members. Every token's
+// hash is compared, so the time taken doesn't tell how far a guess got.
+func (s *TeamServer) authorized(next teamHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		hash := []byte(teamTokenHash(token))
+		var found *TeamMember
+		var tokenID string
+		config := s.currentConfig()
+		for i, member := range config.Members {
+			for _, t := range member.Tokens {
+				if subtle.ConstantTimeCompare(hash, []byte(t.Hash)) == 1 && token != "" {
+					found, tokenID = &config.Members[i], t.ID
+				}
+			}
+		}
+		if found == nil {
+			host, _, _ := net.SplitHostPort(r.RemoteAddr)
+			s.failures.Allow(host, time.Now())
+			companionError(w, http.StatusUnauthorized, "unknown token")
+			return
+		}
+		next(w, r, *found, tokenID)
+	}
+}
+
+func (s *TeamServer) handleMe(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	companionReply(w, TeamProfile{
+		User:     member.Name,
+		Manager:  member.Manager,
+		Projects: append([]string{}, s.currentConfig().Projects...),
+	})
+}
+
+// handleEntries merges a member's entries into the ones on the server, the
+// same way cloud sync merges two devices, and replies with the result.
+func (s *TeamServer) handleEntries(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	var sent TeamEntries
+	if err := json.NewDecoder(io.LimitReader(r.Body, TeamSyncMaxBody)).Decode(&sent); err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.load(member.Name)
+	if err != nil {
+		log.Printf("team server: %s: %v", member.Name, err)
+		companionError(w, http.StatusInternalServerError, "reading entries failed")
+		return
+	}
+	merged := mergeSnapshots(
+		syncSnapshot{Entries: stored.Entries, Tombstones: stored.Tombstones},
+		syncSnapshot{Entries: sent.Entries, Tombstones: sent.Tombstones},
+	)
+	result := TeamEntries{Entries: merged.Entries, Tombstones: merged.Tombstones}
+	if err := s.save(member.Name, result); err != nil {
+		log.Printf("team server: %s: %v", member.Name, err)
+		companionError(w, http.StatusInternalServerError, "saving entries failed")
+		return
+	}
+	companionReply(w, result)
+}
+
+// handleReports totals each member's time per shared project over
+// [from, to), given as RFC 3339 times. Time on other projects is totalled
+// without naming them. Only managers may ask.
+func (s *TeamServer) handleReports(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	if !member.Manager {
+		companionError(w, http.StatusForbidden, "only managers can see team reports")
+		return
+	}
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		companionError(w, http.StatusBadRequest, "from: "+err.Error())
+		return
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		companionError(w, http.StatusBadRequest, "to: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config := s.currentConfig()
+	rows := []TeamReportRow{}
+	for _, m := range config.Members {
+		stored, err := s.load(m.Name)
+		if err != nil {
+			log.Printf("team server: %s: %v", m.Name, err)
+			companionError(w, http.StatusInternalServerError, "reading entries failed")
+			return
+		}
+		totals := make(map[string]time.Duration)
+		for _, entry := range (EntryFilter{From: from, To: to}).Apply(stored.Entries) {
+			project := entry.Project
+			if len(config.Projects) > 0 && !slices.Contains(config.Projects, project) {
+				project = ""
+			}
+			totals[project] += entry.Duration
+		}
+		for project, total := range totals {
+			rows = append(rows, TeamReportRow{User: m.Name, Project: project, Duration: total})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].User != rows[j].User {
+			return rows[i].User < rows[j].User
+		}
+		return rows[i].Project < rows[j].Project
+	})
+	companionReply(w, rows)
+}
+
+// handlePresence relays what a member is timing to everyone following the
+// event stream. The user is always the member the token belongs to.
+func (s *TeamServer) handlePresence(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	var teammate Teammate
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&teammate); err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	teammate.User = member.Name
+
+	s.presenceMu.Lock()
+	s.presence[member.Name] = teammate
+	for follower := range s.followers {
+		select {
+		case follower <- teammate:
+		default:
+			// A follower that can't keep up catches up on reconnecting
+		}
+	}
+	s.presenceMu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEvents streams presence as server-sent events, starting with
+// everyone's latest.
+func (s *TeamServer) handleEvents(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		companionError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	events := make(chan Teammate, 16)
+	s.presenceMu.Lock()
+	latest := make([]Teammate, 0, len(s.presence))
+	for _, teammate := range s.presence {
+		latest = append(latest, teammate)
+	}
+	s.followers[events] = struct{}{}
+	s.presenceMu.Unlock()
+	defer func() {
+		s.presenceMu.Lock()
+		delete(s.followers, events)
+		s.presenceMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	send := func(teammate Teammate) bool {
+		raw, _ := json.Marshal(teammate)
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", raw); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	for _, teammate := range latest {
+		send(teammate)
+	}
+	flusher.Flush()
+	for {
+		select {
+		case teammate := <-events:
+			if !send(teammate) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *TeamServer) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *TeamServer) load(name string) (TeamEntries, error) {
+	var stored TeamEntries
+	raw, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return stored, nil
+	}
+	if err != nil {
+		return stored, err
+	}
+	err = json.Unmarshal(raw, &stored)
+	return stored, err
+}
+
+func (s *TeamServer) save(name string, entries TeamEntries) error {
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(name) + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(name))
+}
+
+// runServeCommand runs a team server, or adds a member or project to its
+// config and exits.
+func runServeCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", DefaultTeamServerAddr, "address to listen on")
+	configPath := flags.String("config", DefaultTeamServerConfig, "members and projects file")
+	dataDir := flags.String("data", DefaultTeamServerData, "directory for the members' entries")
+	addMember := flags.String("add-member", "", "add a member, print their token and exit")
+	manager := flags.Bool("manager", false, "with -add-member, let the member see team reports")
+	addProject := flags.String("add-project", "", "share a project with the team and exit")
+	useTLS := flags.Bool("tls", true, "serve HTTPS, with a self-signed certificate unless -cert and -key are given")
+	certPath := flags.String("cert", "", "TLS certificate file")
+	keyPath := flags.String("key", "", "TLS private key file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config, err := loadTeamServerConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case *addMember != "":
+		if !teamMemberName.MatchString(*addMember) {
+			fmt.Fprintln(stderr, "gotime: member names may only use letters, digits, '.', '_' and '-'")
+			return 2
+		}
+		if slices.ContainsFunc(config.Members, func(m TeamMember) bool { return m.Name == *addMember }) {
+			fmt.Fprintf(stderr, "gotime: %s is already a member\n", *addMember)
+			return 1
+		}
+		token, issued := newTeamToken("command line")
+		config.Members = append(config.Members, TeamMember{Name: *addMember, Manager: *manager, Tokens: []TeamToken{issued}})
+		if err := saveTeamServerConfig(*configPath, config); err != nil {
+			fmt.Fprintf(stderr, "gotime: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, token)
+		return 0
+	case *addProject != "":
+		if !slices.Contains(config.Projects, *addProject) {
+			config.Projects = append(config.Projects, *addProject)
+			sort.Strings(config.Projects)
+		}
+		if err := saveTeamServerConfig(*configPath, config); err != nil {
+			fmt.Fprintf(stderr, "gotime: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(config.Members) == 0 {
+		fmt.Fprintf(stderr, "gotime: no members in %s yet, add one with -add-member\n", *configPath)
+		return 1
+	}
+	if err := os.MkdirAll(*dataDir, 0o700); err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewTeamServer(*configPath, config, *dataDir).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    1 << 16,
+	}
+	if !*useTLS {
+		fmt.Fprintf(stdout, "Serving %d members on %s without TLS\n", len(config.Members), *addr)
+		err = server.ListenAndServe()
+	} else {
+		if *certPath == "" && *keyPath == "" {
+			*certPath, *keyPath = filepath.Join(*dataDir, TeamCertFile), filepath.Join(*dataDir, TeamKeyFile)
+			if err := ensureSelfSignedCert(*certPath, *keyPath); err != nil {
+				fmt.Fprintf(stderr, "gotime: %v\n", err)
+				return 1
+			}
+		}
+		fingerprint, err := certFileFingerprint(*certPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotime: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Serving %d members on %s\nCertificate fingerprint: %s\n", len(config.Members), *addr, fingerprint)
+		err = server.ListenAndServeTLS(*certPath, *keyPath)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotime: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// rateLimiter keeps a token bucket per key, refilled at rate a second up
+// to burst.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate, burst float64) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: burst, buckets: make(map[string]*rateBucket)}
+}
+
+// refill tops up the key's bucket for the time since it was last used.
+func (l *rateLimiter) refill(key string, now time.Time) *rateBucket {
+	bucket, ok := l.buckets[key]
+	if !ok {
+		// Full buckets are forgotten now and then, so the map doesn't grow
+		// with every address ever seen
+		if len(l.buckets) >= 10000 {
+			for k, b := range l.buckets {
+				if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+					delete(l.buckets, k)
+				}
+			}
+		}
+		bucket = &rateBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	return bucket
+}
+
+// Allow takes a token from the key's bucket, reporting whether there was
+// one.
+func (l *rateLimiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.refill(key, now)
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// Empty reports whether the key's bucket is out of tokens, without taking
+// one.
+func (l *rateLimiter) Empty(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.refill(key, now).tokens < 1
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// teamClient makes requests to a team server with a member's token.
+type teamClient struct {
+	t      *testing.T
+	server *httptest.Server
+}
+
+func (c teamClient) do(token, method, path string, body, reply any) int {
+	c.t.Helper()
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, bytes.NewReader(raw))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.server.Client().Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if reply != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(reply); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// newTestTeamServer serves the team config given as JSON.
+func newTestTeamServer(t *testing.T, raw string) teamClient {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.json")
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadTeamServerConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewTeamServer(path, config, dir).Handler())
+	t.Cleanup(server.Close)
+	return teamClient{t: t, server: server}
+}
+
+func TestTeamServerLegacyTokens(t *testing.T) {
+	client := newTestTeamServer(t, `{"members": [
+		{"name": "ann", "manager": true, "token_hash": "`+teamTokenHash("ann-token")+`"},
+		{"name": "bob", "token_hash": "`+teamTokenHash("bob-token")+`"}
+	]}`)
+
+	var members []TeamMemberInfo
+	if status := client.do("ann-token", http.MethodGet, "/members", nil, &members); status != http.StatusOK {
+		t.Fatalf("GET /members: %d", status)
+	}
+	ids := map[string]string{}
+	for _, member := range members {
+		if len(member.Tokens) != 1 {
+			t.Fatalf("%s has %d tokens, want 1", member.Name, len(member.Tokens))
+		}
+		token := member.Tokens[0]
+		ids[member.Name] = token.ID
+		if want := member.Name == "ann"; token.Current != want {
+			t.Errorf("%s's token is current: %v, want %v", member.Name, token.Current, want)
+		}
+	}
+	if ids["ann"] == ids["bob"] {
+		t.Fatalf("migrated tokens share the ID %q", ids["ann"])
+	}
+
+	// Bob's token ID under Ann's name revokes nothing
+	if status := client.do("ann-token", http.MethodDelete, "/members/ann/tokens/"+ids["bob"], nil, nil); status != http.StatusNotFound {
+		t.Errorf("revoking another member's token under one's own name: %d, want 404", status)
+	}
+	if status := client.do("bob-token", http.MethodDelete, "/members/ann/tokens/"+ids["ann"], nil, nil); status != http.StatusForbidden {
+		t.Errorf("a member revoking a manager's token: %d, want 403", status)
+	}
+	if status := client.do("ann-token", http.MethodDelete, "/members/bob/tokens/"+ids["bob"], nil, nil); status != http.StatusNoContent {
+		t.Fatalf("revoking bob's token: %d", status)
+	}
+	if status := client.do("bob-token", http.MethodGet, "/me", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("revoked token still works: %d", status)
+	}
+	if status := client.do("ann-token", http.MethodGet, "/me", nil, nil); status != http.StatusOK {
+		t.Errorf("revoking bob's token signed ann out: %d", status)
+	}
+	if status := client.do("ann-token", http.MethodDelete, "/members/carol/tokens/"+ids["ann"], nil, nil); status != http.StatusNotFound {
+		t.Errorf("revoking an unknown member's token: %d, want 404", status)
+	}
+}
+
+func TestLoadTeamServerConfigKeepsTokenIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team.json")
+	raw := `{"members": [{"name": "ann", "token_hash": "` + teamTokenHash("ann-token") + `"}]}`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	first, err := loadTeamServerConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := loadTeamServerConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := second.Members[0].Tokens, first.Members[0].Tokens; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("tokens after reloading = %+v, want %+v", got, want)
+	}
+}
+
+func TestSharedReportHidesPrivateProjects(t *testing.T) {
+	client := newTestTeamServer(t, `{"projects": ["Web"], "members": [
+		{"name": "ann", "manager": true, "token_hash": "`+teamTokenHash("ann-token")+`"},
+		{"name": "bob", "token_hash": "`+teamTokenHash("bob-token")+`"}
+	]}`)
+	start := time.Now().UTC().Add(-2 * time.Hour)
+	entry := func(id, task, project string) Entry {
+		return Entry{ID: id, Task: task, Project: project, Start: start, Duration: time.Hour, Updated: start}
+	}
+	sent := TeamEntries{Entries: []Entry{entry("1", "Landing page", "Web"), entry("2", "Job interview", "Private")}}
+	if status := client.do("bob-token", http.MethodPost, "/entries", sent, nil); status != http.StatusOK {
+		t.Fatalf("POST /entries: %d", status)
+	}
+
+	var share TeamShare
+	request := TeamShare{Title: "Hours", From: start.Add(-time.Hour), Team: true}
+	if status := client.do("ann-token", http.MethodPost, "/shares", request, &share); status != http.StatusOK {
+		t.Fatalf("POST /shares: %d", status)
+	}
+	resp, err := client.server.Client().Get(client.server.URL + "/r/" + share.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(raw)
+	if strings.Contains(page, "Job interview") {
+		t.Error("the shared report names a task on a private project")
+	}
+	for _, want := range []string{"Landing page", "Other projects", "2.00"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("the shared report is missing %q", want)
+		}
+	}
+}
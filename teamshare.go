@@ -0,0 +1,472 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// teamSharesFile holds the shared report links on the server. A member's
+// name can't start with an underscore, so it never clashes with their file.
+const teamSharesFile = "_shares.json"
+
+// TeamShare is a read-only report anyone with its link can see, covering
+// the owner's entries, or the whole team's if a manager shared it, on a
+// project over [From, To). A zero To leaves the report open-ended.
+type TeamShare struct {
+	Token   string    `json:"token"`
+	Owner   string    `json:"owner"`
+	Title   string    `json:"title"`
+	Project string    `json:"project,omitempty"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to,omitempty"`
+	// Zone is the time zone the report's days are counted in, and Offset
+	// its offset east of UTC in seconds for a system zone without a name
+	Zone    string    `json:"zone,omitempty"`
+	Offset  int       `json:"offset,omitempty"`
+	Team    bool      `json:"team,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func (s *TeamServer) loadShares() ([]TeamShare, error) {
+	var shares []TeamShare
+	raw, err := os.ReadFile(filepath.Join(s.dir, teamSharesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return shares, nil
+	}
+	if err != nil {
+		return shares, err
+	}
+	err = json.Unmarshal(raw, &shares)
+	return shares, err
+}
+
+func (s *TeamServer) saveShares(shares []TeamShare) error {
+	raw, err := json.Marshal(shares)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, teamSharesFile)
+	if err := os.WriteFile(path+".tmp", raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// handleCreateShare makes a new link for the member. Only managers may
+// share the whole team's time.
+func (s *TeamServer) handleCreateShare(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	var share TeamShare
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&share); err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if share.Team && !member.Manager {
+		companionError(w, http.StatusForbidden, "only managers can share the team's time")
+		return
+	}
+	if share.Zone != "" {
+		if _, err := time.LoadLocation(share.Zone); err != nil {
+			companionError(w, http.StatusBadRequest, "zone: "+err.Error())
+			return
+		}
+	}
+	share.Token = rand.Text()
+	share.Owner = member.Name
+	share.Created = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shares, err := s.loadShares()
+	if err == nil {
+		err = s.saveShares(append(shares, share))
+	}
+	if err != nil {
+		log.Printf("team server: shares: %v", err)
+		companionError(w, http.StatusInternalServerError, "saving the link failed")
+		return
+	}
+	companionReply(w, share)
+}
+
+// handleShares lists the member's links, newest first.
+func (s *TeamServer) handleShares(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	s.mu.Lock()
+	shares, err := s.loadShares()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("team server: shares: %v", err)
+		companionError(w, http.StatusInternalServerError, "reading links failed")
+		return
+	}
+	own := []TeamShare{}
+	for _, share := range shares {
+		if share.Owner == member.Name {
+			own = append(own, share)
+		}
+	}
+	sort.Slice(own, func(i, j int) bool {
+		return own[i].Created.After(own[j].Created)
+	})
+	companionReply(w, own)
+}
+
+// handleDeleteShare revokes one of the member's links.
+func (s *TeamServer) handleDeleteShare(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	token := r.PathValue("token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shares, err := s.loadShares()
+	if err != nil {
+		log.Printf("team server: shares: %v", err)
+		companionError(w, http.StatusInternalServerError, "reading links failed")
+		return
+	}
+	kept := slices.DeleteFunc(shares, func(share TeamShare) bool {
+		return share.Token == token && share.Owner == member.Name
+	})
+	if err := s.saveShares(kept); err != nil {
+		log.Printf("team server: shares: %v", err)
+		companionError(w, http.StatusInternalServerError, "saving links failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sharedReport is what a shared report page shows.
+type sharedReport struct {
+	Title   string
+	Period  string
+	Project string
+	Total   string
+	Hours   string
+	Tasks   []sharedReportRow
+	Days    []sharedReportRow
+	Members []sharedReportRow
+	Updated string
+}
+
+type sharedReportRow struct {
+	Name     string
+	Duration string
+	Hours    string
+}
+
+var sharedReportTemplate = htmltemplate.Must(htmltemplate.New("shared").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+td { padding: 0.2em 1em 0.2em 0; }
+td.hours { text-align: right; }
+.muted { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">{{.Period}}{{if .Project}} · {{.Project}}{{end}}</p>
+<p><strong>Total:</strong> {{.Total}} <span class="muted">({{.Hours}} hours)</span></p>
+{{if .Members}}<h2>People</h2>
+<table>
+{{range .Members}}<tr><td>{{.Name}}</td><td>{{.Duration}}</td><td class="hours">{{.Hours}}</td></tr>
+{{end}}</table>
+{{end}}<h2>Tasks</h2>
+<table>
+{{range .Tasks}}<tr><td>{{.Name}}</td><td>{{.Duration}}</td><td class="hours">{{.Hours}}</td></tr>
+{{else}}<tr><td>Nothing tracked yet</td></tr>
+{{end}}</table>
+<h2>Days</h2>
+<table>
+{{range .Days}}<tr><td>{{.Name}}</td><td>{{.Duration}}</td><td class="hours">{{.Hours}}</td></tr>
+{{end}}</table>
+<p class="muted">Updated {{.Updated}}</p>
+</body>
+</html>
+`))
+
+// handleSharedReport renders a shared report for anyone with its link,
+// from the entries as they are now.
+func (s *TeamServer) handleSharedReport(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shares, err := s.loadShares()
+	if err != nil {
+		log.Printf("team server: shares: %v", err)
+		http.Error(w, "Reading the report failed", http.StatusInternalServerError)
+		return
+	}
+	i := slices.IndexFunc(shares, func(share TeamShare) bool { return share.Token == token })
+	if token == "" || i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	share := shares[i]
+
+	// A member removed from the config takes their links with them, and a
+	// manager's team links only cover the team while they're a manager
+	config := s.currentConfig()
+	j := slices.IndexFunc(config.Members, func(m TeamMember) bool { return m.Name == share.Owner })
+	if j < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	share.Team = share.Team && config.Members[j].Manager
+	owners := []string{share.Owner}
+	if share.Team {
+		owners = nil
+		for _, member := range config.Members {
+			owners = append(owners, member.Name)
+		}
+	}
+
+	filter := EntryFilter{Project: share.Project, From: share.From, To: share.To}
+	byMember := make(map[string][]Entry)
+	for _, owner := range owners {
+		stored, err := s.load(owner)
+		if err != nil {
+			log.Printf("team server: %s: %v", owner, err)
+			http.Error(w, "Reading the report failed", http.StatusInternalServerError)
+			return
+		}
+		byMember[owner] = filter.Apply(stored.Entries)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharedReportTemplate.Execute(w, buildSharedReport(share, byMember, config.Projects, time.Now())); err != nil {
+		log.Printf("team server: rendering report: %v", err)
+	}
+}
+
+// buildSharedReport totals the entries per task and per day, and per
+// member for a team share, with days counted in the share's time zone. As in
+// team reports, a team share doesn't name tasks on projects the team doesn't
+// share; their time is totalled as other projects.
+func buildSharedReport(share TeamShare, byMember map[string][]Entry, projects []string, now time.Time) sharedReport {
+	zone := time.FixedZone("", share.Offset)
+	if share.Zone != "" {
+		if loc, err := time.LoadLocation(share.Zone); err == nil {
+			zone = loc
+		}
+	}
+	row := func(name string, d time.Duration) sharedReportRow {
+		return sharedReportRow{Name: name, Duration: formatHumanDuration(d), Hours: strconv.FormatFloat(d.Hours(), 'f', 2, 64)}
+	}
+
+	tasks, days := make(map[string]time.Duration), make(map[string]time.Duration)
+	report := sharedReport{Title: share.Title, Project: share.Project}
+	var total time.Duration
+	names := make([]string, 0, len(byMember))
+	for name := range byMember {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var memberTotal time.Duration
+		for _, entry := range byMember[name] {
+			task := entry.Task
+			if share.Team && len(projects) > 0 && !slices.Contains(projects, entry.Project) {
+				task = "Other projects"
+			}
+			tasks[task] += entry.Duration
+			days[entry.Start.In(zone).Format("2006-01-02 Mon")] += entry.Duration
+			memberTotal += entry.Duration
+		}
+		if share.Team {
+			report.Members = append(report.Members, row(name, memberTotal))
+		}
+		total += memberTotal
+	}
+	for _, task := range sortedTaskNames(tasks) {
+		report.Tasks = append(report.Tasks, row(task, tasks[task]))
+	}
+	dayNames := make([]string, 0, len(days))
+	for day := range days {
+		dayNames = append(dayNames, day)
+	}
+	sort.Strings(dayNames)
+	for _, day := range dayNames {
+		report.Days = append(report.Days, row(day, days[day]))
+	}
+
+	report.Total = formatHumanDuration(total)
+	report.Hours = strconv.FormatFloat(total.Hours(), 'f', 2, 64)
+	report.Period = "From " + share.From.In(zone).Format("2006-01-02")
+	if !share.To.IsZero() {
+		report.Period += " to " + share.To.In(zone).AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	report.Updated = now.In(zone).Format("2006-01-02 15:04 MST")
+	return report
+}
+
+// teamShareURL is the link to a shared report.
+func teamShareURL(share TeamShare) string {
+	return teamServerURL() + "/r/" + share.Token
+}
+
+// showShareReportDialog makes a read-only link on the team server to the
+// report the stats view is filtered to, and lists the links made before
+// so they can be copied or revoked.
+func showShareReportDialog(timer *TaskTimer, filter EntryFilter) {
+	prefs := fyne.CurrentApp().Preferences()
+	if !prefs.Bool(PrefTeamSyncEntries) {
+		dialog.ShowInformation(tr("Share Report"), tr("Turn on syncing your entries with the workspace in Settings to share reports."), timer.window)
+		return
+	}
+	if filter.From.IsZero() {
+		dialog.ShowInformation(tr("Share Report"), tr("Pick the first day of the report to share."), timer.window)
+		return
+	}
+
+	title := tr("Hours")
+	if filter.Project != "" {
+		title = tr("Hours on {{.Project}}", map[string]any{"Project": filter.Project})
+	}
+	titleInput := widget.NewEntry()
+	titleInput.SetText(title)
+	period := formatDate(filter.From)
+	if !filter.To.IsZero() {
+		period += " – " + formatDate(addDays(filter.To, -1))
+	} else {
+		period += " – " + tr("ongoing")
+	}
+	project := filter.Project
+	if project == "" {
+		project = tr(AllProjects)
+	}
+	teamCheck := widget.NewCheck(tr("Include the whole team"), nil)
+	if !prefs.Bool(PrefTeamManager) {
+		teamCheck.Hide()
+	}
+
+	list := container.NewVBox()
+	var render func()
+	render = func() {
+		go func() {
+			var shares []TeamShare
+			err := teamRequest(http.MethodGet, "/shares", nil, &shares)
+			fyne.Do(func() {
+				list.RemoveAll()
+				if err != nil {
+					list.Add(widget.NewLabel(err.Error()))
+					return
+				}
+				if len(shares) == 0 {
+					list.Add(widget.NewLabel(tr("No shared links")))
+				}
+				for _, share := range shares {
+					label := widget.NewLabel(share.Title)
+					label.Truncation = fyne.TextTruncateEllipsis
+					copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+						fyne.CurrentApp().Clipboard().SetContent(teamShareURL(share))
+					})
+					token := share.Token
+					revokeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+						dialog.ShowConfirm(tr("Revoke Link"), tr("Anyone with this link will no longer see the report."), func(ok bool) {
+							if !ok {
+								return
+							}
+							go func() {
+								err := teamRequest(http.MethodDelete, "/shares/"+token, nil, nil)
+								fyne.Do(func() {
+									if err != nil {
+										dialog.ShowError(err, timer.window)
+										return
+									}
+									render()
+								})
+							}()
+						}, timer.window)
+					})
+					list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(copyBtn, revokeBtn), label))
+				}
+			})
+		}()
+	}
+	render()
+
+	linkEntry := widget.NewEntry()
+	linkEntry.Hide()
+	createBtn := widget.NewButtonWithIcon(tr("Create link"), theme.MailSendIcon(), func() {
+		share := TeamShare{
+			Title:   strings.TrimSpace(titleInput.Text),
+			Project: filter.Project,
+			From:    filter.From,
+			To:      filter.To,
+			Team:    teamCheck.Checked,
+		}
+		// The system zone is called Local, which means the server's own
+		// zone there
+		if name := time.Local.String(); name != "Local" {
+			share.Zone = name
+		} else {
+			_, share.Offset = time.Now().Zone()
+		}
+		go func() {
+			var created TeamShare
+			err := teamRequest(http.MethodPost, "/shares", share, &created)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				link := teamShareURL(created)
+				fyne.CurrentApp().Clipboard().SetContent(link)
+				linkEntry.SetText(link)
+				linkEntry.Show()
+				render()
+			})
+		}()
+	})
+
+	help := widget.NewLabel(tr("Anyone with the link sees the hours and tasks, but not the notes. It follows your entries as they sync."))
+	help.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(
+				widget.NewFormItem(tr("Title"), titleInput),
+				widget.NewFormItem(tr("Period"), widget.NewLabel(period)),
+				widget.NewFormItem(tr("Project"), widget.NewLabel(project)),
+			),
+			teamCheck,
+			help,
+			createBtn,
+			linkEntry,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		container.NewVScroll(list),
+	)
+	d := dialog.NewCustom(tr("Share Report"), tr("Close"), content, timer.window)
+	d.Resize(fyne.NewSize(500, 550))
+	d.Show()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	// PrefTeamCertFingerprint pins the team server's certificate by its
+	// SHA-256 fingerprint, for servers with a self-signed one
+	PrefTeamCertFingerprint = "teamCertFingerprint"
+
+	// TeamCertFile and TeamKeyFile are the self-signed certificate and its
+	// key, kept in the server's data directory
+	TeamCertFile = "tls-cert.pem"
+	TeamKeyFile  = "tls-key.pem"
+
+	// TeamCertValidity is how long a self-signed certificate lasts
+	TeamCertValidity = 5 * 365 * 24 * time.Hour
+)
+
+// ensureSelfSignedCert writes a self-signed certificate and its key unless
+// both exist. The certificate names this host and its addresses, so it
+// works on a LAN once clients pin it.
+func ensureSelfSignedCert(certPath, keyPath string) error {
+	_, certErr := os.Stat(certPath)
+	_, keyErr := os.Stat(keyPath)
+	if certErr == nil && keyErr == nil {
+		return nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host, Organization: []string{"gotime team server"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(TeamCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	if host != "" {
+		template.DNSNames = append(template.DNSNames, host, host+".local")
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				template.IPAddresses = append(template.IPAddresses, ipNet.IP)
+			}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+// certFileFingerprint returns the fingerprint of the first certificate in
+// a PEM file.
+func certFileFingerprint(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("%s: no certificate found", path)
+	}
+	return certFingerprint(block.Bytes), nil
+}
+
+// certFingerprint writes a certificate's SHA-256 as colon-separated hex,
+// the way browsers show it.
+func certFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	pairs := make([]string, len(sum))
+	for i, b := range sum {
+		pairs[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(pairs, ":")
+}
+
+// normalizeFingerprint lets a fingerprint be pasted with or without colons
+// and spaces, in either case.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToUpper(strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(fingerprint)))
+}
+
+// teamHTTPClient returns the client for the team server. With a pinned
+// fingerprint, the server's certificate must match it, and needn't be
+// signed by a known authority; otherwise it's checked as usual. A stream
+// client has no overall timeout.
+func teamHTTPClient(stream bool) *http.Client {
+	pinned := normalizeFingerprint(fyne.CurrentApp().Preferences().String(PrefTeamCertFingerprint))
+	if pinned == "" {
+		if stream {
+			return streamClient
+		}
+		return httpClient
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		// The fingerprint check below stands in for the usual chain check
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || normalizeFingerprint(certFingerprint(rawCerts[0])) != pinned {
+				return errors.New("team: the server's certificate doesn't match the fingerprint in Settings")
+			}
+			return nil
+		},
+	}
+	client := &http.Client{Transport: transport}
+	if !stream {
+		client.Timeout = HTTPTimeout
+	}
+	return client
+}
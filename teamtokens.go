@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TeamTokenInfo describes a token without giving it away. Current marks
+// the one the request was made with.
+type TeamTokenInfo struct {
+	ID      string    `json:"id"`
+	Label   string    `json:"label"`
+	Created time.Time `json:"created"`
+	Current bool      `json:"current,omitempty"`
+}
+
+// TeamMemberInfo is a member and their tokens, as listed in Settings.
+type TeamMemberInfo struct {
+	Name    string          `json:"name"`
+	Manager bool            `json:"manager"`
+	Tokens  []TeamTokenInfo `json:"tokens"`
+}
+
+var errTeamNoMember = errors.New("no such member")
+
+// IssuedToken is a new token, the only time it's ever sent.
+type IssuedToken struct {
+	TeamTokenInfo
+	Member string `json:"member"`
+	Token  string `json:"token"`
+}
+
+// handleMembers lists the member's own tokens, or every member's for a
+// manager.
+func (s *TeamServer) handleMembers(w http.ResponseWriter, r *http.Request, member TeamMember, tokenID string) {
+	members := []TeamMemberInfo{}
+	for _, m := range s.currentConfig().Members {
+		if m.Name != member.Name && !member.Manager {
+			continue
+		}
+		info := TeamMemberInfo{Name: m.Name, Manager: m.Manager, Tokens: []TeamTokenInfo{}}
+		for _, t := range m.Tokens {
+			current := m.Name == member.Name && t.ID == tokenID
+			info.Tokens = append(info.Tokens, TeamTokenInfo{ID: t.ID, Label: t.Label, Created: t.Created, Current: current})
+		}
+		members = append(members, info)
+	}
+	companionReply(w, members)
+}
+
+// handleIssueToken gives a member a new token, e.g. for another device.
+// Members may issue their own; managers may issue anyone's, adding the
+// member if they're new.
+func (s *TeamServer) handleIssueToken(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	var request struct {
+		Label   string `json:"label"`
+		Manager bool   `json:"manager"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&request); err != nil {
+		companionError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := r.PathValue("name")
+	if name != member.Name && !member.Manager {
+		companionError(w, http.StatusForbidden, "only managers can issue tokens for others")
+		return
+	}
+	if !teamMemberName.MatchString(name) {
+		companionError(w, http.StatusBadRequest, "member names may only use letters, digits, '.', '_' and '-'")
+		return
+	}
+
+	token, issued := newTeamToken(strings.TrimSpace(request.Label))
+	err := s.updateConfig(func(config *TeamServerConfig) error {
+		i := slices.IndexFunc(config.Members, func(m TeamMember) bool { return m.Name == name })
+		if i < 0 {
+			if !member.Manager {
+				return errTeamNoMember
+			}
+			config.Members = append(config.Members, TeamMember{Name: name, Manager: request.Manager})
+			i = len(config.Members) - 1
+		}
+		config.Members[i].Tokens = append(config.Members[i].Tokens, issued)
+		return nil
+	})
+	if errors.Is(err, errTeamNoMember) {
+		companionError(w, http.StatusNotFound, "no such member")
+		return
+	}
+	if err != nil {
+		log.Printf("team server: issuing a token for %s: %v", name, err)
+		companionError(w, http.StatusInternalServerError, "issuing the token failed")
+		return
+	}
+	companionReply(w, IssuedToken{
+		TeamTokenInfo: TeamTokenInfo{ID: issued.ID, Label: issued.Label, Created: issued.Created},
+		Member:        name,
+		Token:         token,
+	})
+}
+
+// handleRevokeToken revokes one of a member's tokens: the member's own, or
+// anyone's for a manager. It stops working straight away.
+func (s *TeamServer) handleRevokeToken(w http.ResponseWriter, r *http.Request, member TeamMember, _ string) {
+	name, id := r.PathValue("name"), r.PathValue("id")
+	if name != member.Name && !member.Manager {
+		companionError(w, http.StatusForbidden, "only managers can revoke others' tokens")
+		return
+	}
+	found := false
+	err := s.updateConfig(func(config *TeamServerConfig) error {
+		i := slices.IndexFunc(config.Members, func(m TeamMember) bool { return m.Name == name })
+		if i < 0 {
+			return errTeamNoMember
+		}
+		config.Members[i].Tokens = slices.DeleteFunc(config.Members[i].Tokens, func(t TeamToken) bool {
+			found = found || t.ID == id
+			return t.ID == id
+		})
+		return nil
+	})
+	if errors.Is(err, errTeamNoMember) {
+		companionError(w, http.StatusNotFound, "no such member")
+		return
+	}
+	if err != nil {
+		log.Printf("team server: revoking a token of %s: %v", name, err)
+		companionError(w, http.StatusInternalServerError, "revoking the token failed")
+		return
+	}
+	if !found {
+		companionError(w, http.StatusNotFound, "no such token")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// showTeamTokensDialog lists the tokens the user may manage, their own or
+// everyone's for a manager, with a form to issue a new one. A new token is
+// shown once, and copied to the clipboard.
+func showTeamTokensDialog(timer *TaskTimer) {
+	if teamServerURL() == "" {
+		dialog.ShowInformation(tr("Tokens"), tr("Set up a team workspace in Settings first."), timer.window)
+		return
+	}
+	manager := fyne.CurrentApp().Preferences().Bool(PrefTeamManager)
+
+	memberInput := widget.NewSelectEntry(nil)
+	memberInput.PlaceHolder = tr("Member")
+	labelInput := widget.NewEntry()
+	labelInput.PlaceHolder = tr("e.g. laptop, phone")
+	managerCheck := widget.NewCheck(tr("New member is a manager"), nil)
+	if !manager {
+		memberInput.Disable()
+		managerCheck.Hide()
+	}
+	issuedEntry := widget.NewEntry()
+	issuedEntry.Hide()
+
+	list := container.NewVBox()
+	var render func()
+	render = func() {
+		go func() {
+			var members []TeamMemberInfo
+			err := teamRequest(http.MethodGet, "/members", nil, &members)
+			fyne.Do(func() {
+				list.RemoveAll()
+				if err != nil {
+					list.Add(widget.NewLabel(err.Error()))
+					return
+				}
+				sort.Slice(members, func(i, j int) bool {
+					return members[i].Name < members[j].Name
+				})
+				var names []string
+				for _, member := range members {
+					names = append(names, member.Name)
+					if memberInput.Text == "" && slices.ContainsFunc(member.Tokens, func(t TeamTokenInfo) bool { return t.Current }) {
+						memberInput.SetText(member.Name)
+					}
+
+					title := member.Name
+					if member.Manager {
+						title = tr("{{.Name}} (manager)", map[string]any{"Name": member.Name})
+					}
+					list.Add(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+					if len(member.Tokens) == 0 {
+						list.Add(widget.NewLabel(tr("No tokens")))
+					}
+					for _, token := range member.Tokens {
+						label := token.Label
+						if label == "" {
+							label = tr("Unlabelled")
+						}
+						if !token.Created.IsZero() {
+							label += " · " + formatDate(token.Created.Local())
+						}
+						if token.Current {
+							label += " · " + tr("this device")
+						}
+						revokeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+							message := tr("Anything using this token will be signed out.")
+							if token.Current {
+								message = tr("This device uses this token, and will be signed out.")
+							}
+							dialog.ShowConfirm(tr("Revoke Token"), message, func(ok bool) {
+								if !ok {
+									return
+								}
+								go func() {
+									err := teamRequest(http.MethodDelete, "/members/"+url.PathEscape(member.Name)+"/tokens/"+url.PathEscape(token.ID), nil, nil)
+									fyne.Do(func() {
+										if err != nil {
+											dialog.ShowError(err, timer.window)
+											return
+										}
+										render()
+									})
+								}()
+							}, timer.window)
+						})
+						list.Add(container.NewBorder(nil, nil, nil, revokeBtn, widget.NewLabel(label)))
+					}
+				}
+				memberInput.SetOptions(names)
+			})
+		}()
+	}
+	render()
+
+	issueBtn := widget.NewButtonWithIcon(tr("Issue token"), theme.ContentAddIcon(), func() {
+		name := strings.TrimSpace(memberInput.Text)
+		if name == "" {
+			dialog.ShowInformation(tr("Tokens"), tr("Pick the member to issue a token for."), timer.window)
+			return
+		}
+		request := map[string]any{"label": strings.TrimSpace(labelInput.Text), "manager": managerCheck.Checked}
+		go func() {
+			var issued IssuedToken
+			err := teamRequest(http.MethodPost, "/members/"+url.PathEscape(name)+"/tokens", request, &issued)
+			fyne.Do(func() {
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				fyne.CurrentApp().Clipboard().SetContent(issued.Token)
+				issuedEntry.SetText(issued.Token)
+				issuedEntry.Show()
+				labelInput.SetText("")
+				render()
+			})
+		}()
+	})
+
+	help := widget.NewLabel(tr("A new token is shown once and copied to the clipboard. Enter it in the other device's settings."))
+	help.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(
+				widget.NewFormItem(tr("Member"), memberInput),
+				widget.NewFormItem(tr("Label"), labelInput),
+			),
+			managerCheck,
+			help,
+			issueBtn,
+			issuedEntry,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		container.NewVScroll(list),
+	)
+	d := dialog.NewCustom(tr("Tokens"), tr("Close"), content, timer.window)
+	d.Resize(fyne.NewSize(500, 550))
+	d.Show()
+}
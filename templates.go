@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const PrefTaskTemplates = "taskTemplates"
+
+// TaskTemplate presets a session: starting it picks the task, fills in the
+// notes and records the entry with the template's project, tags and billable
+// flag. A template with Days is a recurring task, offered in the timer
+// view's Today list on those days until it has been tracked.
+type TaskTemplate struct {
+	Name     string         `json:"name"`
+	Project  string         `json:"project,omitempty"`
+	Tags     []string       `json:"tags,omitempty"`
+	Notes    string         `json:"notes,omitempty"`
+	Billable bool           `json:"billable"`
+	Days     []time.Weekday `json:"days,omitempty"`
+}
+
+// DueOn reports whether a recurring template is scheduled on day's weekday.
+func (t TaskTemplate) DueOn(day time.Time) bool {
+	return slices.Contains(t.Days, day.Weekday())
+}
+
+// Apply fills in an entry recorded from the template. A project set by the
+// user or a rule is kept.
+func (t TaskTemplate) Apply(entry *Entry) {
+	if entry.Project == "" {
+		entry.Project = t.Project
+	}
+	for _, tag := range t.Tags {
+		if !contains(entry.Tags, tag) {
+			entry.Tags = append(entry.Tags, tag)
+		}
+	}
+	entry.NonBillable = !t.Billable
+}
+
+func loadTaskTemplates() []TaskTemplate {
+	var templates []TaskTemplate
+	raw := fyne.CurrentApp().Preferences().String(PrefTaskTemplates)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &templates); err != nil {
+			log.Printf("templates: reading templates: %v", err)
+		}
+	}
+	return templates
+}
+
+// setTaskTemplates saves the templates and hands them to bound views.
+func setTaskTemplates(timer *TaskTimer, templates []TaskTemplate) {
+	raw, err := json.Marshal(templates)
+	if err != nil {
+		log.Printf("templates: saving templates: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefTaskTemplates, string(raw))
+	timer.templates.Set(templates)
+}
+
+// parseTags reads a comma-separated list of tags, with or without a leading #.
+func parseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" && !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// describeDays names a template's schedule, e.g. "Weekdays" or "Mon, Thu".
+func describeDays(days []time.Weekday) string {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	switch {
+	case len(days) == 7:
+		return tr("Every day")
+	case len(days) == len(weekdays) && !slices.Contains(days, time.Saturday) && !slices.Contains(days, time.Sunday):
+		return tr("Weekdays")
+	}
+	var names []string
+	for _, day := range orderedWeekdays() {
+		if slices.Contains(days, day) {
+			names = append(names, weekdayAbbrev(day))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// orderedWeekdays lists the days of the week from the configured first day.
+func orderedWeekdays() []time.Weekday {
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = (firstWeekday() + time.Weekday(i)) % 7
+	}
+	return days
+}
+
+// startTemplate starts the template's task, with its notes unless some are
+// already written for the session.
+func startTemplate(timer *TaskTimer, template TaskTemplate) {
+	startTask(timer, template.Name)
+	timer.template = template
+	updateFocusMode(timer)
+	if strings.TrimSpace(timer.notesInput.Text) == "" {
+		timer.notesInput.SetText(template.Notes)
+	}
+}
+
+// trackedOn reports whether any time was recorded against a task on day.
+func trackedOn(entries []Entry, taskName string, day time.Time) bool {
+	from, to := dayStart(day), addDays(dayStart(day), 1)
+	for _, entry := range entries {
+		if entry.Task == taskName && !entry.Start.Before(from) && entry.Start.Before(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// createTodayContainer lists the recurring tasks due today that haven't been
+// tracked yet, each with a button to start it. It follows the templates and
+// the history, and starts afresh each day.
+func createTodayContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	today := container.NewVBox(widget.NewLabel(tr("Today")), list)
+	today.Hide()
+
+	render := func() {
+		templates, _ := timer.templates.Get()
+		history, _ := timer.history.Get()
+		now := time.Now()
+
+		list.RemoveAll()
+		for _, template := range templates {
+			if template.DueOn(now) && !trackedOn(history, template.Name, now) {
+				list.Add(widget.NewButton("▶ "+template.Name, func() {
+					startTemplate(timer, template)
+				}))
+			}
+		}
+		today.Hidden = len(list.Objects) == 0
+		today.Refresh()
+	}
+	timer.templates.AddListener(binding.NewDataListener(render))
+	timer.history.AddListener(binding.NewDataListener(render))
+
+	go func() {
+		for {
+			time.Sleep(time.Until(addDays(dayStart(time.Now()), 1)))
+			fyne.Do(render)
+		}
+	}()
+	return today
+}
+
+// createTemplateListContainer lists the templates with buttons to start,
+// edit or delete each one.
+func createTemplateListContainer(timer *TaskTimer) fyne.CanvasObject {
+	list := container.NewVBox()
+	render := func() {
+		templates, _ := timer.templates.Get()
+		list.RemoveAll()
+		for i, template := range templates {
+			text := template.Name
+			if len(template.Days) > 0 {
+				text += " · " + describeDays(template.Days)
+			}
+			label := widget.NewLabel(text)
+			label.Truncation = fyne.TextTruncateEllipsis
+			buttons := container.NewHBox(
+				widget.NewButton(tr("▶ Start"), func() { startTemplate(timer, template) }),
+				widget.NewButton(tr("Edit"), func() { showTemplateDialog(timer, i) }),
+				widget.NewButton(tr("Delete"), func() {
+					dialog.ShowConfirm(tr("Delete Template"), tr("Delete the template for \"{{.Task}}\"?", map[string]any{"Task": template.Name}), func(ok bool) {
+						if ok {
+							setTaskTemplates(timer, slices.Delete(slices.Clone(templates), i, i+1))
+						}
+					}, timer.window)
+				}),
+			)
+			list.Add(container.NewBorder(nil, nil, nil, buttons, label))
+		}
+		if len(templates) == 0 {
+			list.Add(widget.NewLabel(tr("No templates yet")))
+		}
+	}
+	listenWhileShown(timer, timer.templates, render)
+
+	return container.NewVBox(
+		list,
+		widget.NewButton(tr("Add Template"), func() { showTemplateDialog(timer, -1) }),
+	)
+}
+
+// showTemplateDialog edits the template at index, or creates one if index
+// is negative.
+func showTemplateDialog(timer *TaskTimer, index int) {
+	templates, _ := timer.templates.Get()
+	template := TaskTemplate{Billable: true}
+	if index >= 0 {
+		template = templates[index]
+	}
+
+	nameInput := widget.NewSelectEntry(timer.tasks.Active())
+	nameInput.SetText(template.Name)
+	projectInput := widget.NewSelectEntry(knownProjects(timer))
+	projectInput.SetText(template.Project)
+	tagsInput := widget.NewEntry()
+	tagsInput.SetText(strings.Join(template.Tags, ", "))
+	tagsInput.PlaceHolder = tr("Comma-separated")
+	notesInput := widget.NewMultiLineEntry()
+	notesInput.SetText(template.Notes)
+	billableCheck := widget.NewCheck(tr("Billable"), nil)
+	billableCheck.SetChecked(template.Billable)
+
+	dayChecks := container.NewGridWithColumns(4)
+	for _, day := range orderedWeekdays() {
+		check := widget.NewCheck(weekdayAbbrev(day), nil)
+		check.SetChecked(slices.Contains(template.Days, day))
+		dayChecks.Add(check)
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Task"), nameInput),
+		widget.NewFormItem(tr("Project"), projectInput),
+		widget.NewFormItem(tr("Tags"), tagsInput),
+		widget.NewFormItem(tr("Notes"), notesInput),
+		widget.NewFormItem("", billableCheck),
+		widget.NewFormItem(tr("Repeats on"), dayChecks),
+	}
+	title := tr("Add Template")
+	if index >= 0 {
+		title = tr("Edit Template")
+	}
+	d := dialog.NewForm(title, tr("Save"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		name := strings.TrimSpace(nameInput.Text)
+		if name == "" || name == NoTaskSelected || name == tr(NoTaskSelected) {
+			dialog.ShowInformation(title, tr("Choose a task."), timer.window)
+			return
+		}
+
+		edited := TaskTemplate{
+			Name:     name,
+			Project:  strings.TrimSpace(projectInput.Text),
+			Tags:     parseTags(tagsInput.Text),
+			Notes:    strings.TrimSpace(notesInput.Text),
+			Billable: billableCheck.Checked,
+		}
+		for i, day := range orderedWeekdays() {
+			if dayChecks.Objects[i].(*widget.Check).Checked {
+				edited.Days = append(edited.Days, day)
+			}
+		}
+
+		updated := slices.Clone(templates)
+		if index >= 0 {
+			updated[index] = edited
+		} else {
+			updated = append(updated, edited)
+		}
+		timer.tasks.Ensure(name)
+		setTaskTemplates(timer, updated)
+	}, timer.window)
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+}
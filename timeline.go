@@ -0,0 +1,292 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// TimelineHourHeight is how tall an hour is drawn in the timeline
+	TimelineHourHeight float32 = 48
+
+	// TimelineMinGap is the shortest gap offered for filling in
+	TimelineMinGap = 5 * time.Minute
+
+	// The hours the timeline always covers, widened to fit the day's entries
+	timelineFirstHour = 8
+	timelineLastHour  = 18
+)
+
+// createTimelineContainer shows a day as a vertical timeline: each session
+// is a block in its task's color, and each gap between them is a button
+// that fills it in with a manual entry.
+func createTimelineContainer(timer *TaskTimer) fyne.CanvasObject {
+	day := dayStart(time.Now())
+	dayLabel := widget.NewLabel("")
+	timeline := container.New(&timelineLayout{})
+
+	// Marks the day as taken off, or back as a working day
+	offNames := []string{tr("Working day")}
+	for _, kind := range TimeOffKinds {
+		offNames = append(offNames, timeOffName(kind))
+	}
+	offSelect := widget.NewSelect(offNames, nil)
+
+	var render func()
+	render = func() {
+		dayLabel.SetText(weekdayName(day.Weekday()) + ", " + formatDate(day))
+		kind := loadTimeOff().On(day)
+		offSelect.OnChanged = nil
+		offSelect.SetSelectedIndex(slices.Index(TimeOffKinds, kind) + 1)
+		offSelect.OnChanged = func(string) {
+			kind := ""
+			if i := offSelect.SelectedIndex(); i > 0 {
+				kind = TimeOffKinds[i-1]
+			}
+			setTimeOff(day, kind)
+			render()
+		}
+		if kind != "" {
+			dayLabel.SetText(dayLabel.Text + " · " + timeOffName(kind))
+			dayLabel.Importance = widget.HighImportance
+		} else {
+			dayLabel.Importance = widget.MediumImportance
+		}
+		dayLabel.Refresh()
+		spans := timelineSpans(timer, day, time.Now())
+		from, to := timelineRange(day, spans)
+		layout := &timelineLayout{from: from}
+
+		var objects []fyne.CanvasObject
+		for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+			label := canvas.NewText(hour.Format("15:04"), theme.Color(theme.ColorNameForeground))
+			label.TextSize = theme.CaptionTextSize()
+			objects = append(objects, label)
+			layout.items = append(layout.items, timelineItem{hour, hour.Add(time.Hour), true})
+		}
+		for _, span := range spans {
+			objects = append(objects, newTimelineBlock(timer, span))
+			layout.items = append(layout.items, timelineItem{span.Start, span.End, false})
+		}
+		for _, gap := range timelineGaps(spans, from, to, time.Now()) {
+			gapBtn := widget.NewButton("+ "+gap.Start.Format("15:04")+"–"+gap.End.Format("15:04"), func() {
+				showAddEntryDialog(timer, gap.Start, gap.End)
+			})
+			gapBtn.Importance = widget.LowImportance
+			objects = append(objects, gapBtn)
+			layout.items = append(layout.items, timelineItem{gap.Start, gap.End, false})
+		}
+		timeline.Layout = layout
+		timeline.Objects = objects
+		timeline.Refresh()
+	}
+	stepDay := func(days int) {
+		day = addDays(day, days)
+		render()
+	}
+	listenWhileShown(timer, timer.history, render)
+	listenWhileShown(timer, timer.styles, render)
+
+	dayNav := container.NewHBox(
+		widget.NewButtonWithIcon(tr("Previous"), theme.NavigateBackIcon(), func() { stepDay(-1) }),
+		widget.NewButton(tr("Today"), func() {
+			day = dayStart(time.Now())
+			render()
+		}),
+		widget.NewButtonWithIcon(tr("Next"), theme.NavigateNextIcon(), func() { stepDay(1) }),
+	)
+	return container.NewVBox(dayNav, container.NewBorder(nil, nil, nil, offSelect, dayLabel), timeline)
+}
+
+// timelineSpans returns the day's entries in start order, along with the
+// session in progress if it started that day.
+func timelineSpans(timer *TaskTimer, day, now time.Time) []Entry {
+	spans := entriesBetween(timer, day, addDays(day, 1))
+	if timer.elapsedTime > 0 && !timer.sessionStart.Before(day) && timer.sessionStart.Before(addDays(day, 1)) {
+		spans = append(spans, Entry{
+			Task:     timer.taskName,
+			Start:    timer.sessionStart,
+			End:      now,
+			Duration: timer.elapsedTime,
+		})
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+	return spans
+}
+
+// timelineRange returns the whole hours the timeline covers: the usual
+// working day, widened to take in every span.
+func timelineRange(day time.Time, spans []Entry) (from, to time.Time) {
+	from = day.Add(timelineFirstHour * time.Hour)
+	to = day.Add(timelineLastHour * time.Hour)
+	for _, span := range spans {
+		if span.Start.Before(from) {
+			from = span.Start.Truncate(time.Hour)
+		}
+		if span.End.After(to) {
+			to = span.End.Add(time.Hour - 1).Truncate(time.Hour)
+		}
+	}
+	if from.Before(day) {
+		from = day
+	}
+	if end := addDays(day, 1); to.After(end) {
+		to = end
+	}
+	return from, to
+}
+
+// timelineGaps returns the stretches between from and to that no span
+// covers, leaving out the future and gaps shorter than TimelineMinGap.
+func timelineGaps(spans []Entry, from, to, now time.Time) []Entry {
+	var gaps []Entry
+	if now.Before(to) {
+		to = now.Truncate(time.Minute)
+	}
+	cursor := from
+	addGap := func(end time.Time) {
+		if end.Sub(cursor) >= TimelineMinGap {
+			gaps = append(gaps, Entry{Start: cursor, End: end})
+		}
+	}
+	for _, span := range spans {
+		if span.Start.After(cursor) {
+			if span.Start.Before(to) {
+				addGap(span.Start)
+			} else {
+				addGap(to)
+			}
+		}
+		if span.End.After(cursor) {
+			cursor = span.End
+		}
+	}
+	addGap(to)
+	return gaps
+}
+
+// newTimelineBlock draws a session in its task's color, labelled where
+// there's room.
+func newTimelineBlock(timer *TaskTimer, span Entry) fyne.CanvasObject {
+	style := taskStyles(timer)[span.Task]
+	fill, ok := style.Fill()
+	if !ok {
+		fill = theme.Color(theme.ColorNamePrimary)
+	}
+	block := container.NewStack(canvas.NewRectangle(fill))
+	if span.End.Sub(span.Start) >= 20*time.Minute {
+		text := canvas.NewText(style.Label(span.Task)+"  "+span.Start.Format("15:04")+"–"+span.End.Format("15:04"), color.White)
+		text.TextSize = theme.CaptionTextSize()
+		block.Add(container.NewPadded(text))
+	}
+	return block
+}
+
+// timelineItem is where an object goes in the timeline: hour labels in the
+// left margin, everything else beside them.
+type timelineItem struct {
+	start, end time.Time
+	margin     bool
+}
+
+// timelineLayout places its objects by time, one item per object.
+type timelineLayout struct {
+	from  time.Time
+	items []timelineItem
+}
+
+func (l *timelineLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	margin := fyne.MeasureText("00:00", theme.CaptionTextSize(), fyne.TextStyle{}).Width + theme.Padding()*2
+	for i, object := range objects {
+		item := l.items[i]
+		y := float32(item.start.Sub(l.from).Hours()) * TimelineHourHeight
+		height := float32(item.end.Sub(item.start).Hours()) * TimelineHourHeight
+		if item.margin {
+			object.Move(fyne.NewPos(0, y))
+			object.Resize(fyne.NewSize(margin, object.MinSize().Height))
+			continue
+		}
+		object.Move(fyne.NewPos(margin, y))
+		object.Resize(fyne.NewSize(size.Width-margin, height))
+	}
+}
+
+func (l *timelineLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	var height float32
+	for _, item := range l.items {
+		height = max(height, float32(item.end.Sub(l.from).Hours())*TimelineHourHeight)
+	}
+	return fyne.NewSize(200, height)
+}
+
+// showAddEntryDialog records a manual entry, for time that wasn't tracked,
+// with the times filled in from start and end.
+func showAddEntryDialog(timer *TaskTimer, start, end time.Time) {
+	startInput := widget.NewEntry()
+	startInput.SetText(start.Format(historyTimeLayout))
+	endInput := widget.NewEntry()
+	endInput.SetText(end.Format(historyTimeLayout))
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.PlaceHolder = tr("Task")
+	notesInput := widget.NewEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("Start"), startInput),
+		widget.NewFormItem(tr("End"), endInput),
+		widget.NewFormItem(tr("Task"), taskInput),
+		widget.NewFormItem(tr("Notes"), notesInput),
+	}
+	d := dialog.NewForm(tr("Add Entry"), tr("Add"), tr("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		start, err := time.ParseInLocation(historyTimeLayout, startInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		end, err := time.ParseInLocation(historyTimeLayout, endInput.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected || !end.After(start) {
+			dialog.ShowInformation(tr("Add Entry"), tr("Choose a task and an end after the start."), timer.window)
+			return
+		}
+
+		entry := Entry{
+			Task:        taskName,
+			Start:       start,
+			End:         end,
+			Duration:    end.Sub(start),
+			Notes:       strings.TrimSpace(notesInput.Text),
+			NonBillable: isInternalTask(taskName),
+		}
+		applyRules(&entry)
+		saveWithoutOverlaps(timer, tr("Entry added"), entry, func() error {
+			for _, part := range splitAtMidnight(entry) {
+				recordEntry(timer, part)
+			}
+			return nil
+		}, func() {
+			timer.tasks.Ensure(taskName)
+		})
+	}, timer.window)
+	d.Resize(fyne.NewSize(380, 0))
+	d.Show()
+}
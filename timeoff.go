@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	PrefTimeOff = "timeOff"
+
+	TimeOffVacation = "vacation"
+	TimeOffSick     = "sick"
+	TimeOffHoliday  = "holiday"
+)
+
+// TimeOffKinds are the kinds of day off, in the order they're offered.
+var TimeOffKinds = []string{TimeOffVacation, TimeOffSick, TimeOffHoliday}
+
+// timeOffIcon tells the kinds of day off apart at a glance.
+func timeOffIcon(kind string) string {
+	switch kind {
+	case TimeOffVacation:
+		return "🏖"
+	case TimeOffSick:
+		return "🤒"
+	default:
+		return "🎉"
+	}
+}
+
+// timeOffName names a kind of day off, after its icon.
+func timeOffName(kind string) string {
+	name := tr("Public holiday")
+	switch kind {
+	case TimeOffVacation:
+		name = tr("Vacation")
+	case TimeOffSick:
+		name = tr("Sick")
+	}
+	return timeOffIcon(kind) + " " + name
+}
+
+// TimeOff maps days taken off, written YYYY-MM-DD, to their kind. Days off
+// neither count towards nor break streaks, and weekly goals are scaled down
+// by the working days taken off.
+type TimeOff map[string]string
+
+// On returns the kind of day off taken on day, empty if none.
+func (t TimeOff) On(day time.Time) string {
+	return t[dayStart(day).Format(time.DateOnly)]
+}
+
+func loadTimeOff() TimeOff {
+	off := TimeOff{}
+	raw := fyne.CurrentApp().Preferences().String(PrefTimeOff)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &off); err != nil {
+			log.Printf("time off: reading days off: %v", err)
+		}
+	}
+	return off
+}
+
+// setTimeOff marks day as taken off, or as a working day if kind is empty.
+func setTimeOff(day time.Time, kind string) {
+	off := loadTimeOff()
+	key := dayStart(day).Format(time.DateOnly)
+	if kind == "" {
+		delete(off, key)
+	} else {
+		off[key] = kind
+	}
+	raw, err := json.Marshal(off)
+	if err != nil {
+		log.Printf("time off: saving days off: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(PrefTimeOff, string(raw))
+}
+
+// workdays returns the weekdays of the work schedule, Monday to Friday if
+// none is set.
+func workdays() [7]bool {
+	blocks, _ := parseWorkSchedule(fyne.CurrentApp().Preferences().StringList(PrefWorkSchedule))
+	var days [7]bool
+	for _, block := range blocks {
+		for day, works := range block.Days {
+			days[day] = days[day] || works
+		}
+	}
+	if days == [7]bool{} {
+		for day := time.Monday; day <= time.Friday; day++ {
+			days[day] = true
+		}
+	}
+	return days
+}
+
+// weekGoal scales a weekly goal down by the working days taken off in the
+// week starting at start, returning it with the number of those days.
+func weekGoal(goal time.Duration, start time.Time, off TimeOff) (time.Duration, int) {
+	days := workdays()
+	working, taken := 0, 0
+	for i := range 7 {
+		day := addDays(start, i)
+		if !days[day.Weekday()] {
+			continue
+		}
+		working++
+		if off.On(day) != "" {
+			taken++
+		}
+	}
+	if working == 0 {
+		return goal, 0
+	}
+	return goal * time.Duration(working-taken) / time.Duration(working), taken
+}
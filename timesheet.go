@@ -0,0 +1,376 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TimesheetStart is when time added from the timesheet starts on a day
+// with nothing else tracked.
+const TimesheetStart = 9 * time.Hour
+
+// Timesheet is a week of tracked time as a grid: one row per task, one
+// column per day.
+type Timesheet struct {
+	Days  []time.Time
+	Tasks []string
+	Cells map[string][]time.Duration // per task, one per day
+}
+
+// newTimesheet totals the entries of the week starting at start by task
+// and day. Tasks in extra get a row even with nothing tracked.
+func newTimesheet(entries []Entry, start time.Time, extra []string) Timesheet {
+	sheet := Timesheet{Cells: make(map[string][]time.Duration)}
+	for i := range 7 {
+		sheet.Days = append(sheet.Days, addDays(start, i))
+	}
+	row := func(taskName string) []time.Duration {
+		if _, ok := sheet.Cells[taskName]; !ok {
+			sheet.Cells[taskName] = make([]time.Duration, 7)
+			sheet.Tasks = append(sheet.Tasks, taskName)
+		}
+		return sheet.Cells[taskName]
+	}
+	for _, entry := range entries {
+		day := dayStart(entry.Start)
+		if i := slices.IndexFunc(sheet.Days, day.Equal); i >= 0 {
+			row(entry.Task)[i] += entry.Duration
+		}
+	}
+	for _, taskName := range extra {
+		row(taskName)
+	}
+	sort.Strings(sheet.Tasks)
+	return sheet
+}
+
+// RowTotal is a task's time over the week.
+func (s Timesheet) RowTotal(taskName string) time.Duration {
+	var total time.Duration
+	for _, duration := range s.Cells[taskName] {
+		total += duration
+	}
+	return total
+}
+
+// DayTotal is the time tracked on a day, over all tasks.
+func (s Timesheet) DayTotal(day int) time.Duration {
+	var total time.Duration
+	for _, cells := range s.Cells {
+		total += cells[day]
+	}
+	return total
+}
+
+// formatTimesheetCell writes a duration as hours and minutes, e.g. "1:30",
+// blank for none.
+func formatTimesheetCell(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%d:%02d", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+// parseTimesheetCell reads a cell written as hours and minutes, e.g.
+// "1:30", or as hours, e.g. "1.5". A blank cell is no time.
+func parseTimesheetCell(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	hours, minutes, ok := strings.Cut(text, ":")
+	if !ok {
+		return parseHours(text)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m >= 60 {
+		return 0, fmt.Errorf("timesheet: %q: minutes must be 00 to 59", text)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// setTimesheetCell changes a task's time on a day to target. Time is added
+// as a new entry after the day's last one, and taken off the task's latest
+// entries that day first.
+func setTimesheetCell(timer *TaskTimer, taskName string, day time.Time, target time.Duration) error {
+	end := addDays(day, 1)
+	var current time.Duration
+	var taskEntries []Entry
+	start := day.Add(TimesheetStart)
+	for _, entry := range entriesBetween(timer, day, end) {
+		if entry.Task == taskName {
+			current += entry.Duration
+			taskEntries = append(taskEntries, entry)
+		}
+		if entry.End.After(start) {
+			start = entry.End
+		}
+	}
+
+	if target > current {
+		added := target - current
+		if start.Add(added).After(end) {
+			start = end.Add(-added)
+		}
+		entry := Entry{
+			Task:        taskName,
+			Start:       start,
+			End:         start.Add(added),
+			Duration:    added,
+			NonBillable: isInternalTask(taskName),
+		}
+		applyRules(&entry)
+		timer.tasks.Ensure(taskName)
+		recordEntry(timer, entry)
+		return nil
+	}
+
+	excess := current - target
+	sort.Slice(taskEntries, func(i, j int) bool {
+		return taskEntries[i].Start.After(taskEntries[j].Start)
+	})
+	for _, entry := range taskEntries {
+		if excess <= 0 {
+			break
+		}
+		if entry.Duration <= excess {
+			if err := deleteEntry(timer, entry.ID); err != nil {
+				return err
+			}
+			excess -= entry.Duration
+			continue
+		}
+		entry.Duration -= excess
+		entry.End = entry.End.Add(-excess)
+		if err := updateEntry(timer, entry); err != nil {
+			return err
+		}
+		excess = 0
+	}
+	return nil
+}
+
+// createTimesheetContainer shows a week as a grid of tasks by days, with
+// totals for each row and column. Cells are edited in place and saved
+// together, and the grid exports as it is shown.
+func createTimesheetContainer(timer *TaskTimer) fyne.CanvasObject {
+	week := weekStart(time.Now())
+	weekLabel := widget.NewLabel("")
+	grid := container.NewVBox()
+	var extraTasks []string
+	var sheet Timesheet
+	cells := make(map[string][]*widget.Entry)
+
+	render := func() {
+		entries := entriesBetween(timer, week, addDays(week, 7))
+		sheet = newTimesheet(entries, week, extraTasks)
+		weekLabel.SetText(weekTitle(week))
+
+		rows := container.NewGridWithColumns(9)
+		rows.Add(widget.NewLabelWithStyle(tr("Task"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		off := loadTimeOff()
+		for _, day := range sheet.Days {
+			header := widget.NewLabelWithStyle(weekdayAbbrev(day.Weekday())+" "+strconv.Itoa(day.Day()), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+			// Days off are marked with their icon and shown dimmed
+			if kind := off.On(day); kind != "" {
+				header.SetText(timeOffIcon(kind) + " " + header.Text)
+				header.Importance = widget.LowImportance
+			}
+			rows.Add(header)
+		}
+		rows.Add(widget.NewLabelWithStyle(tr("Total"), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+
+		clear(cells)
+		for _, taskName := range sheet.Tasks {
+			label := widget.NewLabel(taskStyles(timer)[taskName].Label(taskName))
+			label.Truncation = fyne.TextTruncateEllipsis
+			rows.Add(label)
+			for _, duration := range sheet.Cells[taskName] {
+				cell := widget.NewEntry()
+				cell.SetText(formatTimesheetCell(duration))
+				cells[taskName] = append(cells[taskName], cell)
+				rows.Add(cell)
+			}
+			rows.Add(widget.NewLabelWithStyle(formatTimesheetCell(sheet.RowTotal(taskName)), fyne.TextAlignTrailing, fyne.TextStyle{}))
+		}
+
+		rows.Add(widget.NewLabelWithStyle(tr("Total"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		var total time.Duration
+		for i := range sheet.Days {
+			total += sheet.DayTotal(i)
+			rows.Add(widget.NewLabelWithStyle(formatTimesheetCell(sheet.DayTotal(i)), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+		}
+		rows.Add(widget.NewLabelWithStyle(formatTimesheetCell(total), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+
+		grid.Objects = []fyne.CanvasObject{rows}
+		grid.Refresh()
+	}
+	listenWhileShown(timer, timer.history, render)
+
+	stepWeek := func(weeks int) {
+		week = addDays(week, 7*weeks)
+		extraTasks = nil
+		render()
+	}
+	weekNav := container.NewHBox(
+		widget.NewButtonWithIcon(tr("Previous"), theme.NavigateBackIcon(), func() { stepWeek(-1) }),
+		widget.NewButton(tr("This week"), func() {
+			week = weekStart(time.Now())
+			extraTasks = nil
+			render()
+		}),
+		widget.NewButtonWithIcon(tr("Next"), theme.NavigateNextIcon(), func() { stepWeek(1) }),
+	)
+
+	// A row for a task with nothing tracked this week yet
+	taskInput := widget.NewSelectEntry(timer.tasks.Active())
+	taskInput.PlaceHolder = tr("Task")
+	addRowBtn := widget.NewButtonWithIcon(tr("Add row"), theme.ContentAddIcon(), func() {
+		taskName := strings.TrimSpace(taskInput.Text)
+		if taskName == "" || taskName == NoTaskSelected || slices.Contains(sheet.Tasks, taskName) {
+			return
+		}
+		extraTasks = append(extraTasks, taskName)
+		taskInput.SetText("")
+		render()
+	})
+
+	saveBtn := widget.NewButtonWithIcon(tr("Save changes"), theme.DocumentSaveIcon(), func() {
+		// Saved as one change, so it's undone as one
+		err := changeEntries(timer, tr("Timesheet saved"), func() error {
+			for _, taskName := range sheet.Tasks {
+				for i, cell := range cells[taskName] {
+					if cell.Text == formatTimesheetCell(sheet.Cells[taskName][i]) {
+						continue
+					}
+					target, err := parseTimesheetCell(cell.Text)
+					if err != nil || target < 0 || target > 24*time.Hour {
+						dialog.ShowInformation(tr("Timesheet"), tr("Enter {{.Task}} on {{.Day}} as hours and minutes, e.g. 1:30.", map[string]any{
+							"Task": taskName,
+							"Day":  weekdayName(sheet.Days[i].Weekday()),
+						}), timer.window)
+						return nil
+					}
+					if err := setTimesheetCell(timer, taskName, sheet.Days[i], target); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			dialog.ShowError(err, timer.window)
+		}
+	})
+
+	exportBtns := container.NewGridWithColumns(2,
+		widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+			saveExport(timer, "timesheet-"+weekFileKey(week)+".csv", func(w io.Writer) error {
+				return writeTimesheetCSV(w, sheet, currentExportLocale())
+			})
+		}),
+		widget.NewButtonWithIcon(tr("Export Excel…"), theme.DocumentSaveIcon(), func() {
+			saveExport(timer, "timesheet-"+weekFileKey(week)+".xlsx", func(w io.Writer) error {
+				return writeTimesheetXLSX(w, sheet, currentExportLocale())
+			})
+		}),
+	)
+
+	return container.NewVBox(
+		weekNav,
+		weekLabel,
+		grid,
+		container.NewBorder(nil, nil, nil, addRowBtn, taskInput),
+		saveBtn,
+		exportBtns,
+	)
+}
+
+// writeTimesheetCSV writes the grid as shown, with a header row of days and
+// a totals row, formatted for the export locale.
+func writeTimesheetCSV(w io.Writer, sheet Timesheet, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+
+	header := []string{locale.Header("task")}
+	for _, day := range sheet.Days {
+		header = append(header, locale.FormatDay(day))
+	}
+	if err := out.Write(append(header, locale.Header("total"))); err != nil {
+		return err
+	}
+
+	for _, taskName := range sheet.Tasks {
+		record := []string{taskName}
+		for _, duration := range sheet.Cells[taskName] {
+			record = append(record, locale.FormatDuration(duration))
+		}
+		if err := out.Write(append(record, locale.FormatDuration(sheet.RowTotal(taskName)))); err != nil {
+			return err
+		}
+	}
+
+	totals := []string{locale.Header("total")}
+	var total time.Duration
+	for i := range sheet.Days {
+		total += sheet.DayTotal(i)
+		totals = append(totals, locale.FormatDuration(sheet.DayTotal(i)))
+	}
+	if err := out.Write(append(totals, locale.FormatDuration(total))); err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// writeTimesheetXLSX writes the grid as shown to a single sheet, with the
+// totals as formulas.
+func writeTimesheetXLSX(w io.Writer, sheet Timesheet, locale ExportLocale) error {
+	header := []xlsxCell{{Value: locale.Header("task"), Style: xlsxStyleHeader}}
+	for _, day := range sheet.Days {
+		header = append(header, xlsxCell{Value: locale.FormatDay(day), Style: xlsxStyleHeader})
+	}
+	header = append(header, xlsxCell{Value: locale.Header("total"), Style: xlsxStyleHeader})
+	rows := [][]xlsxCell{header}
+
+	for _, taskName := range sheet.Tasks {
+		row := []xlsxCell{{Value: taskName}}
+		for _, duration := range sheet.Cells[taskName] {
+			row = append(row, xlsxCell{Number: duration.Hours() / 24, Style: xlsxStyleDuration})
+		}
+		n := len(rows) + 1
+		row = append(row, xlsxCell{Formula: fmt.Sprintf("SUM(B%d:%s%d)", n, xlsxColumn(len(sheet.Days)), n), Style: xlsxStyleTotal})
+		rows = append(rows, row)
+	}
+
+	totals := []xlsxCell{{Value: locale.Header("total"), Style: xlsxStyleHeader}}
+	for i := 1; i <= len(sheet.Days)+1; i++ {
+		column := xlsxColumn(i)
+		formula := "0"
+		if len(rows) > 1 {
+			formula = fmt.Sprintf("SUM(%s2:%s%d)", column, column, len(rows))
+		}
+		totals = append(totals, xlsxCell{Formula: formula, Style: xlsxStyleTotal})
+	}
+	rows = append(rows, totals)
+
+	return writeXLSX(w, []string{xlsxSheetName(locale.Header("timesheet"), map[string]bool{})}, [][][]xlsxCell{rows})
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timewarriorNoTags names intervals that were tracked without tags
+const timewarriorNoTags = "(no tags)"
+
+// parseTimewarriorData reads one of Timewarrior's monthly data files, e.g.
+// ~/.timewarrior/data/2024-03.data, which hold a line per interval:
+//
+//	inc 20240301T090000Z - 20240301T103000Z # "Write code" review # "annotation"
+//
+// The first tag becomes the task and the others its tags, and the annotation
+// becomes the notes. Intervals still open, i.e. running, are skipped.
+func parseTimewarriorData(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		words := timewarriorWords(text)
+		if len(words) < 2 || words[0] != "inc" {
+			return nil, fmt.Errorf("timewarrior: line %d: not an interval", line)
+		}
+		if len(words) < 4 || words[2] != "-" {
+			continue
+		}
+		start, err := time.Parse("20060102T150405Z", words[1])
+		if err != nil {
+			return nil, fmt.Errorf("timewarrior: line %d: %w", line, err)
+		}
+		end, err := time.Parse("20060102T150405Z", words[3])
+		if err != nil {
+			return nil, fmt.Errorf("timewarrior: line %d: %w", line, err)
+		}
+
+		// What follows the first # are the tags, and the second # the
+		// annotation
+		var tags []string
+		var notes string
+		section := 0
+		for _, word := range words[4:] {
+			switch {
+			case word == "#":
+				section++
+			case section == 1:
+				tags = append(tags, word)
+			case section == 2:
+				notes = strings.TrimSpace(notes + " " + word)
+			}
+		}
+
+		entry := Entry{
+			Task:     timewarriorNoTags,
+			Start:    start.Local(),
+			End:      end.Local(),
+			Duration: end.Sub(start),
+			Notes:    notes,
+		}
+		if len(tags) > 0 {
+			entry.Task, entry.Tags = tags[0], tags[1:]
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("timewarrior: %w", err)
+	}
+	return entries, nil
+}
+
+// timewarriorWords splits a data file line on spaces, keeping quoted words,
+// which Timewarrior writes for tags with spaces, together and unquoted.
+func timewarriorWords(line string) []string {
+	var words []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '"' {
+			if quoted, err := strconv.QuotedPrefix(line); err == nil {
+				word, _ := strconv.Unquote(quoted)
+				words = append(words, word)
+				line = line[len(quoted):]
+				continue
+			}
+		}
+		end := strings.IndexByte(line, ' ')
+		if end < 0 {
+			end = len(line)
+		}
+		words = append(words, line[:end])
+		line = line[end:]
+	}
+	return words
+}
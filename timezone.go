@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"time"
+	// Embedded so a configured zone works on systems without a zoneinfo
+	// database, such as Windows
+	_ "time/tzdata"
+
+	"fyne.io/fyne/v2"
+)
+
+// PrefTimeZone is the IANA name of the zone days and weeks are counted in.
+// Empty means the system zone.
+const PrefTimeZone = "timeZone"
+
+// applyTimeZone makes the configured zone the local one. It must run before
+// any other goroutine reads the time, so a changed zone takes effect after a
+// restart.
+func applyTimeZone(prefs fyne.Preferences) {
+	name := prefs.String(PrefTimeZone)
+	if name == "" {
+		return
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("time zone: %v", err)
+		return
+	}
+	time.Local = loc
+}
+
+// dayStart returns local midnight on the day of t. When a DST change skips
+// midnight, the day starts at the first instant that exists instead.
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return dateStart(y, m, d)
+}
+
+// addDays returns the start of the day n calendar days after t's day. Days
+// are 23 or 25 hours long across DST changes, so it steps on the date rather
+// than by 24 hours.
+func addDays(t time.Time, n int) time.Time {
+	y, m, d := t.Local().Date()
+	return dateStart(y, m, d+n)
+}
+
+// dateStart returns the first instant of a date in the local zone, which
+// normalizes out-of-range days like time.Date. Where DST skips midnight, as
+// in Havana or the Azores, time.Date lands in the hour before the change, on
+// the previous day, so the start is moved on to the change itself.
+func dateStart(year int, month time.Month, day int) time.Time {
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	if _, _, d := start.Date(); d != time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Day() {
+		if _, end := start.ZoneBounds(); !end.IsZero() {
+			start = end
+		}
+	}
+	return start
+}
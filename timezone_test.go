@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// inZone runs a test with name as the local zone.
+func inZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = local })
+	return loc
+}
+
+func TestAddDays(t *testing.T) {
+	tests := []struct {
+		zone   string
+		day    string
+		length time.Duration
+		start  string
+	}{
+		// Spring forward and fall back at 2:00
+		{"America/New_York", "2026-03-08", 23 * time.Hour, "00:00"},
+		{"America/New_York", "2026-11-01", 25 * time.Hour, "00:00"},
+		// DST starts at midnight, so the day starts at 1:00
+		{"America/Havana", "2026-03-08", 23 * time.Hour, "01:00"},
+		{"America/Santiago", "2026-09-06", 23 * time.Hour, "01:00"},
+		{"Atlantic/Azores", "2026-03-29", 23 * time.Hour, "01:00"},
+		// DST ends at midnight, repeating the day before's last hour
+		{"America/Santiago", "2026-04-04", 25 * time.Hour, "00:00"},
+	}
+	for _, test := range tests {
+		t.Run(test.zone+" "+test.day, func(t *testing.T) {
+			loc := inZone(t, test.zone)
+			day, err := time.ParseInLocation("2006-01-02", test.day, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := addDays(day.Add(-12*time.Hour), 1)
+			if got := start.Format("2006-01-02 15:04"); got != test.day+" "+test.start {
+				t.Errorf("addDays(day before, 1) = %s, want %s %s", got, test.day, test.start)
+			}
+			if got := dayStart(start.Add(10 * time.Hour)); !got.Equal(start) {
+				t.Errorf("dayStart = %v, want %v", got, start)
+			}
+			next := addDays(start, 1)
+			if got := next.Sub(start); got != test.length {
+				t.Errorf("day is %v long, want %v", got, test.length)
+			}
+			if got := addDays(next, -1); !got.Equal(start) {
+				t.Errorf("addDays(next, -1) = %v, want %v", got, start)
+			}
+		})
+	}
+}
+
+// TestAddDaysAdvances walks every day of a year in zones whose DST changes
+// at midnight, where a day that doesn't advance would hang every day loop.
+func TestAddDaysAdvances(t *testing.T) {
+	for _, zone := range []string{"America/New_York", "America/Havana", "America/Santiago", "Atlantic/Azores"} {
+		loc := inZone(t, zone)
+		day := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+		for range 365 {
+			next := addDays(day, 1)
+			if next.YearDay() != day.YearDay()+1 && next.YearDay() != 1 {
+				t.Fatalf("%s: addDays(%v, 1) = %v", zone, day, next)
+			}
+			day = next
+		}
+		if want := time.Date(2027, 1, 1, 0, 0, 0, 0, loc); !day.Equal(want) {
+			t.Errorf("%s: a year on is %v, want %v", zone, day, want)
+		}
+	}
+}
+
+func TestTotalsBetween(t *testing.T) {
+	tests := []struct {
+		zone string
+		day  string
+	}{
+		{"America/New_York", "2026-03-08"},
+		{"America/New_York", "2026-11-01"},
+		{"America/Havana", "2026-03-08"},
+		{"Atlantic/Azores", "2026-03-29"},
+	}
+	for _, test := range tests {
+		t.Run(test.zone+" "+test.day, func(t *testing.T) {
+			loc := inZone(t, test.zone)
+			day, err := time.ParseInLocation("2006-01-02", test.day, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := dayStart(day.Add(12 * time.Hour))
+			timer := &TaskTimer{
+				taskList:    make(map[string]time.Duration),
+				dailyTotals: make(map[time.Time]map[string]time.Duration),
+			}
+			// An hour at the start and end of the day, and one on each
+			// neighbouring day
+			for _, at := range []time.Time{
+				start.Add(-2 * time.Hour),
+				start,
+				addDays(start, 1).Add(-time.Hour),
+				addDays(start, 1),
+			} {
+				addToTotalsLocked(timer, Entry{Task: "Work", Start: at, Duration: time.Hour}, 1)
+			}
+
+			if got := totalsBetween(timer, start, addDays(start, 1))["Work"]; got != 2*time.Hour {
+				t.Errorf("totalsBetween the day = %v, want 2h", got)
+			}
+			if got := totalsBetween(timer, addDays(start, -1), addDays(start, 2))["Work"]; got != 4*time.Hour {
+				t.Errorf("totalsBetween three days = %v, want 4h", got)
+			}
+			days := dailyTotal(timer, start, addDays(start, 1))
+			if len(days) != 1 || days[start] != 2*time.Hour {
+				t.Errorf("dailyTotal the day = %v, want 2h on %v", days, start)
+			}
+		})
+	}
+}
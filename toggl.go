@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	TogglAPIBase = "https://api.track.toggl.com/api/v9"
+
+	// TogglFetchDays is how many days of entries are asked for at a time, so
+	// no single response has to hold years of them
+	TogglFetchDays = 30
+
+	// togglNoDescription names entries that were tracked without a description
+	togglNoDescription = "(no description)"
+)
+
+// parseTogglCSV reads a Toggl Track "Detailed" CSV export. Workspaces (or the
+// Client column when present) map to clients, projects to projects, and
+// descriptions to tasks.
+func parseTogglCSV(r io.Reader) ([]Entry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("toggl: empty export")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"start date", "start time", "end date", "end time"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("toggl: missing %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []Entry
+	for line, record := range records[1:] {
+		start, err := time.ParseInLocation("2006-01-02 15:04:05",
+			field(record, "start date")+" "+field(record, "start time"), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("toggl: line %d: %w", line+2, err)
+		}
+		end, err := time.ParseInLocation("2006-01-02 15:04:05",
+			field(record, "end date")+" "+field(record, "end time"), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("toggl: line %d: %w", line+2, err)
+		}
+
+		client := field(record, "client")
+		if client == "" {
+			client = field(record, "workspace")
+		}
+		entries = append(entries, Entry{
+			Task:     togglTaskName(field(record, "description")),
+			Project:  field(record, "project"),
+			Client:   client,
+			Start:    start,
+			End:      end,
+			Duration: end.Sub(start),
+		})
+	}
+	return entries, nil
+}
+
+type togglTimeEntry struct {
+	ID          int64     `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	ProjectID   *int64    `json:"project_id"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Duration    int64     `json:"duration"`
+}
+
+type togglNamed struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// fetchTogglEntries downloads finished time entries between since and until
+// through the Toggl Track API, using the personal API token from the profile
+// page. The range is fetched TogglFetchDays at a time.
+func fetchTogglEntries(token string, since, until time.Time) ([]Entry, error) {
+	var workspaces []togglNamed
+	if err := togglGet(token, "/me/workspaces", &workspaces); err != nil {
+		return nil, err
+	}
+
+	workspaceNames := make(map[int64]string)
+	projectNames := make(map[int64]string)
+	for _, workspace := range workspaces {
+		workspaceNames[workspace.ID] = workspace.Name
+
+		var projects []togglNamed
+		if err := togglGet(token, fmt.Sprintf("/workspaces/%d/projects", workspace.ID), &projects); err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			projectNames[project.ID] = project.Name
+		}
+	}
+
+	var entries []Entry
+	// An entry on the boundary between two chunks may come back in both
+	seen := make(map[int64]bool)
+	for from := since; from.Before(until); from = from.AddDate(0, 0, TogglFetchDays) {
+		to := from.AddDate(0, 0, TogglFetchDays)
+		if to.After(until) {
+			to = until
+		}
+		query := url.Values{}
+		query.Set("start_date", from.Format(time.RFC3339))
+		query.Set("end_date", to.Format(time.RFC3339))
+
+		var timeEntries []togglTimeEntry
+		if err := togglGet(token, "/me/time_entries?"+query.Encode(), &timeEntries); err != nil {
+			return nil, err
+		}
+
+		for _, te := range timeEntries {
+			// A negative duration marks the entry that is still running
+			if te.Duration < 0 || te.Stop.IsZero() || seen[te.ID] {
+				continue
+			}
+			seen[te.ID] = true
+
+			entry := Entry{
+				Task:     togglTaskName(te.Description),
+				Client:   workspaceNames[te.WorkspaceID],
+				Start:    te.Start.Local(),
+				End:      te.Stop.Local(),
+				Duration: time.Duration(te.Duration) * time.Second,
+			}
+			if te.ProjectID != nil {
+				entry.Project = projectNames[*te.ProjectID]
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func togglGet(token, path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, TogglAPIBase+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(token, "api_token")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("toggl: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("toggl: %s responded with %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func togglTaskName(description string) string {
+	if description == "" {
+		return togglNoDescription
+	}
+	return description
+}
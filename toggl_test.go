@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to a test server instead.
+type redirectTransport struct{ target *url.URL }
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = rt.target.Scheme, rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestFetchTogglEntriesInChunks(t *testing.T) {
+	since := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 3*TogglFetchDays+5)
+	projectID := int64(7)
+	entry := func(id int64, start time.Time) togglTimeEntry {
+		return togglTimeEntry{
+			ID: id, WorkspaceID: 1, ProjectID: &projectID, Description: "Design",
+			Start: start, Stop: start.Add(time.Hour), Duration: 3600,
+		}
+	}
+	stored := []togglTimeEntry{
+		entry(1, since.Add(9*time.Hour)),
+		// On the boundary between the first two chunks
+		entry(2, since.AddDate(0, 0, TogglFetchDays)),
+		entry(3, since.AddDate(0, 0, 2*TogglFetchDays+10)),
+		entry(4, until.Add(-2*time.Hour)),
+		// Still running
+		{ID: 5, WorkspaceID: 1, Start: until.Add(-time.Hour), Duration: -1},
+	}
+
+	var spans []time.Duration
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v9/me/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]togglNamed{{ID: 1, Name: "Acme"}})
+	})
+	mux.HandleFunc("/api/v9/workspaces/1/projects", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]togglNamed{{ID: projectID, Name: "Website"}})
+	})
+	mux.HandleFunc("/api/v9/me/time_entries", func(w http.ResponseWriter, r *http.Request) {
+		from, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		spans = append(spans, to.Sub(from))
+		// Both ends inclusive, so boundary entries come back twice
+		var matching []togglTimeEntry
+		for _, te := range stored {
+			if !te.Start.Before(from) && !te.Start.After(to) {
+				matching = append(matching, te)
+			}
+		}
+		json.NewEncoder(w).Encode(matching)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := httpClient.Transport
+	httpClient.Transport = redirectTransport{target}
+	defer func() { httpClient.Transport = transport }()
+
+	entries, err := fetchTogglEntries("token", since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 4 {
+		t.Errorf("fetched in %d requests, want 4", len(spans))
+	}
+	for _, span := range spans {
+		if span > TogglFetchDays*24*time.Hour {
+			t.Errorf("a request spans %v, more than %d days", span, TogglFetchDays)
+		}
+	}
+	var starts []time.Time
+	for _, entry := range entries {
+		if entry.Client != "Acme" || entry.Project != "Website" || entry.Task != "Design" || entry.Duration != time.Hour {
+			t.Errorf("entry %+v, want an hour of Design on Acme's Website", entry)
+		}
+		starts = append(starts, entry.Start.UTC())
+	}
+	want := []time.Time{stored[0].Start, stored[1].Start, stored[2].Start, stored[3].Start}
+	if !slices.EqualFunc(starts, want, time.Time.Equal) {
+		t.Errorf("imported entries starting %v, want %v", starts, want)
+	}
+}
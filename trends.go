@@ -0,0 +1,133 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefTrendWeeks is how many weeks the trends cover
+	PrefTrendWeeks = "trendWeeks"
+
+	DefaultTrendWeeks = 8
+)
+
+var TrendWeeks = []int{4, 8, 12, 26}
+
+// projectTrends totals each project's time per week, oldest week first, for
+// the weeks weeks up to and including the one containing now.
+func projectTrends(entries []Entry, now time.Time, weeks int) map[string][]time.Duration {
+	first := addDays(weekStart(now), -7*(weeks-1))
+	trends := make(map[string][]time.Duration)
+	for _, entry := range entries {
+		if entry.Start.Before(first) {
+			continue
+		}
+		week := int(weekStart(entry.Start).Sub(first).Hours()/24+0.5) / 7
+		if week >= weeks {
+			continue
+		}
+		name := reportProjectName(entry)
+		if trends[name] == nil {
+			trends[name] = make([]time.Duration, weeks)
+		}
+		trends[name][week] += entry.Duration
+	}
+	return trends
+}
+
+// createTrendsContainer compares each project's time over the last weeks,
+// with a sparkline per project and this week's change on the last, so a
+// project quietly taking over shows up.
+func createTrendsContainer(timer *TaskTimer) fyne.CanvasObject {
+	prefs := fyne.CurrentApp().Preferences()
+	var weekChoices []string
+	for _, weeks := range TrendWeeks {
+		weekChoices = append(weekChoices, tr("{{.Weeks}} weeks", map[string]any{"Weeks": weeks}))
+	}
+	weeksSelect := widget.NewSelect(weekChoices, nil)
+	weeksSelect.SetSelectedIndex(max(slices.Index(TrendWeeks, prefs.IntWithFallback(PrefTrendWeeks, DefaultTrendWeeks)), 0))
+	list := container.NewVBox()
+
+	render := func() {
+		weeks := TrendWeeks[max(weeksSelect.SelectedIndex(), 0)]
+		history, _ := timer.history.Get()
+		trends := projectTrends(history, time.Now(), weeks)
+
+		totals := make(map[string]time.Duration)
+		for name, values := range trends {
+			for _, value := range values {
+				totals[name] += value
+			}
+		}
+
+		list.RemoveAll()
+		if len(trends) == 0 {
+			list.Add(widget.NewLabel(tr("Nothing tracked in this period")))
+		}
+		for _, name := range sortedTaskNames(totals) {
+			values := trends[name]
+			current, last := values[weeks-1], values[weeks-2]
+			label := widget.NewLabel(name)
+			label.Truncation = fyne.TextTruncateEllipsis
+			change := widget.NewLabel(formatShortDuration(current.Truncate(time.Minute)) + "  " + reportChange(current, last))
+			list.Add(container.NewBorder(nil, nil, nil, container.NewHBox(newSparkline(values), change), label))
+		}
+	}
+	weeksSelect.OnChanged = func(string) {
+		prefs.SetInt(PrefTrendWeeks, TrendWeeks[weeksSelect.SelectedIndex()])
+		render()
+	}
+	listenWhileShown(timer, timer.history, render)
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(tr("Trends by project")), weeksSelect),
+		list,
+	)
+}
+
+// sparklineSize is the size of a sparkline.
+var sparklineSize = fyne.NewSize(120, 24)
+
+// newSparkline draws values as a row of bars scaled to the largest, the
+// last one highlighted.
+func newSparkline(values []time.Duration) fyne.CanvasObject {
+	peak := slices.Max(values)
+	bars := make([]fyne.CanvasObject, len(values))
+	for i := range values {
+		fill := theme.Color(theme.ColorNameDisabled)
+		if i == len(values)-1 {
+			fill = theme.Color(theme.ColorNamePrimary)
+		}
+		bars[i] = canvas.NewRectangle(fill)
+	}
+	return container.New(&sparklineLayout{values: values, peak: peak}, bars...)
+}
+
+// sparklineLayout stands one bar per value on a common baseline.
+type sparklineLayout struct {
+	values []time.Duration
+	peak   time.Duration
+}
+
+func (l *sparklineLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	width := size.Width / float32(len(objects))
+	for i, bar := range objects {
+		height := float32(1)
+		if l.peak > 0 {
+			height = max(height, size.Height*float32(l.values[i])/float32(l.peak))
+		}
+		bar.Move(fyne.NewPos(float32(i)*width, size.Height-height))
+		bar.Resize(fyne.NewSize(max(width-1, 1), height))
+	}
+}
+
+func (l *sparklineLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return sparklineSize
+}
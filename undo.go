@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// UndoToastDuration is how long the toast with an Undo button stays up
+// after a change.
+const UndoToastDuration = 10 * time.Second
+
+// UndoLimit is how many changes the undo history keeps.
+const UndoLimit = 50
+
+type undoAction struct {
+	label string
+	undo  func()
+	redo  func() // nil if the action can't be redone
+}
+
+// UndoStack is the history of changes that can be undone, most recent last,
+// and of those undone that can be redone. Making a new change clears what
+// could be redone.
+type UndoStack struct {
+	mu     sync.Mutex
+	done   []*undoAction
+	undone []*undoAction
+
+	// OnChanged is called after the history changes, e.g. to relabel the
+	// Edit menu.
+	OnChanged func()
+}
+
+// Push records an action with the functions that revert and repeat it. redo
+// may be nil for an action that can only be undone.
+func (s *UndoStack) Push(label string, undo, redo func()) {
+	s.mu.Lock()
+	s.done = append(s.done, &undoAction{label: label, undo: undo, redo: redo})
+	if len(s.done) > UndoLimit {
+		s.done = s.done[len(s.done)-UndoLimit:]
+	}
+	s.undone = nil
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// Undo reverts the most recent action. It reports false if there was
+// nothing to undo.
+func (s *UndoStack) Undo() bool {
+	s.mu.Lock()
+	if len(s.done) == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	action := s.done[len(s.done)-1]
+	s.done = s.done[:len(s.done)-1]
+	if action.redo != nil {
+		s.undone = append(s.undone, action)
+	} else {
+		// Later actions can't be redone in place of one that can't
+		s.undone = nil
+	}
+	s.mu.Unlock()
+
+	action.undo()
+	s.changed()
+	return true
+}
+
+// Redo repeats the action most recently undone. It reports false if there
+// was nothing to redo.
+func (s *UndoStack) Redo() bool {
+	s.mu.Lock()
+	if len(s.undone) == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	action := s.undone[len(s.undone)-1]
+	s.undone = s.undone[:len(s.undone)-1]
+	s.done = append(s.done, action)
+	s.mu.Unlock()
+
+	action.redo()
+	s.changed()
+	return true
+}
+
+// Labels returns the labels of the actions Undo and Redo would take next,
+// empty if there is none.
+func (s *UndoStack) Labels() (undo, redo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.done) > 0 {
+		undo = s.done[len(s.done)-1].label
+	}
+	if len(s.undone) > 0 {
+		redo = s.undone[len(s.undone)-1].label
+	}
+	return undo, redo
+}
+
+func (s *UndoStack) changed() {
+	if s.OnChanged != nil {
+		s.OnChanged()
+	}
+}
+
+// pushUndo records an undoable action and shows a toast with an Undo button
+// along the bottom of the window for a few seconds. redo may be nil for an
+// action that can only be undone.
+func pushUndo(timer *TaskTimer, label string, undo, redo func()) {
+	timer.undo.Push(label, undo, redo)
+
+	if timer.undoToast != nil {
+		timer.undoToast.Hide()
+	}
+
+	var toast *widget.PopUp
+	undoBtn := widget.NewButton(tr("Undo"), func() {
+		toast.Hide()
+		timer.undo.Undo()
+	})
+	toast = widget.NewPopUp(container.NewHBox(widget.NewLabel(label), undoBtn), timer.window.Canvas())
+
+	canvasSize := timer.window.Canvas().Size()
+	toastSize := toast.MinSize()
+	toast.ShowAtPosition(fyne.NewPos(
+		(canvasSize.Width-toastSize.Width)/2,
+		canvasSize.Height-toastSize.Height-theme.Padding()*4,
+	))
+	timer.undoToast = toast
+
+	time.AfterFunc(UndoToastDuration, func() {
+		fyne.Do(toast.Hide)
+	})
+}
+
+// undoLast and redoLast are what the Edit menu does.
+func undoLast(timer *TaskTimer) {
+	if timer.undoToast != nil {
+		timer.undoToast.Hide()
+	}
+	timer.undo.Undo()
+}
+
+func redoLast(timer *TaskTimer) {
+	if timer.undoToast != nil {
+		timer.undoToast.Hide()
+	}
+	timer.undo.Redo()
+}
+
+// changeEntries runs change, which edits, deletes, splits or merges stored
+// entries, and records what it changed as one action under label. Entries
+// are told apart by ID and by when they were last updated, so whatever
+// change managed before failing can still be undone.
+func changeEntries(timer *TaskTimer, label string, change func() error) error {
+	before := allEntries(timer)
+	err := change()
+	removed, added := diffEntries(before, allEntries(timer))
+	if len(removed) > 0 || len(added) > 0 {
+		pushUndo(timer, label, func() {
+			swapEntries(timer, added, removed)
+		}, func() {
+			swapEntries(timer, removed, added)
+		})
+	}
+	return err
+}
+
+// diffEntries returns the entries in before that are gone or changed in
+// after, and the ones in after that are new or changed.
+func diffEntries(before, after []Entry) (removed, added []Entry) {
+	previous := make(map[string]Entry, len(before))
+	for _, entry := range before {
+		previous[entry.ID] = entry
+	}
+	for _, entry := range after {
+		old, ok := previous[entry.ID]
+		if ok && old.Updated.Equal(entry.Updated) {
+			delete(previous, entry.ID)
+			continue
+		}
+		if ok {
+			removed = append(removed, old)
+			delete(previous, entry.ID)
+		}
+		added = append(added, entry)
+	}
+	for _, entry := range before {
+		if _, ok := previous[entry.ID]; ok {
+			removed = append(removed, entry)
+		}
+	}
+	return removed, added
+}
+
+// swapEntries puts the entries in to back in place of those in from. An
+// entry in both is updated, keeping its ID.
+func swapEntries(timer *TaskTimer, from, to []Entry) {
+	kept := make(map[string]bool, len(to))
+	for _, entry := range to {
+		kept[entry.ID] = true
+	}
+	for _, entry := range from {
+		if kept[entry.ID] {
+			continue
+		}
+		// An entry already gone, e.g. deleted by a sync, needs nothing more
+		deleteEntry(timer, entry.ID)
+	}
+	for _, entry := range to {
+		timer.tasks.Ensure(entry.Task)
+		if err := updateEntry(timer, entry); errors.Is(err, errEntryNotFound) {
+			restored := recordEntry(timer, entry)
+			auditEntry(timer, restored.ID, AuditRestored, nil, &restored)
+		}
+	}
+	refreshEntryViews(timer)
+}
+
+// refreshEntryViews rebuilds the current view if it shows entries, which
+// otherwise only redraw after their own edits.
+func refreshEntryViews(timer *TaskTimer) {
+	switch timer.currentView {
+	case "stats", "history", "review", "timeline", "timesheet":
+		updateContentView(timer)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiffEntries(t *testing.T) {
+	at := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	entry := func(id string, updated time.Duration) Entry {
+		return Entry{ID: id, Task: "Code", Start: at, Duration: time.Hour, Updated: at.Add(updated)}
+	}
+	before := []Entry{entry("kept", 0), entry("edited", 0), entry("deleted", 0)}
+	after := []Entry{entry("kept", 0), entry("edited", time.Minute), entry("new", time.Minute)}
+
+	removed, added := diffEntries(before, after)
+	if got, want := entryIDs(removed), []string{"deleted", "edited"}; !slices.Equal(got, want) {
+		t.Errorf("removed %v, want %v", got, want)
+	}
+	if got, want := entryIDs(added), []string{"edited", "new"}; !slices.Equal(got, want) {
+		t.Errorf("added %v, want %v", got, want)
+	}
+	for _, entry := range removed {
+		if entry.ID == "edited" && !entry.Updated.Equal(at) {
+			t.Errorf("removed the edited entry as it is now, want it as it was")
+		}
+	}
+
+	if removed, added := diffEntries(before, before); len(removed) != 0 || len(added) != 0 {
+		t.Errorf("unchanged entries: removed %v, added %v", removed, added)
+	}
+}
+
+func TestUndoStack(t *testing.T) {
+	var stack UndoStack
+	changes := 0
+	stack.OnChanged = func() { changes++ }
+	var log []string
+	push := func(label string, redoable bool) {
+		var redo func()
+		if redoable {
+			redo = func() { log = append(log, "redo "+label) }
+		}
+		stack.Push(label, func() { log = append(log, "undo "+label) }, redo)
+	}
+	labels := func() [2]string {
+		undo, redo := stack.Labels()
+		return [2]string{undo, redo}
+	}
+
+	push("first", true)
+	push("second", false)
+	push("third", true)
+	if got, want := labels(), [2]string{"third", ""}; got != want {
+		t.Errorf("labels %q, want %q", got, want)
+	}
+	stack.Undo()
+	if got, want := labels(), [2]string{"second", "third"}; got != want {
+		t.Errorf("after undoing: labels %q, want %q", got, want)
+	}
+	// Undoing what can't be redone drops what could
+	stack.Undo()
+	if got, want := labels(), [2]string{"first", ""}; got != want {
+		t.Errorf("after undoing one that can't be redone: labels %q, want %q", got, want)
+	}
+	if stack.Redo() {
+		t.Error("redid an action past one that can't be redone")
+	}
+	stack.Undo()
+	stack.Redo()
+	// A new change clears what could be redone
+	stack.Undo()
+	push("fourth", true)
+	if stack.Redo() {
+		t.Error("redid an action after a new change")
+	}
+	stack.Undo()
+	if stack.Undo() {
+		t.Error("undid past the start of the history")
+	}
+
+	want := []string{"undo third", "undo second", "undo first", "redo first", "undo first", "undo fourth"}
+	if !slices.Equal(log, want) {
+		t.Errorf("ran %q, want %q", log, want)
+	}
+	// Every push, undo and redo that did something
+	if changes != 10 {
+		t.Errorf("OnChanged called %d times, want 10", changes)
+	}
+
+	for i := range UndoLimit + 5 {
+		push(strconv.Itoa(i), true)
+	}
+	undone := 0
+	for stack.Undo() {
+		undone++
+	}
+	if undone != UndoLimit {
+		t.Errorf("undid %d actions, want the last %d", undone, UndoLimit)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// KeyringService and KeyringUser name the passphrase of the encrypted
+	// store in the system keychain
+	KeyringService = "io.github.0jc1.gotime"
+	KeyringUser    = "data passphrase"
+)
+
+// unlockStore opens the store and passes it to onOpen. The encrypted store
+// is opened with the passphrase saved in the system keychain if there is
+// one; otherwise the window asks for it first.
+func unlockStore(app fyne.App, w fyne.Window, onOpen func(Store)) {
+	if !needsPassphrase(app.Preferences()) {
+		store, err := openStore(app, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+		onOpen(store)
+		return
+	}
+
+	if passphrase, err := keyring.Get(KeyringService, KeyringUser); err == nil {
+		store, err := openStore(app, passphrase)
+		if err == nil {
+			onOpen(store)
+			return
+		}
+		if !errors.Is(err, errWrongPassphrase) {
+			log.Fatal(err)
+		}
+	}
+	w.SetContent(createUnlockContainer(app, onOpen))
+}
+
+// createUnlockContainer asks for the passphrase, or has one chosen when the
+// encrypted store doesn't exist yet.
+func createUnlockContainer(app fyne.App, onOpen func(Store)) *fyne.Container {
+	path := filepath.Join(app.Storage().RootURI().Path(), storageBackends[StorageEncrypted].file)
+	_, err := os.Stat(path)
+	isNew := os.IsNotExist(err)
+
+	passphraseInput := widget.NewPasswordEntry()
+	confirmInput := widget.NewPasswordEntry()
+	remember := widget.NewCheck(tr("Remember in the system keychain"), nil)
+	errorLabel := widget.NewLabel("")
+	errorLabel.Wrapping = fyne.TextWrapWord
+
+	unlock := func() {
+		passphrase := passphraseInput.Text
+		if isNew && passphrase != confirmInput.Text {
+			errorLabel.SetText(tr("The passphrases don't match."))
+			return
+		}
+		if passphrase == "" {
+			errorLabel.SetText(tr("Enter a passphrase."))
+			return
+		}
+
+		store, err := openStore(app, passphrase)
+		if errors.Is(err, errWrongPassphrase) {
+			errorLabel.SetText(tr("Wrong passphrase."))
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if remember.Checked {
+			if err := keyring.Set(KeyringService, KeyringUser, passphrase); err != nil {
+				log.Printf("keychain: %v", err)
+			}
+		}
+		onOpen(store)
+	}
+	passphraseInput.OnSubmitted = func(string) { unlock() }
+	confirmInput.OnSubmitted = func(string) { unlock() }
+
+	title := tr("Your data is encrypted. Enter the passphrase to unlock it.")
+	form := widget.NewForm(widget.NewFormItem(tr("Passphrase"), passphraseInput))
+	if isNew {
+		title = tr("Choose a passphrase to encrypt your data with. It can't be recovered if you forget it.")
+		form.Append(tr("Confirm"), confirmInput)
+	}
+	titleLabel := widget.NewLabel(title)
+	titleLabel.Wrapping = fyne.TextWrapWord
+
+	return container.NewVBox(
+		titleLabel,
+		form,
+		remember,
+		widget.NewButton(tr("Unlock"), unlock),
+		errorLabel,
+	)
+}
+
+// forgetPassphrase removes the passphrase from the system keychain.
+func forgetPassphrase() {
+	if err := keyring.Delete(KeyringService, KeyringUser); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		log.Printf("keychain: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// URLScheme is the scheme of links that drive the timer, such as
+//
+//	gotime://start?task=Writing&project=Book&tag=draft
+//	gotime://switch?task=Review
+//	gotime://stop
+//	gotime://open
+//
+// Tags may be repeated, or given as one comma-separated tags parameter.
+const URLScheme = "gotime"
+
+// isTimerURL is whether a launch argument is a gotime:// link.
+func isTimerURL(arg string) bool {
+	return strings.HasPrefix(strings.ToLower(arg), URLScheme+":")
+}
+
+// parseTimerURL turns a gotime:// link into a command for the app.
+func parseTimerURL(raw string) (InstanceRequest, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return InstanceRequest{}, err
+	}
+	if !strings.EqualFold(u.Scheme, URLScheme) {
+		return InstanceRequest{}, fmt.Errorf("not a %s:// link: %s", URLScheme, raw)
+	}
+
+	// gotime://start puts the command in the host, gotime:start in the
+	// opaque part, and gotime:///start in the path
+	command := u.Host
+	if command == "" {
+		command = strings.Trim(u.Opaque+u.Path, "/")
+	}
+	command = strings.ToLower(command)
+	if command == "open" {
+		command = "focus"
+	}
+	if !slices.Contains([]string{"focus", "start", "stop", "switch"}, command) {
+		return InstanceRequest{}, fmt.Errorf("unknown link command %q", command)
+	}
+
+	query := u.Query()
+	request := InstanceRequest{
+		Command: command,
+		Task:    strings.TrimSpace(query.Get("task")),
+		Project: strings.TrimSpace(query.Get("project")),
+	}
+	tags := query["tag"]
+	if query.Has("tags") {
+		tags = append(tags, strings.Split(query.Get("tags"), ",")...)
+	}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			request.Tags = append(request.Tags, tag)
+		}
+	}
+	if command == "switch" && request.Task == "" {
+		return InstanceRequest{}, fmt.Errorf("%s://switch needs a task", URLScheme)
+	}
+	return request, nil
+}
@@ -0,0 +1,52 @@
+//go:build darwin && !ios
+
+package main
+
+/*
+#cgo LDFLAGS: -framework AppKit
+#include <stdlib.h>
+
+void startURLEvents(void);
+int bundleDeclaresURLScheme(const char *scheme);
+*/
+import "C"
+
+import (
+	"errors"
+	"log"
+	"unsafe"
+)
+
+// urlEventInstances takes the links macOS hands the running app.
+var urlEventInstances *InstanceServer
+
+// urlSchemeRegistered is whether the app bundle declares gotime:// links,
+// which Launch Services then opens with it.
+func urlSchemeRegistered() bool {
+	scheme := C.CString(URLScheme)
+	defer C.free(unsafe.Pointer(scheme))
+	return C.bundleDeclaresURLScheme(scheme) != 0
+}
+
+// setURLScheme can't change anything on macOS, where links go to whichever
+// app bundle declares the scheme in its Info.plist.
+func setURLScheme(enabled bool) error {
+	return errors.New("on macOS, gotime:// links open the app bundle that declares them in its Info.plist")
+}
+
+// watchURLEvents takes links from Apple events, which is how macOS opens
+// them, both at launch and while the app runs.
+func watchURLEvents(instances *InstanceServer) {
+	urlEventInstances = instances
+	C.startURLEvents()
+}
+
+//export goHandleURL
+func goHandleURL(raw *C.char) {
+	request, err := parseTimerURL(C.GoString(raw))
+	if err != nil {
+		log.Printf("links: %v", err)
+		return
+	}
+	urlEventInstances.Open(request)
+}
@@ -0,0 +1,45 @@
+//go:build darwin && !ios
+
+#import <AppKit/AppKit.h>
+#include "_cgo_export.h"
+
+// GotimeURLHandler passes the links in GetURL Apple events to the app.
+@interface GotimeURLHandler : NSObject
+- (void)handleURLEvent:(NSAppleEventDescriptor *)event withReplyEvent:(NSAppleEventDescriptor *)reply;
+@end
+
+@implementation GotimeURLHandler
+- (void)handleURLEvent:(NSAppleEventDescriptor *)event withReplyEvent:(NSAppleEventDescriptor *)reply {
+	NSString *url = [[event paramDescriptorForKeyword:keyDirectObject] stringValue];
+	if (url != nil) {
+		goHandleURL((char *)[url UTF8String]);
+	}
+}
+@end
+
+void startURLEvents(void) {
+	static GotimeURLHandler *handler;
+	if (handler != nil) {
+		return;
+	}
+	handler = [[GotimeURLHandler alloc] init];
+	[[NSAppleEventManager sharedAppleEventManager] setEventHandler:handler
+	                                                   andSelector:@selector(handleURLEvent:withReplyEvent:)
+	                                                 forEventClass:kInternetEventClass
+	                                                    andEventID:kAEGetURL];
+}
+
+int bundleDeclaresURLScheme(const char *scheme) {
+	@autoreleasepool {
+		NSString *wanted = [[NSString stringWithUTF8String:scheme] lowercaseString];
+		NSArray *types = [[NSBundle mainBundle] objectForInfoDictionaryKey:@"CFBundleURLTypes"];
+		for (NSDictionary *type in types) {
+			for (NSString *declared in type[@"CFBundleURLSchemes"]) {
+				if ([[declared lowercaseString] isEqualToString:wanted]) {
+					return 1;
+				}
+			}
+		}
+		return 0;
+	}
+}
@@ -0,0 +1,82 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// URLHandlerID is the desktop entry that opens gotime:// links.
+const URLHandlerID = AutostartName + ".links.desktop"
+
+func urlHandlerPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "applications", URLHandlerID), nil
+}
+
+func urlSchemeRegistered() bool {
+	path, err := urlHandlerPath()
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	out, err := exec.Command("xdg-mime", "query", "default", "x-scheme-handler/"+URLScheme).Output()
+	return err == nil && strings.TrimSpace(string(out)) == URLHandlerID
+}
+
+// setURLScheme writes a hidden desktop entry for gotime:// links and makes
+// it their default handler through xdg-mime, which has to be installed.
+func setURLScheme(enabled bool) error {
+	path, err := urlHandlerPath()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(exe)
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=gotime
+Comment=Opens %s:// links
+Exec="%s" %%u
+Terminal=false
+NoDisplay=true
+MimeType=x-scheme-handler/%s;
+`, URLScheme, quoted, URLScheme)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return err
+	}
+	if err := exec.Command("xdg-mime", "default", URLHandlerID, "x-scheme-handler/"+URLScheme).Run(); err != nil {
+		return fmt.Errorf("xdg-mime: %w", err)
+	}
+	return nil
+}
+
+// watchURLEvents has nothing to watch: links arrive as launch arguments.
+func watchURLEvents(instances *InstanceServer) {}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows && (!darwin || ios)
+
+package main
+
+import "errors"
+
+func urlSchemeRegistered() bool {
+	return false
+}
+
+func setURLScheme(enabled bool) error {
+	return errors.New("opening links isn't supported here")
+}
+
+func watchURLEvents(instances *InstanceServer) {}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// urlSchemeKey is the per-user class of gotime:// links.
+const urlSchemeKey = `Software\Classes\` + URLScheme
+
+func urlSchemeRegistered() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, urlSchemeKey+`\shell\open\command`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	_, _, err = key.GetStringValue("")
+	return err == nil
+}
+
+// setURLScheme registers this executable for gotime:// links, or removes
+// the registration.
+func setURLScheme(enabled bool) error {
+	if !enabled {
+		for _, path := range []string{`\shell\open\command`, `\shell\open`, `\shell`, ""} {
+			if err := registry.DeleteKey(registry.CURRENT_USER, urlSchemeKey+path); err != nil && !errors.Is(err, registry.ErrNotExist) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, urlSchemeKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	if err := key.SetStringValue("", "URL:gotime"); err != nil {
+		return err
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+	command, _, err := registry.CreateKey(registry.CURRENT_USER, urlSchemeKey+`\shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer command.Close()
+	return command.SetStringValue("", `"`+exe+`" "%1"`)
+}
+
+// watchURLEvents has nothing to watch: links arrive as launch arguments.
+func watchURLEvents(instances *InstanceServer) {}
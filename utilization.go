@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// PrefUtilizationTarget is the share of the scheduled capacity meant to
+	// be billable, as a percentage, zero for none
+	PrefUtilizationTarget = "utilizationTarget"
+
+	DefaultUtilizationTarget = 75
+)
+
+// utilizationPeriod is a row of the utilization report.
+type utilizationPeriod struct {
+	billablePeriod
+	Capacity time.Duration
+}
+
+// OfTracked is the billable share of the time tracked, as a percentage.
+func (p utilizationPeriod) OfTracked() int {
+	return billablePercent(p.Billable, p.Internal)
+}
+
+// OfCapacity is the billable time as a percentage of the scheduled
+// capacity, and false if nothing was scheduled.
+func (p utilizationPeriod) OfCapacity() (int, bool) {
+	if p.Capacity <= 0 {
+		return 0, false
+	}
+	return int((p.Billable*100 + p.Capacity/2) / p.Capacity), true
+}
+
+// utilizationReport adds the scheduled capacity to the billable report's
+// periods. The current period's capacity only runs to the end of today, so
+// it isn't measured against days still to come.
+func utilizationReport(entries []Entry, grouping billableGrouping, now time.Time, count int) []utilizationPeriod {
+	tomorrow := addDays(dayStart(now), 1)
+	var periods []utilizationPeriod
+	for _, period := range billableReport(entries, grouping, now, count) {
+		end := grouping.next(period.Start)
+		if end.After(tomorrow) {
+			end = tomorrow
+		}
+		periods = append(periods, utilizationPeriod{
+			billablePeriod: period,
+			Capacity:       scheduledCapacity(period.Start, end),
+		})
+	}
+	return periods
+}
+
+func utilizationTarget() int {
+	return fyne.CurrentApp().Preferences().IntWithFallback(PrefUtilizationTarget, DefaultUtilizationTarget)
+}
+
+// utilizationChartHeight is the height of the utilization chart.
+const utilizationChartHeight = 120
+
+// newUtilizationChart draws each period's utilization as a bar, those
+// reaching the target highlighted, under a line at the target.
+func newUtilizationChart(percents []int, target int) fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(percents)+1)
+	for _, percent := range percents {
+		fill := theme.Color(theme.ColorNameDisabled)
+		if target > 0 && percent >= target {
+			fill = theme.Color(theme.ColorNamePrimary)
+		}
+		objects = append(objects, canvas.NewRectangle(fill))
+	}
+	line := canvas.NewLine(theme.Color(theme.ColorNameError))
+	line.StrokeWidth = 2
+	if target <= 0 {
+		line.StrokeColor = color.Transparent
+	}
+	objects = append(objects, line)
+	return container.New(&utilizationChartLayout{percents: percents, target: target}, objects...)
+}
+
+// utilizationChartLayout stands a bar per period on a common baseline, scaled
+// so 100% and the tallest bar both fit, and lays the target line across.
+type utilizationChartLayout struct {
+	percents []int
+	target   int
+}
+
+func (l *utilizationChartLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	scale := float32(max(100, l.target))
+	if len(l.percents) > 0 {
+		scale = max(scale, float32(slices.Max(l.percents)))
+	}
+	width := size.Width / float32(max(len(l.percents), 1))
+	for i, percent := range l.percents {
+		height := max(1, size.Height*float32(percent)/scale)
+		objects[i].Move(fyne.NewPos(float32(i)*width+2, size.Height-height))
+		objects[i].Resize(fyne.NewSize(max(width-4, 1), height))
+	}
+	y := size.Height - size.Height*float32(l.target)/scale
+	line := objects[len(objects)-1].(*canvas.Line)
+	line.Position1 = fyne.NewPos(0, y)
+	line.Position2 = fyne.NewPos(size.Width, y)
+}
+
+func (l *utilizationChartLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(float32(len(l.percents))*12, utilizationChartHeight)
+}
+
+// showUtilizationReport shows the billable share of the time tracked and
+// of the scheduled capacity for each recent week or month, charted against
+// a target.
+func showUtilizationReport(timer *TaskTimer) {
+	rows := container.NewVBox()
+	chart := container.NewStack()
+	var periods []utilizationPeriod
+
+	// Quarters are left to the billable report, as capacity is planned by
+	// the week
+	groupings := billableGroupings[:2]
+	var labels []string
+	for _, grouping := range groupings {
+		labels = append(labels, tr(grouping.label))
+	}
+	groupingSelect := widget.NewSelect(labels, nil)
+	targetInput := widget.NewEntry()
+	targetInput.SetText(strconv.Itoa(utilizationTarget()))
+
+	render := func() {
+		grouping := groupings[max(groupingSelect.SelectedIndex(), 0)]
+		periods = utilizationReport(allEntries(timer), grouping, time.Now(), BillableReportPeriods)
+		target := utilizationTarget()
+
+		var percents []int
+		for _, period := range periods {
+			percent, ok := period.OfCapacity()
+			if !ok {
+				percent = period.OfTracked()
+			}
+			percents = append(percents, percent)
+		}
+		chart.Objects = []fyne.CanvasObject{newUtilizationChart(percents, target)}
+		chart.Refresh()
+
+		bold := fyne.TextStyle{Bold: true}
+		rows.RemoveAll()
+		rows.Add(container.NewGridWithColumns(6,
+			widget.NewLabelWithStyle(tr("Period"), fyne.TextAlignLeading, bold),
+			widget.NewLabelWithStyle(tr("Billable"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("Tracked"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("of tracked"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("Capacity"), fyne.TextAlignTrailing, bold),
+			widget.NewLabelWithStyle(tr("of capacity"), fyne.TextAlignTrailing, bold),
+		))
+		for _, period := range slices.Backward(periods) {
+			ofTracked, ofCapacity := "—", "—"
+			if period.Billable+period.Internal > 0 {
+				ofTracked = fmt.Sprintf("%d%%", period.OfTracked())
+			}
+			if percent, ok := period.OfCapacity(); ok {
+				ofCapacity = fmt.Sprintf("%d%%", percent)
+			}
+			capacityLabel := widget.NewLabelWithStyle(ofCapacity, fyne.TextAlignTrailing, fyne.TextStyle{})
+			if percent, ok := period.OfCapacity(); ok && target > 0 && percent >= target {
+				capacityLabel.TextStyle = bold
+			}
+			rows.Add(container.NewGridWithColumns(6,
+				widget.NewLabel(period.Title),
+				widget.NewLabelWithStyle(formatDuration(period.Billable), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatDuration(period.Billable+period.Internal), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(ofTracked, fyne.TextAlignTrailing, fyne.TextStyle{}),
+				widget.NewLabelWithStyle(formatDuration(period.Capacity), fyne.TextAlignTrailing, fyne.TextStyle{}),
+				capacityLabel,
+			))
+		}
+	}
+	groupingSelect.OnChanged = func(string) { render() }
+	targetInput.OnChanged = func(text string) {
+		target, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(text), "%"))
+		if err != nil || target < 0 {
+			return
+		}
+		fyne.CurrentApp().Preferences().SetInt(PrefUtilizationTarget, target)
+		render()
+	}
+	groupingSelect.SetSelectedIndex(0)
+
+	exportBtn := widget.NewButtonWithIcon(tr("Export CSV…"), theme.DocumentSaveIcon(), func() {
+		saveExport(timer, "utilization.csv", func(w io.Writer) error {
+			return writeUtilizationCSV(w, periods, currentExportLocale())
+		})
+	})
+
+	note := widget.NewLabel(tr("Capacity comes from the work schedule, or the weekly hours, less days off. Without either, bars show the share of the time tracked."))
+	note.Wrapping = fyne.TextWrapWord
+	top := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Show"), groupingSelect),
+			widget.NewFormItem(tr("Target %"), targetInput),
+		),
+		chart,
+	)
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(640, 260))
+	content := container.NewBorder(top, container.NewVBox(note, exportBtn), nil, nil, scroll)
+	dialog.NewCustom(tr("Utilization"), tr("Close"), content, timer.window).Show()
+}
+
+// writeUtilizationCSV writes the report with hours as decimals, formatted
+// for the export locale. The share of capacity is blank where none was
+// scheduled.
+func writeUtilizationCSV(w io.Writer, periods []utilizationPeriod, locale ExportLocale) error {
+	out := csv.NewWriter(w)
+	out.Comma = locale.Separator
+	if err := out.Write([]string{"period", "start", "billable_hours", "tracked_hours", "capacity_hours", "percent_of_tracked", "percent_of_capacity"}); err != nil {
+		return err
+	}
+	for _, period := range periods {
+		ofCapacity := ""
+		if percent, ok := period.OfCapacity(); ok {
+			ofCapacity = strconv.Itoa(percent)
+		}
+		if err := out.Write([]string{
+			period.Title,
+			period.Start.Format("2006-01-02"),
+			locale.FormatDecimal(period.Billable.Hours()),
+			locale.FormatDecimal((period.Billable + period.Internal).Hours()),
+			locale.FormatDecimal(period.Capacity.Hours()),
+			strconv.Itoa(period.OfTracked()),
+			ofCapacity,
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
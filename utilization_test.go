@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestScheduledCapacity(t *testing.T) {
+	// Monday to Sunday
+	monday := time.Date(2026, 5, 4, 0, 0, 0, 0, time.Local)
+	week := addDays(monday, 7)
+
+	tests := []struct {
+		name     string
+		schedule []string
+		weekly   float64
+		off      []time.Time
+		want     time.Duration
+	}{
+		{"nothing planned", nil, 0, nil, 0},
+		{"weekly hours over Monday to Friday", nil, 40, nil, 40 * time.Hour},
+		{"weekly hours less a day off", nil, 40, []time.Time{addDays(monday, 2)}, 32 * time.Hour},
+		{"a day off at the weekend", nil, 40, []time.Time{addDays(monday, 5)}, 40 * time.Hour},
+		{"schedule blocks", []string{"Mon-Fri 09:00-17:00", "Sat 10:00-14:00"}, 0, nil, 44 * time.Hour},
+		{"schedule blocks over the weekly hours", []string{"Mon-Thu 08:00-12:00"}, 40, nil, 16 * time.Hour},
+		{"schedule blocks less a day off", []string{"Mon-Fri 09:00-17:00"}, 0, []time.Time{monday}, 32 * time.Hour},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prefs := test.NewTempApp(t).Preferences()
+			prefs.SetStringList(PrefWorkSchedule, tc.schedule)
+			prefs.SetFloat(PrefWeeklyHours, tc.weekly)
+			for _, day := range tc.off {
+				setTimeOff(day, TimeOffVacation)
+			}
+			if got := scheduledCapacity(monday, week); got != tc.want {
+				t.Errorf("scheduledCapacity = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUtilizationReport(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	prefs.SetFloat(PrefWeeklyHours, 40)
+	// Wednesday afternoon, three working days into the week
+	now := time.Date(2026, 5, 6, 15, 0, 0, 0, time.Local)
+	thisWeek := weekStart(now)
+	lastWeek := addDays(thisWeek, -7)
+	setTimeOff(addDays(lastWeek, 4), TimeOffHoliday)
+
+	entry := func(start time.Time, hours int, billable bool) Entry {
+		return Entry{Start: start.Add(9 * time.Hour), Duration: time.Duration(hours) * time.Hour, NonBillable: !billable}
+	}
+	entries := []Entry{
+		entry(lastWeek, 24, true),
+		entry(lastWeek, 8, false),
+		entry(thisWeek, 10, true),
+		entry(addDays(thisWeek, 1), 2, false),
+	}
+	// Left out until reviewed
+	imported := entry(addDays(thisWeek, 1), 6, true)
+	imported.Review = ReviewImported
+	entries = append(entries, imported)
+
+	periods := utilizationReport(entries, billableGroupings[0], now, 3)
+	tests := []struct {
+		start                 time.Time
+		billable, capacity    time.Duration
+		ofTracked, ofCapacity int
+		hasCapacity           bool
+	}{
+		// Nothing tracked against a full week
+		{addDays(lastWeek, -7), 0, 40 * time.Hour, 0, 0, true},
+		// Four days after the holiday
+		{lastWeek, 24 * time.Hour, 32 * time.Hour, 75, 75, true},
+		// Only Monday to today count so far
+		{thisWeek, 10 * time.Hour, 24 * time.Hour, 83, 42, true},
+	}
+	if len(periods) != len(tests) {
+		t.Fatalf("got %d periods, want %d", len(periods), len(tests))
+	}
+	for i, tc := range tests {
+		p := periods[i]
+		if !p.Start.Equal(tc.start) || p.Billable != tc.billable || p.Capacity != tc.capacity {
+			t.Errorf("period %d: from %v, %v billable of %v capacity; want from %v, %v of %v",
+				i, p.Start, p.Billable, p.Capacity, tc.start, tc.billable, tc.capacity)
+		}
+		if got := p.OfTracked(); got != tc.ofTracked {
+			t.Errorf("period %d: %d%% of tracked, want %d%%", i, got, tc.ofTracked)
+		}
+		if got, ok := p.OfCapacity(); got != tc.ofCapacity || ok != tc.hasCapacity {
+			t.Errorf("period %d: %d%% of capacity (%v), want %d%% (%v)", i, got, ok, tc.ofCapacity, tc.hasCapacity)
+		}
+	}
+
+	if _, ok := (utilizationPeriod{}).OfCapacity(); ok {
+		t.Error("a period without capacity has a share of it")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	EventTimerStarted = "timer.started"
+	EventTimerStopped = "timer.stopped"
+
+	PrefWebhookURLs = "webhookURLs"
+
+	HTTPTimeout = 10 * time.Second
+)
+
+// WebhookPayload is the JSON body posted to every configured webhook URL.
+type WebhookPayload struct {
+	Event          string    `json:"event"`
+	Task           string    `json:"task"`
+	ElapsedSeconds float64   `json:"elapsed_seconds"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// httpClient is shared by all outgoing integrations
+var httpClient = &http.Client{Timeout: HTTPTimeout}
+
+// webhookURLs returns the configured webhook URLs, skipping blank lines.
+func webhookURLs() []string {
+	var urls []string
+	for _, url := range fyne.CurrentApp().Preferences().StringList(PrefWebhookURLs) {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// sendWebhooks posts the event to all configured URLs in the background so a
+// slow endpoint never blocks the timer.
+func sendWebhooks(timer *TaskTimer, event string) {
+	urls := webhookURLs()
+	if len(urls) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{
+		Event:          event,
+		Task:           timer.taskName,
+		ElapsedSeconds: timer.elapsedTime.Seconds(),
+		Timestamp:      time.Now(),
+	})
+	if err != nil {
+		log.Printf("webhook: encoding payload: %v", err)
+		return
+	}
+
+	for _, url := range urls {
+		go postWebhook(url, body)
+	}
+}
+
+func postWebhook(url string, body []byte) {
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("webhook: posting to %s: %v", url, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("webhook: %s responded with %s", url, resp.Status)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// WeeklyReportTopTasks is how many tasks the report lists.
+const WeeklyReportTopTasks = 5
+
+// weeklyReport is a week's summary as rendered by the report templates. The
+// labels are in the UI language, and the durations and days already written
+// in the export locale.
+type weeklyReport struct {
+	Title      string
+	TotalLabel string
+	Total      string
+	Comparison string
+
+	AverageLabel      string
+	Average           string
+	AverageComparison string
+
+	ProjectsTitle string
+	Projects      []weeklyReportLine
+	TasksTitle    string
+	Tasks         []weeklyReportLine
+
+	// Days taken off, e.g. "10/12/2026: 🏖 Vacation"
+	TimeOffTitle string
+	TimeOff      []string
+}
+
+// weeklyReportLine is a project or task with its time this week, its share
+// of the week's total and the change on the week before.
+type weeklyReportLine struct {
+	Name     string
+	Duration string
+	Share    string
+	Change   string
+}
+
+// ReportFormat renders a weekly report for saving, copying or email.
+type ReportFormat struct {
+	Name        string
+	Extension   string
+	ContentType string
+	Render      func(w io.Writer, report weeklyReport) error
+}
+
+// The report templates. Markdown numbers the top tasks itself, so each is written as item 1.
+var markdownReportTemplate = texttemplate.Must(texttemplate.New("report").Parse(`# {{.Title}}
+
+**{{.TotalLabel}}:** {{.Total}} ({{.Comparison}})
+**{{.AverageLabel}}:** {{.Average}} ({{.AverageComparison}})
+
+## {{.ProjectsTitle}}
+
+{{range .Projects}}- {{.Name}}: {{.Duration}}, {{.Share}} ({{.Change}})
+{{end}}
+## {{.TasksTitle}}
+
+{{range .Tasks}}1. {{.Name}}: {{.Duration}}, {{.Share}} ({{.Change}})
+{{end}}{{if .TimeOff}}
+## {{.TimeOffTitle}}
+
+{{range .TimeOff}}- {{.}}
+{{end}}{{end}}`))
+
+var htmlReportTemplate = htmltemplate.Must(htmltemplate.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+td { padding: 0.2em 1em 0.2em 0; }
+.change { color: #666; }
+.time-off { color: #2a7ab0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><strong>{{.TotalLabel}}:</strong> {{.Total}} <span class="change">({{.Comparison}})</span><br>
+<strong>{{.AverageLabel}}:</strong> {{.Average}} <span class="change">({{.AverageComparison}})</span></p>
+<h2>{{.ProjectsTitle}}</h2>
+<table>
+{{range .Projects}}<tr><td>{{.Name}}</td><td>{{.Duration}}</td><td>{{.Share}}</td><td class="change">{{.Change}}</td></tr>
+{{end}}</table>
+<h2>{{.TasksTitle}}</h2>
+<ol>
+{{range .Tasks}}<li>{{.Name}}: {{.Duration}}, {{.Share}} <span class="change">({{.Change}})</span></li>
+{{end}}</ol>
+{{if .TimeOff}}<h2>{{.TimeOffTitle}}</h2>
+<ul class="time-off">
+{{range .TimeOff}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+var reportFormats = []ReportFormat{
+	{
+		Name:        "Markdown",
+		Extension:   ".md",
+		ContentType: "text/plain",
+		Render: func(w io.Writer, report weeklyReport) error {
+			return markdownReportTemplate.Execute(w, report)
+		},
+	},
+	{
+		Name:        "HTML",
+		Extension:   ".html",
+		ContentType: "text/html",
+		Render: func(w io.Writer, report weeklyReport) error {
+			return htmlReportTemplate.Execute(w, report)
+		},
+	},
+}
+
+// buildWeeklyReport summarizes the week starting at start: totals per
+// project, the top tasks with their shares of the week, the daily average,
+// and how each compares to the week before. Entries waiting for review are
+// left out.
+func buildWeeklyReport(timer *TaskTimer, start time.Time, locale ExportLocale) weeklyReport {
+	end, previous := addDays(start, 7), addDays(start, -7)
+	entries := reviewedEntries(entriesBetween(timer, start, end))
+	lastEntries := reviewedEntries(entriesBetween(timer, previous, start))
+
+	var total, lastTotal time.Duration
+	projects, lastProjects := make(map[string]time.Duration), make(map[string]time.Duration)
+	for _, entry := range entries {
+		total += entry.Duration
+		projects[reportProjectName(entry)] += entry.Duration
+	}
+	for _, entry := range lastEntries {
+		lastTotal += entry.Duration
+		lastProjects[reportProjectName(entry)] += entry.Duration
+	}
+	tasks, lastTasks := totalsByTask(entries), totalsByTask(lastEntries)
+
+	report := weeklyReport{
+		Title:         weekTitle(start),
+		TotalLabel:    tr("Total"),
+		Total:         locale.FormatDuration(total),
+		Comparison:    reportChange(total, lastTotal),
+		ProjectsTitle: tr("Projects"),
+		TasksTitle:    tr("Top tasks"),
+
+		AverageLabel:      tr("Daily average"),
+		Average:           locale.FormatDuration(dailyAverage(entries)),
+		AverageComparison: reportChange(dailyAverage(entries), dailyAverage(lastEntries)),
+
+		TimeOffTitle: tr("Time off"),
+	}
+	off := loadTimeOff()
+	for i := range 7 {
+		day := addDays(start, i)
+		if kind := off.On(day); kind != "" {
+			report.TimeOff = append(report.TimeOff, locale.FormatDay(day)+": "+timeOffName(kind))
+		}
+	}
+	for _, name := range sortedTaskNames(projects) {
+		report.Projects = append(report.Projects, weeklyReportLine{
+			Name:     name,
+			Duration: locale.FormatDuration(projects[name]),
+			Share:    formatShare(projects[name], total),
+			Change:   reportChange(projects[name], lastProjects[name]),
+		})
+	}
+	taskNames := sortedTaskNames(tasks)
+	if len(taskNames) > WeeklyReportTopTasks {
+		taskNames = taskNames[:WeeklyReportTopTasks]
+	}
+	for _, name := range taskNames {
+		report.Tasks = append(report.Tasks, weeklyReportLine{
+			Name:     name,
+			Duration: locale.FormatDuration(tasks[name]),
+			Share:    formatShare(tasks[name], total),
+			Change:   reportChange(tasks[name], lastTasks[name]),
+		})
+	}
+	return report
+}
+
+// formatShare writes part as a whole percentage of total, e.g. "42%".
+func formatShare(part, total time.Duration) string {
+	if total <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", int(100*part/total))
+}
+
+// dailyAverage is the time tracked per day that had any tracked time.
+func dailyAverage(entries []Entry) time.Duration {
+	var total time.Duration
+	days := make(map[time.Time]bool)
+	for _, entry := range entries {
+		total += entry.Duration
+		days[dayStart(entry.Start)] = true
+	}
+	if len(days) == 0 {
+		return 0
+	}
+	return total / time.Duration(len(days))
+}
+
+func reportProjectName(entry Entry) string {
+	if entry.Project == "" {
+		return tr("No project")
+	}
+	return entry.Project
+}
+
+// reportChange compares a week's time with the week before's, e.g.
+// "+2h (+25%) on last week".
+func reportChange(current, last time.Duration) string {
+	if last == 0 {
+		return tr("nothing last week")
+	}
+	return tr("{{.Change}} on last week", map[string]any{"Change": formatVariance(current, last)})
+}
+
+// periodChange compares a period's time with the period before's, e.g.
+// "+2h (+25%) on the previous period".
+func periodChange(current, last time.Duration) string {
+	if last == 0 {
+		return tr("nothing in the previous period")
+	}
+	return tr("{{.Change}} on the previous period", map[string]any{"Change": formatVariance(current, last)})
+}
+
+// showWeeklyReportDialog previews the report for this or last week in a
+// chosen format, to copy, save or email.
+func showWeeklyReportDialog(timer *TaskTimer) {
+	if !timer.loaded {
+		dialog.ShowInformation(tr("Weekly Report"), tr("Your history is still loading."), timer.window)
+		return
+	}
+
+	weeks := []string{tr("This week"), tr("Last week")}
+	weekSelect := widget.NewSelect(weeks, nil)
+	var formatNames []string
+	for _, format := range reportFormats {
+		formatNames = append(formatNames, format.Name)
+	}
+	formatSelect := widget.NewSelect(formatNames, nil)
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+
+	var start time.Time
+	var format ReportFormat
+	var rendered string
+	render := func() {
+		start = weekStart(time.Now())
+		if weekSelect.SelectedIndex() == 1 {
+			start = addDays(start, -7)
+		}
+		format = reportFormats[max(formatSelect.SelectedIndex(), 0)]
+		var out strings.Builder
+		if err := format.Render(&out, buildWeeklyReport(timer, start, currentExportLocale())); err != nil {
+			dialog.ShowError(err, timer.window)
+			return
+		}
+		rendered = out.String()
+		preview.SetText(rendered)
+	}
+	weekSelect.OnChanged = func(string) { render() }
+	formatSelect.OnChanged = func(string) { render() }
+	weekSelect.SetSelectedIndex(0)
+	formatSelect.SetSelectedIndex(0)
+
+	copyBtn := widget.NewButton(tr("Copy"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(rendered)
+	})
+	saveBtn := widget.NewButton(tr("Save…"), func() {
+		saveExport(timer, "week-"+start.Format("2006-01-02")+format.Extension, func(w io.Writer) error {
+			_, err := io.WriteString(w, rendered)
+			return err
+		})
+	})
+	var emailBtn *widget.Button
+	emailBtn = widget.NewButton(tr("Email"), func() {
+		if !smtpConfigured() {
+			dialog.ShowInformation(tr("Weekly Report"), tr("Set up the mail server and a recipient in Settings first."), timer.window)
+			return
+		}
+		subject := weekTitle(start)
+		body, contentType := rendered, format.ContentType
+		emailBtn.Disable()
+		go func() {
+			err := sendReportEmail(subject, contentType, body)
+			fyne.Do(func() {
+				emailBtn.Enable()
+				if err != nil {
+					dialog.ShowError(err, timer.window)
+					return
+				}
+				dialog.ShowInformation(tr("Weekly Report"), tr("The report was sent."), timer.window)
+			})
+		}()
+	})
+
+	content := container.NewBorder(
+		container.NewGridWithColumns(2, weekSelect, formatSelect),
+		container.NewHBox(copyBtn, saveBtn, emailBtn),
+		nil, nil,
+		container.NewVScroll(preview),
+	)
+	d := dialog.NewCustom(tr("Weekly Report"), tr("Close"), content, timer.window)
+	d.Resize(fyne.NewSize(480, 520))
+	d.Show()
+}
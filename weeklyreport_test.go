@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestWeeklyReportExportLocale(t *testing.T) {
+	prefs := test.NewTempApp(t).Preferences()
+	prefs.SetString(PrefDurationFormat, DurationFormatDecimal)
+	applyDurationFormat(prefs)
+	t.Cleanup(func() { durationFormat.Store(DurationFormatClock) })
+
+	start := weekStart(time.Date(2026, time.October, 14, 12, 0, 0, 0, time.Local))
+	off, err := json.Marshal(TimeOff{addDays(start, 2).Format(time.DateOnly): TimeOffVacation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefs.SetString(PrefTimeOff, string(off))
+	timer := &TaskTimer{entries: []Entry{
+		{Task: "Landing page", Project: "Web", Start: start.Add(9 * time.Hour), Duration: 90 * time.Minute},
+	}}
+
+	tests := map[string]struct{ total, timeOff string }{
+		"":      {total: "5400", timeOff: addDays(start, 2).Format("2006-01-02")},
+		"en-US": {total: "1.50", timeOff: addDays(start, 2).Format("01/02/2006")},
+		"de-DE": {total: "1,50", timeOff: addDays(start, 2).Format("02.01.2006")},
+	}
+	for tag, want := range tests {
+		report := buildWeeklyReport(timer, start, exportLocaleByTag(tag))
+		if report.Total != want.total || report.Average != want.total {
+			t.Errorf("%q: total %q, average %q, want %q", tag, report.Total, report.Average, want.total)
+		}
+		if len(report.Projects) != 1 || report.Projects[0].Duration != want.total {
+			t.Errorf("%q: projects %+v, want Web at %q", tag, report.Projects, want.total)
+		}
+		if len(report.Tasks) != 1 || report.Tasks[0].Duration != want.total {
+			t.Errorf("%q: tasks %+v, want Landing page at %q", tag, report.Tasks, want.total)
+		}
+		if wantOff := want.timeOff + ": " + timeOffName(TimeOffVacation); len(report.TimeOff) != 1 || report.TimeOff[0] != wantOff {
+			t.Errorf("%q: time off %q, want %q", tag, report.TimeOff, wantOff)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSXSheetNameLimit is the longest sheet name Excel accepts.
+const XLSXSheetNameLimit = 31
+
+// Cell styles, by their index in xlsxStyles' cellXfs.
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleDateTime
+	xlsxStyleDuration
+	xlsxStyleHeader
+	xlsxStyleTotal
+	xlsxStyleShare
+)
+
+// xlsxColumns are the columns of each project sheet, followed by the week
+// with ISO weeks. Durations are written in column E, which the totals refer
+// to.
+var xlsxColumns = []string{"date", "task", "start", "end", "hours", "notes"}
+
+// writeEntriesXLSX writes an Excel workbook with a sheet of entries per
+// project and a summary sheet totalling each project, and its share of the
+// whole, with formulas, so the
+// totals follow any edits made in Excel. Times and durations are written as
+// Excel values with number formats rather than as text, and headers follow
+// the export locale.
+func writeEntriesXLSX(w io.Writer, entries []Entry, locale ExportLocale) error {
+	byProject := make(map[string][]Entry)
+	for _, entry := range entries {
+		byProject[entry.Project] = append(byProject[entry.Project], entry)
+	}
+	projects := make([]string, 0, len(byProject))
+	for project := range byProject {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	summaryName := locale.Header("summary")
+	used := map[string]bool{strings.ToLower(summaryName): true}
+	sheetNames := []string{summaryName}
+	summary := [][]xlsxCell{{
+		{Value: locale.Header("project"), Style: xlsxStyleHeader},
+		{Value: locale.Header("hours"), Style: xlsxStyleHeader},
+		{Value: locale.Header("share"), Style: xlsxStyleHeader},
+	}}
+	var sheets [][][]xlsxCell
+	for _, project := range projects {
+		name := project
+		if name == "" {
+			name = locale.Header("noproject")
+		}
+		sheetName := xlsxSheetName(name, used)
+		sheetNames = append(sheetNames, sheetName)
+
+		projectEntries := byProject[project]
+		sort.Slice(projectEntries, func(i, j int) bool {
+			return projectEntries[i].Start.Before(projectEntries[j].Start)
+		})
+		columns := xlsxColumns
+		if isoWeeks() {
+			columns = append(columns[:len(columns):len(columns)], "week")
+		}
+		var header []xlsxCell
+		for _, key := range columns {
+			header = append(header, xlsxCell{Value: locale.Header(key), Style: xlsxStyleHeader})
+		}
+		rows := [][]xlsxCell{header}
+		for _, entry := range projectEntries {
+			row := []xlsxCell{
+				{Value: locale.FormatDay(entry.Start)},
+				{Value: entry.Task},
+				{Number: excelTime(entry.Start), Style: xlsxStyleDateTime},
+				{Number: excelTime(entry.End), Style: xlsxStyleDateTime},
+				{Number: entry.Duration.Hours() / 24, Style: xlsxStyleDuration},
+				{Value: entry.Notes},
+			}
+			if isoWeeks() {
+				row = append(row, xlsxCell{Value: isoWeekLabel(entry.Start)})
+			}
+			rows = append(rows, row)
+		}
+		totalRow := len(rows) + 1
+		rows = append(rows, []xlsxCell{
+			{Value: locale.Header("total"), Style: xlsxStyleHeader},
+			{}, {}, {},
+			{Formula: fmt.Sprintf("SUM(E2:E%d)", totalRow-1), Style: xlsxStyleTotal},
+		})
+		sheets = append(sheets, rows)
+
+		// Shares refer to the total row, the one after the last project
+		summary = append(summary, []xlsxCell{
+			{Value: name},
+			{Formula: fmt.Sprintf("%s!E%d", xlsxQuoteSheet(sheetName), totalRow), Style: xlsxStyleDuration},
+			{Formula: fmt.Sprintf("IF(B$%[2]d=0,0,B%[1]d/B$%[2]d)", len(summary)+1, len(projects)+2), Style: xlsxStyleShare},
+		})
+	}
+	total := "0"
+	if len(summary) > 1 {
+		total = fmt.Sprintf("SUM(B2:B%d)", len(summary))
+	}
+	summary = append(summary, []xlsxCell{
+		{Value: locale.Header("total"), Style: xlsxStyleHeader},
+		{Formula: total, Style: xlsxStyleTotal},
+	})
+	sheets = append([][][]xlsxCell{summary}, sheets...)
+
+	return writeXLSX(w, sheetNames, sheets)
+}
+
+// xlsxCell is a worksheet cell holding text, a number or a formula.
+type xlsxCell struct {
+	Value   string
+	Number  float64
+	Formula string
+	Style   int
+}
+
+// excelTime converts a time to Excel's serial date: days since the end of
+// 1899, in the time's own wall clock time.
+func excelTime(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+}
+
+// xlsxSheetName makes a name Excel accepts as a sheet name, unique among
+// those used so far, which are kept in lower case as Excel ignores case.
+func xlsxSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "-"
+	}
+
+	truncate := func(name string, limit int) string {
+		if runes := []rune(name); len(runes) > limit {
+			return string(runes[:limit])
+		}
+		return name
+	}
+	unique := truncate(name, XLSXSheetNameLimit)
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncate(name, XLSXSheetNameLimit-len(suffix)) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+func xlsxQuoteSheet(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// writeXLSX packs the sheets into a workbook. It writes the few parts of
+// the Office Open XML format a workbook needs, with text inline rather than
+// in a shared string table.
+func writeXLSX(w io.Writer, names []string, sheets [][][]xlsxCell) error {
+	out := zip.NewWriter(w)
+	write := func(name, content string) error {
+		part, err := out.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(part, content)
+		return err
+	}
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	for i, name := range names {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), n, n)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(names)+1)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			contentTypes.String() + `</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			workbookRels.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, rows := range sheets {
+		parts = append(parts, struct{ name, content string }{
+			fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxSheet(rows),
+		})
+	}
+
+	for _, part := range parts {
+		if err := write(part.name, part.content); err != nil {
+			return fmt.Errorf("xlsx: writing %s: %w", part.name, err)
+		}
+	}
+	return out.Close()
+}
+
+func xlsxSheet(rows [][]xlsxCell) string {
+	var sheet strings.Builder
+	sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := xlsxColumn(c) + strconv.Itoa(r+1)
+			switch {
+			case cell.Formula != "":
+				fmt.Fprintf(&sheet, `<c r="%s" s="%d"><f>%s</f></c>`, ref, cell.Style, xmlEscape(cell.Formula))
+			case cell.Value != "":
+				fmt.Fprintf(&sheet, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.Style, xmlEscape(cell.Value))
+			case cell.Style != xlsxStyleDefault:
+				fmt.Fprintf(&sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.Style, strconv.FormatFloat(cell.Number, 'f', -1, 64))
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	return sheet.String()
+}
+
+// xlsxStyles holds the cell styles in the order of the xlsxStyle constants:
+// plain, date and time, duration in hours past 24, bold, and a bold
+// duration for totals.
+var xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/><numFmt numFmtId="165" formatCode="[h]:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="6">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="165" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`<xf numFmtId="9" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs></styleSheet>`
+
+func xmlEscape(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}